@@ -176,6 +176,10 @@ func doAlterBackupSchedules(
 		return err
 	}
 
+	if err := validateCopyTo(ctx, p, s); err != nil {
+		return err
+	}
+
 	if err := processNextRunNow(p, spec, s); err != nil {
 		return err
 	}
@@ -327,6 +331,56 @@ func processScheduleOptions(
 				continue
 			}
 			s.incArgs.UpdatesLastBackupMetric = updatesLastBackupMetric
+		case optRetention, optKeepFullBackups:
+			// The retention policy is only enforced by the full schedule. An empty
+			// value clears the option.
+			if v == "" {
+				if k == optRetention {
+					s.fullArgs.Retention = 0
+				} else {
+					s.fullArgs.KeepFullBackups = 0
+				}
+				continue
+			}
+			policy, err := parseRetentionPolicy(map[string]string{k: v})
+			if err != nil {
+				return err
+			}
+			if k == optRetention {
+				s.fullArgs.Retention = policy.retention
+			} else {
+				s.fullArgs.KeepFullBackups = int32(policy.keepFullBackups)
+			}
+		case optCopyTo:
+			// Both schedules copy the collection after each of their backups. An
+			// empty value clears the option. The destination of the schedule is
+			// checked against the copy destination by validateCopyTo, once all the
+			// changes have been applied.
+			if v != "" {
+				if err := sql.CheckDestinationPrivileges(ctx, p, []string{v}); err != nil {
+					return err
+				}
+			}
+			s.fullArgs.CopyTo = v
+			if s.incArgs == nil {
+				continue
+			}
+			s.incArgs.CopyTo = v
+		case optNotify:
+			// The jobs of both schedules notify the same channel. An empty value
+			// clears the option.
+			if v != "" {
+				if err := jobs.ValidateNotificationURI(v); err != nil {
+					return err
+				}
+			}
+			fullDetails.NotificationURI = v
+			s.fullJob.SetScheduleDetails(*fullDetails)
+			if incDetails == nil {
+				continue
+			}
+			incDetails.NotificationURI = v
+			s.incJob.SetScheduleDetails(*incDetails)
 		default:
 			return errors.Newf("unexpected schedule option: %s = %s", k, v)
 		}
@@ -334,6 +388,30 @@ func processScheduleOptions(
 	return nil
 }
 
+// validateCopyTo checks that the altered schedule can copy its collection, as
+// CREATE SCHEDULE FOR BACKUP does: the schedule must have a single destination
+// and no incremental_location, and the copy destination must differ from it.
+func validateCopyTo(ctx context.Context, p sql.PlanHookState, s scheduleDetails) error {
+	if s.fullArgs.CopyTo == "" {
+		return nil
+	}
+	exprEval := p.ExprEvaluator("BACKUP")
+	to, err := exprEval.StringArray(ctx, tree.Exprs(s.fullStmt.To))
+	if err != nil {
+		return err
+	}
+	if len(to) != 1 || s.fullStmt.Options.IncrementalStorage != nil {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s is only supported for schedules with a single destination and no incremental_location",
+			optCopyTo)
+	}
+	if s.fullArgs.CopyTo == to[0] {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s must differ from the destination of the schedule", optCopyTo)
+	}
+	return nil
+}
+
 func processOptions(spec *alterBackupScheduleSpec, s scheduleDetails) error {
 	opts := spec.backupOptions
 	fullOpts := &s.fullStmt.Options
@@ -484,6 +562,8 @@ func processFullBackupRecurrence(
 			s.fullArgs.UpdatesLastBackupMetric,
 			s.incStmt,
 			s.fullArgs.ChainProtectedTimestampRecords,
+			backupRetentionPolicy{},
+			s.fullArgs.CopyTo,
 		)
 
 		if err != nil {
@@ -727,6 +807,10 @@ var alterBackupScheduleOptions = exprutil.KVOptionValidationMap{
 	optOnExecFailure:           exprutil.KVStringOptAny,
 	optOnPreviousRunning:       exprutil.KVStringOptAny,
 	optUpdatesLastBackupMetric: exprutil.KVStringOptAny,
+	optRetention:               exprutil.KVStringOptAny,
+	optKeepFullBackups:         exprutil.KVStringOptAny,
+	optCopyTo:                  exprutil.KVStringOptAny,
+	optNotify:                  exprutil.KVStringOptAny,
 }
 
 func alterBackupScheduleTypeCheck(
@@ -307,6 +307,39 @@ func TestAlterBackupScheduleWithSQLSpecialCharacters(t *testing.T) {
 	require.Equal(t, "@weekly", fullRecurrence)
 }
 
+func TestAlterBackupScheduleCopyTo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	th, cleanup := newAlterSchedulesTestHelper(t, nil)
+	defer cleanup()
+
+	rows := th.sqlDB.QueryStr(t, `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://1/src' `+
+		`RECURRING '@hourly' FULL BACKUP '@daily' WITH SCHEDULE OPTIONS copy_to = 'nodelocal://1/copy'`)
+	require.Len(t, rows, 2)
+	fullID, err := strconv.Atoi(rows[1][0])
+	require.NoError(t, err)
+
+	// The copy destination must differ from the destination of the schedule,
+	// whichever of them is altered.
+	th.sqlDB.ExpectErr(t, "copy_to must differ from the destination of the schedule",
+		fmt.Sprintf(`ALTER BACKUP SCHEDULE %d SET SCHEDULE OPTION copy_to = 'nodelocal://1/src'`, fullID))
+	th.sqlDB.ExpectErr(t, "copy_to must differ from the destination of the schedule",
+		fmt.Sprintf(`ALTER BACKUP SCHEDULE %d SET INTO 'nodelocal://1/copy'`, fullID))
+	th.sqlDB.ExpectErr(t, "copy_to is only supported for schedules with a single destination and no incremental_location",
+		fmt.Sprintf(`ALTER BACKUP SCHEDULE %d SET WITH incremental_location = 'nodelocal://1/inc'`, fullID))
+	th.sqlDB.Exec(t, fmt.Sprintf(`ALTER BACKUP SCHEDULE %d SET SCHEDULE OPTION copy_to = 'nodelocal://1/copy2'`, fullID))
+
+	// A user that can alter the schedule must also be allowed to access the
+	// copy destination.
+	th.sqlDB.Exec(t, `CREATE USER testuser`)
+	th.sqlDB.Exec(t, `GRANT SYSTEM REPAIRCLUSTER TO testuser`)
+	testuser := sqlutils.MakeSQLRunner(th.server.SQLConn(t, serverutils.User("testuser")))
+	testuser.ExpectErr(t, "only users with the admin role or the EXTERNALIOIMPLICITACCESS system privilege "+
+		"are allowed to access the specified nodelocal URI",
+		fmt.Sprintf(`ALTER BACKUP SCHEDULE %d SET SCHEDULE OPTION copy_to = 'nodelocal://1/copy3'`, fullID))
+}
+
 func scheduleStatusAndRecurrence(
 	t *testing.T, th *alterSchedulesTestHelper, id int,
 ) (status string, recurrence string) {
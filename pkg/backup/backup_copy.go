@@ -0,0 +1,879 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+const (
+	backupCopyOp = "COPY BACKUP"
+
+	backupCopyOptKMS                  = "kms"
+	backupCopyOptEncryptionPassphrase = "encryption_passphrase"
+	backupCopyOptNewKMS               = "new_kms"
+	backupCopyOptDetached             = "detached"
+
+	// backupCopyEncryptionInfoPrefix is the prefix of the ENCRYPTION-INFO files
+	// written alongside an encrypted full backup.
+	backupCopyEncryptionInfoPrefix = "ENCRYPTION-INFO"
+)
+
+var backupCopyOptionExpectValues = exprutil.KVOptionValidationMap{
+	backupCopyOptKMS:                  exprutil.KVStringOptRequireValue,
+	backupCopyOptEncryptionPassphrase: exprutil.KVStringOptRequireValue,
+	backupCopyOptNewKMS:               exprutil.KVStringOptRequireValue,
+	backupCopyOptDetached:             exprutil.KVStringOptRequireNoValue,
+}
+
+var backupCopyHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+	{Name: "status", Typ: types.String},
+	{Name: "backups", Typ: types.Int},
+	{Name: "files", Typ: types.Int},
+	{Name: "bytes", Typ: types.Int},
+}
+
+// backupCopyChecksumTable is the table used to checksum the copied files.
+var backupCopyChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+func backupCopyTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	copyStmt, ok := stmt.(*tree.BackupCopy)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(
+		ctx, backupCopyOp, p.SemaCtx(),
+		exprutil.Strings{copyStmt.From, copyStmt.To},
+		exprutil.KVOptions{
+			KVOptions:  copyStmt.Options,
+			Validation: backupCopyOptionExpectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	for _, opt := range copyStmt.Options {
+		if string(opt.Key) == backupCopyOptDetached {
+			return true, jobs.DetachedJobExecutionResultHeader, nil
+		}
+	}
+	return true, backupCopyHeader, nil
+}
+
+// backupCopyPlanHook implements PlanHookFn for COPY BACKUP.
+func backupCopyPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	copyStmt, ok := stmt.(*tree.BackupCopy)
+	if !ok {
+		return nil, nil, false, nil
+	}
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		backupCopyOp,
+	); err != nil {
+		return nil, nil, false, err
+	}
+
+	exprEval := p.ExprEvaluator(backupCopyOp)
+	from, err := exprEval.String(ctx, copyStmt.From)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	to, err := exprEval.String(ctx, copyStmt.To)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	opts, err := exprEval.KVOptions(ctx, copyStmt.Options, backupCopyOptionExpectValues)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	_, detached := opts[backupCopyOptDetached]
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || detached) {
+			return errors.Errorf("%s cannot be used inside a multi-statement transaction without DETACHED option",
+				backupCopyOp)
+		}
+		if err := sql.CheckDestinationPrivileges(ctx, p, []string{from, to}); err != nil {
+			return err
+		}
+		details, err := makeBackupCopyDetails(from, to, opts)
+		if err != nil {
+			return err
+		}
+		description, err := backupCopyJobDescription(details)
+		if err != nil {
+			return err
+		}
+
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		jr := jobs.Record{
+			Description: description,
+			Details:     details,
+			Progress:    jobspb.BackupCopyProgress{},
+			Username:    p.User(),
+		}
+		plannerTxn := p.Txn()
+
+		if detached {
+			_, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn())
+			if err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(
+				ctx, &sj, jobID, p.InternalSQLTxn(), jr,
+			); err != nil {
+				return err
+			}
+			return plannerTxn.Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if detached {
+		return fn, jobs.DetachedJobExecutionResultHeader, false, nil
+	}
+	return fn, backupCopyHeader, false, nil
+}
+
+// makeBackupCopyDetails validates the evaluated arguments of a COPY BACKUP
+// statement and returns the details of the job that performs the copy.
+func makeBackupCopyDetails(
+	from, to string, opts map[string]string,
+) (jobspb.BackupCopyDetails, error) {
+	if from == to {
+		return jobspb.BackupCopyDetails{}, pgerror.New(pgcode.InvalidParameterValue,
+			"cannot copy a backup collection onto itself")
+	}
+	details := jobspb.BackupCopyDetails{
+		Source:      from,
+		Destination: to,
+		NewKMSURI:   opts[backupCopyOptNewKMS],
+	}
+	kms, hasKMS := opts[backupCopyOptKMS]
+	passphrase, hasPassphrase := opts[backupCopyOptEncryptionPassphrase]
+	switch {
+	case hasKMS && hasPassphrase:
+		return jobspb.BackupCopyDetails{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"cannot specify both %s and %s", backupCopyOptKMS, backupCopyOptEncryptionPassphrase)
+	case hasKMS:
+		details.EncryptionOptions = &jobspb.BackupEncryptionOptions{
+			Mode:       jobspb.EncryptionMode_KMS,
+			RawKmsUris: []string{kms},
+		}
+	case hasPassphrase:
+		details.EncryptionOptions = &jobspb.BackupEncryptionOptions{
+			Mode:          jobspb.EncryptionMode_Passphrase,
+			RawPassphrase: passphrase,
+		}
+	}
+	if details.NewKMSURI != "" {
+		if details.EncryptionOptions == nil {
+			return jobspb.BackupCopyDetails{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s requires the %s that the source backups are encrypted with",
+				backupCopyOptNewKMS, backupCopyOptKMS)
+		}
+		if hasPassphrase {
+			// A passphrase derives the data key from a salt, so there is no data key
+			// that could be encrypted with the new KMS.
+			return jobspb.BackupCopyDetails{}, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s is only supported for backups encrypted with a KMS", backupCopyOptNewKMS)
+		}
+	}
+	return details, nil
+}
+
+// backupCopyJobDescription returns the description of a COPY BACKUP job with
+// the credentials in its URIs redacted.
+func backupCopyJobDescription(details jobspb.BackupCopyDetails) (string, error) {
+	from, err := cloud.SanitizeExternalStorageURI(details.Source, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	to, err := cloud.SanitizeExternalStorageURI(details.Destination, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	stmt := &tree.BackupCopy{From: tree.NewDString(from), To: tree.NewDString(to)}
+	if enc := details.EncryptionOptions; enc != nil {
+		switch enc.Mode {
+		case jobspb.EncryptionMode_KMS:
+			kms, err := cloud.RedactKMSURI(enc.RawKmsUris[0])
+			if err != nil {
+				return "", err
+			}
+			stmt.Options = append(stmt.Options, tree.KVOption{
+				Key: backupCopyOptKMS, Value: tree.NewDString(kms),
+			})
+		case jobspb.EncryptionMode_Passphrase:
+			stmt.Options = append(stmt.Options, tree.KVOption{
+				Key: backupCopyOptEncryptionPassphrase, Value: tree.NewDString(enc.RawPassphrase),
+			})
+		}
+	}
+	if details.NewKMSURI != "" {
+		kms, err := cloud.RedactKMSURI(details.NewKMSURI)
+		if err != nil {
+			return "", err
+		}
+		stmt.Options = append(stmt.Options, tree.KVOption{
+			Key: backupCopyOptNewKMS, Value: tree.NewDString(kms),
+		})
+	}
+	return tree.AsStringWithFlags(stmt, tree.FmtShowFullURIs), nil
+}
+
+// backupCopyJobInfoKey is the info_key of a backup job whose value is the ID of
+// the job started to copy its collection.
+const backupCopyJobInfoKey = "backup_copy_job"
+
+// maybeStartScheduledBackupCopy starts a job that copies the collection of a
+// completed backup to the copy_to destination of the schedule that created
+// the backup job, if any. The copy skips the backups that the destination
+// already has, so a copy that fails is completed by the next one.
+//
+// The ID of the copy job is recorded in the same transaction that creates it,
+// so that a backup job that is resumed after it completed doesn't start a
+// second copy.
+func maybeStartScheduledBackupCopy(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	details jobspb.BackupDetails,
+	id jobspb.JobID,
+) error {
+	if details.CollectionURI == "" {
+		return nil
+	}
+	env := scheduledBackupJobEnv(execCfg)
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		args, err := loadScheduledBackupArgs(ctx, txn, env, id)
+		if err != nil || args == nil || args.CopyTo == "" {
+			return err
+		}
+		infoStorage := jobs.InfoStorageForJob(txn, id)
+		if copyJobID, exists, err := infoStorage.Get(
+			ctx, "get-backup-copy-job", backupCopyJobInfoKey,
+		); err != nil {
+			return err
+		} else if exists {
+			log.Infof(ctx, "job %s already copies the collection of backup job %d",
+				redact.SafeString(copyJobID), id)
+			return nil
+		}
+		// The details of the backup job only hold the resolved encryption of the
+		// backup, so the raw passphrase or KMS URIs are read from the statement of
+		// the schedule.
+		node, err := parser.ParseOne(args.BackupStatement)
+		if err != nil {
+			return err
+		}
+		backupNode, ok := node.AST.(*tree.Backup)
+		if !ok {
+			return errors.Newf("unexpected node type %T in backup schedule", node.AST)
+		}
+		copyDetails := jobspb.BackupCopyDetails{
+			Source:      details.CollectionURI,
+			Destination: args.CopyTo,
+		}
+		if passphrase, ok := backupNode.Options.EncryptionPassphrase.(*tree.StrVal); ok {
+			copyDetails.EncryptionOptions = &jobspb.BackupEncryptionOptions{
+				Mode:          jobspb.EncryptionMode_Passphrase,
+				RawPassphrase: passphrase.RawString(),
+			}
+		} else if len(backupNode.Options.EncryptionKMSURI) > 0 {
+			enc := &jobspb.BackupEncryptionOptions{Mode: jobspb.EncryptionMode_KMS}
+			for _, uri := range backupNode.Options.EncryptionKMSURI {
+				if uri, ok := uri.(*tree.StrVal); ok {
+					enc.RawKmsUris = append(enc.RawKmsUris, uri.RawString())
+				}
+			}
+			copyDetails.EncryptionOptions = enc
+		}
+		description, err := backupCopyJobDescription(copyDetails)
+		if err != nil {
+			return err
+		}
+		jobID := execCfg.JobRegistry.MakeJobID()
+		_, err = execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, jobs.Record{
+			Description: description,
+			Details:     copyDetails,
+			Progress:    jobspb.BackupCopyProgress{},
+			Username:    user,
+		}, jobID, txn)
+		if err != nil {
+			return err
+		}
+		if err := infoStorage.Write(
+			ctx, backupCopyJobInfoKey, []byte(strconv.FormatInt(int64(jobID), 10)),
+		); err != nil {
+			return err
+		}
+		log.Infof(ctx, "started job %d to copy the collection of backup job %d", jobID, id)
+		return nil
+	})
+}
+
+// backupCopyLayer is a single full or incremental backup of a collection.
+type backupCopyLayer struct {
+	// dir is the path of the backup relative to the collection.
+	dir string
+	// fullDir is the path of the full backup that the backup's chain starts
+	// with, relative to the collection. It is equal to dir for full backups.
+	fullDir string
+	// nested are the paths, relative to dir, of incremental backups stored
+	// inside of a full backup by older versions, whose files are not part of
+	// the full backup.
+	nested []string
+}
+
+func (l backupCopyLayer) isFull() bool {
+	return l.dir == l.fullDir
+}
+
+// owns returns whether the file with the given name, relative to the layer,
+// belongs to the layer rather than to one of its nested incremental backups.
+func (l backupCopyLayer) owns(name string) bool {
+	for _, n := range l.nested {
+		if strings.HasPrefix(name, n+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// isBackupCopyManifestFile returns whether the file is one of the manifest
+// files of a backup. The presence of the manifest is what marks a backup as
+// complete, so these files are copied after every other file of the backup.
+func isBackupCopyManifestFile(name string) bool {
+	for _, manifest := range []string{
+		backupbase.BackupManifestName,
+		backupbase.BackupOldManifestName,
+		backupbase.BackupMetadataName,
+	} {
+		if name == manifest || name == manifest+backupinfo.BackupManifestChecksumSuffix {
+			return true
+		}
+	}
+	return false
+}
+
+type backupCopyResumer struct {
+	job *jobs.Job
+
+	// copiedBackups, copiedFiles and copiedBytes count what the current
+	// execution of the job copied, for the results of the statement.
+	copiedBackups int
+	copiedFiles   int64
+	copiedBytes   int64
+}
+
+var _ jobs.Resumer = &backupCopyResumer{}
+
+// Resume implements the jobs.Resumer interface.
+func (r *backupCopyResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	details := r.job.Details().(jobspb.BackupCopyDetails)
+	execCfg := p.ExecCfg()
+	makeStorage := execCfg.DistSQLSrv.ExternalStorageFromURI
+	user := p.User()
+
+	srcCollection, err := makeStorage(ctx, details.Source, user)
+	if err != nil {
+		return err
+	}
+	defer srcCollection.Close()
+
+	layers, err := listBackupCopyLayers(ctx, srcCollection, details.Source, makeStorage, user)
+	if err != nil {
+		return errors.Wrap(err, "listing the backups of the source collection")
+	}
+
+	done := make(map[string]bool)
+	for _, dir := range r.job.Progress().Details.(*jobspb.Progress_BackupCopy).BackupCopy.CopiedBackups {
+		done[dir] = true
+	}
+
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &execCfg.ExternalIODirConfig, execCfg.InternalDB, user,
+	)
+	for _, layer := range layers {
+		if done[layer.dir] {
+			continue
+		}
+		copied, err := r.copyLayer(ctx, p, details, layer, &kmsEnv)
+		if err != nil {
+			return errors.Wrapf(err, "copying backup %s", redact.SafeString(layer.dir))
+		}
+		done[layer.dir] = true
+		if !copied {
+			continue
+		}
+		r.copiedBackups++
+		if err := r.job.NoTxn().Update(ctx, func(
+			txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+		) error {
+			prog := md.Progress.GetBackupCopy()
+			prog.CopiedBackups = append(prog.CopiedBackups, layer.dir)
+			prog.CopiedFiles = r.copiedFiles
+			prog.CopiedBytes = r.copiedBytes
+			md.Progress.RunningStatus = fmt.Sprintf("copied %d of %d backups", len(done), len(layers))
+			ju.UpdateProgress(md.Progress)
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "failed to update job %d", r.job.ID())
+		}
+	}
+
+	if r.copiedBackups == 0 {
+		return nil
+	}
+	return r.maybeWriteLatest(ctx, p, details, srcCollection, done)
+}
+
+// listBackupCopyLayers returns the backups in the collection, ordered such
+// that the full backup of a chain precedes its incremental backups.
+func listBackupCopyLayers(
+	ctx context.Context,
+	collection cloud.ExternalStorage,
+	collectionURI string,
+	makeStorage cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
+) ([]backupCopyLayer, error) {
+	fulls, err := backupdest.ListFullBackupsInCollection(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fulls)
+
+	listIncrementals := func(parts ...string) ([]string, error) {
+		uris, err := backuputils.AppendPaths([]string{collectionURI}, parts...)
+		if err != nil {
+			return nil, err
+		}
+		store, err := makeStorage(ctx, uris[0], user)
+		if err != nil {
+			return nil, err
+		}
+		defer store.Close()
+		incs, err := backupdest.FindPriorBackups(ctx, store, backupdest.OmitManifest)
+		if err != nil {
+			return nil, err
+		}
+		for i := range incs {
+			incs[i] = strings.TrimPrefix(incs[i], "/")
+		}
+		return incs, nil
+	}
+
+	var layers []backupCopyLayer
+	for _, full := range fulls {
+		fullDir := "/" + strings.TrimPrefix(full, "/")
+		nested, err := listIncrementals(fullDir)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, backupCopyLayer{dir: fullDir, fullDir: fullDir, nested: nested})
+		for _, inc := range nested {
+			layers = append(layers, backupCopyLayer{dir: path.Join(fullDir, inc), fullDir: fullDir})
+		}
+		incs, err := listIncrementals(backupbase.DefaultIncrementalsSubdir, fullDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, inc := range incs {
+			layers = append(layers, backupCopyLayer{
+				dir:     path.Join("/", backupbase.DefaultIncrementalsSubdir, fullDir, inc),
+				fullDir: fullDir,
+			})
+		}
+	}
+	return layers, nil
+}
+
+// copyLayer copies the files of the backup to the destination collection,
+// unless the destination already has a complete copy of it, and verifies the
+// copy. It returns whether anything was copied.
+func (r *backupCopyResumer) copyLayer(
+	ctx context.Context,
+	p sql.JobExecContext,
+	details jobspb.BackupCopyDetails,
+	layer backupCopyLayer,
+	kmsEnv cloud.KMSEnv,
+) (bool, error) {
+	makeStorage := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	user := p.User()
+
+	uris, err := backuputils.AppendPaths([]string{details.Source, details.Destination}, layer.dir)
+	if err != nil {
+		return false, err
+	}
+	src, err := makeStorage(ctx, uris[0], user)
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+	dst, err := makeStorage(ctx, uris[1], user)
+	if err != nil {
+		return false, err
+	}
+	defer dst.Close()
+
+	if exists, err := backupCopyFileExists(ctx, dst, backupbase.BackupManifestName); err != nil {
+		return false, err
+	} else if exists {
+		log.Infof(ctx, "backup %s is already present at the destination", redact.SafeString(layer.dir))
+		return false, nil
+	}
+
+	var dataFiles, manifestFiles []string
+	if err := src.List(ctx, "", "", func(name string) error {
+		name = strings.TrimPrefix(name, "/")
+		switch {
+		case !layer.owns(name):
+		case layer.isFull() && details.NewKMSURI != "" &&
+			strings.HasPrefix(name, backupCopyEncryptionInfoPrefix):
+			// The data key is re-encrypted with the new KMS below.
+		case isBackupCopyManifestFile(name):
+			manifestFiles = append(manifestFiles, name)
+		default:
+			dataFiles = append(dataFiles, name)
+		}
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	// BACKUP_MANIFEST is copied last, since its presence is what marks the
+	// backup as complete.
+	if i := slices.Index(manifestFiles, backupbase.BackupManifestName); i >= 0 {
+		manifestFiles = append(slices.Delete(manifestFiles, i, i+1), backupbase.BackupManifestName)
+	}
+
+	if layer.isFull() && details.NewKMSURI != "" {
+		if err := reencryptBackupCopyDataKey(ctx, p, details, uris[0], dst, kmsEnv); err != nil {
+			return false, err
+		}
+	}
+
+	copied := make(map[string]bool, len(dataFiles))
+	for _, name := range append(dataFiles, manifestFiles...) {
+		if err := r.copyFile(ctx, src, dst, name); err != nil {
+			return false, err
+		}
+		copied[name] = true
+	}
+
+	if err := verifyBackupCopyLayer(ctx, p, details, layer, dst, uris[1], copied, kmsEnv); err != nil {
+		// Remove the manifests of the copy so that it is not mistaken for a
+		// complete backup, and is copied again by the next attempt.
+		for _, name := range manifestFiles {
+			if delErr := dst.Delete(ctx, name); delErr != nil {
+				err = errors.CombineErrors(err, delErr)
+			}
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// copyFile copies a single file and checks that the checksum of the file at
+// the destination matches the checksum of the bytes read from the source.
+func (r *backupCopyResumer) copyFile(
+	ctx context.Context, src, dst cloud.ExternalStorage, name string,
+) error {
+	reader, _, err := src.ReadFile(ctx, name, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return err
+	}
+	defer reader.Close(ctx)
+	srcSum := newChecksummingReader(ioctx.ReaderCtxAdapter(ctx, reader))
+	if err := cloud.WriteFile(ctx, dst, name, srcSum); err != nil {
+		return err
+	}
+
+	written, _, err := dst.ReadFile(ctx, name, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return err
+	}
+	defer written.Close(ctx)
+	dstSum := newChecksummingReader(ioctx.ReaderCtxAdapter(ctx, written))
+	if _, err := io.Copy(io.Discard, dstSum); err != nil {
+		return err
+	}
+	if srcSum.n != dstSum.n || srcSum.h.Sum32() != dstSum.h.Sum32() {
+		return errors.Errorf(
+			"checksum mismatch for %s: copied %d bytes with checksum %08x, found %d bytes with checksum %08x",
+			name, srcSum.n, srcSum.h.Sum32(), dstSum.n, dstSum.h.Sum32())
+	}
+	r.copiedFiles++
+	r.copiedBytes += srcSum.n
+	return nil
+}
+
+// checksummingReader checksums and counts the bytes read through it.
+type checksummingReader struct {
+	r io.Reader
+	h hash.Hash32
+	n int64
+}
+
+func newChecksummingReader(r io.Reader) *checksummingReader {
+	return &checksummingReader{r: r, h: crc32.New(backupCopyChecksumTable)}
+}
+
+// Read implements the io.Reader interface.
+func (c *checksummingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	_, _ = c.h.Write(p[:n])
+	return n, err
+}
+
+func backupCopyFileExists(
+	ctx context.Context, store cloud.ExternalStorage, name string,
+) (bool, error) {
+	r, _, err := store.ReadFile(ctx, name, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	r.Close(ctx)
+	return true, nil
+}
+
+// reencryptBackupCopyDataKey writes the ENCRYPTION-INFO file of a copied full
+// backup, holding the data key of the source backup encrypted with the new
+// KMS of the copy.
+func reencryptBackupCopyDataKey(
+	ctx context.Context,
+	p sql.JobExecContext,
+	details jobspb.BackupCopyDetails,
+	srcURI string,
+	dst cloud.ExternalStorage,
+	kmsEnv cloud.KMSEnv,
+) error {
+	srcEnc, err := backupencryption.GetEncryptionFromBase(ctx, p.User(),
+		p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, srcURI, *details.EncryptionOptions, kmsEnv)
+	if err != nil {
+		return err
+	}
+	dataKey, err := backupencryption.GetEncryptionKey(ctx, srcEnc, kmsEnv)
+	if err != nil {
+		return err
+	}
+	keys, _, err := backupencryption.GetEncryptedDataKeyByKMSMasterKeyID(
+		ctx, []string{details.NewKMSURI}, dataKey, kmsEnv)
+	if err != nil {
+		return err
+	}
+	encryptedDataKeys := make(map[string][]byte)
+	keys.RangeOverMap(func(masterKeyID backupencryption.HashedMasterKeyID, dataKey []byte) {
+		encryptedDataKeys[string(masterKeyID)] = dataKey
+	})
+	return backupencryption.WriteEncryptionInfoIfNotExists(ctx,
+		&jobspb.EncryptionInfo{EncryptedDataKeyByKMSMasterKeyID: encryptedDataKeys}, dst)
+}
+
+// verifyBackupCopyLayer reads the manifest of a copied backup, using the
+// encryption of the copy, and checks that every file it references was
+// copied.
+func verifyBackupCopyLayer(
+	ctx context.Context,
+	p sql.JobExecContext,
+	details jobspb.BackupCopyDetails,
+	layer backupCopyLayer,
+	dst cloud.ExternalStorage,
+	dstURI string,
+	copied map[string]bool,
+	kmsEnv cloud.KMSEnv,
+) error {
+	var enc *jobspb.BackupEncryptionOptions
+	if details.EncryptionOptions != nil {
+		params := *details.EncryptionOptions
+		if details.NewKMSURI != "" {
+			params = jobspb.BackupEncryptionOptions{
+				Mode:       jobspb.EncryptionMode_KMS,
+				RawKmsUris: []string{details.NewKMSURI},
+			}
+		}
+		fullURIs, err := backuputils.AppendPaths([]string{details.Destination}, layer.fullDir)
+		if err != nil {
+			return err
+		}
+		enc, err = backupencryption.GetEncryptionFromBase(ctx, p.User(),
+			p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, fullURIs[0], params, kmsEnv)
+		if err != nil {
+			return err
+		}
+	}
+
+	mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	manifest, memSize, err := backupinfo.ReadBackupManifestFromStore(ctx, &mem, dst, dstURI, enc, kmsEnv)
+	if err != nil {
+		return errors.Wrap(err, "reading the manifest of the copy")
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	it, err := backupinfo.NewIterFactory(&manifest, dst, enc, kmsEnv).NewFileIter(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		f := it.Value()
+		if f.LocalityKV != "" {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s does not support locality-aware backups", backupCopyOp)
+		}
+		if !copied[f.Path] {
+			return errors.Errorf("file %s referenced by the manifest was not copied", f.Path)
+		}
+	}
+	return nil
+}
+
+// maybeWriteLatest points the LATEST file of the destination at the latest
+// backup of the source, if that backup has been copied.
+func (r *backupCopyResumer) maybeWriteLatest(
+	ctx context.Context,
+	p sql.JobExecContext,
+	details jobspb.BackupCopyDetails,
+	srcCollection cloud.ExternalStorage,
+	copied map[string]bool,
+) error {
+	latestFile, err := backupdest.FindLatestFile(ctx, srcCollection)
+	if err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer latestFile.Close(ctx)
+	latest, err := ioctx.ReadAll(ctx, latestFile)
+	if err != nil {
+		return err
+	}
+	if !copied["/"+strings.TrimPrefix(string(latest), "/")] {
+		return nil
+	}
+	dstCollection, err := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, details.Destination, p.User())
+	if err != nil {
+		return err
+	}
+	defer dstCollection.Close()
+	return backupdest.WriteNewLatestFile(ctx, p.ExecCfg().Settings, dstCollection, string(latest))
+}
+
+// ReportResults implements the jobs.JobResultsReporter interface.
+func (r *backupCopyResumer) ReportResults(ctx context.Context, resultsCh chan<- tree.Datums) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(r.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDInt(tree.DInt(r.copiedBackups)),
+		tree.NewDInt(tree.DInt(r.copiedFiles)),
+		tree.NewDInt(tree.DInt(r.copiedBytes)),
+	}:
+		return nil
+	}
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface. The files copied by
+// a failed job are left in place, since a later copy skips the backups that
+// are complete at the destination and overwrites the rest.
+func (r *backupCopyResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	return nil
+}
+
+// CollectProfile implements the jobs.Resumer interface.
+func (r *backupCopyResumer) CollectProfile(ctx context.Context, execCtx interface{}) error {
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook("backup copy", backupCopyPlanHook, backupCopyTypeCheck)
+	jobs.RegisterConstructor(
+		jobspb.TypeBackupCopy,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &backupCopyResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
@@ -946,6 +946,23 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		if err := backupdest.WriteNewLatestFile(ctx, p.ExecCfg().Settings, c, suffix); err != nil {
 			return err
 		}
+
+		// Now that the LATEST file points to this backup, older chains of backups
+		// in the collection may have expired. Failing to delete them does not
+		// fail the backup, they will be deleted after the next full backup.
+		if err := maybeEnforceScheduleRetention(
+			ctx, p.ExecCfg(), p.User(), backupDetails, b.job.ID(),
+		); err != nil {
+			log.Warningf(ctx, "failed to enforce retention policy of backup schedule: %v", err)
+		}
+	}
+
+	// Copy the collection to the second destination of the schedule, if any,
+	// now that it holds this backup.
+	if err := maybeStartScheduledBackupCopy(
+		ctx, p.ExecCfg(), p.User(), backupDetails, b.job.ID(),
+	); err != nil {
+		log.Warningf(ctx, "failed to start copy of the backup collection: %v", err)
 	}
 
 	b.backupStats = res
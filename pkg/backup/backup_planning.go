@@ -393,7 +393,9 @@ func backupTypeCheck(
 			backupStmt.Options.CaptureRevisionHistory,
 			backupStmt.Options.IncludeAllSecondaryTenants,
 			backupStmt.Options.UpdatesClusterMonitoringMetrics,
-		}); err != nil {
+		},
+		exprutil.Ints(backupStmt.Options.DependsOn),
+	); err != nil {
 		return false, nil, err
 	}
 	return true, header, nil
@@ -515,6 +517,18 @@ func backupPlanHook(
 		}
 	}
 
+	var dependsOn []jobspb.JobID
+	for _, expr := range backupStmt.Options.DependsOn {
+		id, err := exprEval.Int(ctx, expr)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		dependsOn = append(dependsOn, jobspb.JobID(id))
+	}
+	if len(dependsOn) > 0 && !detached {
+		return nil, nil, false, errors.New("the depends_on option requires the detached option")
+	}
+
 	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
 		// TODO(dan): Move this span into sql.
 		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
@@ -680,6 +694,7 @@ func backupPlanHook(
 				}
 				return sqlDescIDs
 			}(),
+			DependsOn: dependsOn,
 		}
 		plannerTxn := p.Txn()
 
@@ -0,0 +1,744 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupsink"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/idxconstraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/partition"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+const backupRowsBuiltinName = "crdb_internal.backup_rows"
+
+// backupRowsBatchSize is the number of KVs after which the backupRowsGenerator
+// hands the KVs read so far, up to the end of the current row, to its fetcher.
+const backupRowsBatchSize = 1000
+
+func init() {
+	props := tree.FunctionProperties{
+		Category:          builtinconstants.CategoryGenerator,
+		ReturnsRecordType: true,
+		// The generator needs the planner of the session to resolve and read the
+		// backup, so it must run on the gateway.
+		DistsqlBlocklist: true,
+	}
+	params := tree.ParamTypes{
+		{Name: "collection", Typ: types.String},
+		{Name: "subdir", Typ: types.String},
+		{Name: "table", Typ: types.String},
+	}
+	asOfParam := tree.ParamType{Name: "as_of", Typ: types.TimestampTZ}
+	const info = "Reads the rows of a table straight from a backup, without restoring it. " +
+		"The columns to read are selected by the column definition list of the call, " +
+		"which must name columns of the backed-up table with their types. " +
+		"subdir may be LATEST to read the most recent backup in the collection.\n\n" +
+		"Example usage:\n\n" +
+		"`SELECT * FROM crdb_internal.backup_rows('s3://bucket/backups', 'LATEST', 'db.orders') " +
+		"AS t(id INT, status STRING)`"
+	builtinsregistry.Register(backupRowsBuiltinName, &props, []tree.Overload{
+		{
+			Types: params,
+			// NOTE: this type will never actually get used. It is replaced in the
+			// optimizer by looking at the most recent AS alias clause.
+			ReturnType: tree.FixedReturnType(types.EmptyTuple),
+			Generator:  eval.GeneratorOverload(makeBackupRowsGenerator),
+			Class:      tree.GeneratorClass,
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+		{
+			Types:      append(params[:len(params):len(params)], asOfParam),
+			ReturnType: tree.FixedReturnType(types.EmptyTuple),
+			Generator:  eval.GeneratorOverload(makeBackupRowsGenerator),
+			Class:      tree.GeneratorClass,
+			Info: info + "\n\nThe rows are read as of the given time, which must be " +
+				"covered by the revision history of the backup if it is not the end " +
+				"time of one of its layers.",
+			Volatility: volatility.Volatile,
+		},
+		{
+			Types: append(params[:len(params):len(params)], asOfParam,
+				tree.ParamType{Name: "filter", Typ: types.String}),
+			ReturnType: tree.FixedReturnType(types.EmptyTuple),
+			Generator:  eval.GeneratorOverload(makeBackupRowsGenerator),
+			Class:      tree.GeneratorClass,
+			Info: info + "\n\nThe rows are read as of the given time, or as of the end " +
+				"of the backup if it is NULL, and only the rows that satisfy the filter " +
+				"are returned. The filter is a boolean expression over the columns of " +
+				"the backed-up table, which restricts the spans of the primary index " +
+				"that are read where possible.",
+			Volatility:        volatility.Volatile,
+			CalledOnNullInput: true,
+		},
+	})
+}
+
+// backupRowsGenerator implements crdb_internal.backup_rows. It resolves the
+// backup chain in the same way as RESTORE, opens the backup files that
+// overlap the primary index of the requested table, and decodes their KVs with
+// the backed-up table descriptor.
+type backupRowsGenerator struct {
+	evalCtx    *eval.Context
+	collection string
+	subdir     string
+	table      string
+	asOf       hlc.Timestamp
+	// filter, if set, is a predicate that the returned rows satisfy.
+	filter string
+
+	types  []*types.T
+	labels []string
+	// allColumns, if set, reads every column of the backed-up table that can be
+	// read, instead of the columns named by the column definition list.
+	allColumns bool
+	// desc is the backed-up table descriptor, set by start.
+	desc catalog.TableDescriptor
+	// dataFiles identifies the data files that the rows are read from, and
+	// dataEndTime is the end time of the last backup that they belong to.
+	// readTime is the time that the rows are read as of. All are set by start.
+	dataFiles   []string
+	dataEndTime hlc.Timestamp
+	readTime    hlc.Timestamp
+
+	mem     mon.BoundAccount
+	stores  []cloud.ExternalStorage
+	cleanup []func()
+
+	iter    *storage.ReadAsOfIterator
+	fetcher row.Fetcher
+	// elidedPrefix is the prefix of the keys of the primary index that is
+	// elided from the keys in the backup files.
+	elidedPrefix roachpb.Key
+	// spans are the spans of the primary index that remain to be read. The
+	// iterator is positioned in the first one.
+	spans     roachpb.Spans
+	exhausted bool
+
+	// filterExpr is the typed filter, which is evaluated against the fetched
+	// columns through filterVars. The fetched columns start with the requested
+	// ones, followed by the other columns referenced by the filter.
+	filterExpr tree.TypedExpr
+	filterVars schemaexpr.RowIndexedVarContainer
+
+	kvs    []roachpb.KeyValue
+	values tree.Datums
+}
+
+var _ eval.ValueGenerator = &backupRowsGenerator{}
+var _ eval.AliasAwareValueGenerator = &backupRowsGenerator{}
+
+func makeBackupRowsGenerator(
+	ctx context.Context, evalCtx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	// Only the overload with a filter is called on NULL arguments, for which
+	// only as_of and the filter may be NULL.
+	for i, name := range []string{"collection", "subdir", "table"} {
+		if args[i] == tree.DNull {
+			return nil, pgerror.Newf(pgcode.NullValueNotAllowed,
+				"%s: %s must not be NULL", backupRowsBuiltinName, name)
+		}
+	}
+	g := &backupRowsGenerator{
+		evalCtx:    evalCtx,
+		collection: string(tree.MustBeDString(args[0])),
+		subdir:     string(tree.MustBeDString(args[1])),
+		table:      string(tree.MustBeDString(args[2])),
+	}
+	if len(args) > 3 && args[3] != tree.DNull {
+		g.asOf = hlc.Timestamp{WallTime: tree.MustBeDTimestampTZ(args[3]).UnixNano()}
+	}
+	if len(args) > 4 && args[4] != tree.DNull {
+		g.filter = string(tree.MustBeDString(args[4]))
+	}
+	return g, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *backupRowsGenerator) ResolvedType() *types.T {
+	return types.AnyTuple
+}
+
+// SetAlias implements the eval.AliasAwareValueGenerator interface.
+func (g *backupRowsGenerator) SetAlias(types []*types.T, labels []string) error {
+	if len(types) != len(labels) {
+		return errors.AssertionFailedf(
+			"unexpected mismatched types/labels list in backup rows generator %v %v", types, labels)
+	}
+	g.types = types
+	g.labels = labels
+	return nil
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *backupRowsGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	p, ok := g.evalCtx.JobExecContext.(sql.PlanHookState)
+	if !ok {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s cannot be used in this context", backupRowsBuiltinName)
+	}
+	return g.start(ctx, p)
+}
+
+// start resolves the backup and the table in it, and positions the generator
+// on the first row of the table.
+func (g *backupRowsGenerator) start(ctx context.Context, p sql.PlanHookState) error {
+	if len(g.labels) == 0 && !g.allColumns {
+		return pgerror.Newf(pgcode.Syntax,
+			"a column definition list is required for %s", backupRowsBuiltinName)
+	}
+	if err := sql.CheckDestinationPrivileges(ctx, p, []string{g.collection}); err != nil {
+		return err
+	}
+	execCfg := p.ExecCfg()
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	subdir := g.subdir
+	if strings.EqualFold(subdir, backupbase.LatestFileName) {
+		var err error
+		subdir, err = backupdest.ReadLatestFile(ctx, g.collection, mkStore, p.User())
+		if err != nil {
+			return errors.Wrap(err, "read LATEST path")
+		}
+	}
+	fullyResolvedDest, err := backuputils.AppendPaths([]string{g.collection}, subdir)
+	if err != nil {
+		return err
+	}
+	baseStore, err := mkStore(ctx, fullyResolvedDest[0], p.User())
+	if err != nil {
+		return errors.Wrapf(err, "make storage")
+	}
+	g.stores = append(g.stores, baseStore)
+
+	collections, computedSubdir, err := backupdest.CollectionsAndSubdir([]string{g.collection}, subdir)
+	if err != nil {
+		return err
+	}
+	fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, p.User(), execCfg, nil /* explicitIncrementalCollections */, collections, computedSubdir,
+	)
+	if err != nil {
+		return err
+	}
+	incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+		fullyResolvedIncrementalsDirectory)
+	if err != nil {
+		return err
+	}
+	g.cleanup = append(g.cleanup, func() {
+		if err := cleanupFn(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	})
+
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings,
+		&execCfg.ExternalIODirConfig,
+		execCfg.InternalDB,
+		p.User(),
+	)
+	g.mem = execCfg.RootMemoryMonitor.MakeBoundAccount()
+	_, manifests, localityInfo, _, err := backupdest.ResolveBackupManifests(
+		ctx, &g.mem, []cloud.ExternalStorage{baseStore}, incStores, mkStore, fullyResolvedDest,
+		fullyResolvedIncrementalsDirectory, g.asOf, nil /* encryption */, &kmsEnv, p.User(),
+		false, /* includeSkipped */
+	)
+	if err != nil {
+		return err
+	}
+	layerToIterFactory, err := backupinfo.GetBackupManifestIterFactories(
+		ctx, execCfg.DistSQLSrv.ExternalStorage, manifests, nil /* encryption */, &kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+	if err := maybeUpgradeDescriptorsInBackupManifests(ctx,
+		execCfg.Settings.Version.ActiveVersion(ctx),
+		manifests,
+		layerToIterFactory,
+		true /* skipFKsWithNoMatchingTable */); err != nil {
+		return err
+	}
+	table, err := g.resolveTable(ctx, p, manifests, layerToIterFactory)
+	if err != nil {
+		return err
+	}
+	g.desc = table
+	g.readTime = g.asOf
+	if g.readTime.IsEmpty() {
+		g.readTime = manifests[len(manifests)-1].EndTime
+	}
+	if g.allColumns {
+		g.labels, g.types = backupRowsReadableColumns(table)
+	}
+	colIDs, err := g.fetchedColumns(table)
+	if err != nil {
+		return err
+	}
+
+	codec, err := backupinfo.MakeBackupCodec(manifests)
+	if err != nil {
+		return err
+	}
+	span := table.PrimaryIndexSpan(codec)
+	g.spans = roachpb.Spans{span}
+	g.elidedPrefix, err = backupsink.ElidedPrefix(span.Key, manifests[0].ElidedPrefix)
+	if err != nil {
+		return err
+	}
+	if g.filter != "" {
+		if colIDs, err = g.initFilter(ctx, p, codec, table, colIDs); err != nil {
+			return err
+		}
+		if len(g.spans) == 0 {
+			g.exhausted = true
+			return nil
+		}
+	}
+
+	// Only the files that overlap the spans of the primary index to read need
+	// to be read.
+	backupLocalityMap, err := makeBackupLocalityMap(localityInfo, p.User())
+	if err != nil {
+		return err
+	}
+	var storeFiles []storageccl.StoreFile
+	for layer := range manifests {
+		fileIter, err := layerToIterFactory[layer].NewFileIter(ctx)
+		if err != nil {
+			return err
+		}
+		for ; ; fileIter.Next() {
+			if ok, err := fileIter.Valid(); err != nil {
+				fileIter.Close()
+				return err
+			} else if !ok {
+				break
+			}
+			f := fileIter.Value()
+			if !overlapsAnySpan(f.Span, g.spans) {
+				continue
+			}
+			dir := manifests[layer].Dir
+			if localityDir, ok := backupLocalityMap[layer][f.LocalityKV]; ok {
+				dir = localityDir
+			}
+			store, err := execCfg.DistSQLSrv.ExternalStorage(ctx, dir)
+			if err != nil {
+				fileIter.Close()
+				return err
+			}
+			g.stores = append(g.stores, store)
+			storeFiles = append(storeFiles, storageccl.StoreFile{Store: store, FilePath: f.Path})
+			g.dataFiles = append(g.dataFiles, dir.String()+" "+f.Path)
+			g.dataEndTime.Forward(manifests[layer].EndTime)
+		}
+		fileIter.Close()
+	}
+	if len(storeFiles) == 0 {
+		g.exhausted = true
+		return nil
+	}
+
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, nil /* encryption */, storage.IterOptions{
+		RangeKeyMaskingBelow: g.asOf,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	})
+	if err != nil {
+		return err
+	}
+	g.iter = storage.NewReadAsOfIterator(iter, g.asOf)
+	g.iter.SeekGE(storage.MVCCKey{Key: bytes.TrimPrefix(g.spans[0].Key, g.elidedPrefix)})
+
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, table, table.GetPrimaryIndex(), colIDs); err != nil {
+		return err
+	}
+	return g.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	})
+}
+
+// sameRows returns whether the generator is known to return the same rows as
+// the other one without reading them, because they read the same version of
+// the table from the same data files, and neither reads the files as of a time
+// before the latest revisions that they contain.
+func (g *backupRowsGenerator) sameRows(o *backupRowsGenerator) bool {
+	return g.desc.GetID() == o.desc.GetID() && g.desc.GetVersion() == o.desc.GetVersion() &&
+		slices.Equal(g.dataFiles, o.dataFiles) &&
+		g.dataEndTime.LessEq(g.readTime) && o.dataEndTime.LessEq(o.readTime)
+}
+
+// initFilter validates the filter against the backed-up table, restricts the
+// spans to read to those that can contain rows satisfying it, and prepares its
+// evaluation against the rows read from them. It returns the IDs of the
+// columns to fetch, which are the requested ones followed by the other columns
+// that the filter references.
+func (g *backupRowsGenerator) initFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	colIDs []descpb.ColumnID,
+) ([]descpb.ColumnID, error) {
+	expr, err := parser.ParseExpr(g.filter)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid filter")
+	}
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	tn := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+	filter, err := schemaexpr.ValidateRowFilter(
+		ctx, table, expr, tn, &semaCtx, p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+	filterExpr, cols, err := schemaexpr.MakeRowFilterExpr(ctx, table, filter, g.evalCtx, &semaCtx)
+	if err != nil {
+		return nil, err
+	}
+	g.filterExpr = filterExpr
+	g.filterVars.Cols = cols
+	for _, col := range cols {
+		if !slices.Contains(colIDs, col.GetID()) {
+			colIDs = append(colIDs, col.GetID())
+		}
+	}
+	for i, id := range colIDs {
+		g.filterVars.Mapping.Set(id, i)
+	}
+
+	g.spans, err = constrainPrimaryIndexSpans(ctx, g.evalCtx, codec, table, filter)
+	return colIDs, err
+}
+
+// constrainPrimaryIndexSpans returns the spans of the primary index of the
+// table that can contain rows satisfying the filter, as derived by the
+// optimizer's index constraints. The filter must have been validated with
+// schemaexpr.ValidateRowFilter, and may still need to be evaluated against the
+// rows in the returned spans.
+func constrainPrimaryIndexSpans(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	filter string,
+) (roachpb.Spans, error) {
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, err
+	}
+	var f norm.Factory
+	f.Init(ctx, evalCtx, nil /* catalog */)
+	md := f.Metadata()
+	// The columns of the table are added to the metadata, so that the scalar
+	// builder can resolve the column names in the filter.
+	var colMap catalog.TableColMap
+	var notNullCols opt.ColSet
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		id := md.AddColumn(col.GetName(), col.GetType())
+		colMap.Set(col.GetID(), int(id))
+		if !col.IsNullable() {
+			notNullCols.Add(id)
+		}
+	}
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	root, err := optbuilder.NewScalar(ctx, &semaCtx, evalCtx, &f).Build(expr)
+	if err != nil {
+		return nil, err
+	}
+	filters := memo.TrueFilter
+	if _, ok := root.(*memo.TrueExpr); !ok {
+		filters = memo.FiltersExpr{f.ConstructFiltersItem(root)}
+		filters = f.CustomFuncs().SimplifyFilters(filters)
+		filters = f.CustomFuncs().ConsolidateFilters(filters)
+	}
+
+	primary := table.GetPrimaryIndex()
+	indexCols := make([]opt.OrderingColumn, primary.NumKeyColumns())
+	for i := range indexCols {
+		id, ok := colMap.Get(primary.GetKeyColumnID(i))
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"primary key column %d of %q is not readable", primary.GetKeyColumnID(i), table.GetName())
+		}
+		indexCols[i] = opt.MakeOrderingColumn(
+			opt.ColumnID(id), primary.GetKeyColumnDirection(i) == catenumpb.IndexColumn_DESC)
+	}
+	var ic idxconstraint.Instance
+	ic.Init(
+		ctx, filters, nil /* optionalFilters */, indexCols, notNullCols,
+		nil /* computedCols */, opt.ColSet{}, /* colsInComputedColsExpressions */
+		true /* consolidate */, evalCtx, &f, partition.PrefixSorter{},
+		func() {}, /* checkCancellation */
+	)
+	var c constraint.Constraint
+	ic.Constraint(&c)
+
+	var sb span.Builder
+	sb.Init(evalCtx, codec, table, primary)
+	return sb.SpansFromConstraint(&c, span.NoopSplitter())
+}
+
+// overlapsAnySpan returns whether sp overlaps any of the spans.
+func overlapsAnySpan(sp roachpb.Span, spans roachpb.Spans) bool {
+	for _, s := range spans {
+		if sp.Overlaps(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveTable returns the descriptor of the requested table in the backup.
+func (g *backupRowsGenerator) resolveTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	manifests []backuppb.BackupManifest,
+	layerToIterFactory backupinfo.LayerToBackupManifestFileIterFactory,
+) (catalog.TableDescriptor, error) {
+	pattern, err := parser.ParseTablePattern(g.table)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := pattern.(*tree.UnresolvedName); !ok {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s requires the name of a single table, got %q", backupRowsBuiltinName, g.table)
+	}
+	targets := tree.BackupTargetList{Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{pattern}}}
+	_, _, descsByTablePattern, _, err := selectTargets(
+		ctx, p, manifests, layerToIterFactory, targets, tree.RequestedDescriptors, g.asOf,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err,
+			"failed to resolve table in the backup, use SHOW BACKUP to find correct targets")
+	}
+	table, ok := descsByTablePattern[pattern].(catalog.TableDescriptor)
+	if !ok || !table.IsPhysicalTable() || table.IsSequence() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", g.table)
+	}
+	return table, nil
+}
+
+// backupRowsReadableColumns returns the names and types of the columns of the
+// table that can be read from a backup.
+func backupRowsReadableColumns(table catalog.TableDescriptor) ([]string, []*types.T) {
+	var labels []string
+	var typs []*types.T
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		labels = append(labels, col.GetName())
+		typs = append(typs, col.GetType())
+	}
+	return labels, typs
+}
+
+// fetchedColumns returns the IDs of the columns of the table named by the
+// column definition list of the call, checking that their types match.
+func (g *backupRowsGenerator) fetchedColumns(table catalog.TableDescriptor) ([]descpb.ColumnID, error) {
+	colIDs := make([]descpb.ColumnID, len(g.labels))
+	for i, label := range g.labels {
+		col := catalog.FindColumnByName(table, label)
+		if col == nil || !col.Public() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist in the backed-up table %q", label, table.GetName())
+		}
+		if col.IsVirtual() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot read virtual computed column %q from a backup", label)
+		}
+		if col.GetType().UserDefined() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot read column %q of user-defined type from a backup", label)
+		}
+		if !g.types[i].Identical(col.GetType()) {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"column %q has type %s in the backup, but %s was requested",
+				label, col.GetType().SQLString(), g.types[i].SQLString())
+		}
+		for _, id := range colIDs[:i] {
+			if id == col.GetID() {
+				return nil, pgerror.Newf(pgcode.DuplicateColumn,
+					"column %q specified more than once", label)
+			}
+		}
+		colIDs[i] = col.GetID()
+	}
+	return colIDs, nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *backupRowsGenerator) Next(ctx context.Context) (bool, error) {
+	for {
+		if len(g.kvs) > 0 {
+			datums, err := g.fetcher.NextRowDecoded(ctx)
+			if err != nil {
+				return false, err
+			}
+			if datums != nil {
+				if g.filterExpr != nil {
+					if ok, err := g.matchesFilter(ctx, datums); err != nil {
+						return false, err
+					} else if !ok {
+						continue
+					}
+				}
+				g.values = datums[:len(g.labels)]
+				return true, nil
+			}
+			g.kvs = g.kvs[:0]
+		}
+		if g.exhausted {
+			return false, nil
+		}
+		if err := g.readBatch(ctx); err != nil {
+			return false, err
+		}
+	}
+}
+
+// matchesFilter evaluates the filter against a fetched row.
+func (g *backupRowsGenerator) matchesFilter(ctx context.Context, datums tree.Datums) (bool, error) {
+	g.filterVars.CurSourceRow = datums
+	g.evalCtx.PushIVarContainer(&g.filterVars)
+	res, err := eval.Expr(ctx, g.evalCtx, g.filterExpr)
+	g.evalCtx.PopIVarContainer()
+	if err != nil {
+		return false, errors.Wrap(err, "evaluating filter")
+	}
+	return res == tree.DBoolTrue, nil
+}
+
+// readBatch reads the next batch of KVs from the backup and hands them to the
+// fetcher. A batch always ends at the end of a row.
+func (g *backupRowsGenerator) readBatch(ctx context.Context) error {
+	var rowPrefix roachpb.Key
+	for {
+		ok, err := g.iter.Valid()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			g.exhausted = true
+			break
+		}
+		key := g.iter.UnsafeKey()
+		fullKey := append(g.elidedPrefix[:len(g.elidedPrefix):len(g.elidedPrefix)], key.Key...)
+		if bytes.Compare(fullKey, g.spans[0].EndKey) >= 0 {
+			// Move on to the next span, if any. The spans are sorted, and a row
+			// never straddles two of them.
+			g.spans = g.spans[1:]
+			if len(g.spans) == 0 {
+				g.exhausted = true
+				break
+			}
+			if bytes.Compare(fullKey, g.spans[0].Key) < 0 {
+				g.iter.SeekGE(storage.MVCCKey{Key: bytes.TrimPrefix(g.spans[0].Key, g.elidedPrefix)})
+			}
+			continue
+		}
+		prefixLen, err := keys.GetRowPrefixLength(fullKey)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(fullKey[:prefixLen], rowPrefix) {
+			if len(g.kvs) >= backupRowsBatchSize {
+				break
+			}
+			rowPrefix = fullKey[:prefixLen]
+		}
+		v, err := g.iter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		value, err := storage.DecodeValueFromMVCCValue(append([]byte(nil), v...))
+		if err != nil {
+			return err
+		}
+		value.Timestamp = key.Timestamp
+		g.kvs = append(g.kvs, roachpb.KeyValue{Key: fullKey, Value: value})
+		g.iter.NextKey()
+	}
+	if len(g.kvs) == 0 {
+		return nil
+	}
+	return g.fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: g.kvs})
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *backupRowsGenerator) Values() (tree.Datums, error) {
+	return g.values, nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *backupRowsGenerator) Close(ctx context.Context) {
+	g.fetcher.Close(ctx)
+	if g.iter != nil {
+		g.iter.Close()
+	}
+	for _, store := range g.stores {
+		if err := store.Close(); err != nil {
+			log.Warningf(ctx, "close export storage failed %v", err)
+		}
+	}
+	for i := len(g.cleanup) - 1; i >= 0; i-- {
+		g.cleanup[i]()
+	}
+	g.mem.Close(ctx)
+}
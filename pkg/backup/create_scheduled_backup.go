@@ -8,6 +8,8 @@ package backup
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
@@ -30,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -45,6 +48,10 @@ const (
 	optOnPreviousRunning       = "on_previous_running"
 	optIgnoreExistingBackups   = "ignore_existing_backups"
 	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optRetention               = "retention"
+	optKeepFullBackups         = "keep_full_backups"
+	optCopyTo                  = "copy_to"
+	optNotify                  = "notify"
 )
 
 var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
@@ -53,6 +60,10 @@ var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 	optOnPreviousRunning:       exprutil.KVStringOptRequireValue,
 	optIgnoreExistingBackups:   exprutil.KVStringOptRequireNoValue,
 	optUpdatesLastBackupMetric: exprutil.KVStringOptRequireNoValue,
+	optRetention:               exprutil.KVStringOptRequireValue,
+	optKeepFullBackups:         exprutil.KVStringOptRequireValue,
+	optCopyTo:                  exprutil.KVStringOptRequireValue,
+	optNotify:                  exprutil.KVStringOptRequireValue,
 }
 
 // scheduledBackupGCProtectionEnabled is used to enable and disable the chaining
@@ -105,11 +116,44 @@ func makeScheduleDetails(
 			return details, err
 		}
 	}
+	if v, ok := opts[optNotify]; ok {
+		if err := jobs.ValidateNotificationURI(v); err != nil {
+			return details, err
+		}
+		details.NotificationURI = v
+	}
 	details.ClusterID = clusterID
 	details.CreationClusterVersion = version
 	return details, nil
 }
 
+// parseRetentionPolicy returns the retention policy specified by the schedule
+// options, if any.
+func parseRetentionPolicy(opts map[string]string) (backupRetentionPolicy, error) {
+	var policy backupRetentionPolicy
+	if v, ok := opts[optRetention]; ok {
+		d, err := tree.ParseDInterval(duration.IntervalStyle_POSTGRES, v)
+		if err != nil {
+			return policy, errors.Wrapf(err, "invalid %s", optRetention)
+		}
+		secs, ok := d.Duration.AsInt64()
+		if !ok || secs <= 0 || secs > int64(math.MaxInt64/time.Second) {
+			return policy, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s must be a positive interval, got %q", optRetention, v)
+		}
+		policy.retention = time.Duration(secs) * time.Second
+	}
+	if v, ok := opts[optKeepFullBackups]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n <= 0 {
+			return policy, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s must be a positive integer, got %q", optKeepFullBackups, v)
+		}
+		policy.keepFullBackups = int(n)
+	}
+	return policy, nil
+}
+
 func scheduleFirstRun(evalCtx *eval.Context, opts map[string]string) (*time.Time, error) {
 	if v, ok := opts[optFirstRun]; ok {
 		firstRun, _, err := tree.ParseDTimestampTZ(evalCtx, v, time.Microsecond)
@@ -309,6 +353,25 @@ func doCreateBackupSchedules(
 	if err != nil {
 		return err
 	}
+	retention, err := parseRetentionPolicy(scheduleOptions)
+	if err != nil {
+		return err
+	}
+	copyTo := scheduleOptions[optCopyTo]
+	if copyTo != "" {
+		if len(destinations) != 1 || eval.incrementalStorage != nil {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s is only supported for schedules with a single destination and no incremental_location",
+				optCopyTo)
+		}
+		if copyTo == destinations[0] {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s must differ from the destination of the schedule", optCopyTo)
+		}
+		if err := sql.CheckDestinationPrivileges(ctx, p, []string{copyTo}); err != nil {
+			return err
+		}
+	}
 
 	unpauseOnSuccessID := jobspb.InvalidScheduleID
 
@@ -346,7 +409,8 @@ func doCreateBackupSchedules(
 		}
 		inc, incScheduledBackupArgs, err = makeBackupSchedule(
 			env, p.User(), scheduleLabel, incRecurrence, incrementalScheduleDetails, unpauseOnSuccessID,
-			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords, backupRetentionPolicy{},
+			copyTo)
 		if err != nil {
 			return err
 		}
@@ -369,7 +433,7 @@ func doCreateBackupSchedules(
 	var fullScheduledBackupArgs *backuppb.ScheduledBackupExecutionArgs
 	full, fullScheduledBackupArgs, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel, fullRecurrence, details, unpauseOnSuccessID,
-		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords, retention, copyTo)
 	if err != nil {
 		return err
 	}
@@ -475,6 +539,8 @@ func makeBackupSchedule(
 	updateLastMetricOnSuccess bool,
 	backupNode *tree.Backup,
 	chainProtectedTimestampRecords bool,
+	retention backupRetentionPolicy,
+	copyTo string,
 ) (*jobs.ScheduledJob, *backuppb.ScheduledBackupExecutionArgs, error) {
 	sj := jobs.NewScheduledJob(env)
 	sj.SetScheduleLabel(label)
@@ -485,6 +551,9 @@ func makeBackupSchedule(
 		UnpauseOnSuccess:               unpauseOnSuccess,
 		UpdatesLastBackupMetric:        updateLastMetricOnSuccess,
 		ChainProtectedTimestampRecords: chainProtectedTimestampRecords,
+		Retention:                      retention.retention,
+		KeepFullBackups:                int32(retention.keepFullBackups),
+		CopyTo:                         copyTo,
 	}
 	if backupNode.AppendToLatest {
 		args.BackupType = backuppb.ScheduledBackupExecutionArgs_INCREMENTAL
@@ -644,6 +713,9 @@ func makeScheduledBackupSpec(
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to evaluate backup destination paths")
 	}
+	if schedule.BackupOptions.DependsOn != nil {
+		return nil, errors.New("the depends_on option is not supported by backup schedules")
+	}
 	if schedule.BackupOptions.EncryptionPassphrase != nil {
 		passphrase, err := exprEval.String(
 			ctx, schedule.BackupOptions.EncryptionPassphrase,
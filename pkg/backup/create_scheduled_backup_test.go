@@ -1408,6 +1408,34 @@ func TestShowCreateScheduleStatement(t *testing.T) {
 	}
 }
 
+// TestShowCreateScheduleRedactsURIs tests that SHOW CREATE SCHEDULE does not
+// print the secrets in the URIs of the schedule options.
+func TestShowCreateScheduleRedactsURIs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+	defer utilccl.TestingEnableEnterprise()()
+	defer jobs.TestingRegisterNotificationSender("test-webhook",
+		func(context.Context, string, []byte) error { return nil })()
+
+	schedules, err := th.createBackupSchedule(t, `
+CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://1/redact' RECURRING '@hourly' FULL BACKUP ALWAYS
+WITH SCHEDULE OPTIONS copy_to = 'nodelocal://1/copy?AWS_SECRET_ACCESS_KEY=hunter2',
+  notify = 'test-webhook://host/path?auth_header=Bearer%20hunter2'`)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+
+	var stmt string
+	th.sqlDB.QueryRow(t, fmt.Sprintf(
+		`SELECT create_statement FROM [SHOW CREATE SCHEDULE %d]`, schedules[0].ScheduleID(),
+	)).Scan(&stmt)
+	require.Contains(t, stmt, "copy_to = 'nodelocal://1/copy?AWS_SECRET_ACCESS_KEY=redacted'")
+	require.Contains(t, stmt, "notify = 'redacted'")
+	require.NotContains(t, stmt, "hunter2")
+}
+
 // TestCreateScheduledBackupTelemetry tests CREATE SCHEDULE FOR BACKUP correctly
 // publishes telemetry events about the schedule creation.
 func TestCreateScheduledBackupTelemetry(t *testing.T) {
@@ -1494,6 +1522,50 @@ WITH SCHEDULE OPTIONS on_execution_failure = 'pause', ignore_existing_backups, f
 	requireRecoveryEvent(t, beforeBackup.UnixNano(), scheduledBackupEventType, expectedScheduledBackup)
 }
 
+// TestScheduledBackupCopyStartedOnce verifies that a scheduled backup with
+// copy_to starts a single copy job, even if the completion of the backup job
+// is processed again, e.g. because the job was resumed.
+func TestScheduledBackupCopyStartedOnce(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+	th.setOverrideAsOfClauseKnob(t)
+
+	schedules, err := th.createBackupSchedule(t, `
+CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://1/backup' RECURRING '@hourly' FULL BACKUP ALWAYS
+WITH SCHEDULE OPTIONS copy_to = 'nodelocal://1/copy'`)
+	require.NoError(t, err)
+	full := schedules[0]
+
+	th.env.SetTime(full.NextRun().Add(time.Second))
+	require.NoError(t, th.executeSchedules())
+	th.waitForSuccessfulScheduledJob(t, full.ScheduleID())
+
+	var backupJobID jobspb.JobID
+	th.sqlDB.QueryRow(t, "SELECT id FROM "+th.env.SystemJobsTableName()+
+		" WHERE created_by_type=$1 AND created_by_id=$2",
+		jobs.CreatedByScheduledJobs, full.ScheduleID()).Scan(&backupJobID)
+	numCopyJobs := func() int {
+		var n int
+		th.sqlDB.QueryRow(t, "SELECT count(*) FROM "+th.env.SystemJobsTableName()+" WHERE job_type = $1",
+			jobspb.TypeBackupCopy.String()).Scan(&n)
+		return n
+	}
+	require.Equal(t, 1, numCopyJobs())
+
+	execCfg := th.server.ExecutorConfig().(sql.ExecutorConfig)
+	backupJob, err := execCfg.JobRegistry.LoadJob(ctx, backupJobID)
+	require.NoError(t, err)
+	require.NoError(t, maybeStartScheduledBackupCopy(
+		ctx, &execCfg, backupJob.Payload().UsernameProto.Decode(),
+		backupJob.Details().(jobspb.BackupDetails), backupJobID,
+	))
+	require.Equal(t, 1, numCopyJobs())
+}
+
 // TestPauseScheduledBackupOnNewClusterID ensures that a schedule backup pauses
 // if it is running on a cluster with a different ID than is stored in its
 // details.
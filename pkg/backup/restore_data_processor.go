@@ -398,6 +398,11 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 		if err != nil {
 			return errors.Wrap(err, "creating key rewriter from rekeys")
 		}
+		rf, err := makeRestoreRowFilter(ctx, kr, rd.FlowCtx.NewEvalCtx(), rd.spec.RowFilterTableID,
+			rd.spec.RowFilter)
+		if err != nil {
+			return err
+		}
 
 		var sstIter mergedSST
 		for {
@@ -421,7 +426,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 						return done, errors.Wrap(err, "opening SSTs")
 					}
 
-					summary, err := rd.processRestoreSpanEntry(ctx, kr, sstIter)
+					summary, err := rd.processRestoreSpanEntry(ctx, kr, rf, sstIter)
 					if err != nil {
 						return done, errors.Wrap(err, "processing restore span entry")
 					}
@@ -449,7 +454,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 }
 
 func (rd *restoreDataProcessor) processRestoreSpanEntry(
-	ctx context.Context, kr *KeyRewriter, sst mergedSST,
+	ctx context.Context, kr *KeyRewriter, rf *restoreRowFilter, sst mergedSST,
 ) (kvpb.BulkOpSummary, error) {
 	db := rd.FlowCtx.Cfg.DB
 	var summary kvpb.BulkOpSummary
@@ -518,6 +523,13 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 
 	var keyScratch, valueScratch []byte
 
+	ingest := func(key storage.MVCCKey, value []byte) error {
+		if err := batcher.AddMVCCKey(ctx, key, value); err != nil {
+			return errors.Wrapf(err, "adding to batch: %s", key)
+		}
+		return nil
+	}
+
 	startKeyMVCC, endKeyMVCC := storage.MVCCKey{Key: entry.Span.Key},
 		storage.MVCCKey{Key: entry.Span.EndKey}
 
@@ -593,10 +605,21 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 		// were given. We expect that value.ClearChecksum and
 		// value.InitChecksum calls above have modified
 		// valueScratch.
+		if rf != nil {
+			if err := rf.add(ctx, key, valueScratch, ingest); err != nil {
+				return summary, err
+			}
+			continue
+		}
 		if err := batcher.AddMVCCKey(ctx, key, valueScratch); err != nil {
 			return summary, errors.Wrapf(err, "adding to batch: %s -> %s", key, value.PrettyPrint())
 		}
 	}
+	if rf != nil {
+		if err := rf.flush(ctx, ingest); err != nil {
+			return summary, err
+		}
+	}
 	// Flush out the last batch.
 	if err := batcher.Flush(ctx); err != nil {
 		return summary, err
@@ -541,6 +541,8 @@ func restore(
 			execLocality:         details.ExecutionLocality,
 			exclusiveEndKeys:     fsc.isExclusive(),
 			resumeClusterVersion: resumeClusterVersion,
+			rowFilter:            details.RowFilter,
+			rowFilterTableID:     details.RowFilterTableID,
 		}
 		return errors.Wrap(distRestore(
 			ctx,
@@ -1008,6 +1010,9 @@ func createImportingDescriptors(
 		switch desc := desc.(type) {
 		case catalog.TableDescriptor:
 			mut := tabledesc.NewBuilder(desc.TableDesc()).BuildCreatedMutableTable()
+			if details.RowFilter != "" && mut.GetID() == details.RowFilterTableID {
+				stripTableForRowFilter(mut)
+			}
 			if shouldPreRestore(mut) {
 				preRestoreTables = append(preRestoreTables, mut)
 			} else {
@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/rewrite"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -749,6 +750,55 @@ func allocateIDs(
 	return nil
 }
 
+// planRestoreTableAs prepares the single table restored by a RESTORE TABLE ...
+// AS ... [WHERE ...] statement: it renames the table and, if a WHERE clause
+// was specified, validates it and strips the parts of the table that cannot
+// be filtered. It returns the ID of the table in the backup and the serialized
+// row filter, if any.
+func planRestoreTableAs(
+	ctx context.Context,
+	p sql.PlanHookState,
+	restoreStmt *tree.Restore,
+	tablesByID map[descpb.ID]*tabledesc.Mutable,
+) (descpb.ID, string, error) {
+	if len(tablesByID) != 1 {
+		return descpb.InvalidID, "", errors.Errorf(
+			"RESTORE TABLE ... AS must restore exactly one table, found %d", len(tablesByID))
+	}
+	var table *tabledesc.Mutable
+	for _, t := range tablesByID {
+		table = t
+	}
+	newName := restoreStmt.NewTableName.Object()
+
+	var rowFilter string
+	if restoreStmt.Where != nil {
+		if !table.IsTable() {
+			return descpb.InvalidID, "", pgerror.Newf(pgcode.WrongObjectType,
+				"cannot restore %q with a WHERE clause: it is not a table", table.GetName())
+		}
+		var err error
+		rowFilter, err = schemaexpr.ValidateRowFilter(
+			ctx,
+			table,
+			restoreStmt.Where.Expr,
+			tree.NewUnqualifiedTableName(tree.Name(table.GetName())),
+			p.SemaCtx(),
+			p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+		)
+		if err != nil {
+			return descpb.InvalidID, "", err
+		}
+		if indexNames := stripTableForRowFilter(table); len(indexNames) > 0 {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"secondary indexes of %q are not restored when a WHERE clause is specified: %s",
+				newName, strings.Join(indexNames, ", ")))
+		}
+	}
+	table.SetName(newName)
+	return table.GetID(), rowFilter, nil
+}
+
 // allocateDescriptorRewrites determines the new ID and parentID (a "DescriptorRewrite")
 // for each table in sqlDescs and returns a mapping from old ID to said
 // DescriptorRewrite. It first validates that the provided sqlDescs can be restored
@@ -1132,6 +1182,8 @@ func restoreJobDescription(
 		DescriptorCoverage: restore.DescriptorCoverage,
 		AsOf:               restore.AsOf,
 		Targets:            restore.Targets,
+		NewTableName:       restore.NewTableName,
+		Where:              restore.Where,
 		Subdir:             tree.NewDString("/" + strings.TrimPrefix(resolvedSubdir, "/")),
 	}
 
@@ -1328,6 +1380,24 @@ func restorePlanHook(
 		return nil, nil, false, errors.New("cannot run online restore with verify_backup_table_data")
 	}
 
+	if restoreStmt.NewTableName != nil {
+		if restoreStmt.Options.IntoDB != nil {
+			return nil, nil, false, errors.Errorf(
+				"cannot use %q option when restoring a table under a new name", restoreOptIntoDB)
+		}
+		if restoreStmt.Options.ExperimentalOnline && restoreStmt.Where != nil {
+			return nil, nil, false, errors.New("cannot run online restore with a WHERE clause")
+		}
+		switch restoreStmt.NewTableName.NumParts {
+		case 2:
+			// RESTORE TABLE t AS db.new_t restores the table into database db.
+			intoDB = restoreStmt.NewTableName.Parts[1]
+		case 3:
+			return nil, nil, false, errors.New(
+				"cannot specify the schema of a table restored under a new name")
+		}
+	}
+
 	var newTenantID *roachpb.TenantID
 	var newTenantName *roachpb.TenantName
 	if restoreStmt.Options.AsTenant != nil || restoreStmt.Options.ForceTenantID != nil {
@@ -1969,6 +2039,15 @@ func doRestorePlan(
 		}
 	}
 
+	var tableAsID descpb.ID
+	var rowFilter string
+	if restoreStmt.NewTableName != nil {
+		tableAsID, rowFilter, err = planRestoreTableAs(ctx, p, restoreStmt, filteredTablesByID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// If we are stripping localities, wipe tables of their LocalityConfig before we allocate
 	// descriptor rewrites - as validation in remapTables compares these tables with the non-mr
 	// database and fails otherwise
@@ -1994,6 +2073,9 @@ func doRestorePlan(
 	if err != nil {
 		return err
 	}
+	if restoreStmt.NewTableName != nil {
+		descriptorRewrites[tableAsID].NewTableName = restoreStmt.NewTableName.Object()
+	}
 
 	if restoreStmt.Options.ExperimentalOnline {
 		if err := checkBackupElidedPrefixForOnlineCompat(ctx, mainBackupManifests, descriptorRewrites); err != nil {
@@ -2094,6 +2176,10 @@ func doRestorePlan(
 		RemoveRegions:                    restoreStmt.Options.RemoveRegions,
 		UnsafeRestoreIncompatibleVersion: restoreStmt.Options.UnsafeRestoreIncompatibleVersion,
 	}
+	if rowFilter != "" {
+		restoreDetails.RowFilter = rowFilter
+		restoreDetails.RowFilterTableID = tableAsID
+	}
 
 	jr := jobs.Record{
 		Description: description,
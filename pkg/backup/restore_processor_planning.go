@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -43,6 +44,8 @@ type restoreJobMetadata struct {
 	execLocality         roachpb.Locality
 	exclusiveEndKeys     bool
 	resumeClusterVersion roachpb.Version
+	rowFilter            string
+	rowFilterTableID     descpb.ID
 }
 
 // distRestore plans a 2 stage distSQL flow for a distributed restore. It
@@ -118,6 +121,8 @@ func distRestore(
 			PKIDs:                md.dataToRestore.getPKIDs(),
 			ValidateOnly:         md.dataToRestore.isValidateOnly(),
 			ResumeClusterVersion: md.resumeClusterVersion,
+			RowFilter:            md.rowFilter,
+			RowFilterTableID:     md.rowFilterTableID,
 		}
 
 		// Plan SplitAndScatter on the coordinator node.
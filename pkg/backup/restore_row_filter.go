@@ -0,0 +1,208 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+)
+
+// restoreRowFilter decides which rows of the table restored by a RESTORE
+// TABLE ... AS ... WHERE get ingested. The KVs of a row are buffered until the
+// row is complete, at which point the filter is evaluated against the decoded
+// row and its KVs are either all ingested or all dropped.
+//
+// Backup files never end mid-row (see backupsink.adjustFileEndKey), so a row is
+// never split across two restore span entries.
+type restoreRowFilter struct {
+	evalCtx *eval.Context
+	expr    tree.TypedExpr
+	ivars   schemaexpr.RowIndexedVarContainer
+	fetcher row.Fetcher
+
+	// indexPrefix is the prefix of the primary index of the table, in the
+	// keyspace it is restored into.
+	indexPrefix roachpb.Key
+
+	// rowPrefix is the row prefix of the KVs in pending.
+	rowPrefix roachpb.Key
+	pending   []restoreRowFilterKV
+	kvs       []roachpb.KeyValue
+}
+
+type restoreRowFilterKV struct {
+	key   storage.MVCCKey
+	value []byte
+}
+
+// makeRestoreRowFilter returns a restoreRowFilter that evaluates filter
+// against the rows of the table that kr rewrites from the given old ID, or nil
+// if filter is empty.
+func makeRestoreRowFilter(
+	ctx context.Context, kr *KeyRewriter, evalCtx *eval.Context, oldID descpb.ID, filter string,
+) (*restoreRowFilter, error) {
+	if filter == "" {
+		return nil, nil
+	}
+	table, ok := kr.descs[oldID]
+	if !ok {
+		return nil, errors.AssertionFailedf("no rekey for filtered table %d", oldID)
+	}
+	codec := kr.codec
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	expr, cols, err := schemaexpr.MakeRowFilterExpr(ctx, table, filter, evalCtx, &semaCtx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "building row filter for table %q", table.GetName())
+	}
+
+	f := &restoreRowFilter{
+		evalCtx:     evalCtx,
+		expr:        expr,
+		indexPrefix: rowenc.MakeIndexKeyPrefix(codec, table.GetID(), table.GetPrimaryIndexID()),
+	}
+	colIDs := make([]descpb.ColumnID, len(cols))
+	for i, col := range cols {
+		colIDs[i] = col.GetID()
+		f.ivars.Mapping.Set(col.GetID(), i)
+	}
+	f.ivars.Cols = cols
+
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, table, table.GetPrimaryIndex(), colIDs); err != nil {
+		return nil, err
+	}
+	if err := f.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// add adds a rewritten KV to the filter. KVs that do not belong to the primary
+// index of the filtered table are passed to ingest right away; the others are
+// passed to ingest once their row is complete, if it satisfies the filter.
+func (f *restoreRowFilter) add(
+	ctx context.Context,
+	key storage.MVCCKey,
+	value []byte,
+	ingest func(storage.MVCCKey, []byte) error,
+) error {
+	if !bytes.HasPrefix(key.Key, f.indexPrefix) {
+		return ingest(key, value)
+	}
+	prefixLen, err := keys.GetRowPrefixLength(key.Key)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(key.Key[:prefixLen], f.rowPrefix) {
+		if err := f.flush(ctx, ingest); err != nil {
+			return err
+		}
+		f.rowPrefix = append(f.rowPrefix[:0], key.Key[:prefixLen]...)
+	}
+	key.Key = key.Key.Clone()
+	f.pending = append(f.pending, restoreRowFilterKV{
+		key:   key,
+		value: append([]byte(nil), value...),
+	})
+	return nil
+}
+
+// flush evaluates the filter against the buffered row, if any, and passes its
+// KVs to ingest if the row satisfies it.
+func (f *restoreRowFilter) flush(
+	ctx context.Context, ingest func(storage.MVCCKey, []byte) error,
+) error {
+	if len(f.pending) == 0 {
+		return nil
+	}
+	defer func() {
+		f.pending = f.pending[:0]
+		f.rowPrefix = f.rowPrefix[:0]
+	}()
+
+	f.kvs = f.kvs[:0]
+	for _, kv := range f.pending {
+		value, err := storage.DecodeValueFromMVCCValue(kv.value)
+		if err != nil {
+			return err
+		}
+		value.Timestamp = kv.key.Timestamp
+		f.kvs = append(f.kvs, roachpb.KeyValue{Key: kv.key.Key, Value: value})
+	}
+	if err := f.fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: f.kvs}); err != nil {
+		return err
+	}
+	datums, err := f.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return err
+	}
+	if datums == nil {
+		return errors.AssertionFailedf("no row decoded from %d KVs", len(f.pending))
+	}
+
+	f.ivars.CurSourceRow = datums
+	f.evalCtx.PushIVarContainer(&f.ivars)
+	res, err := eval.Expr(ctx, f.evalCtx, f.expr)
+	f.evalCtx.PopIVarContainer()
+	if err != nil {
+		return errors.Wrap(err, "evaluating row filter")
+	}
+	if res != tree.DBoolTrue {
+		return nil
+	}
+	for _, kv := range f.pending {
+		if err := ingest(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stripTableForRowFilter removes the parts of a table restored with a row
+// filter that cannot be filtered or that the filtered rows may no longer
+// satisfy: its secondary indexes, whose entries cannot be evaluated against
+// the filter, and its self-referencing foreign keys. It returns the names of
+// the removed indexes.
+func stripTableForRowFilter(table *tabledesc.Mutable) []string {
+	var indexNames []string
+	for _, idx := range table.PublicNonPrimaryIndexes() {
+		indexNames = append(indexNames, idx.GetName())
+	}
+	table.Indexes = nil
+
+	outbound := table.OutboundFKs[:0]
+	for _, fk := range table.OutboundFKs {
+		if fk.ReferencedTableID != table.GetID() {
+			outbound = append(outbound, fk)
+		}
+	}
+	table.OutboundFKs = outbound
+	inbound := table.InboundFKs[:0]
+	for _, fk := range table.InboundFKs {
+		if fk.OriginTableID != table.GetID() {
+			inbound = append(inbound, fk)
+		}
+	}
+	table.InboundFKs = inbound
+	return indexNames
+}
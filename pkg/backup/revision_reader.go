@@ -7,12 +7,26 @@ package backup
 
 import (
 	"context"
+	"slices"
 
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
 )
 
 // VersionedValues is similar to roachpb.KeyValue except instead of just the
@@ -109,3 +123,406 @@ func GetAllRevisions(
 		startKey = exportResp.ResumeSpan.Key
 	}
 }
+
+// RevisionHistory provides the MVCC history of the data in the latest chain of
+// revision history backups in a backup collection. Unlike GetAllRevisions, it
+// can return revisions that have been garbage collected in KV.
+type RevisionHistory struct {
+	mkStore      cloud.ExternalStorageFromURIFactory
+	user         username.SQLUsername
+	kmsEnv       cloud.KMSEnv
+	defaultURIs  []string
+	manifests    []backuppb.BackupManifest
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo
+	mem          mon.BoundAccount
+}
+
+// ResolveRevisionHistory resolves the latest backup chain in the given
+// collection. All the backups in the chain must have been taken with
+// revision_history. Encrypted backups are not supported. Close must be called
+// on the returned RevisionHistory.
+func ResolveRevisionHistory(
+	ctx context.Context, execCfg *sql.ExecutorConfig, user username.SQLUsername, collectionURI string,
+) (_ *RevisionHistory, retErr error) {
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	from := []string{collectionURI}
+	subdir, err := backupdest.ReadLatestFile(ctx, collectionURI, mkStore, user)
+	if err != nil {
+		return nil, err
+	}
+	baseDirs, err := backuputils.AppendPaths(from, subdir)
+	if err != nil {
+		return nil, err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, nil /* explicitIncrementalCollections */, from, subdir)
+	if err != nil && !errors.Is(err, cloud.ErrListingUnsupported) {
+		return nil, err
+	}
+
+	baseStores, cleanupBase, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, baseDirs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cleanupBase(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+	incStores, cleanupInc, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, incDirs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cleanupInc(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+
+	ioConf := baseStores[0].ExternalIOConf()
+	kmsEnv := backupencryption.MakeBackupKMSEnv(execCfg.Settings, &ioConf, execCfg.InternalDB, user)
+	h := &RevisionHistory{
+		mkStore: mkStore,
+		user:    user,
+		kmsEnv:  &kmsEnv,
+		mem:     execCfg.RootMemoryMonitor.MakeBoundAccount(),
+	}
+	defer func() {
+		if retErr != nil {
+			h.Close(ctx)
+		}
+	}()
+	// The memory reserved for the manifests is released by Close.
+	h.defaultURIs, h.manifests, h.localityInfo, _, err = backupdest.ResolveBackupManifests(
+		ctx, &h.mem, baseStores, incStores, mkStore, baseDirs, incDirs, hlc.Timestamp{},
+		nil /* encryption */, h.kmsEnv, user, false, /* includeSkipped */
+	)
+	if err != nil {
+		return nil, err
+	}
+	for i := range h.manifests {
+		if h.manifests[i].MVCCFilter != backuppb.MVCCFilter_All {
+			return nil, errors.Newf("backup %s was not taken with revision_history", h.defaultURIs[i])
+		}
+	}
+	return h, nil
+}
+
+// StartTime returns the timestamp after which the history of all revisions is
+// available.
+func (h *RevisionHistory) StartTime() hlc.Timestamp {
+	start := h.manifests[0].RevisionStartTime
+	for i := 1; i < len(h.manifests); i++ {
+		// If the history was garbage collected before an incremental backup
+		// exported it, the history before its RevisionStartTime is incomplete.
+		if m := &h.manifests[i]; m.StartTime.Less(m.RevisionStartTime) {
+			start = m.RevisionStartTime
+		}
+	}
+	return start
+}
+
+// EndTime returns the timestamp up to which the history of all revisions is
+// available.
+func (h *RevisionHistory) EndTime() hlc.Timestamp {
+	return h.manifests[len(h.manifests)-1].EndTime
+}
+
+// Close releases the resources held by the RevisionHistory.
+func (h *RevisionHistory) Close(ctx context.Context) {
+	h.mem.Close(ctx)
+}
+
+// revisionBatchSize is the approximate size of the revisions that are read
+// from backup data files before they are sent as a batch.
+const revisionBatchSize = 1 << 20 // 1 MiB
+
+// GetAllRevisions is like the package-level GetAllRevisions, but reads the
+// revisions of the keys in the span from the data files of the backups. The
+// revisions of each key are sorted by timestamp, and the revisions in a batch
+// sent on allRevs are never older than the ones in earlier batches for the
+// same key. MVCC range tombstones are returned as deletions of the keys that
+// they deleted.
+func (h *RevisionHistory) GetAllRevisions(
+	ctx context.Context,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+	allRevs chan []VersionedValues,
+) error {
+	if startTime.Less(h.StartTime()) || h.EndTime().Less(endTime) {
+		return errors.Newf("backups contain revisions between %s and %s, not between %s and %s",
+			h.StartTime(), h.EndTime(), startTime, endTime)
+	}
+
+	stores := make(map[string]cloud.ExternalStorage)
+	defer func() {
+		for _, store := range stores {
+			if err := store.Close(); err != nil {
+				log.Warningf(ctx, "failed to close backup store: %+v", err)
+			}
+		}
+	}()
+	getStore := func(uri string) (cloud.ExternalStorage, error) {
+		if store, ok := stores[uri]; ok {
+			return store, nil
+		}
+		store, err := h.mkStore(ctx, uri, h.user)
+		if err != nil {
+			return nil, err
+		}
+		stores[uri] = store
+		return store, nil
+	}
+	// addFiles appends the data files of the layer that overlap the span to
+	// files, and returns whether any of them has range keys. Several entries
+	// of a manifest may refer to the same data file, which is only added once.
+	type fileKey struct {
+		uri, path string
+	}
+	added := make(map[fileKey]struct{})
+	addFiles := func(
+		layer int, span roachpb.Span, files []storageccl.StoreFile,
+	) (_ []storageccl.StoreFile, hasRangeKeys bool, _ error) {
+		store, err := getStore(h.defaultURIs[layer])
+		if err != nil {
+			return nil, false, err
+		}
+		it, err := backupinfo.NewIterFactory(
+			&h.manifests[layer], store, nil /* encryption */, h.kmsEnv).NewFileIter(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		defer it.Close()
+		for ; ; it.Next() {
+			if ok, err := it.Valid(); err != nil {
+				return nil, false, err
+			} else if !ok {
+				return files, hasRangeKeys, nil
+			}
+			file := it.Value()
+			if !file.Span.Overlaps(span) {
+				continue
+			}
+			hasRangeKeys = hasRangeKeys || file.HasRangeKeys
+			uri := h.defaultURIs[layer]
+			if file.LocalityKV != "" {
+				if localityURI, ok := h.localityInfo[layer].URIsByOriginalLocalityKV[file.LocalityKV]; ok {
+					uri = localityURI
+				}
+			}
+			if _, ok := added[fileKey{uri, file.Path}]; ok {
+				continue
+			}
+			added[fileKey{uri, file.Path}] = struct{}{}
+			fileStore, err := getStore(uri)
+			if err != nil {
+				return nil, false, err
+			}
+			files = append(files, storageccl.StoreFile{Store: fileStore, FilePath: file.Path})
+		}
+	}
+
+	// The layers are sorted by time, so iterating over them in order returns
+	// the revisions of each key in timestamp order. Within a layer, the
+	// revisions of a key may be split between several files, so all the files
+	// of the layer are read through a single merged iterator.
+	for layer := range h.manifests {
+		m := &h.manifests[layer]
+		if m.EndTime.LessEq(startTime) || endTime.LessEq(m.StartTime) {
+			continue
+		}
+		clear(added)
+		files, hasRangeKeys, err := addFiles(layer, span, nil /* files */)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if hasRangeKeys {
+			// The keys deleted by the layer's range tombstones may have been
+			// written before the backup, so the files of the earlier layers are
+			// needed to find them.
+			for older := layer - 1; older >= 0; older-- {
+				if files, _, err = addFiles(older, span, files); err != nil {
+					return err
+				}
+			}
+		}
+		// Only the revisions written during the layer's backup are read from
+		// its files, since the files of earlier layers may be read too.
+		layerStart, layerEnd := startTime, endTime
+		layerStart.Forward(m.StartTime)
+		layerEnd.Backward(m.EndTime)
+		if err := h.readRevisionsFromBackupFiles(ctx, files, span, layerStart, layerEnd, allRevs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRevisionsFromBackupFiles sends the revisions of the keys in the span,
+// with timestamps in (startTime, endTime], from backup data files on allRevs.
+// The revisions are sent in batches of about revisionBatchSize, whose memory
+// is reserved from the RevisionHistory's account until they are sent.
+//
+// An MVCC range tombstone is returned as a deletion of each key that it covers
+// whose previous revision is a live value. The previous revisions may be in
+// any of the files.
+func (h *RevisionHistory) readRevisionsFromBackupFiles(
+	ctx context.Context,
+	files []storageccl.StoreFile,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+	allRevs chan []VersionedValues,
+) error {
+	iterOpts := storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsAndRanges,
+		LowerBound: span.Key,
+		UpperBound: span.EndKey,
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, files, nil /* encryption */, iterOpts)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	var batch []VersionedValues
+	var batchSize int64
+	defer func() { h.mem.Shrink(ctx, batchSize) }()
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case allRevs <- batch:
+		}
+		h.mem.Shrink(ctx, batchSize)
+		batch, batchSize = nil, 0
+		return nil
+	}
+
+	iter.SeekGE(storage.MVCCKey{Key: span.Key})
+	for {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); !hasPoint {
+			// A bare range key has no revisions of its own.
+			iter.Next()
+			continue
+		}
+		revs, err := readKeyRevisions(iter, startTime, endTime)
+		if err != nil {
+			return err
+		}
+		if len(revs.Values) == 0 {
+			continue
+		}
+		size := int64(len(revs.Key))
+		for _, v := range revs.Values {
+			size += int64(len(v.RawBytes))
+		}
+		if err := h.mem.Grow(ctx, size); err != nil {
+			return err
+		}
+		batch = append(batch, revs)
+		batchSize += size
+		if batchSize >= revisionBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// readKeyRevisions returns the revisions of the key at which the iterator is
+// positioned, with timestamps in (startTime, endTime] and in increasing
+// timestamp order, and moves the iterator to the next key.
+func readKeyRevisions(
+	iter storage.SimpleMVCCIterator, startTime, endTime hlc.Timestamp,
+) (VersionedValues, error) {
+	res := VersionedValues{Key: iter.UnsafeKey().Key.Clone()}
+	var rangeTombstones []hlc.Timestamp
+	if _, hasRange := iter.HasPointAndRange(); hasRange {
+		for _, v := range iter.RangeKeys().Versions {
+			rangeTombstones = append(rangeTombstones, v.Timestamp)
+		}
+	}
+
+	// The revisions of a key are sorted by decreasing timestamp. The first
+	// point revision at or below startTime is read too, in case it is deleted
+	// by a range tombstone in the time range.
+	type revision struct {
+		value          roachpb.Value
+		rangeTombstone bool
+	}
+	var revs []revision
+	addRangeTombstones := func(above hlc.Timestamp) {
+		for len(rangeTombstones) > 0 && above.Less(rangeTombstones[0]) {
+			revs = append(revs, revision{
+				value:          roachpb.Value{Timestamp: rangeTombstones[0]},
+				rangeTombstone: true,
+			})
+			rangeTombstones = rangeTombstones[1:]
+		}
+	}
+	for {
+		if ok, err := iter.Valid(); err != nil {
+			return VersionedValues{}, err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if !key.Key.Equal(res.Key) {
+			break
+		}
+		if hasPoint, _ := iter.HasPointAndRange(); !hasPoint {
+			iter.Next()
+			continue
+		}
+		if endTime.Less(key.Timestamp) {
+			iter.Next()
+			continue
+		}
+		addRangeTombstones(key.Timestamp)
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return VersionedValues{}, err
+		}
+		value, err := storage.DecodeValueFromMVCCValue(v)
+		if err != nil {
+			return VersionedValues{}, err
+		}
+		value.RawBytes = slices.Clone(value.RawBytes)
+		value.Timestamp = key.Timestamp
+		revs = append(revs, revision{value: value})
+		if key.Timestamp.LessEq(startTime) {
+			iter.NextKey()
+			break
+		}
+		iter.Next()
+	}
+	addRangeTombstones(hlc.Timestamp{})
+
+	for i := len(revs) - 1; i >= 0; i-- {
+		rev := revs[i]
+		if rev.value.Timestamp.LessEq(startTime) || endTime.Less(rev.value.Timestamp) {
+			continue
+		}
+		if rev.rangeTombstone {
+			// The range tombstone only deleted the key if its previous
+			// revision is a live value.
+			if i+1 == len(revs) || revs[i+1].rangeTombstone || !revs[i+1].value.IsPresent() {
+				continue
+			}
+		}
+		res.Values = append(res.Values, rev.value)
+	}
+	return res, nil
+}
@@ -6,17 +6,26 @@
 package backup
 
 import (
+	"bytes"
 	"context"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuptestutils"
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/storageutils"
 	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -84,3 +93,163 @@ func TestGetAllRevisionsCPULimiterPagination(t *testing.T) {
 	require.Equal(t, 2, numRequests)
 	require.Equal(t, 2, numResponses)
 }
+
+func TestRevisionHistoryFromBackups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc, sqlDB, _, cleanupFn := backuptestutils.StartBackupRestoreTestCluster(t, singleNode)
+	defer cleanupFn()
+	s := tc.ApplicationLayer(0)
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+
+	const collection = `nodelocal://1/revisions`
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+	var tableID uint32
+	sqlDB.QueryRow(t, `SELECT 'foo'::regclass::int`).Scan(&tableID)
+	var startStr string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&startStr)
+	start, err := hlc.ParseHLC(startStr)
+	require.NoError(t, err)
+
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 1), (2, 2)`)
+	sqlDB.Exec(t, `BACKUP TABLE foo INTO $1 WITH revision_history`, collection)
+	sqlDB.Exec(t, `UPDATE foo SET b = b + 10`)
+	sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+	sqlDB.Exec(t, `BACKUP TABLE foo INTO LATEST IN $1 WITH revision_history`, collection)
+	// Delete the remaining row with a range tombstone.
+	prefix := s.Codec().TablePrefix(tableID)
+	require.NoError(t, tc.SystemLayer(0).DB().DelRangeUsingTombstone(ctx, prefix, prefix.PrefixEnd()))
+	sqlDB.Exec(t, `BACKUP TABLE foo INTO LATEST IN $1 WITH revision_history`, collection)
+
+	h, err := ResolveRevisionHistory(ctx, &execCfg, username.RootUserName(), collection)
+	require.NoError(t, err)
+	defer h.Close(ctx)
+	require.True(t, h.StartTime().LessEq(start))
+	require.True(t, start.Less(h.EndTime()))
+
+	allRevs := make(chan []VersionedValues)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(allRevs)
+		return h.GetAllRevisions(ctx, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()},
+			start, h.EndTime(), allRevs)
+	})
+	revs := make(map[string][]roachpb.Value)
+	for res := range allRevs {
+		for _, kv := range res {
+			revs[string(kv.Key)] = append(revs[string(kv.Key)], kv.Values...)
+		}
+	}
+	require.NoError(t, g.Wait())
+
+	// Both rows were inserted, updated and deleted. The row with a=2 was
+	// deleted before the range tombstone was written, which doesn't delete it
+	// again.
+	var numRevs []int
+	for _, values := range revs {
+		numRevs = append(numRevs, len(values))
+		for i := 1; i < len(values); i++ {
+			require.True(t, values[i-1].Timestamp.Less(values[i].Timestamp))
+		}
+		if len(values) == 3 {
+			require.False(t, values[2].IsPresent())
+		}
+	}
+	require.ElementsMatch(t, []int{3, 3}, numRevs)
+
+	// Revisions from before the history in the backups are not available.
+	if h.StartTime().IsSet() {
+		err := h.GetAllRevisions(ctx, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()},
+			h.StartTime().Prev(), h.EndTime(), make(chan []VersionedValues, 10))
+		require.ErrorContains(t, err, "backups contain revisions between")
+	}
+}
+
+// TestRevisionHistoryKeySplitAcrossFiles verifies that the revisions of a key
+// are returned in timestamp order when a revision history backup splits them
+// between several data files.
+func TestRevisionHistoryKeySplitAcrossFiles(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc, _, _, cleanupFn := backuptestutils.StartBackupRestoreTestCluster(t, singleNode)
+	defer cleanupFn()
+	execCfg := tc.ApplicationLayer(0).ExecutorConfig().(sql.ExecutorConfig)
+	st := cluster.MakeTestingClusterSettings()
+
+	// The newest revisions of b are in the first file, and the oldest ones in
+	// the second file.
+	sst1, _, _ := storageutils.MakeSST(t, st, []interface{}{
+		storageutils.PointKV("a", 2, "a2"),
+		storageutils.PointKV("b", 5, "b5"),
+		storageutils.PointKV("b", 4, "b4"),
+	})
+	sst2, _, _ := storageutils.MakeSST(t, st, []interface{}{
+		storageutils.PointKV("b", 3, "b3"),
+		storageutils.PointKV("b", 2, ""),
+		storageutils.PointKV("c", 3, "c3"),
+	})
+	const uri = "nodelocal://1/split"
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	store, err := mkStore(ctx, uri, username.RootUserName())
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, cloud.WriteFile(ctx, store, "1.sst", bytes.NewReader(sst1)))
+	require.NoError(t, cloud.WriteFile(ctx, store, "2.sst", bytes.NewReader(sst2)))
+
+	h := &RevisionHistory{
+		mkStore:     mkStore,
+		user:        username.RootUserName(),
+		defaultURIs: []string{uri},
+		manifests: []backuppb.BackupManifest{{
+			EndTime:    storageutils.WallTS(10),
+			MVCCFilter: backuppb.MVCCFilter_All,
+			Files: []backuppb.BackupManifest_File{
+				{Span: roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("b").Next()}, Path: "1.sst"},
+				{Span: roachpb.Span{Key: roachpb.Key("b"), EndKey: roachpb.Key("d")}, Path: "2.sst"},
+			},
+		}},
+		localityInfo: make([]jobspb.RestoreDetails_BackupLocalityInfo, 1),
+		mem:          execCfg.RootMemoryMonitor.MakeBoundAccount(),
+	}
+	defer h.Close(ctx)
+
+	allRevs := make(chan []VersionedValues)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(allRevs)
+		return h.GetAllRevisions(ctx, roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("d")},
+			storageutils.WallTS(1), h.EndTime(), allRevs)
+	})
+	type revision struct {
+		key   string
+		ts    int64
+		value string
+	}
+	var revs []revision
+	for res := range allRevs {
+		for _, kv := range res {
+			for _, v := range kv.Values {
+				rev := revision{key: string(kv.Key), ts: v.Timestamp.WallTime}
+				if v.IsPresent() {
+					b, err := v.GetBytes()
+					require.NoError(t, err)
+					rev.value = string(b)
+				}
+				revs = append(revs, rev)
+			}
+		}
+	}
+	require.NoError(t, g.Wait())
+	require.Equal(t, []revision{
+		{"a", 2, "a2"},
+		{"b", 2, ""},
+		{"b", 3, "b3"},
+		{"b", 4, "b4"},
+		{"b", 5, "b5"},
+		{"c", 3, "c3"},
+	}, revs)
+}
@@ -7,9 +7,11 @@ package backup
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
@@ -20,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -300,6 +303,49 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 		},
 	}
 
+	// The retention policy is stored on the full schedule.
+	fullArgs := args
+	if backupNode.AppendToLatest {
+		fullArgs = nil
+		if dependentSchedule != nil {
+			fullArgs = &backuppb.ScheduledBackupExecutionArgs{}
+			if err := pbtypes.UnmarshalAny(dependentSchedule.ExecutionArgs().Args, fullArgs); err != nil {
+				return "", errors.Wrap(err, "un-marshaling args")
+			}
+		}
+	}
+	if fullArgs != nil && fullArgs.Retention != 0 {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optRetention,
+			Value: tree.NewDString(duration.MakeDurationJustifyHours(fullArgs.Retention.Nanoseconds(), 0, 0).String()),
+		})
+	}
+	if fullArgs != nil && fullArgs.KeepFullBackups != 0 {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optKeepFullBackups,
+			Value: tree.NewDString(strconv.Itoa(int(fullArgs.KeepFullBackups))),
+		})
+	}
+
+	if args.CopyTo != "" {
+		copyTo, err := cloud.SanitizeExternalStorageURI(args.CopyTo, nil /* extraParams */)
+		if err != nil {
+			return "", err
+		}
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optCopyTo,
+			Value: tree.NewDString(copyTo),
+		})
+	}
+	if sj.ScheduleDetails().NotificationURI != "" {
+		// Notification URIs may carry credentials, e.g. the auth_header of
+		// webhooks, so they are redacted entirely, as for changefeeds.
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optNotify,
+			Value: tree.NewDString("redacted"),
+		})
+	}
+
 	var destinations []string
 	for i := range backupNode.To {
 		dest, ok := backupNode.To[i].(*tree.StrVal)
@@ -0,0 +1,380 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// A backup schedule keeps adding chains of backups, each made of a full backup
+// and the incremental backups appended to it, to its collection. The schedule
+// options `retention` and `keep_full_backups` bound the growth of the
+// collection by deleting chains that are no longer needed:
+//
+// - A chain is only considered for deletion once it has been superseded by a
+//   newer full backup, since incremental backups may still be appended to the
+//   most recent chain. Its age is the time of the full backup that superseded
+//   it, i.e. the time after which the chain no longer has to be restored from.
+//
+// - With `retention`, a superseded chain is deleted once it is older than the
+//   retention period.
+//
+// - With `keep_full_backups = N`, the N most recent chains are kept. If both
+//   options are set, a chain is only deleted if both allow it.
+//
+// - The chain that the LATEST file of the collection points to, and any chain
+//   that is newer than it, is never deleted. Neither is a chain that is still
+//   protected by the protected timestamp record of the incremental schedule,
+//   since that record may still be pulled up by an incremental backup
+//   appending to it.
+//
+// The policy is enforced by the backup job of the full schedule, once the
+// backup has completed and the LATEST file points to it. A chain is deleted
+// whole, incremental backups first and the manifest of the full backup last,
+// so that a chain whose deletion was interrupted is still listed, and deleted,
+// the next time the policy is enforced.
+
+// backupRetentionPolicy is the retention policy of a backup schedule.
+type backupRetentionPolicy struct {
+	retention       time.Duration
+	keepFullBackups int
+}
+
+func (p backupRetentionPolicy) isEmpty() bool {
+	return p.retention == 0 && p.keepFullBackups == 0
+}
+
+// backupChain is a chain of backups in a collection, identified by the subdir
+// of its full backup.
+type backupChain struct {
+	subdir string
+	// fullTime is the time of the full backup of the chain, as encoded in its
+	// subdir.
+	fullTime time.Time
+	// supersededAt is the time of the next full backup in the collection, if
+	// any.
+	supersededAt time.Time
+}
+
+// scheduledBackupJobEnv returns the environment of the job scheduler.
+func scheduledBackupJobEnv(execCfg *sql.ExecutorConfig) scheduledjobs.JobSchedulerEnv {
+	if knobs, ok := execCfg.DistSQLSrv.TestingKnobs.JobsTestingKnobs.(*jobs.TestingKnobs); ok {
+		if knobs.JobSchedulerEnv != nil {
+			return knobs.JobSchedulerEnv
+		}
+	}
+	return scheduledjobs.ProdJobSchedulerEnv
+}
+
+// loadScheduledBackupArgs returns the execution arguments of the schedule that
+// created the backup job with the given ID, or nil if the job was not created
+// by a schedule or the schedule no longer exists.
+func loadScheduledBackupArgs(
+	ctx context.Context, txn isql.Txn, env scheduledjobs.JobSchedulerEnv, id jobspb.JobID,
+) (*backuppb.ScheduledBackupExecutionArgs, error) {
+	// We cannot rely on the job containing created_by_id because on job
+	// resumption the registry does not populate the resumers' CreatedByInfo.
+	datums, err := txn.QueryRowEx(
+		ctx,
+		"lookup-schedule-info",
+		txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf(
+			"SELECT created_by_id FROM %s WHERE id=$1 AND created_by_type=$2",
+			env.SystemJobsTableName()),
+		id, jobs.CreatedByScheduledJobs)
+	if err != nil {
+		return nil, errors.Wrap(err, "schedule info lookup")
+	}
+	if datums == nil {
+		// Not a scheduled backup.
+		return nil, nil
+	}
+
+	scheduleID := jobspb.ScheduleID(tree.MustBeDInt(datums[0]))
+	_, args, err := getScheduledBackupExecutionArgsFromSchedule(
+		ctx, env, jobs.ScheduledJobTxn(txn), scheduleID,
+	)
+	if err != nil {
+		if jobs.HasScheduledJobNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "load scheduled job")
+	}
+	return args, nil
+}
+
+// maybeEnforceScheduleRetention deletes the chains of backups that have
+// expired according to the retention policy of the schedule that created the
+// job, if any.
+func maybeEnforceScheduleRetention(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	details jobspb.BackupDetails,
+	id jobspb.JobID,
+) error {
+	// Only full backups written to a collection supersede older chains.
+	if !details.StartTime.IsEmpty() || details.CollectionURI == "" {
+		return nil
+	}
+
+	env := scheduledBackupJobEnv(execCfg)
+	var policy backupRetentionPolicy
+	var protectedTS hlc.Timestamp
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		policy, protectedTS = backupRetentionPolicy{}, hlc.Timestamp{}
+		args, err := loadScheduledBackupArgs(ctx, txn, env, id)
+		if err != nil || args == nil {
+			return err
+		}
+		if args.BackupType != backuppb.ScheduledBackupExecutionArgs_FULL {
+			return nil
+		}
+		policy = backupRetentionPolicy{
+			retention:       args.Retention,
+			keepFullBackups: int(args.KeepFullBackups),
+		}
+		if policy.isEmpty() || args.DependentScheduleID == 0 {
+			return nil
+		}
+
+		_, incArgs, err := getScheduledBackupExecutionArgsFromSchedule(
+			ctx, env, jobs.ScheduledJobTxn(txn), args.DependentScheduleID,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "load dependent schedule %d", args.DependentScheduleID)
+		}
+		if incArgs.ProtectedTimestampRecord == nil {
+			return nil
+		}
+		record, err := execCfg.ProtectedTimestampProvider.WithTxn(txn).GetRecord(
+			ctx, *incArgs.ProtectedTimestampRecord,
+		)
+		if err != nil {
+			if errors.Is(err, protectedts.ErrNotExists) {
+				return nil
+			}
+			return err
+		}
+		protectedTS = record.Timestamp
+		return nil
+	}); err != nil {
+		return err
+	}
+	if policy.isEmpty() {
+		return nil
+	}
+
+	defaultURI, urisByLocalityKV, err := backupdest.GetURIsByLocalityKV(details.Destination.To, "")
+	if err != nil {
+		return err
+	}
+	collectionURIs := []string{defaultURI}
+	for _, uri := range urisByLocalityKV {
+		collectionURIs = append(collectionURIs, uri)
+	}
+
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, defaultURI, user)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	subdirs, err := backupdest.ListFullBackupsInCollection(ctx, store)
+	if err != nil {
+		return err
+	}
+	latest, err := backupdest.ReadLatestFile(
+		ctx, defaultURI, execCfg.DistSQLSrv.ExternalStorageFromURI, user,
+	)
+	if err != nil {
+		return errors.Wrap(err, "reading LATEST file")
+	}
+
+	expired := expiredBackupChains(subdirs, latest, policy, protectedTS, env.Now())
+	if len(expired) == 0 {
+		return nil
+	}
+
+	redactedURI, err := cloud.SanitizeExternalStorageURI(defaultURI, nil /* extraParams */)
+	if err != nil {
+		return err
+	}
+	for _, chain := range expired {
+		if err := deleteBackupChain(
+			ctx, execCfg, user, collectionURIs, details.Destination.IncrementalStorage, chain.subdir,
+		); err != nil {
+			return errors.Wrapf(err, "deleting expired backup chain %s in %s", chain.subdir, redactedURI)
+		}
+		log.Infof(ctx, "deleted backup chain %s in %s, superseded at %s, per the retention policy of the schedule",
+			chain.subdir, redactedURI, chain.supersededAt)
+	}
+	return nil
+}
+
+// expiredBackupChains returns the chains of backups in the collection that
+// have expired according to the retention policy, given the subdirs of the
+// full backups in the collection and the subdir the LATEST file points to.
+// Full backups whose subdir does not follow the default naming scheme are
+// ignored.
+func expiredBackupChains(
+	subdirs []string,
+	latest string,
+	policy backupRetentionPolicy,
+	protectedTS hlc.Timestamp,
+	now time.Time,
+) []backupChain {
+	parse := func(subdir string) (backupChain, bool) {
+		subdir = "/" + strings.TrimPrefix(subdir, "/")
+		t, err := time.Parse(backupbase.DateBasedIntoFolderName, subdir)
+		if err != nil {
+			return backupChain{}, false
+		}
+		return backupChain{subdir: subdir, fullTime: t}, true
+	}
+
+	latestChain, ok := parse(latest)
+	if !ok {
+		// We cannot tell which chains have been superseded.
+		return nil
+	}
+	var chains []backupChain
+	for _, subdir := range subdirs {
+		if c, ok := parse(subdir); ok {
+			chains = append(chains, c)
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].fullTime.Before(chains[j].fullTime)
+	})
+
+	var expired []backupChain
+	for i := 0; i+1 < len(chains); i++ {
+		chain := chains[i]
+		chain.supersededAt = chains[i+1].fullTime
+		if !chain.fullTime.Before(latestChain.fullTime) {
+			break
+		}
+		if policy.keepFullBackups != 0 && len(chains)-i <= policy.keepFullBackups {
+			break
+		}
+		if policy.retention != 0 && now.Sub(chain.supersededAt) < policy.retention {
+			break
+		}
+		if !protectedTS.IsEmpty() && protectedTS.GoTime().Before(chain.supersededAt) {
+			break
+		}
+		expired = append(expired, chain)
+	}
+	return expired
+}
+
+// deleteBackupChain deletes the chain of backups whose full backup is in the
+// given subdir of the collection, including its incremental backups.
+func deleteBackupChain(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	collectionURIs []string,
+	incrementalStorage []string,
+	subdir string,
+) error {
+	var incURIs []string
+	if len(incrementalStorage) > 0 {
+		defaultIncURI, incURIsByLocalityKV, err := backupdest.GetURIsByLocalityKV(incrementalStorage, "")
+		if err != nil {
+			return err
+		}
+		incURIs = append(incURIs, defaultIncURI)
+		for _, uri := range incURIsByLocalityKV {
+			incURIs = append(incURIs, uri)
+		}
+		if incURIs, err = backuputils.AppendPaths(incURIs, subdir); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		incURIs, err = backuputils.AppendPaths(collectionURIs, backupbase.DefaultIncrementalsSubdir, subdir)
+		if err != nil {
+			return err
+		}
+	}
+	fullURIs, err := backuputils.AppendPaths(collectionURIs, subdir)
+	if err != nil {
+		return err
+	}
+
+	// Delete the incremental backups before the full backup they depend on, and
+	// the manifest of the full backup last.
+	for _, uri := range incURIs {
+		if err := deleteAllFiles(ctx, execCfg, user, uri, "" /* last */); err != nil {
+			return err
+		}
+	}
+	for _, uri := range fullURIs {
+		if err := deleteAllFiles(ctx, execCfg, user, uri, backupbase.BackupManifestName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAllFiles deletes all files under the given URI. If last is non-empty,
+// the file with that name is deleted after all other files.
+func deleteAllFiles(
+	ctx context.Context, execCfg *sql.ExecutorConfig, user username.SQLUsername, uri, last string,
+) error {
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var files []string
+	var hasLast bool
+	if err := store.List(ctx, "", "", func(f string) error {
+		f = strings.TrimPrefix(f, "/")
+		if last != "" && f == last {
+			hasLast = true
+			return nil
+		}
+		files = append(files, f)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if hasLast {
+		files = append(files, last)
+	}
+	for _, f := range files {
+		if err := store.Delete(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
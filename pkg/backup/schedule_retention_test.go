@@ -0,0 +1,107 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredBackupChains(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	// One full backup every week, the most recent one a day ago.
+	var subdirs []string
+	for i := 5; i >= 0; i-- {
+		ts := now.Add(-day - time.Duration(i)*7*day)
+		// ListFullBackupsInCollection returns subdirs without a leading slash.
+		subdirs = append(subdirs, ts.Format(backupbase.DateBasedIntoFolderName)[1:])
+	}
+	// A full backup written to a custom subdir is never deleted.
+	subdirs = append(subdirs, "custom/sub/dir")
+	latest := "/" + subdirs[5]
+	subdir := func(i int) string { return "/" + subdirs[i] }
+
+	for _, tc := range []struct {
+		name        string
+		policy      backupRetentionPolicy
+		latest      string
+		protectedTS hlc.Timestamp
+		expected    []string
+	}{
+		{
+			name:     "retention",
+			policy:   backupRetentionPolicy{retention: 20 * day},
+			latest:   latest,
+			expected: []string{subdir(0), subdir(1)},
+		},
+		{
+			name:     "keep full backups",
+			policy:   backupRetentionPolicy{keepFullBackups: 2},
+			latest:   latest,
+			expected: []string{subdir(0), subdir(1), subdir(2), subdir(3)},
+		},
+		{
+			name:     "retention and keep full backups",
+			policy:   backupRetentionPolicy{retention: 20 * day, keepFullBackups: 5},
+			latest:   latest,
+			expected: []string{subdir(0)},
+		},
+		{
+			name:     "latest is never deleted",
+			policy:   backupRetentionPolicy{keepFullBackups: 1},
+			latest:   subdir(3),
+			expected: []string{subdir(0), subdir(1), subdir(2)},
+		},
+		{
+			name:        "protected chain",
+			policy:      backupRetentionPolicy{keepFullBackups: 1},
+			latest:      latest,
+			protectedTS: hlc.Timestamp{WallTime: now.Add(-day - 28*day).UnixNano()},
+			expected:    []string{subdir(0)},
+		},
+		{
+			name:     "unknown latest",
+			policy:   backupRetentionPolicy{keepFullBackups: 1},
+			latest:   "custom/sub/dir",
+			expected: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, c := range expiredBackupChains(subdirs, tc.latest, tc.policy, tc.protectedTS, now) {
+				actual = append(actual, c.subdir)
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseRetentionPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	policy, err := parseRetentionPolicy(map[string]string{
+		optRetention:       "30d",
+		optKeepFullBackups: "4",
+	})
+	require.NoError(t, err)
+	require.Equal(t, backupRetentionPolicy{retention: 30 * 24 * time.Hour, keepFullBackups: 4}, policy)
+
+	_, err = parseRetentionPolicy(map[string]string{optRetention: "-1 day"})
+	require.ErrorContains(t, err, "retention must be a positive interval")
+	_, err = parseRetentionPolicy(map[string]string{optKeepFullBackups: "0"})
+	require.ErrorContains(t, err, "keep_full_backups must be a positive integer")
+}
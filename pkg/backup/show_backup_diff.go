@@ -0,0 +1,495 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+const showBackupDiffOp = "SHOW BACKUP DIFF"
+
+var showBackupDiffHeader = colinfo.ResultColumns{
+	{Name: "change", Typ: types.String},
+	{Name: "primary_key", Typ: types.Jsonb},
+	{Name: "before", Typ: types.Jsonb},
+	{Name: "after", Typ: types.Jsonb},
+}
+
+// The values of the change column of SHOW BACKUP DIFF.
+const (
+	backupDiffInsert = "insert"
+	backupDiffUpdate = "update"
+	backupDiffDelete = "delete"
+)
+
+func showBackupDiffTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	diff, ok := stmt.(*tree.ShowBackupDiff)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(ctx, showBackupDiffOp, p.SemaCtx(),
+		exprutil.Strings{
+			diff.From.Path, diff.From.InCollection,
+			diff.To.Path, diff.To.InCollection,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, showBackupDiffHeader, nil
+}
+
+// backupDiffSide is one side of a SHOW BACKUP DIFF, resolved from a
+// tree.BackupDiffSource.
+type backupDiffSide struct {
+	// live is set if the side is the live table, read as of asOf.
+	live bool
+	// collection and subdir locate the backup if the side is not live.
+	collection string
+	subdir     string
+	// asOf is the time the side is read as of. It is empty for a backup read as
+	// of its end time.
+	asOf hlc.Timestamp
+}
+
+func evalBackupDiffSide(
+	ctx context.Context, p sql.PlanHookState, src tree.BackupDiffSource,
+) (backupDiffSide, error) {
+	var side backupDiffSide
+	if src.AsOf.Expr != nil {
+		asOf, err := p.EvalAsOfTimestamp(ctx, src.AsOf)
+		if err != nil {
+			return side, err
+		}
+		side.asOf = asOf.Timestamp
+	}
+	if src.Path == nil {
+		side.live = true
+		return side, nil
+	}
+	exprEval := p.ExprEvaluator(showBackupDiffOp)
+	path, err := exprEval.String(ctx, src.Path)
+	if err != nil {
+		return side, err
+	}
+	if src.InCollection == nil {
+		// The path is the URI of the backup itself.
+		side.collection = path
+		return side, nil
+	}
+	side.subdir = path
+	side.collection, err = exprEval.String(ctx, src.InCollection)
+	return side, err
+}
+
+// showBackupDiffPlanHook implements PlanHookFn for SHOW BACKUP DIFF.
+func showBackupDiffPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	diff, ok := stmt.(*tree.ShowBackupDiff)
+	if !ok {
+		return nil, nil, false, nil
+	}
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		showBackupDiffOp,
+	); err != nil {
+		return nil, nil, false, err
+	}
+
+	from, err := evalBackupDiffSide(ctx, p, diff.From)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	to, err := evalBackupDiffSide(ctx, p, diff.To)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		d := &backupDiffer{p: p, tableName: diff.Table}
+		defer d.close(ctx)
+		return d.run(ctx, from, to, resultsCh)
+	}
+	return fn, showBackupDiffHeader, false, nil
+}
+
+// backupDiffRows is a stream of the rows of a table, in the order of its
+// primary index.
+type backupDiffRows interface {
+	Next(ctx context.Context) (bool, error)
+	Values() (tree.Datums, error)
+	Close(ctx context.Context)
+}
+
+// backupDiffer merges the rows of the two sides of a SHOW BACKUP DIFF.
+type backupDiffer struct {
+	p         sql.PlanHookState
+	tableName *tree.UnresolvedObjectName
+
+	// table is the descriptor that the compared columns and the primary key
+	// are taken from: the one of the FROM side.
+	table  catalog.TableDescriptor
+	labels []string
+	types  []*types.T
+	// keyCols are the positions of the primary key columns in labels, and
+	// keyDirs their directions in the primary index.
+	keyCols []int
+	keyDirs []catenumpb.IndexColumn_Direction
+	// liveTable is the descriptor of the live table, if a side is live.
+	liveTable catalog.TableDescriptor
+
+	sources []backupDiffRows
+}
+
+func (d *backupDiffer) run(
+	ctx context.Context, fromSide, toSide backupDiffSide, resultsCh chan<- tree.Datums,
+) error {
+	if fromSide.live || toSide.live {
+		tn := d.tableName.ToTableName()
+		_, table, err := resolver.ResolveExistingTableObject(ctx, d.p, &tn, tree.ObjectLookupFlags{
+			Required:             true,
+			DesiredObjectKind:    tree.TableObject,
+			DesiredTableDescKind: tree.ResolveRequireTableDesc,
+		})
+		if err != nil {
+			return err
+		}
+		if err := d.p.CheckPrivilege(ctx, table, privilege.SELECT); err != nil {
+			return err
+		}
+		d.liveTable = table
+	}
+
+	// The FROM side is opened first, since it determines the compared columns.
+	from, err := d.open(ctx, fromSide)
+	if err != nil {
+		return err
+	}
+	if fromSide.live && toSide.live {
+		if same, err := d.sameFingerprint(ctx, fromSide.asOf, toSide.asOf); err != nil {
+			return err
+		} else if same {
+			return nil
+		}
+	}
+	to, err := d.open(ctx, toSide)
+	if err != nil {
+		return err
+	}
+	if !fromSide.live && !toSide.live {
+		// Like the fingerprints of the live table, the data files that the
+		// backups are read from can show that no row changed.
+		if to.(*backupRowsGenerator).sameRows(from.(*backupRowsGenerator)) {
+			return nil
+		}
+	}
+
+	fromRow, err := d.nextRow(ctx, from)
+	if err != nil {
+		return err
+	}
+	toRow, err := d.nextRow(ctx, to)
+	if err != nil {
+		return err
+	}
+	for fromRow != nil || toRow != nil {
+		var cmp int
+		switch {
+		case fromRow == nil:
+			cmp = 1
+		case toRow == nil:
+			cmp = -1
+		default:
+			if cmp, err = d.compareKeys(ctx, fromRow, toRow); err != nil {
+				return err
+			}
+		}
+		switch {
+		case cmp < 0:
+			if err := d.emit(ctx, resultsCh, backupDiffDelete, fromRow, nil); err != nil {
+				return err
+			}
+			fromRow, err = d.nextRow(ctx, from)
+		case cmp > 0:
+			if err := d.emit(ctx, resultsCh, backupDiffInsert, nil, toRow); err != nil {
+				return err
+			}
+			toRow, err = d.nextRow(ctx, to)
+		default:
+			var changed bool
+			if changed, err = d.rowChanged(ctx, fromRow, toRow); err != nil {
+				return err
+			}
+			if changed {
+				if err := d.emit(ctx, resultsCh, backupDiffUpdate, fromRow, toRow); err != nil {
+					return err
+				}
+			}
+			if fromRow, err = d.nextRow(ctx, from); err != nil {
+				return err
+			}
+			toRow, err = d.nextRow(ctx, to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// open opens the rows of a side. The first side that is opened determines the
+// columns that are read from both sides.
+func (d *backupDiffer) open(ctx context.Context, side backupDiffSide) (backupDiffRows, error) {
+	if side.live {
+		if d.table == nil {
+			d.setTable(d.liveTable)
+		}
+		return d.openLive(ctx, side.asOf)
+	}
+	g := &backupRowsGenerator{
+		evalCtx:    &d.p.ExtendedEvalContext().Context,
+		collection: side.collection,
+		subdir:     side.subdir,
+		table:      d.tableName.String(),
+		asOf:       side.asOf,
+		allColumns: d.table == nil,
+		labels:     d.labels,
+		types:      d.types,
+	}
+	d.sources = append(d.sources, g)
+	if err := g.start(ctx, d.p); err != nil {
+		return nil, err
+	}
+	if d.table == nil {
+		d.setTable(g.desc)
+	}
+	return g, nil
+}
+
+func (d *backupDiffer) setTable(table catalog.TableDescriptor) {
+	d.table = table
+	d.labels, d.types = backupRowsReadableColumns(table)
+	idx := table.GetPrimaryIndex()
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		name := idx.GetKeyColumnName(i)
+		for j, label := range d.labels {
+			if label == name {
+				d.keyCols = append(d.keyCols, j)
+				d.keyDirs = append(d.keyDirs, idx.GetKeyColumnDirection(i))
+				break
+			}
+		}
+	}
+}
+
+// openLive reads the rows of the live table as of the given time, ordered
+// like its primary index.
+func (d *backupDiffer) openLive(ctx context.Context, asOf hlc.Timestamp) (backupDiffRows, error) {
+	var buf strings.Builder
+	buf.WriteString("SELECT ")
+	for i, label := range d.labels {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(label))
+	}
+	fmt.Fprintf(&buf, " FROM [%d AS t] AS OF SYSTEM TIME %s ORDER BY ",
+		d.liveTable.GetID(), asOf.AsOfSystemTime())
+	for i, col := range d.keyCols {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(d.labels[col]))
+		if d.keyDirs[i] == catenumpb.IndexColumn_DESC {
+			buf.WriteString(" DESC")
+		}
+	}
+	rows, err := d.p.ExecCfg().InternalDB.Executor().QueryIteratorEx(
+		ctx, "backup-diff-live-rows", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: d.p.User()}, buf.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	live := &backupDiffLiveRows{rows: rows}
+	d.sources = append(d.sources, live)
+	return live, nil
+}
+
+// sameFingerprint returns whether the primary index of the live table has
+// the same fingerprint at both times, in which case no row changed.
+func (d *backupDiffer) sameFingerprint(ctx context.Context, from, to hlc.Timestamp) (bool, error) {
+	span := d.liveTable.PrimaryIndexSpan(d.p.ExecCfg().Codec)
+	var fingerprints [2]tree.Datum
+	for i, ts := range []hlc.Timestamp{from, to} {
+		row, err := d.p.ExecCfg().InternalDB.Executor().QueryRowEx(
+			ctx, "backup-diff-fingerprint", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			fmt.Sprintf(`SELECT crdb_internal.fingerprint(ARRAY[$1, $2]::BYTES[], true) AS OF SYSTEM TIME %s`,
+				ts.AsOfSystemTime()),
+			[]byte(span.Key), []byte(span.EndKey),
+		)
+		if err != nil {
+			return false, errors.Wrap(err, "fingerprinting the live table")
+		}
+		fingerprints[i] = row[0]
+	}
+	cmp, err := fingerprints[0].Compare(ctx, &d.p.ExtendedEvalContext().Context, fingerprints[1])
+	return cmp == 0, err
+}
+
+// nextRow returns the next row of the source, or nil if it is exhausted.
+func (d *backupDiffer) nextRow(ctx context.Context, rows backupDiffRows) (tree.Datums, error) {
+	ok, err := rows.Next(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	values, err := rows.Values()
+	if err != nil {
+		return nil, err
+	}
+	// The sources may reuse the slice of the current row.
+	return append(tree.Datums(nil), values...), nil
+}
+
+// compareKeys compares the primary keys of the rows in the order of the
+// primary index.
+func (d *backupDiffer) compareKeys(ctx context.Context, a, b tree.Datums) (int, error) {
+	evalCtx := &d.p.ExtendedEvalContext().Context
+	for i, col := range d.keyCols {
+		cmp, err := a[col].Compare(ctx, evalCtx, b[col])
+		if err != nil {
+			return 0, err
+		}
+		if cmp != 0 {
+			if d.keyDirs[i] == catenumpb.IndexColumn_DESC {
+				cmp = -cmp
+			}
+			return cmp, nil
+		}
+	}
+	return 0, nil
+}
+
+func (d *backupDiffer) rowChanged(ctx context.Context, a, b tree.Datums) (bool, error) {
+	evalCtx := &d.p.ExtendedEvalContext().Context
+	for i := range a {
+		cmp, err := a[i].Compare(ctx, evalCtx, b[i])
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *backupDiffer) emit(
+	ctx context.Context, resultsCh chan<- tree.Datums, change string, before, after tree.Datums,
+) error {
+	keyRow := after
+	if keyRow == nil {
+		keyRow = before
+	}
+	key, err := d.rowJSON(keyRow, d.keyCols)
+	if err != nil {
+		return err
+	}
+	beforeJSON, err := d.rowJSON(before, nil /* cols */)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := d.rowJSON(after, nil /* cols */)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resultsCh <- tree.Datums{tree.NewDString(change), key, beforeJSON, afterJSON}:
+		return nil
+	}
+}
+
+// rowJSON returns a JSON object of the given columns of the row, or of all of
+// its columns if cols is nil.
+func (d *backupDiffer) rowJSON(row tree.Datums, cols []int) (tree.Datum, error) {
+	if row == nil {
+		return tree.DNull, nil
+	}
+	if cols == nil {
+		cols = make([]int, len(row))
+		for i := range cols {
+			cols[i] = i
+		}
+	}
+	sd := d.p.SessionData()
+	b := json.NewObjectBuilder(len(cols))
+	for _, col := range cols {
+		j, err := tree.AsJSON(row[col], sd.DataConversionConfig, sd.GetLocation())
+		if err != nil {
+			return nil, err
+		}
+		b.Add(d.labels[col], j)
+	}
+	return tree.NewDJSON(b.Build()), nil
+}
+
+func (d *backupDiffer) close(ctx context.Context) {
+	for _, src := range d.sources {
+		src.Close(ctx)
+	}
+}
+
+// backupDiffLiveRows adapts the rows of a query to backupDiffRows.
+type backupDiffLiveRows struct {
+	rows isql.Rows
+}
+
+// Next implements the backupDiffRows interface.
+func (r *backupDiffLiveRows) Next(ctx context.Context) (bool, error) {
+	return r.rows.Next(ctx)
+}
+
+// Values implements the backupDiffRows interface.
+func (r *backupDiffLiveRows) Values() (tree.Datums, error) {
+	return r.rows.Cur(), nil
+}
+
+// Close implements the backupDiffRows interface.
+func (r *backupDiffLiveRows) Close(ctx context.Context) {
+	if err := r.rows.Close(); err != nil {
+		log.Warningf(ctx, "failed to close live rows of the diffed table: %v", err)
+	}
+}
+
+func init() {
+	sql.AddPlanHook("backup diff", showBackupDiffPlanHook, showBackupDiffTypeCheck)
+}
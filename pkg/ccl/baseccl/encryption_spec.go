@@ -32,6 +32,10 @@ type StoreEncryptionSpec struct {
 	KeyPath        string
 	OldKeyPath     string
 	RotationPeriod time.Duration
+	// KMSURI and OldKMSURI, if set, are the URIs of the KMS keys wrapping the
+	// store keys in KeyPath and OldKeyPath respectively.
+	KMSURI    string
+	OldKMSURI string
 }
 
 // ToEncryptionOptions convert to a serialized EncryptionOptions protobuf.
@@ -39,8 +43,10 @@ func (es StoreEncryptionSpec) ToEncryptionOptions() ([]byte, error) {
 	opts := EncryptionOptions{
 		KeySource: EncryptionKeySource_KeyFiles,
 		KeyFiles: &EncryptionKeyFiles{
-			CurrentKey: es.KeyPath,
-			OldKey:     es.OldKeyPath,
+			CurrentKey:       es.KeyPath,
+			OldKey:           es.OldKeyPath,
+			CurrentKeyKmsUri: es.KMSURI,
+			OldKeyKmsUri:     es.OldKMSURI,
 		},
 		DataKeyRotationPeriod: int64(es.RotationPeriod / time.Second),
 	}
@@ -50,9 +56,16 @@ func (es StoreEncryptionSpec) ToEncryptionOptions() ([]byte, error) {
 
 // String returns a fully parsable version of the encryption spec.
 func (es StoreEncryptionSpec) String() string {
-	// All fields are set.
-	return fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
+	// All required fields are set.
+	s := fmt.Sprintf("path=%s,key=%s,old-key=%s,rotation-period=%s",
 		es.Path, es.KeyPath, es.OldKeyPath, es.RotationPeriod)
+	if es.KMSURI != "" {
+		s += ",kms=" + es.KMSURI
+	}
+	if es.OldKMSURI != "" {
+		s += ",old-kms=" + es.OldKMSURI
+	}
+	return s
 }
 
 // PathMatches returns true if this StoreEncryptionSpec matches the given store path.
@@ -128,6 +141,10 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 			if err != nil {
 				return StoreEncryptionSpec{}, errors.Wrapf(err, "could not parse rotation-duration value: %s", value)
 			}
+		case "kms":
+			es.KMSURI = value
+		case "old-kms":
+			es.OldKMSURI = value
 		default:
 			return StoreEncryptionSpec{}, fmt.Errorf("%s is not a valid enterprise-encryption field", field)
 		}
@@ -143,6 +160,12 @@ func NewStoreEncryptionSpec(value string) (StoreEncryptionSpec, error) {
 	if es.OldKeyPath == "" {
 		return StoreEncryptionSpec{}, fmt.Errorf("no old-key specified")
 	}
+	if es.KMSURI != "" && es.KeyPath == plaintextFieldValue {
+		return StoreEncryptionSpec{}, fmt.Errorf("kms cannot be used with a plaintext key")
+	}
+	if es.OldKMSURI != "" && es.OldKeyPath == plaintextFieldValue {
+		return StoreEncryptionSpec{}, fmt.Errorf("old-kms cannot be used with a plaintext old-key")
+	}
 
 	return es, nil
 }
@@ -48,12 +48,17 @@ func TestNewStoreEncryptionSpec(t *testing.T) {
 		{"path=data,key=new.key,old-key=old.key,rotation-period=1", `could not parse rotation-duration value: 1: time: missing unit in duration "1"`, StoreEncryptionSpec{}},
 		{"path=data,key=new.key,old-key=old.key,rotation-period=1d", `could not parse rotation-duration value: 1d: time: unknown unit "d" in duration "1d"`, StoreEncryptionSpec{}},
 
+		// KMS.
+		{"path=data,key=plain,old-key=old.key,kms=aws-kms:///key", "kms cannot be used with a plaintext key", StoreEncryptionSpec{}},
+		{"path=data,key=new.key,old-key=plain,old-kms=aws-kms:///key", "old-kms cannot be used with a plaintext old-key", StoreEncryptionSpec{}},
+
 		// Good values. Note that paths get absolutized so we start most of them
 		// with / so we can used fixed expected values.
 		{"path=/data,key=/new.key,old-key=/old.key", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod}},
 		{"path=/data,key=/new.key,old-key=/old.key,rotation-period=1h", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: time.Hour}},
 		{"path=/data,key=plain,old-key=/old.key,rotation-period=1h", "", StoreEncryptionSpec{Path: "/data", KeyPath: "plain", OldKeyPath: "/old.key", RotationPeriod: time.Hour}},
 		{"path=/data,key=/new.key,old-key=plain,rotation-period=1h", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "plain", RotationPeriod: time.Hour}},
+		{"path=/data,key=/new.key,old-key=/old.key,kms=aws-kms:///new?REGION=us-east-1,old-kms=aws-kms:///old?REGION=us-east-1", "", StoreEncryptionSpec{Path: "/data", KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod, KMSURI: "aws-kms:///new?REGION=us-east-1", OldKMSURI: "aws-kms:///old?REGION=us-east-1"}},
 
 		// One relative path to test absolutization.
 		{"path=data,key=/new.key,old-key=/old.key", "", StoreEncryptionSpec{Path: absDataPath, KeyPath: "/new.key", OldKeyPath: "/old.key", RotationPeriod: DefaultRotationPeriod}},
@@ -0,0 +1,110 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/backup"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvfeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// backupHistoryReader is a kvfeed.HistoryReader that reads the history of the
+// watched spans from the revision history backups in a backup collection. The
+// backups are only resolved once the history is first needed, which is
+// usually never.
+type backupHistoryReader struct {
+	execCfg       *sql.ExecutorConfig
+	user          username.SQLUsername
+	collectionURI string
+
+	mu struct {
+		syncutil.Mutex
+		history *backup.RevisionHistory
+	}
+}
+
+var _ kvfeed.HistoryReader = (*backupHistoryReader)(nil)
+
+func newBackupHistoryReader(
+	execCfg *sql.ExecutorConfig, user username.SQLUsername, collectionURI string,
+) *backupHistoryReader {
+	return &backupHistoryReader{
+		execCfg:       execCfg,
+		user:          user,
+		collectionURI: collectionURI,
+	}
+}
+
+func (r *backupHistoryReader) history(ctx context.Context) (*backup.RevisionHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.history == nil {
+		h, err := backup.ResolveRevisionHistory(ctx, r.execCfg, r.user, r.collectionURI)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.history = h
+	}
+	return r.mu.history, nil
+}
+
+// Bounds implements the kvfeed.HistoryReader interface.
+func (r *backupHistoryReader) Bounds(ctx context.Context) (start, end hlc.Timestamp, _ error) {
+	h, err := r.history(ctx)
+	if err != nil {
+		return hlc.Timestamp{}, hlc.Timestamp{}, err
+	}
+	return h.StartTime(), h.EndTime(), nil
+}
+
+// ReadRevisions implements the kvfeed.HistoryReader interface.
+func (r *backupHistoryReader) ReadRevisions(
+	ctx context.Context,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+	fn func(key roachpb.Key, value roachpb.Value) error,
+) error {
+	h, err := r.history(ctx)
+	if err != nil {
+		return err
+	}
+	allRevs := make(chan []backup.VersionedValues)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(allRevs)
+		return h.GetAllRevisions(ctx, span, startTime, endTime, allRevs)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for revs := range allRevs {
+			for _, rev := range revs {
+				for _, value := range rev.Values {
+					if err := fn(rev.Key, value); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	return g.Wait()
+}
+
+// Close implements the kvfeed.HistoryReader interface.
+func (r *backupHistoryReader) Close(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.history != nil {
+		r.mu.history.Close(ctx)
+		r.mu.history = nil
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdceval
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/lib/pq/oid"
+)
+
+// RowFilterForExpression returns a rangefeed row filter which can be pushed
+// down to the rangefeed servers to drop events for rows the changefeed
+// expression is guaranteed to discard. Select clause expression assumed to be
+// normalized.
+//
+// Only conjuncts of the WHERE clause which compare a non-key INT, STRING or
+// BOOL column of the target family against a constant are compiled; all other
+// conjuncts are ignored, which only makes the filter less selective. Returns
+// nil if there is nothing to push down.
+func RowFilterForExpression(
+	codec keys.SQLCodec,
+	desc catalog.TableDescriptor,
+	target jobspb.ChangefeedTargetSpecification,
+	sc *tree.SelectClause,
+) (*kvpb.RangeFeedRowFilter, error) {
+	family, err := getTargetFamilyDescriptor(desc, target)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &kvpb.RangeFeedRowFilter{
+		Prefix: codec.IndexPrefix(uint32(desc.GetID()), uint32(desc.GetPrimaryIndexID())),
+	}
+	if desc.NumFamilies() > 1 {
+		// The changefeed only decodes the target family, so events for the
+		// other families can be dropped.
+		filter.Families = []uint32{uint32(family.ID)}
+	}
+
+	// Values of single column families other than the primary family don't
+	// encode the ID of their column, so they can't be evaluated.
+	if sc.Where != nil && (family.ID == 0 || len(family.ColumnIDs) > 1) {
+		c := rowFilterCompiler{
+			desc:       desc,
+			family:     family,
+			tableNames: tableNamesForSelect(desc, sc),
+			keyColumns: desc.GetPrimaryIndex().CollectKeyColumnIDs(),
+		}
+		c.compile(sc.Where.Expr, filter)
+	}
+
+	if len(filter.Families) == 0 && len(filter.Conditions) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// tableNamesForSelect returns the names which may be used to qualify columns
+// of the target table in the select clause.
+func tableNamesForSelect(desc catalog.TableDescriptor, sc *tree.SelectClause) []string {
+	names := []string{desc.GetName()}
+	if len(sc.From.Tables) == 1 {
+		if t, ok := sc.From.Tables[0].(*tree.AliasedTableExpr); ok && t.As.Alias != "" {
+			names = append(names, string(t.As.Alias))
+		}
+	}
+	return names
+}
+
+type rowFilterCompiler struct {
+	desc       catalog.TableDescriptor
+	family     *descpb.ColumnFamilyDescriptor
+	tableNames []string
+	keyColumns catalog.TableColSet
+}
+
+// compile adds the conditions which can be derived from expr to the filter.
+func (c *rowFilterCompiler) compile(expr tree.Expr, filter *kvpb.RangeFeedRowFilter) {
+	switch e := expr.(type) {
+	case *tree.ParenExpr:
+		c.compile(e.Expr, filter)
+	case *tree.AndExpr:
+		c.compile(e.Left, filter)
+		c.compile(e.Right, filter)
+	case *tree.ComparisonExpr:
+		if cond, ok := c.compileComparison(e); ok {
+			filter.Conditions = append(filter.Conditions, cond)
+		}
+	}
+}
+
+// compileComparison compiles a comparison between a column and a constant.
+func (c *rowFilterCompiler) compileComparison(
+	e *tree.ComparisonExpr,
+) (kvpb.RangeFeedRowFilter_Condition, bool) {
+	var op kvpb.RangeFeedRowFilter_Op
+	var flippedOp kvpb.RangeFeedRowFilter_Op
+	switch e.Operator.Symbol {
+	case treecmp.EQ:
+		op, flippedOp = kvpb.RangeFeedRowFilter_EQ, kvpb.RangeFeedRowFilter_EQ
+	case treecmp.NE:
+		op, flippedOp = kvpb.RangeFeedRowFilter_NE, kvpb.RangeFeedRowFilter_NE
+	case treecmp.LT:
+		op, flippedOp = kvpb.RangeFeedRowFilter_LT, kvpb.RangeFeedRowFilter_GT
+	case treecmp.LE:
+		op, flippedOp = kvpb.RangeFeedRowFilter_LE, kvpb.RangeFeedRowFilter_GE
+	case treecmp.GT:
+		op, flippedOp = kvpb.RangeFeedRowFilter_GT, kvpb.RangeFeedRowFilter_LT
+	case treecmp.GE:
+		op, flippedOp = kvpb.RangeFeedRowFilter_GE, kvpb.RangeFeedRowFilter_LE
+	default:
+		return kvpb.RangeFeedRowFilter_Condition{}, false
+	}
+
+	col, ok := c.resolveColumn(e.Left)
+	constant := e.Right
+	if !ok {
+		col, ok = c.resolveColumn(e.Right)
+		constant, op = e.Left, flippedOp
+	}
+	if !ok {
+		return kvpb.RangeFeedRowFilter_Condition{}, false
+	}
+	value, ok := encodeConstant(col.GetType(), constant)
+	if !ok {
+		return kvpb.RangeFeedRowFilter_Condition{}, false
+	}
+	return kvpb.RangeFeedRowFilter_Condition{
+		FamilyID: uint32(c.family.ID),
+		ColumnID: uint32(col.GetID()),
+		Op:       op,
+		Value:    value,
+	}, true
+}
+
+// resolveColumn returns the column referenced by expr, if expr is a reference
+// to a column of the target table which is stored in the target family's
+// value.
+func (c *rowFilterCompiler) resolveColumn(expr tree.Expr) (catalog.Column, bool) {
+	if p, ok := expr.(*tree.ParenExpr); ok {
+		return c.resolveColumn(p.Expr)
+	}
+	name, ok := expr.(*tree.UnresolvedName)
+	if !ok {
+		return nil, false
+	}
+	vn, err := name.NormalizeVarName()
+	if err != nil {
+		return nil, false
+	}
+	item, ok := vn.(*tree.ColumnItem)
+	if !ok {
+		return nil, false
+	}
+	if item.TableName != nil {
+		// Make sure the column isn't qualified with something other than the
+		// target table (e.g. cdc_prev).
+		if item.TableName.NumParts != 1 || !c.isTableName(item.TableName.Parts[0]) {
+			return nil, false
+		}
+	}
+	col, err := catalog.MustFindColumnByTreeName(c.desc, item.ColumnName)
+	if err != nil || !col.Public() || col.IsVirtual() || c.keyColumns.Contains(col.GetID()) {
+		return nil, false
+	}
+	for _, id := range c.family.ColumnIDs {
+		if id == col.GetID() {
+			return col, true
+		}
+	}
+	return nil, false
+}
+
+func (c *rowFilterCompiler) isTableName(name string) bool {
+	for _, n := range c.tableNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeConstant returns the value encoding of the constant expr, if it can be
+// compared to the column type on the rangefeed servers.
+func encodeConstant(typ *types.T, expr tree.Expr) ([]byte, bool) {
+	if p, ok := expr.(*tree.ParenExpr); ok {
+		return encodeConstant(typ, p.Expr)
+	}
+	switch typ.Family() {
+	case types.IntFamily:
+		switch e := expr.(type) {
+		case *tree.NumVal:
+			i, err := e.AsInt64()
+			if err != nil {
+				return nil, false
+			}
+			return encoding.EncodeIntValue(nil, encoding.NoColumnID, i), true
+		case *tree.DInt:
+			return encoding.EncodeIntValue(nil, encoding.NoColumnID, int64(*e)), true
+		}
+	case types.StringFamily:
+		// Other string types (e.g. CHAR) don't compare bytewise.
+		if typ.Oid() != oid.T_text && typ.Oid() != oid.T_varchar {
+			return nil, false
+		}
+		switch e := expr.(type) {
+		case *tree.StrVal:
+			return encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte(e.RawString())), true
+		case *tree.DString:
+			return encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte(*e)), true
+		}
+	case types.BoolFamily:
+		if e, ok := expr.(*tree.DBool); ok {
+			return encoding.EncodeBoolValue(nil, encoding.NoColumnID, bool(*e)), true
+		}
+	}
+	return nil, false
+}
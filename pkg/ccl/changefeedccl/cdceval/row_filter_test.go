@@ -0,0 +1,230 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdceval
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowFilterForExpression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.ExecMultiple(t,
+		`CREATE TABLE foo (
+a INT PRIMARY KEY,
+b INT,
+c STRING,
+d BOOL,
+e FLOAT,
+f STRING,
+FAMILY main (a, b, c, d, e),
+FAMILY extra (f)
+)`,
+		`INSERT INTO foo VALUES (1, 1, 'x', true, 1.5, 'f1'), (2, 5, 'y', false, 2.5, 'f2'), (3, 10, 'x', NULL, 3.5, 'f3')`,
+		`CREATE TABLE bar (a INT PRIMARY KEY, b INT)`,
+	)
+
+	codec := s.Codec()
+	fooDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "foo")
+	barDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "bar")
+	span := fooDesc.PrimaryIndexSpan(codec)
+	rows, err := kvDB.Scan(ctx, span.Key, span.EndKey, 0 /* maxRows */)
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+
+	// matchingRows returns the primary key and family of the rows of foo for
+	// which the rangefeed servers emit events with the filter.
+	prefix := codec.IndexPrefix(uint32(fooDesc.GetID()), uint32(fooDesc.GetPrimaryIndexID()))
+	matchingRows := func(t *testing.T, f *rangefeed.RowFilter) []string {
+		var res []string
+		for _, row := range rows {
+			if !f.Matches(row.Key, *row.Value) {
+				continue
+			}
+			require.True(t, bytes.HasPrefix(row.Key, prefix))
+			_, pk, err := encoding.DecodeVarintAscending(row.Key[len(prefix):])
+			require.NoError(t, err)
+			family, err := keys.DecodeFamilyKey(row.Key)
+			require.NoError(t, err)
+			res = append(res, fmt.Sprintf("%d/%d", pk, family))
+		}
+		return res
+	}
+	allMain := []string{"1/0", "2/0", "3/0"}
+
+	for _, tc := range []struct {
+		name           string
+		desc           catalog.TableDescriptor
+		stmt           string
+		targetFamily   string
+		eachFamily     bool
+		expectErr      string
+		expectNil      bool
+		expectConds    int
+		expectMatching []string
+	}{
+		{
+			name:      "no filter on single family table",
+			desc:      barDesc,
+			stmt:      "SELECT * FROM bar",
+			expectNil: true,
+		},
+		{
+			name:      "nothing to push down on single family table",
+			desc:      barDesc,
+			stmt:      "SELECT * FROM bar WHERE b > 1 OR b < 0",
+			expectNil: true,
+		},
+		{
+			name:           "no where clause projects the target family",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo",
+			expectMatching: allMain,
+		},
+		{
+			name:           "int comparison",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo WHERE b > 3",
+			expectConds:    1,
+			expectMatching: []string{"2/0", "3/0"},
+		},
+		{
+			name:           "flipped comparison",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo WHERE 3 < b",
+			expectConds:    1,
+			expectMatching: []string{"2/0", "3/0"},
+		},
+		{
+			name:           "string comparison",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo WHERE c = 'x'",
+			expectConds:    1,
+			expectMatching: []string{"1/0", "3/0"},
+		},
+		{
+			name:        "bool comparison",
+			desc:        fooDesc,
+			stmt:        "SELECT * FROM foo WHERE d = true",
+			expectConds: 1,
+			// Rows where the column is NULL are always emitted.
+			expectMatching: []string{"1/0", "3/0"},
+		},
+		{
+			name:           "conjunction",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo WHERE (b > 3) AND c = 'x'",
+			expectConds:    2,
+			expectMatching: []string{"3/0"},
+		},
+		{
+			name:           "qualified with alias",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo AS t WHERE t.b < 5 AND foo.c = 'x'",
+			expectConds:    2,
+			expectMatching: []string{"1/0"},
+		},
+		{
+			name:           "reject all rows",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo WHERE b > 100",
+			expectConds:    1,
+			expectMatching: nil,
+		},
+		{
+			name: "unsupported conjuncts are ignored",
+			desc: fooDesc,
+			stmt: "SELECT * FROM foo WHERE b > 3 AND (c = 'y' OR c = 'z') AND a = 3 AND e > 2.0 " +
+				"AND cdc_prev.b > 1 AND lower(c) = 'x' AND b > 'str'",
+			expectConds:    1,
+			expectMatching: []string{"2/0", "3/0"},
+		},
+		{
+			name:           "unknown column is ignored",
+			desc:           fooDesc,
+			stmt:           "SELECT * FROM foo WHERE nope > 3",
+			expectMatching: allMain,
+		},
+		{
+			name:         "column of single column family",
+			desc:         fooDesc,
+			stmt:         "SELECT f FROM foo WHERE f = 'f1'",
+			targetFamily: "extra",
+			// The values of the family don't encode the ID of the column.
+			expectMatching: []string{"1/1", "2/1", "3/1"},
+		},
+		{
+			name:         "unknown family",
+			desc:         fooDesc,
+			stmt:         "SELECT * FROM foo WHERE b = 1",
+			targetFamily: "nope",
+			expectErr:    "no such family nope",
+		},
+		{
+			name:       "each family",
+			desc:       fooDesc,
+			stmt:       "SELECT * FROM foo WHERE b = 1",
+			eachFamily: true,
+			expectErr:  "expressions can't reference columns from more than one column family",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			target := jobspb.ChangefeedTargetSpecification{
+				TableID:           tc.desc.GetID(),
+				StatementTimeName: tc.desc.GetName(),
+			}
+			if tc.targetFamily != "" {
+				target.Type = jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY
+				target.FamilyName = tc.targetFamily
+			}
+			if tc.eachFamily {
+				target.Type = jobspb.ChangefeedTargetSpecification_EACH_FAMILY
+			}
+			sc, err := ParseChangefeedExpression(tc.stmt)
+			require.NoError(t, err)
+
+			filter, err := RowFilterForExpression(codec, tc.desc, target, sc)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectNil {
+				require.Nil(t, filter)
+				return
+			}
+			require.NotNil(t, filter)
+			require.Len(t, filter.Conditions, tc.expectConds)
+
+			f, err := rangefeed.NewRowFilter(filter)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectMatching, matchingRows(t, f))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -221,6 +222,26 @@ func fetchSpansForTables(
 		sd, tableDescs[0], initialHighwater, target, sc)
 }
 
+// rowFilterForTables returns the row filter which the change aggregators
+// push down to the rangefeed servers, or nil if the changefeed expression (if
+// any) can't be pushed down.
+func rowFilterForTables(
+	execCtx sql.JobExecContext,
+	tableDescs []catalog.TableDescriptor,
+	details jobspb.ChangefeedDetails,
+) (*kvpb.RangeFeedRowFilter, error) {
+	if details.Select == "" || len(tableDescs) != 1 {
+		return nil, nil
+	}
+	sc, err := cdceval.ParseChangefeedExpression(details.Select)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue,
+			"could not parse changefeed expression")
+	}
+	return cdceval.RowFilterForExpression(execCtx.ExecCfg().Codec, tableDescs[0],
+		details.TargetSpecifications[0], sc)
+}
+
 // startDistChangefeed starts distributed changefeed execution.
 func startDistChangefeed(
 	ctx context.Context,
@@ -251,6 +272,11 @@ func startDistChangefeed(
 	}
 	localState.trackedSpans = trackedSpans
 
+	rowFilter, err := rowFilterForTables(execCtx, tableDescs, details)
+	if err != nil {
+		return err
+	}
+
 	// Changefeed flows handle transactional consistency themselves.
 	var noTxn *kv.Txn
 
@@ -262,7 +288,7 @@ func startDistChangefeed(
 		checkpoint = progress.Checkpoint
 	}
 	p, planCtx, err := makePlan(execCtx, jobID, details, description, initialHighWater,
-		trackedSpans, checkpoint, localState.drainingNodes)(ctx, dsp)
+		trackedSpans, rowFilter, checkpoint, localState.drainingNodes)(ctx, dsp)
 	if err != nil {
 		return err
 	}
@@ -378,6 +404,7 @@ func makePlan(
 	description string,
 	initialHighWater hlc.Timestamp,
 	trackedSpans []roachpb.Span,
+	rowFilter *kvpb.RangeFeedRowFilter,
 	checkpoint *jobspb.ChangefeedProgress_Checkpoint,
 	drainingNodes []roachpb.NodeID,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
@@ -486,6 +513,7 @@ func makePlan(
 				JobID:       jobID,
 				Select:      execinfrapb.Expression{Expr: details.Select},
 				Description: description,
+				RowFilter:   rowFilter,
 			}
 		}
 
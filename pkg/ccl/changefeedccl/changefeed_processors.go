@@ -511,6 +511,12 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		return kvfeed.Config{}, err
 	}
 
+	var historyReader kvfeed.HistoryReader
+	if uri, ok := config.Opts.GetCatchUpBackupCollection(); ok {
+		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
+		historyReader = newBackupHistoryReader(execCfg, ca.spec.User(), uri)
+	}
+
 	return kvfeed.Config{
 		Writer:               buf,
 		Settings:             cfg.Settings,
@@ -536,6 +542,8 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		ScopedTimers:         ca.sliMetrics.Timers,
 		MonitoringCfg:        monitoringCfg,
 		ConsumerID:           int64(ca.spec.JobID),
+		RowFilter:            ca.spec.RowFilter,
+		HistoryReader:        historyReader,
 	}, nil
 }
 
@@ -517,8 +517,18 @@ func createChangefeedJobRecord(
 			hasChangefeedPrivOnAllTables = hasChangefeedPrivOnAllTables && hasChangefeed
 		}
 	}
+	notificationURI, _ := opts.GetNotificationURI()
 	if checkPrivs {
-		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry()); err != nil {
+		// Notifications are sent from the cluster like the rows of the
+		// changefeed, so the notify URI is authorized like the sink.
+		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry(), notificationURI); err != nil {
+			return nil, err
+		}
+	}
+	// The backups are read with the privileges of the user, so they must be
+	// allowed to access the collection, as in RESTORE.
+	if uri, ok := opts.GetCatchUpBackupCollection(); ok {
+		if err := sql.CheckDestinationPrivileges(ctx, p, []string{uri}); err != nil {
 			return nil, err
 		}
 	}
@@ -769,6 +779,19 @@ Few hours to a few days range are appropriate values for this option.`
 		}
 	}
 
+	if notificationURI != "" {
+		// The jobs registry cannot resolve external connections, so the URI of
+		// the connection is the one that notifications are sent to.
+		resolved, err := resolveDest(ctx, p.ExecCfg(), notificationURI)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", changefeedbase.OptNotify)
+		}
+		if err := jobs.ValidateNotificationURI(resolved); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", changefeedbase.OptNotify)
+		}
+		notificationURI = resolved
+	}
+
 	jr := &jobs.Record{
 		Description: description,
 		Username:    p.User(),
@@ -778,9 +801,10 @@ Few hours to a few days range are appropriate values for this option.`
 			}
 			return sqlDescIDs
 		}(),
-		Details:       details,
-		CreatedBy:     changefeedStmt.CreatedByInfo,
-		MaximumPTSAge: ptsExpiration,
+		Details:         details,
+		CreatedBy:       changefeedStmt.CreatedByInfo,
+		MaximumPTSAge:   ptsExpiration,
+		NotificationURI: notificationURI,
 	}
 
 	return jr, nil
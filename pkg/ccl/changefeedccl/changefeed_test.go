@@ -706,6 +706,43 @@ func TestChangefeedSendError(t *testing.T) {
 	}, feedTestEnterpriseSinks)
 }
 
+// TestChangefeedRowFilterPushdown tests that the rows rejected by the WHERE
+// clause of a CDC query are dropped by the rangefeed servers and never reach
+// the changefeed.
+func TestChangefeedRowFilterPushdown(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cdcTest(t, func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT, c STRING)`)
+
+		knobs := s.TestingKnobs.
+			DistSQL.(*execinfra.TestingKnobs).
+			Changefeed.(*TestingKnobs)
+		var numValues atomic.Int64
+		knobs.FeedKnobs.OnRangeFeedValue = func() error {
+			numValues.Add(1)
+			return nil
+		}
+
+		foo := feed(t, f,
+			`CREATE CHANGEFEED WITH initial_scan='no' AS SELECT * FROM foo WHERE b > 7 AND c = 'x'`)
+		defer closeFeed(t, foo)
+
+		sqlDB.Exec(t, `INSERT INTO foo SELECT i, i, IF(i % 2 = 0, 'x', 'y') FROM generate_series(1, 10) AS g(i)`)
+		assertPayloads(t, foo, []string{
+			`foo: [8]->{"a": 8, "b": 8, "c": "x"}`,
+			`foo: [10]->{"a": 10, "b": 10, "c": "x"}`,
+		})
+		// Without the filter, the changefeed would have received all ten rows.
+		// The matching rows may be received more than once if the rangefeed
+		// restarts.
+		require.GreaterOrEqual(t, numValues.Load(), int64(2))
+		require.Less(t, numValues.Load(), int64(10))
+	}, feedTestEnterpriseSinks)
+}
+
 func TestChangefeedBasicConfluentKafka(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -3545,7 +3582,41 @@ func TestChangefeedCreateAuthorizationWithChangefeedPriv(t *testing.T) {
 			"CREATE CHANGEFEED for table_a, table_b INTO 'external://nope'",
 		)
 	})
+	// The notify URI is gated like the sink.
+	withUser(t, "user1", func(userDB *sqlutils.SQLRunner) {
+		userDB.ExpectErr(t,
+			"pq: the CHANGEFEED privilege on all tables can only be used with external connection sinks",
+			"CREATE CHANGEFEED for table_a, table_b INTO 'external://nope' WITH notify = 'webhook-https://example.com/hook'",
+		)
+	})
+	rootDB.Exec(t, `CREATE EXTERNAL CONNECTION "hook" AS 'webhook-https://example.com/hook'`)
+	withUser(t, "user1", func(userDB *sqlutils.SQLRunner) {
+		userDB.ExpectErr(t,
+			"pq: user user1 does not have USAGE privilege on external_connection hook",
+			"CREATE CHANGEFEED for table_a, table_b INTO 'external://nope' WITH notify = 'external://hook'",
+		)
+	})
+	rootDB.Exec(t, "GRANT USAGE ON EXTERNAL CONNECTION hook to user1")
+	withUser(t, "user1", func(userDB *sqlutils.SQLRunner) {
+		userDB.Exec(t,
+			"CREATE CHANGEFEED for table_a, table_b INTO 'external://nope' WITH notify = 'external://hook'",
+		)
+	})
 	rootDB.Exec(t, "SET CLUSTER SETTING changefeed.permissions.require_external_connection_sink.enabled = false")
+
+	// The user needs access to the backup collection to catch up from it.
+	withUser(t, "user1", func(userDB *sqlutils.SQLRunner) {
+		userDB.ExpectErr(t,
+			"only users with the admin role or the EXTERNALIOIMPLICITACCESS system privilege are allowed to access the specified nodelocal URI",
+			"CREATE CHANGEFEED for table_a, table_b INTO 'external://nope' WITH catchup_backup_collection = 'nodelocal://1/backups'",
+		)
+	})
+	rootDB.Exec(t, "GRANT SYSTEM EXTERNALIOIMPLICITACCESS TO user1")
+	withUser(t, "user1", func(userDB *sqlutils.SQLRunner) {
+		userDB.Exec(t,
+			"CREATE CHANGEFEED for table_a, table_b INTO 'external://nope' WITH catchup_backup_collection = 'nodelocal://1/backups'",
+		)
+	})
 }
 
 func TestChangefeedGrant(t *testing.T) {
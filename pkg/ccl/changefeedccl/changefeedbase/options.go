@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -103,6 +104,8 @@ const (
 	OptLaggingRangesPollingInterval       = `lagging_ranges_polling_interval`
 	OptIgnoreDisableChangefeedReplication = `ignore_disable_changefeed_replication`
 	OptEncodeJSONValueNullAsObject        = `encode_json_value_null_as_object`
+	OptCatchUpBackupCollection            = `catchup_backup_collection`
+	OptNotify                             = `notify`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
@@ -369,6 +372,8 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptLaggingRangesPollingInterval:       durationOption,
 	OptIgnoreDisableChangefeedReplication: flagOption,
 	OptEncodeJSONValueNullAsObject:        flagOption,
+	OptCatchUpBackupCollection:            stringOption,
+	OptNotify:                             stringOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -383,6 +388,7 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
 	OptIgnoreDisableChangefeedReplication, OptEncodeJSONValueNullAsObject,
+	OptCatchUpBackupCollection, OptNotify,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
@@ -440,6 +446,14 @@ var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptCatchUpBackupCollection: redactExternalStorageURI,
+	OptNotify:                  redactSimple,
+}
+
+// redactExternalStorageURI removes the credentials from an external storage
+// URI.
+func redactExternalStorageURI(uri string) (string, error) {
+	return cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -472,7 +486,7 @@ var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
 // allowed to alter either of these options. We need to support the alteration
 // of these fields.
 var AlterChangefeedUnsupportedOptions OptionsSet = makeStringSet(OptCursor, OptInitialScan,
-	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
+	OptNoInitialScan, OptInitialScanOnly, OptEndTime, OptNotify)
 
 // AlterChangefeedOptionExpectValues is used to parse alter changefeed options
 // using PlanHookState.TypeAsStringOpts().
@@ -523,6 +537,7 @@ func makeDirectedInvertedIndex(pairs []dependentOption) map[string][]dependentOp
 
 var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
 	{opt1: OptUnordered, opt2: OptResolvedTimestamps, reason: `resolved timestamps cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptCatchUpBackupCollection, opt2: OptDiff, reason: `the previous values of rows are not available when catching up from backups`},
 })
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
@@ -967,6 +982,22 @@ func (s StatementOptions) GetMetricScope() (string, bool) {
 	return v, ok
 }
 
+// GetCatchUpBackupCollection returns the URI of the backup collection that the
+// changefeed catches up from when the MVCC history it needs has been garbage
+// collected, or false if none has been provided.
+func (s StatementOptions) GetCatchUpBackupCollection() (string, bool) {
+	v, ok := s.m[OptCatchUpBackupCollection]
+	return v, ok
+}
+
+// GetNotificationURI returns the URI of the channel that notifications about
+// the state changes of the changefeed job are sent to, or false if none has
+// been provided.
+func (s StatementOptions) GetNotificationURI() (string, bool) {
+	v, ok := s.m[OptNotify]
+	return v, ok
+}
+
 // GetLaggingRangesConfig returns the threshold and polling rate to use for
 // lagging ranges metrics.
 func (s StatementOptions) GetLaggingRangesConfig(
@@ -0,0 +1,98 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/errors"
+)
+
+// notificationParamAuthHeader is the query parameter of a webhook
+// notification URI whose value is sent as the Authorization header.
+const notificationParamAuthHeader = `auth_header`
+
+// notificationClientTimeout is the timeout of a single attempt to deliver a
+// notification to a webhook.
+const notificationClientTimeout = 30 * time.Second
+
+func init() {
+	jobs.RegisterNotificationSender(
+		changefeedbase.SinkSchemeWebhookHTTPS, sendWebhookNotification, validateWebhookNotificationURI,
+	)
+}
+
+// validateWebhookNotificationURI checks the parameters of a webhook
+// notification URI, which are the TLS parameters of the webhook sink along
+// with auth_header.
+func validateWebhookNotificationURI(uri string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	u := &changefeedbase.SinkURL{URL: parsed}
+	u.ConsumeParam(notificationParamAuthHeader)
+	client, err := makeWebhookClient(u, notificationClientTimeout, 1 /* parallelism */, nil /* nm */)
+	if err != nil {
+		return err
+	}
+	client.CloseIdleConnections()
+	if unknownParams := u.RemainingQueryParams(); len(unknownParams) > 0 {
+		return errors.Errorf(
+			`unknown webhook notification query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	return nil
+}
+
+// sendWebhookNotification POSTs a job notification to the webhook identified
+// by uri, a webhook-https URI that accepts the same TLS parameters as the
+// webhook sink, along with an auth_header parameter.
+func sendWebhookNotification(ctx context.Context, uri string, body []byte) error {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	u := &changefeedbase.SinkURL{URL: parsed}
+	authHeader := u.ConsumeParam(notificationParamAuthHeader)
+	client, err := makeWebhookClient(u, notificationClientTimeout, 1 /* parallelism */, nil /* nm */)
+	if err != nil {
+		return err
+	}
+	defer client.CloseIdleConnections()
+	u.Scheme = strings.TrimPrefix(u.Scheme, `webhook-`)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", applicationTypeJSON)
+	if authHeader != "" {
+		req.Header.Set(authorizationHeader, authHeader)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if !(res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices) {
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read body for HTTP response with status: %d", res.StatusCode)
+		}
+		return fmt.Errorf("%s: %s", res.Status, string(resBody))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvfeed
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+)
+
+// HistoryReader reads the MVCC history of the watched spans from outside of
+// KV, such as from revision history backups. The kvfeed uses it to catch up
+// when the history that a rangefeed needs for its catch-up scan has been
+// garbage collected.
+type HistoryReader interface {
+	// Bounds returns the timestamps between which the history of all
+	// revisions is available.
+	Bounds(ctx context.Context) (start, end hlc.Timestamp, _ error)
+	// ReadRevisions calls fn for the revisions of the keys in the span with
+	// timestamps in (startTime, endTime]. The revisions of each key are passed
+	// in timestamp order.
+	ReadRevisions(
+		ctx context.Context,
+		span roachpb.Span,
+		startTime, endTime hlc.Timestamp,
+		fn func(key roachpb.Key, value roachpb.Value) error,
+	) error
+	// Close releases the resources held by the HistoryReader.
+	Close(ctx context.Context)
+}
+
+// isGCThresholdError returns true if the error was returned because the
+// requested MVCC history has been garbage collected.
+func isGCThresholdError(err error) bool {
+	return errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil))
+}
+
+// catchUpFromHistory emits the revisions of the watched spans from the resume
+// frontier up to the end of the history of the historyReader, and forwards the
+// frontier accordingly, after which the rangefeeds can be restarted. gcErr is
+// the error that the rangefeed returned because the history that it needed was
+// garbage collected.
+//
+// Like runUntilTableEvent, it stops before the first table event or the end
+// time. If it stopped before a table event, it returns true and the frontier
+// is ts.Prev() where ts is the timestamp of the table event. If it stopped
+// before the end time, it returns an errEndTimeReached.
+func (f *kvFeed) catchUpFromHistory(
+	ctx context.Context, resumeFrontier span.Frontier, gcErr error,
+) (reachedTableEvent bool, _ error) {
+	historyStart, historyEnd, err := f.historyReader.Bounds(ctx)
+	if err != nil {
+		return false, errors.CombineErrors(gcErr, err)
+	}
+	start := resumeFrontier.Frontier()
+	if start.Less(historyStart) || historyEnd.LessEq(start) {
+		return false, errors.Wrapf(gcErr,
+			"cannot catch up from %s using backups with history between %s and %s",
+			start, historyStart, historyEnd)
+	}
+
+	// Find where to stop. Revisions at or before stopAt are emitted.
+	stopAt := historyEnd
+	events, err := f.tableFeed.Peek(ctx, historyEnd)
+	if err != nil {
+		return false, err
+	}
+	if len(events) > 0 {
+		stopAt = events[0].Timestamp().Prev()
+		reachedTableEvent = true
+	}
+	endTimeReached := false
+	if f.endTime.IsSet() && f.endTime.LessEq(stopAt) {
+		stopAt = f.endTime.Prev()
+		reachedTableEvent = false
+		endTimeReached = true
+	}
+	log.Infof(ctx, "catching up from %s to %s using the history from backups", start, stopAt)
+
+	var entries []kvcoord.SpanTimePair
+	resumeFrontier.Entries(func(s roachpb.Span, ts hlc.Timestamp) (done span.OpResult) {
+		if ts.Less(stopAt) {
+			entries = append(entries, kvcoord.SpanTimePair{Span: s, StartAfter: ts})
+		}
+		return span.ContinueMatch
+	})
+	for _, e := range entries {
+		if err := f.historyReader.ReadRevisions(ctx, e.Span, e.StartAfter, stopAt,
+			func(key roachpb.Key, value roachpb.Value) error {
+				return f.writer.Add(ctx, kvevent.MakeKVEvent(&kvpb.RangeFeedEvent{
+					Val: &kvpb.RangeFeedValue{Key: key, Value: value},
+				}))
+			}); err != nil {
+			return false, err
+		}
+		if _, err := resumeFrontier.Forward(e.Span, stopAt); err != nil {
+			return false, err
+		}
+		if err := f.writer.Add(ctx,
+			kvevent.NewBackfillResolvedEvent(e.Span, stopAt, jobspb.ResolvedSpan_NONE)); err != nil {
+			return false, err
+		}
+	}
+
+	if endTimeReached {
+		return false, &errEndTimeReached{endTime: f.endTime}
+	}
+	return reachedTableEvent, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -90,6 +91,17 @@ type Config struct {
 	// enables filtering out any transactional writes with that flag set to true.
 	WithFiltering bool
 
+	// RowFilter, if set, is propagated via the RangefeedRequest to the rangefeed
+	// server, which uses it to drop events for rows the changefeed would
+	// discard. The server may ignore it, so events must still be filtered by
+	// the changefeed.
+	RowFilter *kvpb.RangeFeedRowFilter
+
+	// HistoryReader, if set, is used to catch up when the MVCC history that
+	// the rangefeeds need has been garbage collected. It is closed when the
+	// feed finishes.
+	HistoryReader HistoryReader
+
 	// WithFrontierQuantize specifies the resolved timestamp quantization
 	// granularity. If non-zero, resolved timestamps from rangefeed checkpoint
 	// events will be rounded down to the nearest multiple of the quantization
@@ -107,6 +119,9 @@ type Config struct {
 // Run will run the kvfeed. The feed runs synchronously and returns an
 // error when it finishes.
 func Run(ctx context.Context, cfg Config) error {
+	if cfg.HistoryReader != nil {
+		defer cfg.HistoryReader.Close(ctx)
+	}
 
 	var sc kvScanner
 	{
@@ -139,6 +154,8 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.Targets, cfg.ScopedTimers, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.rowFilter = cfg.RowFilter
+	f.historyReader = cfg.HistoryReader
 	f.rangeObserver = startLaggingRangesObserver(g, cfg.MonitoringCfg.LaggingRangesCallback,
 		cfg.MonitoringCfg.LaggingRangesPollingInterval, cfg.MonitoringCfg.LaggingRangesThreshold)
 
@@ -268,6 +285,8 @@ type kvFeed struct {
 
 	onBackfillCallback func() func()
 	rangeObserver      kvcoord.RangeObserver
+	rowFilter          *kvpb.RangeFeedRowFilter
+	historyReader      HistoryReader
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass
 	schemaChangePolicy changefeedbase.SchemaChangePolicy
 
@@ -379,7 +398,19 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 			return errChangefeedCompleted
 		}
 
-		if err = f.runUntilTableEvent(ctx, rangeFeedResumeFrontier); err != nil {
+		err = f.runUntilTableEvent(ctx, rangeFeedResumeFrontier)
+		for f.historyReader != nil && isGCThresholdError(err) {
+			// The history that the rangefeeds need to catch up has been garbage
+			// collected. Catch up from the history reader instead, and then
+			// restart the rangefeeds from where that history ends.
+			var reachedTableEvent bool
+			reachedTableEvent, err = f.catchUpFromHistory(ctx, rangeFeedResumeFrontier, err)
+			if err != nil || reachedTableEvent {
+				break
+			}
+			err = f.runUntilTableEvent(ctx, rangeFeedResumeFrontier)
+		}
+		if err != nil {
 			if tErr := (*errEndTimeReached)(nil); errors.As(err, &tErr) {
 				if err := emitResolved(rangeFeedResumeFrontier.Frontier(), jobspb.ResolvedSpan_EXIT); err != nil {
 					return err
@@ -612,6 +643,7 @@ func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Fro
 		Frontier:             resumeFrontier.Frontier(),
 		WithDiff:             f.withDiff,
 		WithFiltering:        f.withFiltering,
+		RowFilter:            f.rowFilter,
 		WithFrontierQuantize: f.withFrontierQuantize,
 		ConsumerID:           f.consumerID,
 		Knobs:                f.knobs,
@@ -108,6 +108,8 @@ func TestKVFeed(t *testing.T) {
 		spans                []roachpb.Span
 		checkpoint           []roachpb.Span
 		events               []kvpb.RangeFeedEvent
+		gcThreshold          hlc.Timestamp
+		history              *testHistoryReader
 
 		descs []catalog.TableDescriptor
 
@@ -143,6 +145,19 @@ func TestKVFeed(t *testing.T) {
 			}
 		})
 		ref := rawEventFeed(tc.events)
+		rf := func(
+			ctx context.Context,
+			spans []kvcoord.SpanTimePair,
+			eventC chan<- kvcoord.RangeFeedMessage,
+			opts ...kvcoord.RangeFeedOption,
+		) error {
+			for _, s := range spans {
+				if s.StartAfter.Less(tc.gcThreshold) {
+					return &kvpb.BatchTimestampBeforeGCError{Timestamp: s.StartAfter, Threshold: tc.gcThreshold}
+				}
+			}
+			return ref.run(ctx, spans, eventC, opts...)
+		}
 		tf := newRawTableFeed(tc.descs, tc.initialHighWater)
 		st := timers.New(time.Minute).GetOrCreateScopedTimers("")
 		f := newKVFeed(buf, tc.spans, tc.checkpoint, hlc.Timestamp{},
@@ -151,9 +166,12 @@ func TestKVFeed(t *testing.T) {
 			0, /* consumerID */
 			tc.initialHighWater, tc.endTime,
 			codec,
-			tf, sf, rangefeedFactory(ref.run), bufferFactory,
+			tf, sf, rangefeedFactory(rf), bufferFactory,
 			changefeedbase.Targets{},
 			st, TestingKnobs{})
+		if tc.history != nil {
+			f.historyReader = tc.history
+		}
 		ctx, cancel := context.WithCancel(context.Background())
 		g := ctxgroup.WithContext(ctx)
 		g.GoCtx(func(ctx context.Context) error {
@@ -188,7 +206,7 @@ func TestKVFeed(t *testing.T) {
 		})
 
 		// Wait for the feed to fail rather than canceling it.
-		if tc.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyStop {
+		if tc.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyStop || tc.expErrRE != "" {
 			testG.Go(func() error {
 				_ = g.Wait()
 				return nil
@@ -401,6 +419,52 @@ func TestKVFeed(t *testing.T) {
 			},
 			expEventsCount: 4,
 		},
+		{
+			name:               "gc threshold - catch up from history",
+			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyBackfill,
+			initialHighWater:   ts(2),
+			gcThreshold:        ts(5),
+			history: &testHistoryReader{
+				start: ts(1),
+				end:   ts(6),
+				kvs: []roachpb.KeyValue{
+					kv(codec, 42, "a", "b", ts(2)), // ensure that old revisions are filtered
+					kv(codec, 42, "a", "b", ts(3)),
+					kv(codec, 42, "a", "c", ts(4)),
+				},
+			},
+			spans: []roachpb.Span{
+				tableSpan(codec, 42),
+			},
+			events: []kvpb.RangeFeedEvent{
+				kvEvent(codec, 42, "a", "d", ts(7)),
+				checkpointEvent(tableSpan(codec, 42), ts(8)),
+			},
+			expEvents: []kvpb.RangeFeedEvent{
+				kvEvent(codec, 42, "a", "b", ts(3)),
+				kvEvent(codec, 42, "a", "c", ts(4)),
+				checkpointEvent(tableSpan(codec, 42), ts(6)),
+				kvEvent(codec, 42, "a", "d", ts(7)),
+				checkpointEvent(tableSpan(codec, 42), ts(8)),
+			},
+			expEventsCount: 5,
+		},
+		{
+			name:               "gc threshold - history does not cover frontier",
+			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyBackfill,
+			initialHighWater:   ts(2),
+			gcThreshold:        ts(5),
+			history: &testHistoryReader{
+				start: ts(3),
+				end:   ts(6),
+			},
+			spans: []roachpb.Span{
+				tableSpan(codec, 42),
+			},
+			expErrRE: "cannot catch up from .* using backups",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			runTest(t, tc)
@@ -416,6 +480,38 @@ func (s scannerFunc) Scan(ctx context.Context, sink kvevent.Writer, cfg scanConf
 
 var _ kvScanner = (scannerFunc)(nil)
 
+// testHistoryReader is a HistoryReader over a fixed set of revisions.
+type testHistoryReader struct {
+	start, end hlc.Timestamp
+	kvs        []roachpb.KeyValue
+}
+
+var _ HistoryReader = (*testHistoryReader)(nil)
+
+func (r *testHistoryReader) Bounds(context.Context) (start, end hlc.Timestamp, _ error) {
+	return r.start, r.end, nil
+}
+
+func (r *testHistoryReader) ReadRevisions(
+	ctx context.Context,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+	fn func(key roachpb.Key, value roachpb.Value) error,
+) error {
+	for _, kv := range r.kvs {
+		if !span.ContainsKey(kv.Key) || kv.Value.Timestamp.LessEq(startTime) ||
+			endTime.Less(kv.Value.Timestamp) {
+			continue
+		}
+		if err := fn(kv.Key, kv.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *testHistoryReader) Close(context.Context) {}
+
 type rawTableFeed struct {
 	events []schemafeed.TableEvent
 }
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -763,3 +764,27 @@ func TestWebhookSinkRetry(t *testing.T) {
 	sinkDest.Close()
 	require.NoError(t, sinkSrc.Close())
 }
+
+func TestWebhookNotificationURIValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		uri string
+		err string
+	}{
+		{uri: `webhook-https://example.com/hook?auth_header=Bearer%20x&insecure_tls_skip_verify=true`},
+		{uri: `webhook-https://example.com/hook?foo=bar`, err: `unknown webhook notification query parameters: foo`},
+		{uri: `webhook-https://example.com/hook?ca_cert=!!!`, err: `ca_cert`},
+		{uri: `webhook-https:///hook`, err: `has no host`},
+		{uri: `kafka://example.com`, err: `unsupported notification URI scheme "kafka"`},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			err := jobs.ValidateNotificationURI(tc.uri)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
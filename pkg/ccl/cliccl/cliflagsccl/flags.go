@@ -26,11 +26,18 @@ Valid fields:
 * key     (required): path to the current key file, or "plain"
 * old-key (required): path to the previous key file, or "plain"
 * rotation-period   : amount of time after which data keys should be rotated
+* kms               : URI of the KMS key (e.g. aws-kms, gcp-kms, azure-kms)
+                      wrapping the current key. If the key file does not
+                      exist, a new store key is generated and wrapped with
+                      this KMS key. filekms:///<path> wraps the key with an
+                      AES-256 key read from a local file, for testing
+* old-kms           : URI of the KMS key wrapping the previous key
 
 </PRE>
-example:
+examples:
 <PRE>
-  --enterprise-encryption=path=cockroach-data,key=/keys/aes-128.key,old-key=plain</PRE>
+  --enterprise-encryption=path=cockroach-data,key=/keys/aes-128.key,old-key=plain
+  --enterprise-encryption=path=cockroach-data,key=/keys/v2.wrapped,old-key=/keys/v1.wrapped,kms=aws-kms:///v2?AUTH=implicit&REGION=us-east-1,old-kms=aws-kms:///v1?AUTH=implicit&REGION=us-east-1</PRE>
 `,
 	}
 )
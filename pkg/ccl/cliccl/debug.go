@@ -198,6 +198,7 @@ type PrettyStoreKey struct {
 	Type     string
 	Created  JSONTime
 	Source   string
+	KMSKeyID string          `json:",omitempty"`
 	Files    []string        `json:",omitempty"`
 	DataKeys []PrettyDataKey `json:",omitempty"`
 }
@@ -277,11 +278,12 @@ func runEncryptionStatus(cmd *cobra.Command, args []string) error {
 	sort.Sort(storeKeyList)
 	for _, storeKey := range storeKeyList {
 		storeNode := PrettyStoreKey{
-			ID:      storeKey.KeyId,
-			Active:  (storeKey.KeyId == keyRegistry.ActiveStoreKeyId),
-			Type:    storeKey.EncryptionType.String(),
-			Created: JSONTime(timeutil.Unix(storeKey.CreationTime, 0)),
-			Source:  storeKey.Source,
+			ID:       storeKey.KeyId,
+			Active:   (storeKey.KeyId == keyRegistry.ActiveStoreKeyId),
+			Type:     storeKey.EncryptionType.String(),
+			Created:  JSONTime(timeutil.Unix(storeKey.CreationTime, 0)),
+			Source:   storeKey.Source,
+			KMSKeyID: storeKey.KmsKeyId,
 		}
 
 		// Files encrypted by the store key. This should only be the data key registry.
@@ -9,8 +9,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -306,6 +311,10 @@ func newEncryptedEnv(
 		fs:                unencryptedFS,
 		activeKeyFilename: options.KeyFiles.CurrentKey,
 		oldKeyFilename:    options.KeyFiles.OldKey,
+		activeKeyKMSURI:   options.KeyFiles.CurrentKeyKmsUri,
+		oldKeyKMSURI:      options.KeyFiles.OldKeyKmsUri,
+		kmsEnv:            makeStoreKMSEnv(),
+		readOnly:          readOnly,
 	}
 	if err := storeKeyManager.Load(context.TODO()); err != nil {
 		return nil, err
@@ -356,6 +365,43 @@ func newEncryptedEnv(
 	}, nil
 }
 
+// storeKMSEnv is the cloud.KMSEnv used to unwrap store keys. Store keys are
+// loaded when opening the engine, before the node has joined the cluster, so
+// the environment uses default settings and has no database handle.
+type storeKMSEnv struct {
+	settings *cluster.Settings
+	conf     *base.ExternalIODirConfig
+}
+
+var _ cloud.KMSEnv = &storeKMSEnv{}
+
+func makeStoreKMSEnv() *storeKMSEnv {
+	return &storeKMSEnv{
+		settings: cluster.MakeClusterSettings(),
+		conf:     &base.ExternalIODirConfig{},
+	}
+}
+
+// ClusterSettings implements cloud.KMSEnv.
+func (e *storeKMSEnv) ClusterSettings() *cluster.Settings {
+	return e.settings
+}
+
+// KMSConfig implements cloud.KMSEnv.
+func (e *storeKMSEnv) KMSConfig() *base.ExternalIODirConfig {
+	return e.conf
+}
+
+// DBHandle implements cloud.KMSEnv.
+func (e *storeKMSEnv) DBHandle() isql.DB {
+	return nil
+}
+
+// User implements cloud.KMSEnv.
+func (e *storeKMSEnv) User() username.SQLUsername {
+	return username.NodeUserName()
+}
+
 func canRegistryElide(entry *enginepb.FileEntry) bool {
 	if entry == nil {
 		return true
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -82,6 +83,16 @@ type StoreKeyManager struct {
 	fs                vfs.FS
 	activeKeyFilename string
 	oldKeyFilename    string
+	// If set, the corresponding key file holds a store key wrapped by the KMS
+	// key with this URI.
+	activeKeyKMSURI string
+	oldKeyKMSURI    string
+	// kmsEnv is the environment used to open KMS connections. Only required
+	// if a KMS URI is set.
+	kmsEnv cloud.KMSEnv
+	// If readOnly is false and the active key is wrapped by a KMS, a missing
+	// active key file is populated with a newly generated store key.
+	readOnly bool
 
 	// Implementation. Both are not nil after a successful call to Load().
 	activeKey *enginepbccl.SecretKey
@@ -91,11 +102,11 @@ type StoreKeyManager struct {
 // Load must be called before calling other functions.
 func (m *StoreKeyManager) Load(ctx context.Context) error {
 	var err error
-	m.activeKey, err = LoadKeyFromFile(m.fs, m.activeKeyFilename)
+	m.activeKey, err = m.loadKey(ctx, m.activeKeyFilename, m.activeKeyKMSURI, !m.readOnly)
 	if err != nil {
 		return err
 	}
-	m.oldKey, err = LoadKeyFromFile(m.fs, m.oldKeyFilename)
+	m.oldKey, err = m.loadKey(ctx, m.oldKeyFilename, m.oldKeyKMSURI, false /* create */)
 	if err != nil {
 		return err
 	}
@@ -128,28 +139,125 @@ func (m *StoreKeyManager) GetKey(id string) (*enginepbccl.SecretKey, error) {
 	return nil, fmt.Errorf("store key ID %s was not found", id)
 }
 
+// loadKey loads the store key in the given file, unwrapping it with the KMS
+// key at kmsURI if set.
+func (m *StoreKeyManager) loadKey(
+	ctx context.Context, filename, kmsURI string, create bool,
+) (*enginepbccl.SecretKey, error) {
+	if kmsURI == "" {
+		return LoadKeyFromFile(m.fs, filename)
+	}
+	if m.kmsEnv == nil {
+		return nil, errors.AssertionFailedf("no KMS environment to load store key %s", filename)
+	}
+	kms, err := cloud.KMSFromURI(ctx, kmsURI, m.kmsEnv)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening KMS for store key %s", filename)
+	}
+	defer func() {
+		if err := kms.Close(); err != nil {
+			log.Warningf(ctx, "failed to close KMS for store key %s: %v", filename, err)
+		}
+	}()
+	return LoadKeyFromKMS(ctx, m.fs, filename, kms, create)
+}
+
 // LoadKeyFromFile reads a secret key from the given file.
 func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepbccl.SecretKey, error) {
-	now := kmTimeNow().Unix()
-	key := &enginepbccl.SecretKey{}
-	key.Info = &enginepbccl.KeyInfo{}
 	if filename == storeFileNamePlain {
+		key := &enginepbccl.SecretKey{}
+		key.Info = &enginepbccl.KeyInfo{}
 		key.Info.EncryptionType = enginepbccl.EncryptionType_Plaintext
 		key.Info.KeyId = plainKeyID
-		key.Info.CreationTime = now
+		key.Info.CreationTime = kmTimeNow().Unix()
 		key.Info.Source = storeFileNamePlain
 		return key, nil
 	}
 
-	f, err := fs.Open(filename)
+	b, err := readKeyFile(fs, filename)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-	b, err := io.ReadAll(f)
+	return parseKey(b, filename)
+}
+
+// LoadKeyFromKMS reads a secret key wrapped by the given KMS from the given
+// file. The file contains the ciphertext of the KMS encrypting a key file in
+// any of the formats accepted by LoadKeyFromFile.
+//
+// If create is true and the file does not exist, a new AES-256 key is
+// generated, wrapped by the KMS and written to the file. Rotating the KMS key
+// (or its version) is thus done by pointing the active key at the new KMS key
+// and a new key file, and the old key at the previous ones.
+func LoadKeyFromKMS(
+	ctx context.Context, fs vfs.FS, filename string, kms cloud.KMS, create bool,
+) (*enginepbccl.SecretKey, error) {
+	if filename == storeFileNamePlain {
+		return nil, errors.Newf("store key wrapped by KMS %s cannot be plaintext", kms.MasterKeyID())
+	}
+	var plaintext []byte
+	ciphertext, err := readKeyFile(fs, filename)
+	switch {
+	case err == nil:
+		plaintext, err = kms.Decrypt(ctx, ciphertext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unwrapping store key %s with KMS %s", filename, kms.MasterKeyID())
+		}
+	case oserror.IsNotExist(err) && create:
+		plaintext, err = generateStoreKeyFile()
+		if err != nil {
+			return nil, err
+		}
+		ciphertext, err = kms.Encrypt(ctx, plaintext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "wrapping new store key %s with KMS %s", filename, kms.MasterKeyID())
+		}
+		if err := writeKeyFile(fs, filename, ciphertext); err != nil {
+			return nil, err
+		}
+		log.Infof(ctx, "generated new store key %s wrapped by KMS %s", filename, kms.MasterKeyID())
+	default:
+		return nil, err
+	}
+	key, err := parseKey(plaintext, filename)
 	if err != nil {
 		return nil, err
 	}
+	key.Info.KmsKeyId = kms.MasterKeyID()
+	return key, nil
+}
+
+// generateStoreKeyFile returns the contents of a new, old-style AES-256 key
+// file.
+func generateStoreKeyFile() ([]byte, error) {
+	b := make([]byte, keyIDLength+32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, errors.Wrap(err, "generating store key")
+	}
+	return b, nil
+}
+
+func readKeyFile(fs vfs.FS, filename string) ([]byte, error) {
+	f, err := fs.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// writeKeyFile writes a key file through a temporary file and syncs its
+// directory, so that a crash can't leave a torn or missing key file behind.
+func writeKeyFile(vfsFS vfs.FS, filename string, b []byte) error {
+	return fs.SafeWriteToFile(vfsFS, vfsFS.PathDir(filename), filename, b, fs.UnspecifiedWriteCategory)
+}
+
+// parseKey parses the contents of a key file. Source is recorded in the
+// returned key's info.
+func parseKey(b []byte, source string) (*enginepbccl.SecretKey, error) {
+	var err error
+	key := &enginepbccl.SecretKey{}
+	key.Info = &enginepbccl.KeyInfo{}
 
 	// We support two file formats:
 	// - Old-style keys are just raw random data with no delimiters; the only
@@ -198,8 +306,8 @@ func LoadKeyFromFile(fs vfs.FS, filename string) (*enginepbccl.SecretKey, error)
 		// Hex encoding to make it human readable.
 		key.Info.KeyId = hex.EncodeToString(b[:keyIDLength])
 	}
-	key.Info.CreationTime = now
-	key.Info.Source = filename
+	key.Info.CreationTime = kmTimeNow().Unix()
+	key.Info.Source = source
 
 	return key, nil
 }
@@ -8,9 +8,11 @@ package engineccl
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors/oserror"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/pebble/vfs/atomicfs"
 	"github.com/gogo/protobuf/proto"
@@ -590,3 +593,97 @@ func TestDataKeyManagerBlockedWriteAllowsRead(t *testing.T) {
 	fs.WaitForBlockAndUnblock()
 	require.NoError(t, dkm.Close())
 }
+
+func TestStoreKeyManagerKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	masterKeyURI := func(name string) string {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); oserror.IsNotExist(err) {
+			key := make([]byte, 32)
+			_, err := rand.Read(key)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, key, 0600))
+		}
+		return "filekms://" + filepath.ToSlash(path)
+	}
+	v1URI, v2URI := masterKeyURI("v1.key"), masterKeyURI("v2.key")
+
+	// The paths of the key files are absolute, since the directory of the
+	// generated key files is synced.
+	memFS := vfs.NewMem()
+	load := func(
+		activeFile, activeURI, oldFile, oldURI string, readOnly bool,
+	) (*StoreKeyManager, error) {
+		skm := &StoreKeyManager{
+			fs:                memFS,
+			activeKeyFilename: activeFile,
+			oldKeyFilename:    oldFile,
+			activeKeyKMSURI:   activeURI,
+			oldKeyKMSURI:      oldURI,
+			kmsEnv:            makeStoreKMSEnv(),
+			readOnly:          readOnly,
+		}
+		return skm, skm.Load(ctx)
+	}
+
+	// A missing wrapped key is not generated in read-only mode.
+	_, err := load("/v1.wrapped", v1URI, "plain", "", true /* readOnly */)
+	require.True(t, oserror.IsNotExist(err), "%v", err)
+
+	// Otherwise, a new store key is generated and wrapped.
+	skm, err := load("/v1.wrapped", v1URI, "plain", "", false /* readOnly */)
+	require.NoError(t, err)
+	v1Key, err := skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	require.Equal(t, enginepbccl.EncryptionType_AES256_CTR, v1Key.Info.EncryptionType)
+	require.Equal(t, filepath.Join(dir, "v1.key"), v1Key.Info.KmsKeyId)
+	require.Equal(t, "/v1.wrapped", v1Key.Info.Source)
+
+	// The wrapped key file doesn't contain the raw key.
+	b, err := readKeyFile(memFS, "/v1.wrapped")
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(b, v1Key.Key))
+
+	// Reloading unwraps the same key.
+	skm, err = load("/v1.wrapped", v1URI, "plain", "", true /* readOnly */)
+	require.NoError(t, err)
+	require.Equal(t, v1Key.Key, skm.activeKey.Key)
+	require.Equal(t, v1Key.Info.KeyId, skm.activeKey.Info.KeyId)
+
+	// The key can't be unwrapped by another KMS key.
+	_, err = load("/v1.wrapped", v2URI, "plain", "", true /* readOnly */)
+	require.Error(t, err)
+
+	// Rotate to a new KMS key.
+	skm, err = load("/v2.wrapped", v2URI, "/v1.wrapped", v1URI, false /* readOnly */)
+	require.NoError(t, err)
+	v2Key, err := skm.ActiveKeyForWriter(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, v1Key.Info.KeyId, v2Key.Info.KeyId)
+	require.Equal(t, filepath.Join(dir, "v2.key"), v2Key.Info.KmsKeyId)
+	key, err := skm.GetKey(v1Key.Info.KeyId)
+	require.NoError(t, err)
+	require.Equal(t, v1Key.Key, key.Key)
+	require.Equal(t, filepath.Join(dir, "v1.key"), key.Info.KmsKeyId)
+
+	// Wrapped keys may also be provided by the operator, and mixed with plain
+	// key files.
+	writeToFile(t, memFS, "/32.key", []byte(keyFile256))
+	kms, err := makeFileKMS(ctx, v1URI, makeStoreKMSEnv())
+	require.NoError(t, err)
+	// File KMS keys can't be used outside of stores.
+	_, err = makeFileKMS(ctx, v1URI, nil /* env */)
+	require.ErrorContains(t, err, "can only wrap store keys")
+	wrapped, err := kms.Encrypt(ctx, []byte(keyFile256))
+	require.NoError(t, err)
+	writeToFile(t, memFS, "/32.wrapped", wrapped)
+	skm, err = load("/32.wrapped", v1URI, "/32.key", "", true /* readOnly */)
+	require.NoError(t, err)
+	require.Equal(t, keyID256, skm.activeKey.Info.KeyId)
+	require.Equal(t, key256, string(skm.activeKey.Key))
+	require.Equal(t, keyID256, skm.oldKey.Info.KeyId)
+	require.Empty(t, skm.oldKey.Info.KmsKeyId)
+}
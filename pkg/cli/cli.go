@@ -257,6 +257,8 @@ func init() {
 		nodeLocalCmd,
 		userFileCmd,
 		importCmd,
+		sqlDumpCmd,
+		schemaCmd,
 
 		// Miscellaneous commands.
 		// TODO(pmattis): stats
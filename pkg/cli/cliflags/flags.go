@@ -310,6 +310,35 @@ as the timestamp type.`,
 Dumps all databases, for each non-system database provides dump of all available tables.`,
 	}
 
+	DumpOutputDir = FlagInfo{
+		Name: "output-dir",
+		Description: `
+Writes the dump to files in the specified directory, one per table, instead of
+the standard output.`,
+	}
+
+	DumpConcurrency = FlagInfo{
+		Name: "concurrency",
+		Description: `
+The maximum number of tables to dump simultaneously when --output-dir is
+specified.`,
+	}
+
+	SchemaDiffFrom = FlagInfo{
+		Name: "from",
+		Description: `
+The schema to migrate from: a connection URL, or the path of a file or of a
+directory of .sql files containing DDL statements. Defaults to the database
+selected by the connection flags.`,
+	}
+
+	SchemaDiffTo = FlagInfo{
+		Name: "to",
+		Description: `
+The schema to migrate to: a connection URL, or the path of a file or of a
+directory of .sql files containing DDL statements.`,
+	}
+
 	Execute = FlagInfo{
 		Name:      "execute",
 		Shorthand: "e",
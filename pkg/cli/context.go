@@ -49,6 +49,7 @@ func initCLIDefaults() {
 	setSQLContextDefaults()
 	setZipContextDefaults()
 	setDumpContextDefaults()
+	setSchemaDiffContextDefaults()
 	setDebugContextDefaults()
 	setStartContextDefaults()
 	setDrainContextDefaults()
@@ -414,6 +415,14 @@ var dumpCtx struct {
 
 	// dumpAll determines whenever we going to dump all databases
 	dumpAll bool
+
+	// outputDir, if set, is the directory that the dump is written to, one
+	// file per table, instead of the standard output.
+	outputDir string
+
+	// concurrency is the number of tables that are dumped in parallel when
+	// outputDir is set.
+	concurrency int
 }
 
 // setDumpContextDefaults set the default values in dumpCtx.  This
@@ -423,6 +432,25 @@ func setDumpContextDefaults() {
 	dumpCtx.dumpMode = dumpBoth
 	dumpCtx.asOf = ""
 	dumpCtx.dumpAll = false
+	dumpCtx.outputDir = ""
+	dumpCtx.concurrency = 4
+}
+
+// schemaDiffCtx captures the command-line parameters of the `schema diff`
+// command. See below for defaults.
+var schemaDiffCtx struct {
+	// from and to are the schemas that are compared, each a connection URL or
+	// the path of DDL files.
+	from string
+	to   string
+}
+
+// setSchemaDiffContextDefaults sets the default values in schemaDiffCtx.
+// This function is called by initCLIDefaults() and thus re-called in every
+// test that exercises command-line parsing.
+func setSchemaDiffContextDefaults() {
+	schemaDiffCtx.from = ""
+	schemaDiffCtx.to = ""
 }
 
 // authCtx captures the command-line parameters of the `auth-session`
@@ -1428,7 +1428,8 @@ func (m lockValueFormatter) Format(f fmt.State, c rune) {
 var pebbleToolFS = &autoDecryptFS{}
 
 func init() {
-	debugZipCmd.AddCommand(debugZipUploadCmd)
+	debugZipCmd.AddCommand(debugZipUploadCmd, debugZipAnalyzeCmd)
+	debugTimeSeriesDumpCmd.AddCommand(debugTimeSeriesServeCmd)
 	DebugCmd.AddCommand(debugCmds...)
 
 	// Note: we hook up FormatValue here in order to avoid a circular dependency
@@ -1570,6 +1571,10 @@ func init() {
 	f.StringSliceVar(&debugMergeLogsOpts.tenantIDsFilter, "tenant-ids", nil,
 		"tenant IDs to filter logs by")
 
+	f = debugZipAnalyzeCmd.Flags()
+	f.StringVar(&debugZipAnalyzeOpts.format, "format", debugZipAnalyzeOpts.format,
+		"output format of the report (text, json)")
+
 	f = debugZipUploadCmd.Flags()
 	f.StringVar(&debugZipUploadOpts.ddAPIKey, "dd-api-key", getEnvOrDefault(datadogAPIKeyEnvVar, ""),
 		"Datadog API key to use to send debug.zip artifacts to datadog")
@@ -1632,6 +1637,10 @@ func init() {
 	f.StringVar(&debugTimeSeriesDumpOpts.organizationName, "org-name", "", "organization name to use in datadog upload")
 	f.StringVar(&debugTimeSeriesDumpOpts.userName, "user-name", "", "name of the user to perform datadog upload")
 
+	f = debugTimeSeriesServeCmd.Flags()
+	f.StringVar(&debugTimeSeriesServeOpts.file, "file", "", "raw tsdump to serve, as created with --format=raw")
+	f.StringVar(&debugTimeSeriesServeOpts.addr, "addr", debugTimeSeriesServeOpts.addr, "address to serve the Prometheus query API on")
+
 	f = debugSendKVBatchCmd.Flags()
 	f.StringVar(&debugSendKVBatchContext.traceFormat, "trace", debugSendKVBatchContext.traceFormat,
 		"which format to use for the trace output (off, text, jaeger)")
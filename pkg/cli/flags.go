@@ -675,6 +675,8 @@ func init() {
 	clientCmds = append(clientCmds, nodeCmds...)
 	clientCmds = append(clientCmds, nodeLocalCmds...)
 	clientCmds = append(clientCmds, importCmds...)
+	clientCmds = append(clientCmds, sqlDumpCmd)
+	clientCmds = append(clientCmds, schemaDiffCmd)
 	clientCmds = append(clientCmds, userFileCmds...)
 	clientCmds = append(clientCmds, stmtDiagCmds...)
 	clientCmds = append(clientCmds, debugResetQuorumCmd)
@@ -792,6 +794,8 @@ func init() {
 	sqlCmds = append(sqlCmds, stmtDiagCmds...)
 	sqlCmds = append(sqlCmds, nodeLocalCmds...)
 	sqlCmds = append(sqlCmds, importCmds...)
+	sqlCmds = append(sqlCmds, sqlDumpCmd)
+	sqlCmds = append(sqlCmds, schemaDiffCmd)
 	sqlCmds = append(sqlCmds, userFileCmds...)
 	for _, cmd := range sqlCmds {
 		clientflags.AddSQLFlags(cmd, &cliCtx.clientOpts, sqlCtx,
@@ -928,6 +932,23 @@ func init() {
 		cliflagcfg.StringFlag(t, &cliCtx.clientOpts.Database, cliflags.Database)
 	}
 
+	// sql-dump command.
+	{
+		f := sqlDumpCmd.Flags()
+		cliflagcfg.VarFlag(f, &dumpCtx.dumpMode, cliflags.DumpMode)
+		cliflagcfg.StringFlag(f, &dumpCtx.asOf, cliflags.ReadTime)
+		cliflagcfg.BoolFlag(f, &dumpCtx.dumpAll, cliflags.DumpAll)
+		cliflagcfg.StringFlag(f, &dumpCtx.outputDir, cliflags.DumpOutputDir)
+		cliflagcfg.IntFlag(f, &dumpCtx.concurrency, cliflags.DumpConcurrency)
+	}
+
+	// schema diff command.
+	{
+		f := schemaDiffCmd.Flags()
+		cliflagcfg.StringFlag(f, &schemaDiffCtx.from, cliflags.SchemaDiffFrom)
+		cliflagcfg.StringFlag(f, &schemaDiffCtx.to, cliflags.SchemaDiffTo)
+	}
+
 	// sqlfmt command.
 	{
 		f := sqlfmtCmd.Flags()
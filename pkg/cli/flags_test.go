@@ -1326,6 +1326,8 @@ Available Commands:
   nodelocal         upload and delete nodelocal files
   userfile          upload, list and delete user scoped files
   import            import a db or table from a local PGDUMP or MYSQLDUMP file
+  sql-dump          dump the schema and data of a database as SQL statements
+  schema            compare the schemas of databases
   demo              open a demo sql shell
   convert-url       convert a SQL connection string for use with various client drivers
   gen               generate auxiliary files
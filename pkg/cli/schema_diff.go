@@ -0,0 +1,1566 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlclient"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [command]",
+	Short: "compare the schemas of databases",
+	Long:  "Compare the schemas of databases and generate migrations between them.",
+	RunE:  UsageAndErr,
+}
+
+var schemaDiffCmd = &cobra.Command{
+	Use:   "diff [--from <url|file>] --to <url|file>",
+	Short: "generate the DDL that migrates a schema to another",
+	Long: `
+Compares the schema of the database specified by --from with the schema
+specified by --to, and prints the DDL statements that migrate the former to
+the latter.
+
+Each of --from and --to is either a connection URL, whose database is
+compared, or the path of a file or of a directory of .sql files containing DDL
+statements. If --from is omitted, the database selected by the connection
+flags is compared. Databases are read from the catalog as of a single point
+in time; files are parsed without being executed.
+
+The comparison covers schemas, types, sequences, tables with their columns,
+indexes and constraints, views, functions, procedures, zone configurations
+and row-level security policies. The statements are ordered such that the
+objects they depend on exist when they run, and each of them can be run by
+the declarative schema changer in its own transaction.
+
+Definitions are compared after normalizing how the cluster formats them, but
+a definition that is written differently from how the cluster stores it, such
+as a view whose query does not qualify its columns, is reported as changed
+and replaced. Column families, partitioning, storage parameters, comments and
+privileges are not compared. The expressions of row-level security policies
+are not stored in the catalog, so they are only compared between files.
+`,
+	Args: cobra.NoArgs,
+	RunE: clierrorplus.MaybeShoutError(runSchemaDiff),
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaDiffCmd)
+}
+
+func runSchemaDiff(cmd *cobra.Command, args []string) error {
+	if schemaDiffCtx.to == "" {
+		return errors.New("--to must be specified")
+	}
+	ctx := context.Background()
+	from, err := readSchemaSnapshot(ctx, schemaDiffCtx.from)
+	if err != nil {
+		return errors.Wrap(err, "reading the --from schema")
+	}
+	to, err := readSchemaSnapshot(ctx, schemaDiffCtx.to)
+	if err != nil {
+		return errors.Wrap(err, "reading the --to schema")
+	}
+	for _, stmt := range diffSchemas(from, to) {
+		fmt.Printf("%s;\n", stmt)
+	}
+	return nil
+}
+
+// readSchemaSnapshot reads the schema specified by source: a connection URL,
+// the path of DDL files or, if empty, the database of the connection flags.
+func readSchemaSnapshot(ctx context.Context, source string) (_ *schemaSnapshot, resErr error) {
+	if source != "" && !strings.Contains(source, "://") {
+		return readSchemaFiles(source)
+	}
+	var conn clisqlclient.Conn
+	var err error
+	if source == "" {
+		conn, err = makeSQLClient(ctx, "cockroach schema diff", useDefaultDb)
+	} else {
+		conn, err = sqlCtx.MakeConn(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { resErr = errors.CombineErrors(resErr, conn.Close()) }()
+	if err := conn.EnsureConn(ctx); err != nil {
+		return nil, err
+	}
+	return readLiveSchema(ctx, conn)
+}
+
+// readSchemaFiles parses the DDL statements of the file at path or, if path is
+// a directory, of its .sql files in lexical order. It returns an error if the
+// files do not define any object, since comparing with an empty schema would
+// drop everything.
+func readSchemaFiles(path string) (*schemaSnapshot, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.sql")); err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, errors.Newf("no .sql files found in %s", path)
+		}
+	}
+	s := newSchemaSnapshot("" /* database */)
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.parse(string(sql)); err != nil {
+			return nil, errors.Wrapf(err, "%s", file)
+		}
+	}
+	if s.empty() {
+		return nil, errors.Newf("no schema objects defined in %s", path)
+	}
+	s.finish()
+	return s, nil
+}
+
+// readLiveSchema reads the schema of the current database of conn from the
+// catalog, as of a single point in time.
+func readLiveSchema(ctx context.Context, conn clisqlclient.Conn) (*schemaSnapshot, error) {
+	vals, err := conn.QueryRow(ctx,
+		`SELECT current_database(), cluster_logical_timestamp()::STRING`)
+	if err != nil {
+		return nil, err
+	}
+	db, _ := vals[0].(string)
+	if db == "" {
+		return nil, errors.New("no database specified")
+	}
+	asOf, ok := vals[1].(string)
+	if !ok {
+		return nil, errors.Newf("unexpected cluster timestamp %v", vals[1])
+	}
+	d := &sqlDumper{conn: conn, db: db, asOf: asOf}
+	s := newSchemaSnapshot(db)
+	parse := func(vals []driver.Value) error {
+		return s.parse(vals[0].(string))
+	}
+	// The statements are read in the order in which they can be replayed: the
+	// foreign keys are added once all the tables exist.
+	for _, query := range []string{
+		`SELECT create_statement FROM %s.crdb_internal.create_schema_statements %s
+ WHERE database_name = $1 AND schema_name != 'public' ORDER BY descriptor_id`,
+		`SELECT create_statement FROM %s.crdb_internal.create_type_statements %s
+ WHERE database_name = $1 ORDER BY descriptor_id`,
+		`SELECT create_nofks FROM %s.crdb_internal.create_statements %s
+ WHERE database_name = $1 AND NOT is_virtual AND NOT is_temporary ORDER BY descriptor_id`,
+		`SELECT unnest(alter_statements) FROM %s.crdb_internal.create_statements %s
+ WHERE database_name = $1 AND NOT is_virtual AND NOT is_temporary ORDER BY descriptor_id`,
+		`SELECT create_statement FROM %s.crdb_internal.create_function_statements %s
+ WHERE database_name = $1 ORDER BY function_id`,
+		`SELECT create_statement FROM %s.crdb_internal.create_procedure_statements %s
+ WHERE database_name = $1 ORDER BY procedure_id`,
+		`SELECT raw_config_sql FROM %s.crdb_internal.zones %s
+ WHERE database_name = $1 AND raw_config_sql IS NOT NULL ORDER BY zone_id, subzone_id`,
+	} {
+		if err := d.query(ctx, conn,
+			fmt.Sprintf(query, lexbase.EscapeSQLIdent(db), d.aost()), parse, db); err != nil {
+			return nil, err
+		}
+	}
+
+	// The policies are only stored in the table descriptors.
+	if err := d.query(ctx, conn, fmt.Sprintf(`
+SELECT t.schema_name, t.name, p.policy::STRING
+  FROM %s.crdb_internal.tables AS t
+  JOIN system.descriptor AS d ON d.id = t.table_id,
+       jsonb_array_elements(crdb_internal.pb_to_json(
+         'cockroach.sql.sqlbase.Descriptor', d.descriptor)->'table'->'policies') AS p (policy)
+       %s
+ WHERE t.database_name = $1 AND t.drop_time IS NULL
+ ORDER BY t.table_id`, lexbase.EscapeSQLIdent(db), d.aost()),
+		func(vals []driver.Value) error {
+			var p struct {
+				Name      string   `json:"name"`
+				Type      string   `json:"type"`
+				Command   string   `json:"command"`
+				RoleNames []string `json:"roleNames"`
+			}
+			if err := json.Unmarshal([]byte(vals[2].(string)), &p); err != nil {
+				return err
+			}
+			tn := s.objectName(tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+				SchemaName: tree.Name(vals[0].(string)), ExplicitSchema: true,
+			}, tree.Name(vals[1].(string))))
+			n := &tree.CreatePolicy{
+				PolicyName: tree.Name(p.Name),
+				TableName:  tn.ToUnresolvedObjectName(),
+			}
+			if p.Type == tree.PolicyTypeRestrictive.String() {
+				n.Type = tree.PolicyTypeRestrictive
+			}
+			for c := tree.PolicyCommandAll; c <= tree.PolicyCommandDelete; c++ {
+				if p.Command == c.String() {
+					n.Cmd = c
+				}
+			}
+			for _, role := range p.RoleNames {
+				n.Roles = append(n.Roles, tree.MakeRoleSpecWithRoleName(role))
+			}
+			s.addPolicy(n, false /* hasExprs */)
+			return nil
+		}, db); err != nil {
+		return nil, errors.Wrap(err, "reading the row-level security policies")
+	}
+	s.finish()
+	return s, nil
+}
+
+// schemaSnapshot is the schema of a database, read from a cluster or parsed
+// from DDL files. The objects are normalized as they are added, such that the
+// objects of two snapshots compare equal if their descriptors would.
+type schemaSnapshot struct {
+	// database is the name of the database, if known. Names qualified with it
+	// are treated as unqualified.
+	database string
+
+	schemas   []*schemaObject
+	types     []*schemaObject
+	sequences []*schemaObject
+	tables    []*schemaTable
+	views     []*schemaObject
+	routines  []*schemaObject
+	zones     []*schemaZone
+	policies  []*schemaPolicy
+
+	tablesByKey map[string]*schemaTable
+}
+
+func newSchemaSnapshot(database string) *schemaSnapshot {
+	return &schemaSnapshot{
+		database:    database,
+		tablesByKey: make(map[string]*schemaTable),
+	}
+}
+
+// schemaObject is a schema, type, sequence, view or routine.
+type schemaObject struct {
+	key string
+	// stmt is the normalized statement that creates the object, and def is how
+	// it is formatted.
+	stmt tree.Statement
+	def  string
+	// replace, if set, is the statement that replaces another version of the
+	// object with this one.
+	replace string
+}
+
+func newSchemaObject(key string, stmt tree.Statement) *schemaObject {
+	return &schemaObject{key: key, stmt: stmt, def: tree.AsString(stmt)}
+}
+
+// schemaTable is a table, along with its indexes and constraints.
+type schemaTable struct {
+	key        string
+	name       tree.TableName
+	columns    []*tree.ColumnTableDef
+	primaryKey *tree.UniqueConstraintTableDef
+	// implicitRowID is set if the table has no primary key, and thus uses the
+	// hidden rowid column as its primary key.
+	implicitRowID bool
+	indexes       []*tree.CreateIndex
+	// constraints are the check constraints and the unique constraints without
+	// an index.
+	constraints []tree.ConstraintTableDef
+	fks         []*tree.ForeignKeyConstraintTableDef
+}
+
+// schemaZone is the zone configuration of a database, table, index or
+// partition.
+type schemaZone struct {
+	key     string
+	spec    tree.ZoneSpecifier
+	options tree.KVOptions
+}
+
+// schemaPolicy is a row-level security policy.
+type schemaPolicy struct {
+	key  string
+	stmt *tree.CreatePolicy
+	// hasExprs is set if the expressions of the policy are known, which they
+	// are not for the policies read from a cluster.
+	hasExprs bool
+}
+
+// parse adds the objects created by the statements in sql.
+func (s *schemaSnapshot) parse(sql string) error {
+	stmts, err := parser.Parse(sql)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err := s.add(stmt.AST); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add adds the object created or altered by stmt. Statements that do not
+// modify the schema are ignored.
+func (s *schemaSnapshot) add(stmt tree.Statement) error {
+	switch n := stmt.(type) {
+	case *tree.CreateDatabase:
+		if s.database == "" {
+			s.database = string(n.Name)
+		}
+	case *tree.CreateSchema:
+		prefix := tree.ObjectNamePrefix{SchemaName: n.Schema.SchemaName, ExplicitSchema: true}
+		s.schemas = append(s.schemas, newSchemaObject(prefix.Schema(), &tree.CreateSchema{Schema: prefix}))
+	case *tree.CreateType:
+		s.addType(n)
+	case *tree.CreateSequence:
+		tn := s.objectName(n.Name)
+		s.sequences = append(s.sequences, newSchemaObject(tree.AsString(&tn), &tree.CreateSequence{
+			Name:    tn,
+			Options: normalizeSequenceOptions(n.Options),
+		}))
+	case *tree.CreateTable:
+		return s.addTable(n)
+	case *tree.CreateIndex:
+		t, err := s.table(n.Table)
+		if err != nil {
+			return err
+		}
+		t.addIndex(&tree.CreateIndex{
+			Name:         n.Name,
+			Unique:       n.Unique,
+			Inverted:     n.Inverted,
+			Vector:       n.Vector,
+			Columns:      n.Columns,
+			Sharded:      n.Sharded,
+			Storing:      n.Storing,
+			Predicate:    n.Predicate,
+			Invisibility: n.Invisibility,
+		})
+	case *tree.AlterTable:
+		return s.alterTable(n)
+	case *tree.CreateView:
+		s.addView(n)
+	case *tree.CreateRoutine:
+		s.addRoutine(n)
+	case *tree.SetZoneConfig:
+		return s.addZone(n)
+	case *tree.CreatePolicy:
+		s.addPolicy(n, true /* hasExprs */)
+	default:
+		if tree.CanModifySchema(stmt) && !strings.HasPrefix(stmt.StatementTag(), "COMMENT ON") {
+			return errors.Newf("unsupported statement: %s", stmt.StatementTag())
+		}
+	}
+	return nil
+}
+
+// empty returns whether the snapshot does not contain any object.
+func (s *schemaSnapshot) empty() bool {
+	return len(s.schemas) == 0 && len(s.types) == 0 && len(s.sequences) == 0 &&
+		len(s.tables) == 0 && len(s.views) == 0 && len(s.routines) == 0 &&
+		len(s.zones) == 0 && len(s.policies) == 0
+}
+
+// finish resolves the references between the objects once they are all added.
+func (s *schemaSnapshot) finish() {
+	for _, t := range s.tables {
+		for _, fk := range t.fks {
+			if len(fk.ToCols) > 0 {
+				continue
+			}
+			// A foreign key without columns references the primary key.
+			if ref, ok := s.tablesByKey[tree.AsString(&fk.Table)]; ok {
+				for _, elem := range ref.primaryKey.Columns {
+					fk.ToCols = append(fk.ToCols, elem.Column)
+				}
+			}
+		}
+	}
+}
+
+// objectName returns the name of an object qualified by its schema, which
+// defaults to public, and not by its database.
+func (s *schemaSnapshot) objectName(tn tree.TableName) tree.TableName {
+	return tree.MakeTableNameFromPrefix(objectPrefix(tn.ObjectNamePrefix), tn.ObjectName)
+}
+
+func objectPrefix(prefix tree.ObjectNamePrefix) tree.ObjectNamePrefix {
+	schema := tree.Name(catconstants.PublicSchemaName)
+	if prefix.ExplicitSchema {
+		schema = prefix.SchemaName
+	}
+	return tree.ObjectNamePrefix{SchemaName: schema, ExplicitSchema: true}
+}
+
+// typeRef normalizes the name of a user-defined type.
+func (s *schemaSnapshot) typeRef(ref tree.ResolvableTypeReference) tree.ResolvableTypeReference {
+	switch t := ref.(type) {
+	case *tree.UnresolvedObjectName:
+		tn := s.objectName(t.ToTableName())
+		return tn.ToUnresolvedObjectName()
+	case *tree.ArrayTypeReference:
+		return &tree.ArrayTypeReference{ElementType: s.typeRef(t.ElementType)}
+	}
+	return ref
+}
+
+func (s *schemaSnapshot) table(name tree.TableName) (*schemaTable, error) {
+	tn := s.objectName(name)
+	t, ok := s.tablesByKey[tree.AsString(&tn)]
+	if !ok {
+		return nil, errors.Newf("relation %q does not exist", tree.AsString(&tn))
+	}
+	return t, nil
+}
+
+func (s *schemaSnapshot) addType(n *tree.CreateType) {
+	tn := s.objectName(n.TypeName.ToTableName())
+	c := &tree.CreateType{
+		TypeName:   tn.ToUnresolvedObjectName(),
+		Variety:    n.Variety,
+		EnumLabels: n.EnumLabels,
+	}
+	for _, elem := range n.CompositeTypeList {
+		elem.Type = s.typeRef(elem.Type)
+		c.CompositeTypeList = append(c.CompositeTypeList, elem)
+	}
+	s.types = append(s.types, newSchemaObject(tree.AsString(&tn), c))
+}
+
+// normalizeSequenceOptions returns the value of each of the options of a
+// sequence that are compared, defaulted as in CREATE SEQUENCE.
+func normalizeSequenceOptions(opts tree.SequenceOptions) tree.SequenceOptions {
+	increment, cache := int64(1), int64(1)
+	lower, upper := int64(math.MinInt64), int64(math.MaxInt64)
+	var minValue, maxValue, start *int64
+	var virtual bool
+	for _, opt := range opts {
+		switch opt.Name {
+		case tree.SeqOptAs:
+			switch opt.AsIntegerType.Width() {
+			case 16:
+				lower, upper = math.MinInt16, math.MaxInt16
+			case 32:
+				lower, upper = math.MinInt32, math.MaxInt32
+			}
+		case tree.SeqOptIncrement:
+			increment = *opt.IntVal
+		case tree.SeqOptCache:
+			cache = *opt.IntVal
+		case tree.SeqOptMinValue:
+			minValue = opt.IntVal
+		case tree.SeqOptMaxValue:
+			maxValue = opt.IntVal
+		case tree.SeqOptStart:
+			start = opt.IntVal
+		case tree.SeqOptVirtual:
+			virtual = true
+		}
+	}
+	if minValue == nil {
+		v := int64(1)
+		if increment < 0 {
+			v = lower
+		}
+		minValue = &v
+	}
+	if maxValue == nil {
+		v := upper
+		if increment < 0 {
+			v = -1
+		}
+		maxValue = &v
+	}
+	if start == nil {
+		start = minValue
+		if increment < 0 {
+			start = maxValue
+		}
+	}
+	res := tree.SequenceOptions{
+		{Name: tree.SeqOptMinValue, IntVal: minValue},
+		{Name: tree.SeqOptMaxValue, IntVal: maxValue},
+		{Name: tree.SeqOptIncrement, IntVal: &increment},
+		{Name: tree.SeqOptStart, IntVal: start},
+		{Name: tree.SeqOptCache, IntVal: &cache},
+	}
+	if virtual {
+		res = append(res, tree.SequenceOption{Name: tree.SeqOptVirtual})
+	}
+	return res
+}
+
+func (s *schemaSnapshot) addTable(n *tree.CreateTable) error {
+	if n.As() {
+		return errors.Newf("CREATE TABLE ... AS is not supported: %s", tree.AsString(&n.Table))
+	}
+	tn := s.objectName(n.Table)
+	t := &schemaTable{key: tree.AsString(&tn), name: tn}
+	for _, def := range n.Defs {
+		if err := s.addTableDef(t, def); err != nil {
+			return err
+		}
+	}
+	if t.primaryKey == nil {
+		rowID := &tree.ColumnTableDef{Name: "rowid", Type: types.Int, Hidden: true}
+		rowID.Nullable.Nullability = tree.NotNull
+		rowID.DefaultExpr.Expr = uniqueRowIDExpr()
+		t.columns = append(t.columns, rowID)
+		t.setPrimaryKey("", tree.IndexElemList{{Column: rowID.Name}})
+		t.implicitRowID = true
+	} else if cols := t.primaryKey.Columns; len(cols) == 1 && cols[0].Column == "rowid" {
+		// The tables read from a cluster show the rowid column and the primary
+		// key that it implements.
+		if col := t.column("rowid"); col != nil && col.Hidden &&
+			exprString(col.DefaultExpr.Expr) == exprString(uniqueRowIDExpr()) {
+			t.implicitRowID = true
+		}
+	}
+	for _, elem := range t.primaryKey.Columns {
+		if col := t.column(elem.Column); col != nil {
+			col.Nullable.Nullability = tree.NotNull
+		}
+	}
+	s.tables = append(s.tables, t)
+	s.tablesByKey[t.key] = t
+	return nil
+}
+
+func (s *schemaSnapshot) addTableDef(t *schemaTable, def tree.TableDef) error {
+	switch d := def.(type) {
+	case *tree.ColumnTableDef:
+		return s.addColumn(t, d)
+	case *tree.IndexTableDef:
+		t.addIndex(&tree.CreateIndex{
+			Name:         d.Name,
+			Inverted:     d.Inverted,
+			Vector:       d.Vector,
+			Columns:      d.Columns,
+			Sharded:      d.Sharded,
+			Storing:      d.Storing,
+			Predicate:    d.Predicate,
+			Invisibility: d.Invisibility,
+		})
+	case tree.ConstraintTableDef:
+		return s.addConstraint(t, d)
+	case *tree.FamilyTableDef:
+		// Column families are not compared.
+	default:
+		return errors.Newf("unsupported table definition: %s", tree.AsString(def))
+	}
+	return nil
+}
+
+// addColumn adds a column along with the constraints that are defined inline.
+func (s *schemaSnapshot) addColumn(t *schemaTable, d *tree.ColumnTableDef) error {
+	if isShardColumn(d) {
+		// The shard columns of hash-sharded indexes are shown in the tables
+		// read from a cluster, but they are created along with the indexes.
+		return nil
+	}
+	col := &tree.ColumnTableDef{
+		Name:              d.Name,
+		Type:              s.typeRef(d.Type),
+		GeneratedIdentity: d.GeneratedIdentity,
+		Hidden:            d.Hidden,
+		Computed:          d.Computed,
+	}
+	col.Nullable.Nullability = tree.Null
+	if d.Nullable.Nullability == tree.NotNull || d.PrimaryKey.IsPrimaryKey {
+		col.Nullable.Nullability = tree.NotNull
+	}
+	col.DefaultExpr.Expr = normalizeSchemaExpr(d.DefaultExpr.Expr)
+	col.OnUpdateExpr.Expr = normalizeSchemaExpr(d.OnUpdateExpr.Expr)
+	col.Computed.Expr = normalizeSchemaExpr(d.Computed.Expr)
+	if d.IsSerial {
+		// This assumes the default serial_normalization of rowid.
+		col.Type = types.Int
+		col.Nullable.Nullability = tree.NotNull
+		col.DefaultExpr.Expr = uniqueRowIDExpr()
+	}
+	t.columns = append(t.columns, col)
+
+	if d.PrimaryKey.IsPrimaryKey {
+		t.setPrimaryKey("", tree.IndexElemList{{Column: d.Name}})
+	}
+	if d.Unique.IsUnique {
+		if err := s.addConstraint(t, &tree.UniqueConstraintTableDef{
+			IndexTableDef: tree.IndexTableDef{
+				Name:    d.Unique.ConstraintName,
+				Columns: tree.IndexElemList{{Column: d.Name}},
+			},
+			WithoutIndex: d.Unique.WithoutIndex,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, check := range d.CheckExprs {
+		if err := s.addConstraint(t, &tree.CheckConstraintTableDef{
+			Name: check.ConstraintName,
+			Expr: check.Expr,
+		}); err != nil {
+			return err
+		}
+	}
+	if d.References.Table != nil {
+		fk := &tree.ForeignKeyConstraintTableDef{
+			Name:     d.References.ConstraintName,
+			Table:    *d.References.Table,
+			FromCols: tree.NameList{d.Name},
+			Actions:  d.References.Actions,
+			Match:    d.References.Match,
+		}
+		if d.References.Col != "" {
+			fk.ToCols = tree.NameList{d.References.Col}
+		}
+		return s.addConstraint(t, fk)
+	}
+	return nil
+}
+
+func (s *schemaSnapshot) addConstraint(t *schemaTable, def tree.ConstraintTableDef) error {
+	switch d := def.(type) {
+	case *tree.UniqueConstraintTableDef:
+		switch {
+		case d.PrimaryKey:
+			if t.implicitRowID {
+				// The rowid column is dropped along with the primary key that it
+				// implements.
+				t.dropColumn("rowid")
+				t.implicitRowID = false
+			}
+			t.setPrimaryKey(d.Name, d.Columns)
+		case d.WithoutIndex:
+			c := &tree.UniqueConstraintTableDef{
+				IndexTableDef: tree.IndexTableDef{
+					Name:      d.Name,
+					Columns:   normalizeIndexElems(d.Columns),
+					Predicate: normalizeSchemaExpr(d.Predicate),
+				},
+				WithoutIndex: true,
+			}
+			if c.Name == "" {
+				c.Name = t.defaultIndexName(c.Columns, true /* unique */)
+			}
+			t.constraints = append(t.constraints, c)
+		default:
+			t.addIndex(&tree.CreateIndex{
+				Name:         d.Name,
+				Unique:       true,
+				Columns:      d.Columns,
+				Sharded:      d.Sharded,
+				Storing:      d.Storing,
+				Predicate:    d.Predicate,
+				Invisibility: d.Invisibility,
+			})
+		}
+	case *tree.CheckConstraintTableDef:
+		if d.FromHashShardedColumn {
+			return nil
+		}
+		c := &tree.CheckConstraintTableDef{Name: d.Name, Expr: normalizeSchemaExpr(d.Expr)}
+		if c.Name == "" {
+			c.Name = defaultCheckName(c.Expr)
+		}
+		t.constraints = append(t.constraints, c)
+	case *tree.ForeignKeyConstraintTableDef:
+		fk := &tree.ForeignKeyConstraintTableDef{
+			Name:     d.Name,
+			Table:    s.objectName(d.Table),
+			FromCols: d.FromCols,
+			ToCols:   d.ToCols,
+			Actions:  d.Actions,
+			Match:    d.Match,
+		}
+		if fk.Name == "" {
+			// See tabledesc.ForeignKeyConstraintName.
+			cols := make([]string, len(fk.FromCols))
+			for i, col := range fk.FromCols {
+				cols[i] = string(col)
+			}
+			fk.Name = tree.Name(fmt.Sprintf("%s_%s_fkey", t.name.Table(), strings.Join(cols, "_")))
+		}
+		t.fks = append(t.fks, fk)
+	default:
+		return errors.Newf("unsupported constraint: %s", tree.AsString(def))
+	}
+	return nil
+}
+
+func (s *schemaSnapshot) alterTable(n *tree.AlterTable) error {
+	t, err := s.table(n.Table.ToTableName())
+	if err != nil {
+		return err
+	}
+	for _, cmd := range n.Cmds {
+		switch c := cmd.(type) {
+		case *tree.AlterTableAddColumn:
+			err = s.addColumn(t, c.ColumnDef)
+		case *tree.AlterTableAddConstraint:
+			err = s.addConstraint(t, c.ConstraintDef)
+		case *tree.AlterTableValidateConstraint, *tree.AlterTableSetRLSMode:
+			// Neither is recorded in the descriptors.
+		default:
+			err = errors.Newf("unsupported ALTER TABLE command: %s", tree.AsString(cmd))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *schemaSnapshot) addView(n *tree.CreateView) {
+	tn := s.objectName(n.Name)
+	c := &tree.CreateView{
+		Name:         tn,
+		ColumnNames:  n.ColumnNames,
+		AsSource:     n.AsSource,
+		Materialized: n.Materialized,
+		WithData:     n.WithData,
+	}
+	o := &schemaObject{key: tree.AsString(&tn), stmt: c, def: s.formatView(c)}
+	if !c.Materialized {
+		r := *c
+		r.Replace = true
+		o.replace = s.formatView(&r)
+	}
+	s.views = append(s.views, o)
+}
+
+// formatView formats n without qualifying the names of the tables by the
+// database of the snapshot, which the cluster does in the queries of views.
+func (s *schemaSnapshot) formatView(n *tree.CreateView) string {
+	return tree.AsStringWithFlags(n, tree.FmtSimple, tree.FmtReformatTableNames(
+		func(ctx *tree.FmtCtx, tn *tree.TableName) {
+			name := *tn
+			if name.ExplicitCatalog && string(name.CatalogName) == s.database {
+				name.CatalogName = ""
+				name.ExplicitCatalog = false
+			}
+			ctx.WithReformatTableNames(nil, func() {
+				ctx.FormatNode(&name)
+			})
+		}))
+}
+
+func (s *schemaSnapshot) addRoutine(n *tree.CreateRoutine) {
+	c := *n
+	c.Replace = false
+	c.Name = tree.MakeRoutineNameFromPrefix(objectPrefix(n.Name.ObjectNamePrefix), n.Name.ObjectName)
+	c.Params = make(tree.RoutineParams, len(n.Params))
+	var sig []string
+	for i, param := range n.Params {
+		param.Type = s.typeRef(param.Type)
+		c.Params[i] = param
+		if param.Class != tree.RoutineParamOut {
+			sig = append(sig, param.Type.SQLString())
+		}
+	}
+	if n.ReturnType != nil {
+		c.ReturnType = &tree.RoutineReturnType{Type: s.typeRef(n.ReturnType.Type), SetOf: n.ReturnType.SetOf}
+	}
+	o := newSchemaObject(fmt.Sprintf("%s(%s)", tree.AsString(&c.Name), strings.Join(sig, ", ")), &c)
+	r := c
+	r.Replace = true
+	o.replace = tree.AsString(&r)
+	s.routines = append(s.routines, o)
+}
+
+func (s *schemaSnapshot) addZone(n *tree.SetZoneConfig) error {
+	if n.YAMLConfig != nil && n.YAMLConfig != tree.DNull {
+		return errors.New("zone configurations in YAML are not supported")
+	}
+	spec := n.ZoneSpecifier
+	key := "DATABASE"
+	if spec.Database != "" {
+		if s.database == "" {
+			s.database = string(spec.Database)
+		}
+	} else {
+		if spec.TargetsTable() {
+			spec.TableOrIndex.Table = s.objectName(spec.TableOrIndex.Table)
+		}
+		key = tree.AsString(&spec)
+	}
+	if n.Discard || n.YAMLConfig == tree.DNull {
+		for i, z := range s.zones {
+			if z.key == key {
+				s.zones = append(s.zones[:i], s.zones[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+	var z *schemaZone
+	for _, existing := range s.zones {
+		if existing.key == key {
+			z = existing
+		}
+	}
+	if z == nil {
+		z = &schemaZone{key: key, spec: spec}
+		s.zones = append(s.zones, z)
+	}
+	if n.SetDefault {
+		z.options = nil
+	}
+	for _, opt := range n.Options {
+		options := z.options[:0:0]
+		for _, existing := range z.options {
+			if existing.Key != opt.Key {
+				options = append(options, existing)
+			}
+		}
+		// A value of COPY FROM PARENT removes the option.
+		if opt.Value != nil {
+			options = append(options, opt)
+		}
+		z.options = options
+	}
+	return nil
+}
+
+func (s *schemaSnapshot) addPolicy(n *tree.CreatePolicy, hasExprs bool) {
+	tn := s.objectName(n.TableName.ToTableName())
+	c := &tree.CreatePolicy{
+		PolicyName: n.PolicyName,
+		TableName:  tn.ToUnresolvedObjectName(),
+		Type:       n.Type,
+		Cmd:        n.Cmd,
+		Roles:      append(tree.RoleSpecList(nil), n.Roles...),
+	}
+	if c.Type == tree.PolicyTypeDefault {
+		c.Type = tree.PolicyTypePermissive
+	}
+	if c.Cmd == tree.PolicyCommandDefault {
+		c.Cmd = tree.PolicyCommandAll
+	}
+	if len(c.Roles) == 0 {
+		c.Roles = tree.RoleSpecList{tree.MakeRoleSpecWithRoleName(catconstants.PublicSchemaName)}
+	}
+	sort.Slice(c.Roles, func(i, j int) bool {
+		return tree.AsString(&c.Roles[i]) < tree.AsString(&c.Roles[j])
+	})
+	if hasExprs {
+		c.Exprs.Using = normalizeSchemaExpr(n.Exprs.Using)
+		c.Exprs.WithCheck = normalizeSchemaExpr(n.Exprs.WithCheck)
+	}
+	s.policies = append(s.policies, &schemaPolicy{
+		key:      fmt.Sprintf("%s.%s", tree.AsString(&tn), tree.AsString(&c.PolicyName)),
+		stmt:     c,
+		hasExprs: hasExprs,
+	})
+}
+
+// isShardColumn returns whether the column is the shard column of a
+// hash-sharded index. See tabledesc.GetShardColumnName.
+func isShardColumn(d *tree.ColumnTableDef) bool {
+	name := string(d.Name)
+	return d.Hidden && d.Computed.Computed &&
+		strings.HasPrefix(name, "crdb_internal_") && strings.Contains(name, "_shard_")
+}
+
+func (t *schemaTable) column(name tree.Name) *tree.ColumnTableDef {
+	for _, col := range t.columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (t *schemaTable) dropColumn(name tree.Name) {
+	for i, col := range t.columns {
+		if col.Name == name {
+			t.columns = append(t.columns[:i:i], t.columns[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *schemaTable) setPrimaryKey(name tree.Name, cols tree.IndexElemList) {
+	if name == "" {
+		// See tabledesc.PrimaryKeyIndexName.
+		name = tree.Name(t.name.Table() + "_pkey")
+	}
+	t.primaryKey = &tree.UniqueConstraintTableDef{
+		IndexTableDef: tree.IndexTableDef{Name: name, Columns: normalizeIndexElems(cols)},
+		PrimaryKey:    true,
+	}
+}
+
+func (t *schemaTable) addIndex(n *tree.CreateIndex) {
+	n.Table = t.name
+	n.Columns = normalizeIndexElems(n.Columns)
+	if (n.Inverted || n.Vector) && len(n.Columns) > 0 {
+		// The direction of the last column of an inverted or vector index is
+		// not shown.
+		n.Columns[len(n.Columns)-1].Direction = tree.DefaultDirection
+	}
+	n.Predicate = normalizeSchemaExpr(n.Predicate)
+	if n.Name == "" {
+		n.Name = t.defaultIndexName(n.Columns, n.Unique)
+	}
+	t.indexes = append(t.indexes, n)
+}
+
+// defaultIndexName returns the name of an index that is created without one.
+// See tabledesc.BuildIndexName.
+func (t *schemaTable) defaultIndexName(cols tree.IndexElemList, unique bool) tree.Name {
+	segments := []string{t.name.Table()}
+	exprCount := 0
+	for _, elem := range cols {
+		if elem.Expr == nil {
+			segments = append(segments, string(elem.Column))
+			continue
+		}
+		if exprCount == 0 {
+			segments = append(segments, "expr")
+		} else {
+			segments = append(segments, fmt.Sprintf("expr%d", exprCount))
+		}
+		exprCount++
+	}
+	if unique {
+		segments = append(segments, "key")
+	} else {
+		segments = append(segments, "idx")
+	}
+	baseName := strings.Join(segments, "_")
+	name := baseName
+	for i := 1; t.hasIndex(tree.Name(name)); i++ {
+		name = fmt.Sprintf("%s%d", baseName, i)
+	}
+	return tree.Name(name)
+}
+
+func (t *schemaTable) hasIndex(name tree.Name) bool {
+	if t.primaryKey != nil && t.primaryKey.Name == name {
+		return true
+	}
+	for _, idx := range t.indexes {
+		if idx.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// def returns the formatted definition of the table, which is equal for two
+// tables only if they need no migration.
+func (t *schemaTable) def() string {
+	var b strings.Builder
+	for _, col := range t.columns {
+		fmt.Fprintf(&b, "%s\n", tree.AsString(col))
+	}
+	fmt.Fprintf(&b, "%s\n", tree.AsString(t.primaryKey))
+	for _, idx := range t.indexes {
+		fmt.Fprintf(&b, "%s\n", tree.AsString(idx))
+	}
+	for _, c := range t.constraints {
+		fmt.Fprintf(&b, "%s\n", tree.AsString(c))
+	}
+	for _, fk := range t.fks {
+		fmt.Fprintf(&b, "%s\n", tree.AsString(fk))
+	}
+	return b.String()
+}
+
+// alter returns an ALTER TABLE statement of the table with a single command,
+// which is the form of ALTER TABLE that the declarative schema changer runs.
+func (t *schemaTable) alter(cmd tree.AlterTableCmd) *tree.AlterTable {
+	return &tree.AlterTable{Table: t.name.ToUnresolvedObjectName(), Cmds: tree.AlterTableCmds{cmd}}
+}
+
+func normalizeIndexElems(elems tree.IndexElemList) tree.IndexElemList {
+	res := make(tree.IndexElemList, len(elems))
+	for i, elem := range elems {
+		elem.Expr = normalizeSchemaExpr(elem.Expr)
+		if elem.Direction == tree.DefaultDirection {
+			elem.Direction = tree.Ascending
+		}
+		if (elem.Direction == tree.Ascending && elem.NullsOrder == tree.NullsFirst) ||
+			(elem.Direction == tree.Descending && elem.NullsOrder == tree.NullsLast) {
+			elem.NullsOrder = tree.DefaultNullsOrder
+		}
+		res[i] = elem
+	}
+	return res
+}
+
+// normalizeSchemaExpr removes the type annotations that the cluster adds to
+// the expressions that it stores, such that they compare equal to the
+// expressions as written in a file.
+func normalizeSchemaExpr(expr tree.Expr) tree.Expr {
+	if expr == nil {
+		return nil
+	}
+	res, err := tree.SimpleVisit(expr, func(e tree.Expr) (bool, tree.Expr, error) {
+		if a, ok := e.(*tree.AnnotateTypeExpr); ok {
+			return true, a.Expr, nil
+		}
+		return true, e, nil
+	})
+	if err != nil {
+		return expr
+	}
+	return tree.StripParens(res)
+}
+
+func exprString(expr tree.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	return tree.AsString(expr)
+}
+
+func uniqueRowIDExpr() tree.Expr {
+	return &tree.FuncExpr{
+		Func: tree.ResolvableFunctionReference{FunctionReference: tree.NewUnresolvedName("unique_rowid")},
+	}
+}
+
+// defaultCheckName returns the name of a check constraint that is created
+// without one: check followed by the columns that the expression references.
+func defaultCheckName(expr tree.Expr) tree.Name {
+	var b strings.Builder
+	b.WriteString("check")
+	_, _ = tree.SimpleVisit(expr, func(e tree.Expr) (bool, tree.Expr, error) {
+		if v, ok := e.(tree.VarName); ok {
+			if n, err := v.NormalizeVarName(); err == nil {
+				if col, ok := n.(*tree.ColumnItem); ok {
+					b.WriteString("_")
+					b.WriteString(string(col.ColumnName))
+				}
+			}
+		}
+		return true, e, nil
+	})
+	return tree.Name(b.String())
+}
+
+// schemaDiffPhase is a step of a migration. The statements of a migration are
+// ordered by phase, such that the objects that a statement depends on exist
+// when it runs, and the objects that depend on the objects that it drops are
+// dropped before it runs.
+type schemaDiffPhase int
+
+const (
+	phaseDropPolicies schemaDiffPhase = iota
+	phaseDropViews
+	phaseDropRoutines
+	phaseDropForeignKeys
+	phaseDropConstraints
+	phaseDropIndexes
+	phaseDropTables
+	phaseDropSequences
+	phaseDropReplacedTypes
+	phaseCreateSchemas
+	phaseCreateTypes
+	phaseCreateSequences
+	phaseCreateTables
+	phaseAlterColumns
+	phaseCreateIndexes
+	phaseAddConstraints
+	phaseAddForeignKeys
+	phaseCreateRoutines
+	phaseCreateViews
+	phaseZoneConfigs
+	phaseCreatePolicies
+	phaseDropTypes
+	phaseDropSchemas
+	numSchemaDiffPhases
+)
+
+// schemaMigration is the statements of a migration, by phase.
+type schemaMigration [numSchemaDiffPhases][]string
+
+func (m *schemaMigration) add(phase schemaDiffPhase, stmt tree.NodeFormatter) {
+	m.addString(phase, tree.AsString(stmt))
+}
+
+func (m *schemaMigration) addString(phase schemaDiffPhase, stmt string) {
+	m[phase] = append(m[phase], stmt)
+}
+
+// diffSchemaItems matches the items of two lists by key. It calls fn with
+// each item that is only in from and a zero to, then with each item of to and
+// the item of from with the same key, or a zero from if there is none, if
+// their definitions differ.
+func diffSchemaItems[T comparable](from, to []T, key, def func(T) string, fn func(from, to T)) {
+	var zero T
+	toKeys := make(map[string]struct{}, len(to))
+	for _, t := range to {
+		toKeys[key(t)] = struct{}{}
+	}
+	fromByKey := make(map[string]T, len(from))
+	for _, f := range from {
+		fromByKey[key(f)] = f
+		if _, ok := toKeys[key(f)]; !ok {
+			fn(f, zero)
+		}
+	}
+	for _, t := range to {
+		if f, ok := fromByKey[key(t)]; !ok {
+			fn(zero, t)
+		} else if def(f) != def(t) {
+			fn(f, t)
+		}
+	}
+}
+
+// diffSchemas returns the statements that migrate the schema from to the
+// schema to.
+func diffSchemas(from, to *schemaSnapshot) []string {
+	var m schemaMigration
+	objectKey := func(o *schemaObject) string { return o.key }
+	objectDef := func(o *schemaObject) string { return o.def }
+
+	diffSchemaItems(from.schemas, to.schemas, objectKey, objectDef, func(f, t *schemaObject) {
+		if t == nil {
+			m.add(phaseDropSchemas, &tree.DropSchema{
+				Names: tree.ObjectNamePrefixList{f.stmt.(*tree.CreateSchema).Schema},
+			})
+		} else {
+			m.addString(phaseCreateSchemas, t.def)
+		}
+	})
+
+	diffSchemaItems(from.types, to.types, objectKey, objectDef, func(f, t *schemaObject) {
+		switch {
+		case t == nil:
+			m.add(phaseDropTypes, &tree.DropType{
+				Names: []*tree.UnresolvedObjectName{f.stmt.(*tree.CreateType).TypeName},
+			})
+		case f == nil:
+			m.addString(phaseCreateTypes, t.def)
+		default:
+			diffType(&m, f.stmt.(*tree.CreateType), t.stmt.(*tree.CreateType))
+		}
+	})
+
+	diffSchemaItems(from.sequences, to.sequences, objectKey, objectDef, func(f, t *schemaObject) {
+		switch {
+		case t == nil:
+			m.add(phaseDropSequences, &tree.DropSequence{
+				Names: tree.TableNames{f.stmt.(*tree.CreateSequence).Name},
+			})
+		case f == nil:
+			m.addString(phaseCreateSequences, t.def)
+		default:
+			diffSequence(&m, f.stmt.(*tree.CreateSequence), t.stmt.(*tree.CreateSequence))
+		}
+	})
+
+	diffSchemaItems(from.tables, to.tables,
+		func(t *schemaTable) string { return t.key },
+		func(t *schemaTable) string { return t.def() },
+		func(f, t *schemaTable) { diffTable(&m, f, t) })
+
+	diffSchemaItems(from.routines, to.routines, objectKey, objectDef, func(f, t *schemaObject) {
+		switch {
+		case t == nil:
+			n := f.stmt.(*tree.CreateRoutine)
+			obj := tree.RoutineObj{FuncName: n.Name, Params: tree.RoutineParams{}}
+			for _, param := range n.Params {
+				if param.Class != tree.RoutineParamOut {
+					obj.Params = append(obj.Params, tree.RoutineParam{Type: param.Type, Class: param.Class})
+				}
+			}
+			m.add(phaseDropRoutines, &tree.DropRoutine{
+				Procedure: n.IsProcedure, Routines: tree.RoutineObjs{obj},
+			})
+		case f == nil:
+			m.addString(phaseCreateRoutines, t.def)
+		default:
+			m.addString(phaseCreateRoutines, t.replace)
+		}
+	})
+
+	// The views are dropped in the reverse order of their creation, such that
+	// the views that depend on other views are dropped first. The views that
+	// cannot be replaced, that is the materialized views, are dropped and
+	// created again.
+	var dropViews []tree.NodeFormatter
+	diffSchemaItems(from.views, to.views, objectKey, objectDef, func(f, t *schemaObject) {
+		if f != nil && (t == nil || t.replace == "") {
+			n := f.stmt.(*tree.CreateView)
+			dropViews = append(dropViews, &tree.DropView{
+				Names: tree.TableNames{n.Name}, IsMaterialized: n.Materialized,
+			})
+		}
+		switch {
+		case t == nil:
+		case f == nil || t.replace == "":
+			m.addString(phaseCreateViews, t.def)
+		default:
+			m.addString(phaseCreateViews, t.replace)
+		}
+	})
+	for i := len(dropViews) - 1; i >= 0; i-- {
+		m.add(phaseDropViews, dropViews[i])
+	}
+
+	database := from.database
+	if database == "" {
+		database = to.database
+	}
+	diffSchemaItems(from.zones, to.zones,
+		func(z *schemaZone) string { return z.key },
+		func(z *schemaZone) string {
+			return tree.AsString(&tree.ZoneConfigSettings{Options: z.options})
+		},
+		func(f, t *schemaZone) { diffZone(&m, database, f, t) })
+
+	diffSchemaItems(from.policies, to.policies,
+		func(p *schemaPolicy) string { return p.key },
+		func(p *schemaPolicy) string { return tree.AsString(p.stmt) },
+		func(f, t *schemaPolicy) { diffPolicy(&m, f, t) })
+
+	var stmts []string
+	for _, phase := range m {
+		stmts = append(stmts, phase...)
+	}
+	return stmts
+}
+
+// diffType migrates a type that exists in both schemas. Values are added to
+// and dropped from enums; other types are dropped and created again.
+func diffType(m *schemaMigration, from, to *tree.CreateType) {
+	if from.Variety != tree.Enum || to.Variety != tree.Enum {
+		m.add(phaseDropReplacedTypes, &tree.DropType{Names: []*tree.UnresolvedObjectName{from.TypeName}})
+		m.add(phaseCreateTypes, to)
+		return
+	}
+	fromLabels := make(map[tree.EnumValue]bool, len(from.EnumLabels))
+	for _, label := range from.EnumLabels {
+		fromLabels[label] = true
+	}
+	toLabels := make(map[tree.EnumValue]bool, len(to.EnumLabels))
+	for i, label := range to.EnumLabels {
+		toLabels[label] = true
+		if fromLabels[label] {
+			continue
+		}
+		// Each value is placed after the value that precedes it, which exists by
+		// then, or before the first existing value.
+		add := &tree.AlterTypeAddValue{NewVal: label}
+		if i > 0 {
+			add.Placement = &tree.AlterTypeAddValuePlacement{ExistingVal: to.EnumLabels[i-1]}
+		} else if len(from.EnumLabels) > 0 {
+			add.Placement = &tree.AlterTypeAddValuePlacement{Before: true, ExistingVal: from.EnumLabels[0]}
+		}
+		m.add(phaseCreateTypes, &tree.AlterType{Type: to.TypeName, Cmd: add})
+	}
+	for _, label := range from.EnumLabels {
+		if !toLabels[label] {
+			m.add(phaseDropTypes, &tree.AlterType{Type: from.TypeName, Cmd: &tree.AlterTypeDropValue{Val: label}})
+		}
+	}
+}
+
+// diffSequence alters the options of a sequence that differ.
+func diffSequence(m *schemaMigration, from, to *tree.CreateSequence) {
+	fromOpts := make(map[string]string, len(from.Options))
+	for _, opt := range from.Options {
+		fromOpts[opt.Name] = tree.AsString(&tree.SequenceOptions{opt})
+	}
+	var opts tree.SequenceOptions
+	for _, opt := range to.Options {
+		if opt.Name != tree.SeqOptVirtual && fromOpts[opt.Name] != tree.AsString(&tree.SequenceOptions{opt}) {
+			opts = append(opts, opt)
+		}
+	}
+	if len(opts) > 0 {
+		m.add(phaseCreateSequences, &tree.AlterSequence{Name: to.Name.ToUnresolvedObjectName(), Options: opts})
+	}
+}
+
+// diffTable migrates a table, which is missing from one of the schemas if
+// from or to is nil.
+func diffTable(m *schemaMigration, from, to *schemaTable) {
+	if to == nil {
+		for _, fk := range from.fks {
+			m.add(phaseDropForeignKeys, from.alter(&tree.AlterTableDropConstraint{Constraint: fk.Name}))
+		}
+		m.add(phaseDropTables, &tree.DropTable{Names: tree.TableNames{from.name}})
+		return
+	}
+	if from == nil {
+		// The table is created with its columns and primary key, and its indexes
+		// and constraints are added as to an existing table.
+		defs := make(tree.TableDefs, 0, len(to.columns)+1)
+		for _, col := range to.columns {
+			defs = append(defs, col)
+		}
+		defs = append(defs, to.primaryKey)
+		m.add(phaseCreateTables, &tree.CreateTable{Table: to.name, Defs: defs})
+		from = &schemaTable{
+			key: to.key, name: to.name, columns: to.columns,
+			primaryKey: to.primaryKey, implicitRowID: to.implicitRowID,
+		}
+	}
+
+	// The columns are added, then altered, then the primary key is changed,
+	// and then the columns that are no longer used are dropped.
+	var adds, alters, drops []tree.AlterTableCmd
+	var dropIndexes []tree.NodeFormatter
+	diffSchemaItems(from.columns, to.columns,
+		func(col *tree.ColumnTableDef) string { return string(col.Name) },
+		func(col *tree.ColumnTableDef) string { return tree.AsString(col) },
+		func(f, t *tree.ColumnTableDef) {
+			switch {
+			case t == nil && from.implicitRowID && f.Name == "rowid":
+				// The rowid column is dropped by ALTER PRIMARY KEY.
+			case t == nil:
+				drops = append(drops, &tree.AlterTableDropColumn{Column: f.Name})
+			case f == nil:
+				adds = append(adds, &tree.AlterTableAddColumn{ColumnDef: t})
+			default:
+				alters = append(alters, diffColumn(f, t, &drops, &adds)...)
+			}
+		})
+
+	if tree.AsString(from.primaryKey) != tree.AsString(to.primaryKey) {
+		if tree.AsString(&from.primaryKey.Columns) == tree.AsString(&to.primaryKey.Columns) {
+			alters = append(alters, &tree.AlterTableRenameConstraint{
+				Constraint: from.primaryKey.Name, NewName: to.primaryKey.Name,
+			})
+		} else {
+			alters = append(alters, &tree.AlterTableAlterPrimaryKey{Columns: to.primaryKey.Columns})
+			// ALTER PRIMARY KEY keeps a unique index on the columns of the old
+			// primary key, unless it is the rowid column.
+			if !from.implicitRowID && !to.hasUniqueIndex(from.primaryKey.Columns) {
+				dropIndexes = append(dropIndexes, &tree.DropIndex{
+					IndexList: tree.TableIndexNames{{
+						Table: to.name,
+						Index: tree.UnrestrictedName(from.defaultIndexName(from.primaryKey.Columns, true /* unique */)),
+					}},
+					DropBehavior: tree.DropCascade,
+				})
+			}
+		}
+	}
+	for _, cmds := range [][]tree.AlterTableCmd{adds, alters, drops} {
+		for _, cmd := range cmds {
+			m.add(phaseAlterColumns, to.alter(cmd))
+		}
+	}
+	for _, stmt := range dropIndexes {
+		m.add(phaseAlterColumns, stmt)
+	}
+
+	diffSchemaItems(from.indexes, to.indexes,
+		func(idx *tree.CreateIndex) string { return string(idx.Name) },
+		func(idx *tree.CreateIndex) string { return tree.AsString(idx) },
+		func(f, t *tree.CreateIndex) {
+			if f != nil {
+				drop := &tree.DropIndex{
+					IndexList: tree.TableIndexNames{{Table: from.name, Index: tree.UnrestrictedName(f.Name)}},
+				}
+				if f.Unique {
+					// The indexes of unique constraints can only be dropped with
+					// CASCADE.
+					drop.DropBehavior = tree.DropCascade
+				}
+				m.add(phaseDropIndexes, drop)
+			}
+			if t != nil {
+				m.add(phaseCreateIndexes, t)
+			}
+		})
+
+	diffSchemaItems(from.constraints, to.constraints, constraintName,
+		func(c tree.ConstraintTableDef) string { return tree.AsString(c) },
+		func(f, t tree.ConstraintTableDef) {
+			if f != nil {
+				m.add(phaseDropConstraints, from.alter(&tree.AlterTableDropConstraint{
+					Constraint: tree.Name(constraintName(f)),
+				}))
+			}
+			if t != nil {
+				m.add(phaseAddConstraints, to.alter(&tree.AlterTableAddConstraint{ConstraintDef: t}))
+			}
+		})
+
+	diffSchemaItems(from.fks, to.fks,
+		func(fk *tree.ForeignKeyConstraintTableDef) string { return string(fk.Name) },
+		func(fk *tree.ForeignKeyConstraintTableDef) string { return tree.AsString(fk) },
+		func(f, t *tree.ForeignKeyConstraintTableDef) {
+			if f != nil {
+				m.add(phaseDropForeignKeys, from.alter(&tree.AlterTableDropConstraint{Constraint: f.Name}))
+			}
+			if t != nil {
+				m.add(phaseAddForeignKeys, to.alter(&tree.AlterTableAddConstraint{ConstraintDef: t}))
+			}
+		})
+}
+
+// diffColumn returns the commands that alter a column that exists in both
+// tables. A column whose computed expression changes is dropped and added
+// again instead, with the commands appended to drops and adds.
+func diffColumn(
+	from, to *tree.ColumnTableDef, drops, adds *[]tree.AlterTableCmd,
+) []tree.AlterTableCmd {
+	var cmds []tree.AlterTableCmd
+	if from.Computed.Computed != to.Computed.Computed || from.Computed.Virtual != to.Computed.Virtual ||
+		exprString(from.Computed.Expr) != exprString(to.Computed.Expr) {
+		if from.Computed.Computed && !from.Computed.Virtual && !to.Computed.Computed {
+			cmds = append(cmds, &tree.AlterTableDropStored{Column: to.Name})
+		} else {
+			// The values of computed columns can be computed again, so no data is
+			// lost.
+			*drops = append(*drops, &tree.AlterTableDropColumn{Column: from.Name})
+			*adds = append(*adds, &tree.AlterTableAddColumn{ColumnDef: to})
+			return nil
+		}
+	}
+	if from.Type.SQLString() != to.Type.SQLString() {
+		cmds = append(cmds, &tree.AlterTableAlterColumnType{Column: to.Name, ToType: to.Type})
+	}
+	if from.Nullable.Nullability != to.Nullable.Nullability {
+		if to.Nullable.Nullability == tree.NotNull {
+			cmds = append(cmds, &tree.AlterTableSetNotNull{Column: to.Name})
+		} else {
+			cmds = append(cmds, &tree.AlterTableDropNotNull{Column: to.Name})
+		}
+	}
+	if exprString(from.DefaultExpr.Expr) != exprString(to.DefaultExpr.Expr) {
+		cmds = append(cmds, &tree.AlterTableSetDefault{Column: to.Name, Default: to.DefaultExpr.Expr})
+	}
+	if exprString(from.OnUpdateExpr.Expr) != exprString(to.OnUpdateExpr.Expr) {
+		cmds = append(cmds, &tree.AlterTableSetOnUpdate{Column: to.Name, Expr: to.OnUpdateExpr.Expr})
+	}
+	if from.Hidden != to.Hidden {
+		cmds = append(cmds, &tree.AlterTableSetVisible{Column: to.Name, Visible: !to.Hidden})
+	}
+	return cmds
+}
+
+func (t *schemaTable) hasUniqueIndex(cols tree.IndexElemList) bool {
+	for _, idx := range t.indexes {
+		if idx.Unique && idx.Predicate == nil && tree.AsString(&idx.Columns) == tree.AsString(&cols) {
+			return true
+		}
+	}
+	return false
+}
+
+func constraintName(c tree.ConstraintTableDef) string {
+	switch d := c.(type) {
+	case *tree.CheckConstraintTableDef:
+		return string(d.Name)
+	case *tree.UniqueConstraintTableDef:
+		return string(d.Name)
+	case *tree.ForeignKeyConstraintTableDef:
+		return string(d.Name)
+	}
+	return ""
+}
+
+// diffZone sets the options of a zone configuration that differ, resetting
+// the options that are no longer set to the value of the parent zone.
+func diffZone(m *schemaMigration, database string, from, to *schemaZone) {
+	if to == nil {
+		m.add(phaseZoneConfigs, &tree.SetZoneConfig{
+			ZoneSpecifier:      from.specifier(database),
+			ZoneConfigSettings: tree.ZoneConfigSettings{YAMLConfig: tree.DNull},
+		})
+		return
+	}
+	fromOpts := make(map[tree.Name]string)
+	if from != nil {
+		for _, opt := range from.options {
+			fromOpts[opt.Key] = tree.AsString(opt.Value)
+		}
+	}
+	settings := tree.ZoneConfigSettings{}
+	toOpts := make(map[tree.Name]bool, len(to.options))
+	for _, opt := range to.options {
+		toOpts[opt.Key] = true
+		if v, ok := fromOpts[opt.Key]; !ok || v != tree.AsString(opt.Value) {
+			settings.Options = append(settings.Options, opt)
+		}
+	}
+	if from != nil {
+		for _, opt := range from.options {
+			if !toOpts[opt.Key] {
+				settings.Options = append(settings.Options, tree.KVOption{Key: opt.Key})
+			}
+		}
+	}
+	if len(settings.Options) == 0 {
+		settings.SetDefault = true
+	}
+	m.add(phaseZoneConfigs, &tree.SetZoneConfig{
+		ZoneSpecifier:      to.specifier(database),
+		ZoneConfigSettings: settings,
+	})
+}
+
+// specifier returns the specifier of the zone, with the database of the
+// migration for the zone of the database.
+func (z *schemaZone) specifier(database string) tree.ZoneSpecifier {
+	spec := z.spec
+	if spec.Database != "" {
+		spec.Database = tree.Name(database)
+	}
+	return spec
+}
+
+// diffPolicy migrates a row-level security policy. The roles and expressions
+// of a policy are altered; a policy whose type or command changes is dropped
+// and created again.
+func diffPolicy(m *schemaMigration, from, to *schemaPolicy) {
+	if from != nil && (to == nil || from.stmt.Type != to.stmt.Type || from.stmt.Cmd != to.stmt.Cmd) {
+		m.add(phaseDropPolicies, &tree.DropPolicy{
+			PolicyName: from.stmt.PolicyName, TableName: from.stmt.TableName,
+		})
+		from = nil
+	}
+	switch {
+	case to == nil:
+	case from == nil:
+		m.add(phaseCreatePolicies, to.stmt)
+	default:
+		alter := &tree.AlterPolicy{PolicyName: to.stmt.PolicyName, TableName: to.stmt.TableName}
+		if tree.AsString(&from.stmt.Roles) != tree.AsString(&to.stmt.Roles) {
+			alter.Roles = to.stmt.Roles
+		}
+		if from.hasExprs && to.hasExprs &&
+			tree.AsString(&from.stmt.Exprs) != tree.AsString(&to.stmt.Exprs) {
+			alter.Exprs = to.stmt.Exprs
+		}
+		if len(alter.Roles) > 0 || alter.Exprs != (tree.PolicyExpressions{}) {
+			m.add(phaseCreatePolicies, alter)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := NewCLITest(TestCLIParams{T: t})
+	defer c.Cleanup()
+	c.omitArgs = true
+
+	dir, cleanFn := testutils.TempDir(t)
+	defer cleanFn()
+
+	run := func(args ...string) string {
+		out, err := c.RunWithCaptureArgs(args)
+		require.NoError(t, err)
+		require.NotContains(t, out, "ERROR")
+		return out
+	}
+
+	run("sql", "-e", "CREATE DATABASE d1; CREATE DATABASE d2")
+	run("sql", "--database=d1", "-e", `
+CREATE TYPE color AS ENUM ('red', 'green');
+CREATE SEQUENCE s;
+CREATE TABLE p (id INT PRIMARY KEY, c color);
+CREATE TABLE t (id INT PRIMARY KEY, p INT, s STRING, INDEX (s));
+CREATE TABLE old (id INT PRIMARY KEY);
+CREATE VIEW v AS SELECT id FROM t;
+`)
+	run("sql", "--database=d2", "-e", `
+CREATE TYPE color AS ENUM ('red', 'blue', 'green');
+CREATE SEQUENCE s INCREMENT 2;
+CREATE TABLE p (id INT PRIMARY KEY, c color);
+CREATE TABLE t (
+  id INT PRIMARY KEY,
+  p INT NOT NULL DEFAULT 0 REFERENCES p (id),
+  s STRING,
+  n INT CHECK (n > 0),
+  INDEX (p)
+);
+CREATE TABLE q (id INT PRIMARY KEY, t INT REFERENCES t (id));
+CREATE VIEW v AS SELECT id, s FROM t;
+CREATE FUNCTION f(x INT) RETURNS INT LANGUAGE SQL AS 'SELECT x + 1';
+ALTER TABLE t CONFIGURE ZONE USING gc.ttlseconds = 600;
+`)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, c.Server.AdvSQLAddr(), "TestSchemaDiff", url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = "d1"
+	from := pgURL.String()
+	pgURL.Path = "d2"
+	to := pgURL.String()
+
+	out := run("schema", "diff", "--from", from, "--to", to)
+	for _, stmt := range []string{
+		"ALTER TYPE public.color ADD VALUE 'blue' AFTER 'red'",
+		"ALTER SEQUENCE public.s INCREMENT 2",
+		"ALTER TABLE public.t ALTER COLUMN p SET NOT NULL",
+		"DROP INDEX public.t@t_s_idx",
+		"DROP TABLE public.old",
+		"CREATE INDEX t_p_idx ON public.t (p ASC)",
+		"ADD CONSTRAINT check_n CHECK (n > 0)",
+		"ADD CONSTRAINT t_p_fkey FOREIGN KEY (p) REFERENCES public.p (id)",
+		"CREATE FUNCTION public.f",
+		"CREATE OR REPLACE VIEW public.v",
+		"ALTER TABLE public.t CONFIGURE ZONE USING gc.ttlseconds = 600",
+	} {
+		require.Contains(t, out, stmt)
+	}
+
+	// Once the migration is applied, the schemas are the same.
+	path := filepath.Join(dir, "migration.sql")
+	require.NoError(t, os.WriteFile(path, []byte(out), 0644))
+	run("sql", "--database=d1", "-f", path)
+	require.Empty(t, run("schema", "diff", "--from", from, "--to", to))
+
+	// A database can be compared with DDL files.
+	path = filepath.Join(dir, "schema.sql")
+	require.NoError(t, os.WriteFile(path, []byte(`
+CREATE TABLE p (id INT PRIMARY KEY);
+CREATE POLICY pol ON p USING (id > 0);
+`), 0644))
+	out = run("schema", "diff", "--from", from, "--to", path)
+	require.Contains(t, out, "ALTER TABLE public.p DROP COLUMN c")
+	require.Contains(t, out, "CREATE POLICY pol ON public.p")
+	require.Contains(t, out, "DROP TABLE public.q")
+
+	out, err := c.RunWithCaptureArgs([]string{"schema", "diff", "--from", from})
+	require.NoError(t, err)
+	require.Contains(t, out, "--to must be specified")
+}
+
+// TestSchemaDiffImplicitRowID checks the migration of tables read from a
+// cluster that use the rowid column as their primary key, or that have
+// hash-sharded indexes, to DDL files.
+func TestSchemaDiffImplicitRowID(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := NewCLITest(TestCLIParams{T: t})
+	defer c.Cleanup()
+	c.omitArgs = true
+
+	dir, cleanFn := testutils.TempDir(t)
+	defer cleanFn()
+
+	run := func(args ...string) string {
+		out, err := c.RunWithCaptureArgs(args)
+		require.NoError(t, err)
+		require.NotContains(t, out, "ERROR")
+		return out
+	}
+
+	run("sql", "-e", "CREATE DATABASE d")
+	run("sql", "--database=d", "-e", `
+CREATE TABLE t (a INT, b INT);
+CREATE TABLE h (id INT PRIMARY KEY, x INT, INDEX h_x_idx (x ASC) USING HASH WITH (bucket_count=16));
+`)
+	pgURL, cleanup := sqlutils.PGUrl(t, c.Server.AdvSQLAddr(), "TestSchemaDiffImplicitRowID", url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = "d"
+	from := pgURL.String()
+
+	path := filepath.Join(dir, "schema.sql")
+	require.NoError(t, os.WriteFile(path, []byte(`
+CREATE TABLE t (a INT PRIMARY KEY, b INT);
+CREATE TABLE h (id INT PRIMARY KEY, x INT, INDEX h_x_idx (x ASC) USING HASH WITH (bucket_count=16));
+`), 0644))
+	out := run("schema", "diff", "--from", from, "--to", path)
+	require.Contains(t, out, "ALTER TABLE public.t ALTER PRIMARY KEY USING COLUMNS (a ASC)")
+	// ALTER PRIMARY KEY drops the rowid column, and keeps no index on it.
+	require.NotContains(t, out, "rowid")
+	require.NotContains(t, out, "DROP INDEX")
+	// The shard column is not compared.
+	require.NotContains(t, out, "crdb_internal")
+
+	// Once the migration is applied, the schemas are the same.
+	migration := filepath.Join(dir, "migration.sql")
+	require.NoError(t, os.WriteFile(migration, []byte(out), 0644))
+	run("sql", "--database=d", "-f", migration)
+	require.Empty(t, run("schema", "diff", "--from", from, "--to", path))
+}
+
+func TestReadSchemaFilesEmpty(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, cleanFn := testutils.TempDir(t)
+	defer cleanFn()
+
+	// A directory without .sql files.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema.txt"), []byte(`CREATE TABLE t (id INT PRIMARY KEY)`), 0644))
+	_, err := readSchemaFiles(dir)
+	require.ErrorContains(t, err, "no .sql files found")
+
+	// Files without any schema object.
+	path := filepath.Join(dir, "empty.sql")
+	require.NoError(t, os.WriteFile(path, []byte(`-- nothing here
+SELECT 1;
+`), 0644))
+	for _, p := range []string{dir, path} {
+		_, err = readSchemaFiles(p)
+		require.ErrorContains(t, err, "no schema objects defined")
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "t.sql"), []byte(`CREATE TABLE t (id INT PRIMARY KEY)`), 0644))
+	s, err := readSchemaFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, s.tables, 1)
+}
@@ -0,0 +1,508 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlclient"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var sqlDumpCmd = &cobra.Command{
+	Use:   "sql-dump [<database> [<table>...]]",
+	Short: "dump the schema and data of a database as SQL statements",
+	Long: `
+Dumps the schema and the data of a database as SQL statements, as of a single
+point in time. The schema is dumped as CREATE statements, and the data of each
+table as a COPY ... FROM stdin block, so that the dump can be loaded with
+cockroach sql, psql or IMPORT PGDUMP.
+
+If tables are specified, only those tables, views and sequences are dumped, and
+the user-defined schemas and types they use must exist where the dump is
+loaded.
+
+By default the dump is written to the standard output. If --output-dir is
+specified, the schema is written to schema.sql, the data of each table to its
+own file, and the foreign keys and the values of the sequences to
+post_data.sql, and up to --concurrency tables are dumped in parallel. The
+files must be loaded in that order, the data files in any order. With
+--dump-all, each database is written to its own subdirectory.
+`,
+	Args: cobra.ArbitraryArgs,
+	RunE: clierrorplus.MaybeShoutError(runSQLDump),
+}
+
+// dumpObject is a schema, type, sequence, table or view that is dumped.
+type dumpObject struct {
+	kind   string
+	schema string
+	name   string
+	// create is the statement that creates the object. For tables, it omits
+	// the foreign keys, which are added by fks once all the tables exist.
+	create   string
+	fks      []string
+	validate []string
+}
+
+const (
+	dumpKindSchema   = "schema"
+	dumpKindType     = "type"
+	dumpKindSequence = "sequence"
+	dumpKindTable    = "table"
+	dumpKindView     = "view"
+)
+
+// dumpKindOrder is the order in which the objects of each kind are created,
+// such that the objects they depend on exist.
+var dumpKindOrder = []string{
+	dumpKindSchema, dumpKindType, dumpKindSequence, dumpKindTable, dumpKindView,
+}
+
+func (o *dumpObject) qualifiedName() string {
+	return lexbase.EscapeSQLIdent(o.schema) + "." + lexbase.EscapeSQLIdent(o.name)
+}
+
+// copyEscaper escapes the values in the text format of COPY.
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func runSQLDump(cmd *cobra.Command, args []string) (resErr error) {
+	if dumpCtx.dumpAll && len(args) > 0 {
+		return errors.New("cannot specify a database or tables with --dump-all")
+	}
+	if !dumpCtx.dumpAll && len(args) == 0 {
+		return errors.New("must specify a database to dump, or use --dump-all")
+	}
+	if dumpCtx.concurrency < 1 {
+		return errors.Newf("invalid --%s: %d", "concurrency", dumpCtx.concurrency)
+	}
+
+	ctx := context.Background()
+	conn, err := makeSQLClient(ctx, "cockroach sql-dump", useDefaultDb)
+	if err != nil {
+		return err
+	}
+	defer func() { resErr = errors.CombineErrors(resErr, conn.Close()) }()
+	if err := conn.EnsureConn(ctx); err != nil {
+		return err
+	}
+
+	asOf, err := dumpTimestamp(ctx, conn)
+	if err != nil {
+		return err
+	}
+	dbs := args[:1]
+	var tables []string
+	if dumpCtx.dumpAll {
+		if dbs, err = dumpDatabases(ctx, conn, asOf); err != nil {
+			return err
+		}
+	} else {
+		tables = args[1:]
+	}
+
+	var w *bufio.Writer
+	if dumpCtx.outputDir == "" {
+		w = bufio.NewWriter(os.Stdout)
+		defer func() { resErr = errors.CombineErrors(resErr, w.Flush()) }()
+	}
+	for _, db := range dbs {
+		d := &sqlDumper{conn: conn, db: db, asOf: asOf}
+		objects, err := d.objects(ctx, tables)
+		if err != nil {
+			return errors.Wrapf(err, "database %s", db)
+		}
+		if w == nil {
+			dir := dumpCtx.outputDir
+			if dumpCtx.dumpAll {
+				dir = filepath.Join(dir, db)
+			}
+			err = d.dumpToDir(ctx, dir, objects)
+		} else {
+			err = d.dumpTo(ctx, w, objects)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "database %s", db)
+		}
+	}
+	return nil
+}
+
+// dumpTimestamp returns the timestamp that the dump is taken at, so that all
+// the objects are read at the same point in time, even when they are read
+// over several connections.
+func dumpTimestamp(ctx context.Context, conn clisqlclient.Conn) (string, error) {
+	if dumpCtx.asOf != "" {
+		// Validate the timestamp. This prevents SQL injection.
+		if _, _, err := tree.ParseDTimestamp(nil, dumpCtx.asOf, time.Nanosecond); err != nil {
+			return "", err
+		}
+		return dumpCtx.asOf, nil
+	}
+	vals, err := conn.QueryRow(ctx, `SELECT cluster_logical_timestamp()::STRING`)
+	if err != nil {
+		return "", err
+	}
+	ts, ok := vals[0].(string)
+	if !ok {
+		return "", errors.Newf("unexpected cluster timestamp %v", vals[0])
+	}
+	return ts, nil
+}
+
+// dumpDatabases returns the names of the databases that --dump-all dumps.
+func dumpDatabases(ctx context.Context, conn clisqlclient.Conn, asOf string) ([]string, error) {
+	rows, err := conn.Query(ctx, fmt.Sprintf(
+		`SELECT name FROM crdb_internal.databases AS OF SYSTEM TIME %s WHERE name != 'system' ORDER BY name`,
+		lexbase.EscapeSQLString(asOf)))
+	if err != nil {
+		return nil, err
+	}
+	var dbs []string
+	vals := make([]driver.Value, 1)
+	for err = rows.Next(vals); err == nil; err = rows.Next(vals) {
+		dbs = append(dbs, vals[0].(string))
+	}
+	if err != io.EOF {
+		return nil, errors.CombineErrors(err, rows.Close())
+	}
+	return dbs, rows.Close()
+}
+
+// sqlDumper dumps the objects of a database as of a timestamp.
+type sqlDumper struct {
+	conn clisqlclient.Conn
+	db   string
+	asOf string
+}
+
+func (d *sqlDumper) aost() string {
+	return "AS OF SYSTEM TIME " + lexbase.EscapeSQLString(d.asOf)
+}
+
+// query runs the query and calls fn with the values of each row.
+func (d *sqlDumper) query(
+	ctx context.Context,
+	conn clisqlclient.Conn,
+	query string,
+	fn func(vals []driver.Value) error,
+	args ...interface{},
+) error {
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	vals := make([]driver.Value, len(rows.Columns()))
+	for err = rows.Next(vals); err == nil; err = rows.Next(vals) {
+		if err := fn(vals); err != nil {
+			return errors.CombineErrors(err, rows.Close())
+		}
+	}
+	if err != io.EOF {
+		return errors.CombineErrors(err, rows.Close())
+	}
+	return rows.Close()
+}
+
+// objects returns the objects of the database, in the order they must be
+// created in. If tables is not empty, only the tables, views and sequences
+// with these names, optionally qualified by their schema, are returned.
+func (d *sqlDumper) objects(ctx context.Context, tables []string) ([]*dumpObject, error) {
+	db := lexbase.EscapeSQLIdent(d.db)
+	byKind := make(map[string][]*dumpObject)
+	if len(tables) == 0 {
+		if err := d.query(ctx, d.conn, fmt.Sprintf(`
+SELECT schema_name, create_statement
+  FROM %s.crdb_internal.create_schema_statements %s
+ WHERE database_name = $1 AND schema_name != 'public'
+ ORDER BY descriptor_id`, db, d.aost()),
+			func(vals []driver.Value) error {
+				byKind[dumpKindSchema] = append(byKind[dumpKindSchema], &dumpObject{
+					kind:   dumpKindSchema,
+					name:   vals[0].(string),
+					create: vals[1].(string),
+				})
+				return nil
+			}, d.db); err != nil {
+			return nil, err
+		}
+		if err := d.query(ctx, d.conn, fmt.Sprintf(`
+SELECT schema_name, descriptor_name, create_statement
+  FROM %s.crdb_internal.create_type_statements %s
+ WHERE database_name = $1
+ ORDER BY descriptor_id`, db, d.aost()),
+			func(vals []driver.Value) error {
+				byKind[dumpKindType] = append(byKind[dumpKindType], &dumpObject{
+					kind:   dumpKindType,
+					schema: vals[0].(string),
+					name:   vals[1].(string),
+					create: vals[2].(string),
+				})
+				return nil
+			}, d.db); err != nil {
+			return nil, err
+		}
+	}
+
+	wanted := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		wanted[t] = false
+	}
+	if err := d.query(ctx, d.conn, fmt.Sprintf(`
+SELECT schema_name, descriptor_name, descriptor_type, create_nofks,
+       to_json(alter_statements)::STRING, to_json(validate_statements)::STRING
+  FROM %s.crdb_internal.create_statements %s
+ WHERE database_name = $1 AND NOT is_virtual AND NOT is_temporary
+ ORDER BY descriptor_id`, db, d.aost()),
+		func(vals []driver.Value) error {
+			o := &dumpObject{
+				kind:   vals[2].(string),
+				schema: vals[0].(string),
+				name:   vals[1].(string),
+				create: vals[3].(string),
+			}
+			if len(tables) > 0 {
+				found := false
+				for _, n := range []string{o.name, o.schema + "." + o.name} {
+					if _, ok := wanted[n]; ok {
+						wanted[n] = true
+						found = true
+					}
+				}
+				if !found {
+					return nil
+				}
+			}
+			if err := json.Unmarshal([]byte(vals[4].(string)), &o.fks); err != nil {
+				return err
+			}
+			if err := json.Unmarshal([]byte(vals[5].(string)), &o.validate); err != nil {
+				return err
+			}
+			byKind[o.kind] = append(byKind[o.kind], o)
+			return nil
+		}, d.db); err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if !wanted[t] {
+			return nil, errors.Newf("relation %q does not exist", t)
+		}
+	}
+
+	var objects []*dumpObject
+	for _, kind := range dumpKindOrder {
+		objects = append(objects, byKind[kind]...)
+	}
+	return objects, nil
+}
+
+// dumpTo writes the dump of the objects to w.
+func (d *sqlDumper) dumpTo(ctx context.Context, w io.Writer, objects []*dumpObject) error {
+	fmt.Fprintf(w, "-- Dump of database %s as of system time %s.\n\n",
+		lexbase.EscapeSQLIdent(d.db), d.asOf)
+	if dumpCtx.dumpAll {
+		if dumpCtx.dumpMode != dumpDataOnly {
+			fmt.Fprintf(w, "CREATE DATABASE IF NOT EXISTS %s;\n", lexbase.EscapeSQLIdent(d.db))
+		}
+		fmt.Fprintf(w, "USE %s;\n\n", lexbase.EscapeSQLIdent(d.db))
+	}
+	if dumpCtx.dumpMode != dumpDataOnly {
+		writeCreateStatements(w, objects)
+	}
+	if dumpCtx.dumpMode != dumpSchemaOnly {
+		for _, o := range objects {
+			if o.kind == dumpKindTable {
+				if err := d.dumpTable(ctx, d.conn, w, o); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return d.dumpPostData(ctx, w, objects)
+}
+
+// dumpToDir writes the dump of the objects to files in dir, dumping the data
+// of up to --concurrency tables in parallel.
+func (d *sqlDumper) dumpToDir(ctx context.Context, dir string, objects []*dumpObject) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if dumpCtx.dumpMode != dumpDataOnly {
+		if err := writeDumpFile(filepath.Join(dir, "schema.sql"), func(w io.Writer) error {
+			writeCreateStatements(w, objects)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if dumpCtx.dumpMode != dumpSchemaOnly {
+		tables := make(chan *dumpObject)
+		g := ctxgroup.WithContext(ctx)
+		g.GoCtx(func(ctx context.Context) error {
+			defer close(tables)
+			for _, o := range objects {
+				if o.kind != dumpKindTable {
+					continue
+				}
+				select {
+				case tables <- o:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		for i := 0; i < dumpCtx.concurrency; i++ {
+			g.GoCtx(func(ctx context.Context) (resErr error) {
+				// Each worker reads over its own connection, which reuses the
+				// credentials of the main one.
+				conn, err := sqlCtx.MakeConn(d.conn.GetURL())
+				if err != nil {
+					return err
+				}
+				defer func() { resErr = errors.CombineErrors(resErr, conn.Close()) }()
+				for o := range tables {
+					name := url.PathEscape(o.schema) + "." + url.PathEscape(o.name) + ".sql"
+					if err := writeDumpFile(filepath.Join(dir, name), func(w io.Writer) error {
+						return d.dumpTable(ctx, conn, w, o)
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+	}
+
+	return writeDumpFile(filepath.Join(dir, "post_data.sql"), func(w io.Writer) error {
+		return d.dumpPostData(ctx, w, objects)
+	})
+}
+
+// writeDumpFile creates the file at path and calls fn to write its contents.
+func writeDumpFile(path string, fn func(w io.Writer) error) (resErr error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() { resErr = errors.CombineErrors(resErr, f.Close()) }()
+	w := bufio.NewWriter(f)
+	if err := fn(w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// writeCreateStatements writes the statements that create the objects.
+func writeCreateStatements(w io.Writer, objects []*dumpObject) {
+	for _, o := range objects {
+		fmt.Fprintf(w, "%s;\n\n", o.create)
+	}
+}
+
+// dumpTable writes the data of the table as a COPY block. Hidden and computed
+// columns are omitted, since they cannot be written.
+func (d *sqlDumper) dumpTable(
+	ctx context.Context, conn clisqlclient.Conn, w io.Writer, o *dumpObject,
+) error {
+	var cols []string
+	if err := d.query(ctx, conn, fmt.Sprintf(`
+SELECT column_name
+  FROM %s.information_schema.columns %s
+ WHERE table_schema = $1 AND table_name = $2 AND is_hidden = 'NO' AND is_generated = 'NEVER'
+ ORDER BY ordinal_position`, lexbase.EscapeSQLIdent(d.db), d.aost()),
+		func(vals []driver.Value) error {
+			cols = append(cols, lexbase.EscapeSQLIdent(vals[0].(string)))
+			return nil
+		}, o.schema, o.name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "COPY %s (%s) FROM stdin;\n", o.qualifiedName(), strings.Join(cols, ", "))
+	exprs := make([]string, len(cols))
+	for i, col := range cols {
+		exprs[i] = col + "::STRING"
+	}
+	if err := d.query(ctx, conn, fmt.Sprintf(`SELECT %s FROM %s.%s %s`,
+		strings.Join(exprs, ", "), lexbase.EscapeSQLIdent(d.db), o.qualifiedName(), d.aost()),
+		func(vals []driver.Value) error {
+			for i, v := range vals {
+				if i > 0 {
+					fmt.Fprint(w, "\t")
+				}
+				switch t := v.(type) {
+				case nil:
+					fmt.Fprint(w, `\N`)
+				case string:
+					fmt.Fprint(w, copyEscaper.Replace(t))
+				case []byte:
+					fmt.Fprint(w, copyEscaper.Replace(string(t)))
+				default:
+					fmt.Fprint(w, copyEscaper.Replace(fmt.Sprint(t)))
+				}
+			}
+			fmt.Fprint(w, "\n")
+			return nil
+		}); err != nil {
+		return errors.Wrapf(err, "dumping table %s", o.qualifiedName())
+	}
+	fmt.Fprint(w, "\\.\n\n")
+	return nil
+}
+
+// dumpPostData writes the statements that set the values of the sequences and
+// add the foreign keys, once all the tables and their data exist.
+func (d *sqlDumper) dumpPostData(ctx context.Context, w io.Writer, objects []*dumpObject) error {
+	if dumpCtx.dumpMode != dumpSchemaOnly {
+		for _, o := range objects {
+			if o.kind != dumpKindSequence {
+				continue
+			}
+			if err := d.query(ctx, d.conn, fmt.Sprintf(`SELECT last_value, is_called FROM %s.%s %s`,
+				lexbase.EscapeSQLIdent(d.db), o.qualifiedName(), d.aost()),
+				func(vals []driver.Value) error {
+					fmt.Fprintf(w, "SELECT setval(%s, %d, %t);\n\n",
+						lexbase.EscapeSQLString(o.qualifiedName()), vals[0], vals[1])
+					return nil
+				}); err != nil {
+				return errors.Wrapf(err, "dumping sequence %s", o.qualifiedName())
+			}
+		}
+	}
+	if dumpCtx.dumpMode != dumpDataOnly {
+		for _, o := range objects {
+			for _, stmt := range o.fks {
+				fmt.Fprintf(w, "%s;\n\n", stmt)
+			}
+		}
+		for _, o := range objects {
+			for _, stmt := range o.validate {
+				fmt.Fprintf(w, "%s;\n\n", stmt)
+			}
+		}
+	}
+	return nil
+}
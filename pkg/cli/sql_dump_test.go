@@ -0,0 +1,90 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLDump(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := NewCLITest(TestCLIParams{T: t})
+	defer c.Cleanup()
+	c.omitArgs = true
+
+	dir, cleanFn := testutils.TempDir(t)
+	defer cleanFn()
+
+	run := func(args ...string) string {
+		out, err := c.RunWithCaptureArgs(args)
+		require.NoError(t, err)
+		require.NotContains(t, out, "ERROR")
+		return out
+	}
+
+	run("sql", "-e", "CREATE DATABASE d; CREATE DATABASE d2")
+	run("sql", "--database=d", "-e", `
+CREATE SEQUENCE s;
+CREATE TYPE color AS ENUM ('red', 'green');
+CREATE TABLE p (id INT PRIMARY KEY, c color);
+CREATE TABLE t (
+  id INT PRIMARY KEY DEFAULT nextval('s'),
+  p INT REFERENCES p (id),
+  s STRING,
+  b BYTES,
+  j JSONB,
+  a INT[],
+  doubled INT AS (id * 2) STORED
+);
+CREATE VIEW v AS SELECT id, s FROM t;
+INSERT INTO p VALUES (1, 'red'), (2, NULL);
+INSERT INTO t (p, s, b, j, a) VALUES
+  (1, e'tab\there\nnewline \\ backslash', b'\x00\x01', '{"k": "v"}', ARRAY[1, NULL]),
+  (2, NULL, NULL, NULL, NULL);
+`)
+
+	// Load the dump of d into d2 and check that they have the same contents.
+	dump := run("sql-dump", "d")
+	path := filepath.Join(dir, "d.sql")
+	require.NoError(t, os.WriteFile(path, []byte(dump), 0644))
+	run("sql", "--database=d2", "-f", path)
+	for _, query := range []string{
+		`SELECT * FROM t ORDER BY id`,
+		`SELECT * FROM v ORDER BY id`,
+		`SELECT * FROM p ORDER BY id`,
+		`SELECT constraint_name FROM [SHOW CONSTRAINTS FROM t] WHERE constraint_type = 'FOREIGN KEY'`,
+		`SELECT nextval('s')`,
+	} {
+		require.Equal(t,
+			run("sql", "--database=d", "-e", query),
+			run("sql", "--database=d2", "-e", query),
+			query)
+	}
+
+	// With --output-dir, the data of each table is written to its own file.
+	run("sql-dump", "d", "t", "--dump-mode=data", "--output-dir", dir)
+	_, err := os.Stat(filepath.Join(dir, "schema.sql"))
+	require.True(t, os.IsNotExist(err))
+	data, err := os.ReadFile(filepath.Join(dir, "public.t.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "COPY public.t (id, p, s, b, j, a) FROM stdin;\n"+
+		"1\t1\ttab\\there\\nnewline \\\\ backslash\t\\\\x0001\t{\"k\": \"v\"}\t{1,NULL}\n"+
+		"2\t2\t\\N\t\\N\t\\N\t\\N\n"+
+		"\\.\n\n", string(data))
+	_, err = os.Stat(filepath.Join(dir, "post_data.sql"))
+	require.NoError(t, err)
+
+	out, err := c.RunWithCaptureArgs([]string{"sql-dump", "d", "missing"})
+	require.NoError(t, err)
+	require.Contains(t, out, `relation "missing" does not exist`)
+}
@@ -0,0 +1,933 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/ts"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
+	"github.com/cockroachdb/cockroach/pkg/ts/tsutil"
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/spf13/cobra"
+)
+
+var debugTimeSeriesServeOpts = struct {
+	file string
+	addr string
+}{
+	addr: "localhost:9090",
+}
+
+var debugTimeSeriesServeCmd = &cobra.Command{
+	Use:   "serve --file=<raw tsdump>",
+	Short: "serve a raw tsdump through a Prometheus-compatible query API",
+	Long: `
+Loads a tsdump previously created with --format=raw into memory and serves it
+through the Prometheus HTTP query API, so that it can be added as a Prometheus
+data source to Grafana and graphed without access to the cluster.
+
+Timeseries are named as with --format=openmetrics: cr.node.sql.select.count
+from node 1 is served as sql_select_count{node_id="1"}.
+
+The following endpoints are supported:
+
+  /api/v1/query
+  /api/v1/query_range
+  /api/v1/series
+  /api/v1/labels
+  /api/v1/label/<name>/values
+
+Queries support a subset of PromQL: selectors, arithmetic, the sum, avg,
+min, max and count aggregations, and the rate, irate, increase and
+histogram_quantile functions. CockroachDB does not record histogram buckets,
+so histogram_quantile(q, ...) over a _bucket metric selects the percentile
+that was recorded for q instead (one of 0.5, 0.75, 0.9, 0.99, 0.999, 0.9999,
+0.99999 or 1 for the maximum). Recorded percentiles are already computed over
+a sliding window, so rate functions are ignored within histogram_quantile,
+and since percentiles cannot be summed, sum is computed as max, which is an
+upper bound of the percentile of the aggregated histogram.
+`,
+	Args: cobra.NoArgs,
+	RunE: clierrorplus.MaybeDecorateError(runDebugTimeSeriesServe),
+}
+
+func runDebugTimeSeriesServe(_ *cobra.Command, _ []string) error {
+	if debugTimeSeriesServeOpts.file == "" {
+		return errors.New("a raw tsdump must be provided with --file")
+	}
+	s := newTSDumpStore()
+	if err := s.load(debugTimeSeriesServeOpts.file); err != nil {
+		return errors.Wrapf(err, "loading %s", debugTimeSeriesServeOpts.file)
+	}
+	ln, err := net.Listen("tcp", debugTimeSeriesServeOpts.addr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "serving %d timeseries from %s at http://%s\n",
+		len(s.series), debugTimeSeriesServeOpts.file, ln.Addr())
+	server := http.Server{Handler: s.handler()}
+	return server.Serve(ln)
+}
+
+const (
+	// promNameLabel is the label holding the metric name of a series.
+	promNameLabel = "__name__"
+	// promLookback is how far back an instant vector selector looks for the
+	// latest sample of a series, as in Prometheus.
+	promLookback = 5 * time.Minute
+	// promMaxPoints is the maximum number of points per series in the result
+	// of a range query, as in Prometheus.
+	promMaxPoints = 11000
+)
+
+// promQuantileSuffixes maps the quantiles accepted by histogram_quantile to
+// the suffix of the series that recorded them. See
+// metric.HistogramMetricComputers.
+var promQuantileSuffixes = map[float64]string{
+	0.5:     "p50",
+	0.75:    "p75",
+	0.9:     "p90",
+	0.99:    "p99",
+	0.999:   "p99_9",
+	0.9999:  "p99_99",
+	0.99999: "p99_999",
+	1:       "max",
+}
+
+// promSeries is a timeseries of a tsdump along with its Prometheus labels.
+type promSeries struct {
+	labels map[string]string
+	points []tspb.TimeSeriesDatapoint
+}
+
+// tsDumpStore holds the timeseries of a tsdump in memory so that they can be
+// queried through the Prometheus HTTP API. It implements tsWriter so that it
+// can be fed by the same code as the other formats.
+type tsDumpStore struct {
+	series map[string]*promSeries
+	// sorted contains the series ordered by their labels. It is populated by
+	// Flush.
+	sorted []*promSeries
+	// maxTimestampNanos is the timestamp of the latest sample, used to
+	// evaluate instant queries which do not specify a time.
+	maxTimestampNanos int64
+}
+
+var _ tsWriter = (*tsDumpStore)(nil)
+
+func newTSDumpStore() *tsDumpStore {
+	return &tsDumpStore{series: make(map[string]*promSeries)}
+}
+
+// load reads a tsdump created with --format=raw into the store.
+func (s *tsDumpStore) load(fileName string) error {
+	f, err := getFileReader(fileName)
+	if err != nil {
+		return err
+	}
+	dec := gob.NewDecoder(f)
+	gob.Register(&roachpb.KeyValue{})
+	dumper := ts.DefaultDumper{Send: s.Emit}
+	for {
+		var v roachpb.KeyValue
+		if err := dec.Decode(&v); err != nil {
+			if err == io.EOF {
+				return s.Flush()
+			}
+			return err
+		}
+		if err := dumper.Dump(&v); err != nil {
+			return err
+		}
+	}
+}
+
+// Emit implements the tsWriter interface.
+func (s *tsDumpStore) Emit(data *tspb.TimeSeriesData) error {
+	labels := makePromLabels(data.Name, data.Source)
+	key := promLabelsKey(labels)
+	ps, ok := s.series[key]
+	if !ok {
+		ps = &promSeries{labels: labels}
+		s.series[key] = ps
+	}
+	ps.points = append(ps.points, data.Datapoints...)
+	return nil
+}
+
+// Flush implements the tsWriter interface. It sorts the samples of each
+// series, as a tsdump contains the samples of the 30m resolution before those
+// of the 10s resolution.
+func (s *tsDumpStore) Flush() error {
+	keys := make([]string, 0, len(s.series))
+	for key, ps := range s.series {
+		keys = append(keys, key)
+		sort.SliceStable(ps.points, func(i, j int) bool {
+			return ps.points[i].TimestampNanos < ps.points[j].TimestampNanos
+		})
+		// Drop samples with duplicate timestamps, keeping the last one.
+		points := ps.points[:0]
+		for i, pt := range ps.points {
+			if i+1 < len(ps.points) && ps.points[i+1].TimestampNanos == pt.TimestampNanos {
+				continue
+			}
+			points = append(points, pt)
+		}
+		ps.points = points
+		if n := len(points); n > 0 && points[n-1].TimestampNanos > s.maxTimestampNanos {
+			s.maxTimestampNanos = points[n-1].TimestampNanos
+		}
+	}
+	sort.Strings(keys)
+	s.sorted = s.sorted[:0]
+	for _, key := range keys {
+		s.sorted = append(s.sorted, s.series[key])
+	}
+	return nil
+}
+
+// makePromLabels returns the labels, including the metric name, of the
+// series with the given name and source. The naming matches the one of the
+// openmetrics format.
+func makePromLabels(name, source string) map[string]string {
+	labels := make(map[string]string)
+	if sl := reCrStoreNode.FindStringSubmatch(name); len(sl) != 0 {
+		storeNodeKey := sl[1]
+		if storeNodeKey == "node" {
+			storeNodeKey += "_id"
+		}
+		primary, tenant := tsutil.DecodeSource(source)
+		labels[storeNodeKey] = primary
+		if tenant != "" {
+			labels["tenant_id"] = tenant
+		}
+		name = sl[2]
+	}
+	labels[promNameLabel] = rePromTSName.ReplaceAllLiteralString(name, `_`)
+	return labels
+}
+
+// promLabelsKey returns a string that uniquely identifies a set of labels.
+func promLabelsKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", name, labels[name])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// promVector is a series in the result of a query, with one value per
+// evaluation step.
+type promVector struct {
+	labels map[string]string
+	values []float64
+	// present indicates whether the series has a value at each step.
+	present []bool
+}
+
+// promValue is the result of evaluating a PromQL expression.
+type promValue struct {
+	// scalar is set when the result is a scalar, in which case series
+	// contains a single vector without labels.
+	scalar bool
+	series []promVector
+}
+
+// promEval evaluates PromQL expressions against a tsDumpStore at regular
+// steps between start and end.
+type promEval struct {
+	store            *tsDumpStore
+	start, end, step int64
+	// quantile is the suffix of the recorded percentile to select in place of
+	// histogram buckets, while evaluating the argument of histogram_quantile.
+	quantile string
+}
+
+func (ev *promEval) numSteps() int {
+	return int((ev.end-ev.start)/ev.step) + 1
+}
+
+func (ev *promEval) stepTime(i int) int64 {
+	return ev.start + int64(i)*ev.step
+}
+
+func (ev *promEval) newVector(labels map[string]string) promVector {
+	n := ev.numSteps()
+	return promVector{labels: labels, values: make([]float64, n), present: make([]bool, n)}
+}
+
+func (ev *promEval) eval(expr parser.Expr) (promValue, error) {
+	switch e := expr.(type) {
+	case *parser.ParenExpr:
+		return ev.eval(e.Expr)
+	case *parser.NumberLiteral:
+		v := ev.newVector(nil)
+		for i := range v.values {
+			v.values[i], v.present[i] = e.Val, true
+		}
+		return promValue{scalar: true, series: []promVector{v}}, nil
+	case *parser.UnaryExpr:
+		val, err := ev.eval(e.Expr)
+		if err != nil || e.Op != parser.SUB {
+			return val, err
+		}
+		for _, v := range val.series {
+			delete(v.labels, promNameLabel)
+			for i := range v.values {
+				v.values[i] = -v.values[i]
+			}
+		}
+		return val, nil
+	case *parser.VectorSelector:
+		return ev.evalSelector(e)
+	case *parser.Call:
+		return ev.evalCall(e)
+	case *parser.AggregateExpr:
+		return ev.evalAggregate(e)
+	case *parser.BinaryExpr:
+		return ev.evalBinary(e)
+	default:
+		return promValue{}, errors.Newf("unsupported expression: %s", expr)
+	}
+}
+
+// selectSeries returns the series matched by a selector.
+func (ev *promEval) selectSeries(vs *parser.VectorSelector) ([]*promSeries, error) {
+	if vs.Timestamp != nil || vs.StartOrEnd != 0 {
+		return nil, errors.Newf("the @ modifier is not supported: %s", vs)
+	}
+	name := vs.Name
+	if ev.quantile != "" {
+		if !strings.HasSuffix(name, "_bucket") {
+			return nil, errors.Newf("histogram_quantile requires a _bucket metric, found %s", vs)
+		}
+		name = strings.TrimSuffix(name, "_bucket") + "_" + ev.quantile
+	}
+	var res []*promSeries
+	for _, ps := range ev.store.sorted {
+		if name != "" && ps.labels[promNameLabel] != name {
+			continue
+		}
+		matches := true
+		for _, m := range vs.LabelMatchers {
+			v := ps.labels[m.Name]
+			if ev.quantile != "" {
+				if m.Name == "le" {
+					continue
+				}
+				if m.Name == promNameLabel {
+					v = vs.Name
+				}
+			}
+			if !m.Matches(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			res = append(res, ps)
+		}
+	}
+	return res, nil
+}
+
+// evalSelector evaluates an instant vector selector, which selects the latest
+// sample of each series within the lookback window of each step.
+func (ev *promEval) evalSelector(vs *parser.VectorSelector) (promValue, error) {
+	series, err := ev.selectSeries(vs)
+	if err != nil {
+		return promValue{}, err
+	}
+	var val promValue
+	for _, ps := range series {
+		v := ev.newVector(copyPromLabels(ps.labels))
+		for i := range v.values {
+			window := promWindow(ps.points, ev.stepTime(i)-vs.OriginalOffset.Nanoseconds(), promLookback)
+			if len(window) > 0 {
+				v.values[i], v.present[i] = window[len(window)-1].Value, true
+			}
+		}
+		val.series = append(val.series, v)
+	}
+	return val, nil
+}
+
+// promWindow returns the points with a timestamp in (t-r, t].
+func promWindow(
+	points []tspb.TimeSeriesDatapoint, t int64, r time.Duration,
+) []tspb.TimeSeriesDatapoint {
+	end := sort.Search(len(points), func(i int) bool { return points[i].TimestampNanos > t })
+	start := sort.Search(end, func(i int) bool { return points[i].TimestampNanos > t-r.Nanoseconds() })
+	return points[start:end]
+}
+
+func (ev *promEval) evalCall(e *parser.Call) (promValue, error) {
+	switch e.Func.Name {
+	case "rate", "irate", "increase":
+		ms, ok := e.Args[0].(*parser.MatrixSelector)
+		if !ok {
+			return promValue{}, errors.Newf("unsupported argument to %s: %s", e.Func.Name, e.Args[0])
+		}
+		vs := ms.VectorSelector.(*parser.VectorSelector)
+		if ev.quantile != "" {
+			// Recorded percentiles are computed over a sliding window already,
+			// so they are selected as they are.
+			val, err := ev.evalSelector(vs)
+			for _, v := range val.series {
+				delete(v.labels, promNameLabel)
+			}
+			return val, err
+		}
+		series, err := ev.selectSeries(vs)
+		if err != nil {
+			return promValue{}, err
+		}
+		var val promValue
+		for _, ps := range series {
+			v := ev.newVector(copyPromLabels(ps.labels))
+			delete(v.labels, promNameLabel)
+			for i := range v.values {
+				window := promWindow(ps.points, ev.stepTime(i)-vs.OriginalOffset.Nanoseconds(), ms.Range)
+				v.values[i], v.present[i] = promRate(e.Func.Name, window, ms.Range)
+			}
+			val.series = append(val.series, v)
+		}
+		return val, nil
+	case "histogram_quantile":
+		if ev.quantile != "" {
+			return promValue{}, errors.Newf("nested histogram_quantile is not supported")
+		}
+		q, err := ev.eval(e.Args[0])
+		if err != nil {
+			return promValue{}, err
+		}
+		if !q.scalar || !q.series[0].present[0] {
+			return promValue{}, errors.Newf("unsupported quantile: %s", e.Args[0])
+		}
+		suffix, ok := promQuantileSuffixes[q.series[0].values[0]]
+		if !ok {
+			return promValue{}, errors.Newf(
+				"quantile %v was not recorded, use one of 0.5, 0.75, 0.9, 0.99, 0.999, 0.9999, 0.99999 or 1",
+				q.series[0].values[0])
+		}
+		inner := *ev
+		inner.quantile = suffix
+		val, err := inner.eval(e.Args[1])
+		if err != nil {
+			return promValue{}, err
+		}
+		for _, v := range val.series {
+			delete(v.labels, promNameLabel)
+			delete(v.labels, "le")
+		}
+		return val, nil
+	default:
+		return promValue{}, errors.Newf("unsupported function: %s", e.Func.Name)
+	}
+}
+
+// promRate computes rate, irate or increase over the samples of a window,
+// accounting for counter resets. Unlike Prometheus, it does not extrapolate
+// to the boundaries of the window.
+func promRate(
+	fn string, window []tspb.TimeSeriesDatapoint, r time.Duration,
+) (float64, bool) {
+	if len(window) < 2 {
+		return 0, false
+	}
+	if fn == "irate" {
+		window = window[len(window)-2:]
+	}
+	var delta float64
+	for i := 1; i < len(window); i++ {
+		if cur, prev := window[i].Value, window[i-1].Value; cur >= prev {
+			delta += cur - prev
+		} else {
+			delta += cur
+		}
+	}
+	rate := delta / time.Duration(window[len(window)-1].TimestampNanos-window[0].TimestampNanos).Seconds()
+	if fn == "increase" {
+		return rate * r.Seconds(), true
+	}
+	return rate, true
+}
+
+func (ev *promEval) evalAggregate(e *parser.AggregateExpr) (promValue, error) {
+	val, err := ev.eval(e.Expr)
+	if err != nil {
+		return promValue{}, err
+	}
+	if val.scalar {
+		return promValue{}, errors.Newf("expected a vector in aggregation: %s", e)
+	}
+	op := e.Op
+	switch op {
+	case parser.SUM, parser.AVG, parser.MIN, parser.MAX, parser.COUNT:
+	default:
+		return promValue{}, errors.Newf("unsupported aggregation: %s", op)
+	}
+	if ev.quantile != "" && op == parser.SUM {
+		// Percentiles cannot be summed. Their maximum is an upper bound of the
+		// percentile of the aggregated histogram.
+		op = parser.MAX
+	}
+	grouping := make(map[string]bool, len(e.Grouping))
+	for _, name := range e.Grouping {
+		grouping[name] = true
+	}
+
+	groups := make(map[string]*promVector)
+	var counts map[string][]int
+	if op == parser.AVG {
+		counts = make(map[string][]int)
+	}
+	var keys []string
+	for _, v := range val.series {
+		labels := make(map[string]string)
+		for name, value := range v.labels {
+			if name != promNameLabel && grouping[name] != e.Without {
+				labels[name] = value
+			}
+		}
+		key := promLabelsKey(labels)
+		g, ok := groups[key]
+		if !ok {
+			nv := ev.newVector(labels)
+			g = &nv
+			groups[key] = g
+			keys = append(keys, key)
+			if counts != nil {
+				counts[key] = make([]int, len(nv.values))
+			}
+		}
+		for i, present := range v.present {
+			if !present {
+				continue
+			}
+			x := v.values[i]
+			switch {
+			case !g.present[i]:
+				g.values[i] = x
+				if op == parser.COUNT {
+					g.values[i] = 1
+				}
+			case op == parser.SUM, op == parser.AVG:
+				g.values[i] += x
+			case op == parser.COUNT:
+				g.values[i]++
+			case op == parser.MIN:
+				g.values[i] = math.Min(g.values[i], x)
+			case op == parser.MAX:
+				g.values[i] = math.Max(g.values[i], x)
+			}
+			g.present[i] = true
+			if counts != nil {
+				counts[key][i]++
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	var res promValue
+	for _, key := range keys {
+		g := groups[key]
+		for i := range g.values {
+			if counts != nil && g.present[i] {
+				g.values[i] /= float64(counts[key][i])
+			}
+		}
+		res.series = append(res.series, *g)
+	}
+	return res, nil
+}
+
+func (ev *promEval) evalBinary(e *parser.BinaryExpr) (promValue, error) {
+	var apply func(l, r float64) float64
+	switch e.Op {
+	case parser.ADD:
+		apply = func(l, r float64) float64 { return l + r }
+	case parser.SUB:
+		apply = func(l, r float64) float64 { return l - r }
+	case parser.MUL:
+		apply = func(l, r float64) float64 { return l * r }
+	case parser.DIV:
+		apply = func(l, r float64) float64 { return l / r }
+	default:
+		return promValue{}, errors.Newf("unsupported operator: %s", e.Op)
+	}
+	lhs, err := ev.eval(e.LHS)
+	if err != nil {
+		return promValue{}, err
+	}
+	rhs, err := ev.eval(e.RHS)
+	if err != nil {
+		return promValue{}, err
+	}
+
+	combine := func(labels map[string]string, l, r promVector) promVector {
+		v := ev.newVector(labels)
+		for i := range v.values {
+			if l.present[i] && r.present[i] {
+				v.values[i], v.present[i] = apply(l.values[i], r.values[i]), true
+			}
+		}
+		return v
+	}
+	withoutName := func(labels map[string]string) map[string]string {
+		res := copyPromLabels(labels)
+		delete(res, promNameLabel)
+		return res
+	}
+
+	var res promValue
+	switch {
+	case lhs.scalar && rhs.scalar:
+		res.scalar = true
+		res.series = []promVector{combine(nil, lhs.series[0], rhs.series[0])}
+	case lhs.scalar:
+		for _, r := range rhs.series {
+			res.series = append(res.series, combine(withoutName(r.labels), lhs.series[0], r))
+		}
+	case rhs.scalar:
+		for _, l := range lhs.series {
+			res.series = append(res.series, combine(withoutName(l.labels), l, rhs.series[0]))
+		}
+	default:
+		matching := e.VectorMatching
+		if matching.Card != parser.CardOneToOne {
+			return promValue{}, errors.Newf("only one-to-one vector matching is supported: %s", e)
+		}
+		matchingLabels := make(map[string]bool, len(matching.MatchingLabels))
+		for _, name := range matching.MatchingLabels {
+			matchingLabels[name] = true
+		}
+		signature := func(labels map[string]string) map[string]string {
+			res := make(map[string]string)
+			for name, value := range labels {
+				if name != promNameLabel && matchingLabels[name] == matching.On {
+					res[name] = value
+				}
+			}
+			return res
+		}
+		rhsBySignature := make(map[string]promVector, len(rhs.series))
+		for _, r := range rhs.series {
+			key := promLabelsKey(signature(r.labels))
+			if _, ok := rhsBySignature[key]; ok {
+				return promValue{}, errors.Newf(
+					"many-to-many matching is not supported, multiple series match %s on the right-hand side", key)
+			}
+			rhsBySignature[key] = r
+		}
+		for _, l := range lhs.series {
+			sig := signature(l.labels)
+			r, ok := rhsBySignature[promLabelsKey(sig)]
+			if !ok {
+				continue
+			}
+			labels := sig
+			if !matching.On {
+				labels = withoutName(l.labels)
+			}
+			res.series = append(res.series, combine(labels, l, r))
+		}
+	}
+	return res, nil
+}
+
+func copyPromLabels(labels map[string]string) map[string]string {
+	res := make(map[string]string, len(labels))
+	for name, value := range labels {
+		res[name] = value
+	}
+	return res
+}
+
+// query evaluates a PromQL query between start and end.
+func (s *tsDumpStore) query(query string, start, end, step int64) (promValue, error) {
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return promValue{}, err
+	}
+	ev := &promEval{store: s, start: start, end: end, step: step}
+	return ev.eval(expr)
+}
+
+// promResponse is the envelope of the responses of the Prometheus HTTP API.
+type promResponse struct {
+	Status    string      `json:"status"`
+	Data      interface{} `json:"data,omitempty"`
+	ErrorType string      `json:"errorType,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+type promQueryData struct {
+	ResultType string      `json:"resultType"`
+	Result     interface{} `json:"result"`
+}
+
+type promMatrixSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]interface{}  `json:"values"`
+}
+
+type promVectorSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+// promSample formats a sample as a pair of its timestamp in seconds and its
+// value as a string.
+func promSample(t int64, v float64) [2]interface{} {
+	return [2]interface{}{float64(t) / 1e9, strconv.FormatFloat(v, 'f', -1, 64)}
+}
+
+func (s *tsDumpStore) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query", s.handleQuery)
+	mux.HandleFunc("/api/v1/query_range", s.handleQueryRange)
+	mux.HandleFunc("/api/v1/series", s.handleSeries)
+	mux.HandleFunc("/api/v1/labels", s.handleLabels)
+	mux.HandleFunc("/api/v1/label/", s.handleLabelValues)
+	return mux
+}
+
+func (s *tsDumpStore) handleQuery(w http.ResponseWriter, r *http.Request) {
+	t := s.maxTimestampNanos
+	if param := r.FormValue("time"); param != "" {
+		var err error
+		if t, err = parsePromTime(param); err != nil {
+			writePromError(w, err)
+			return
+		}
+	}
+	val, err := s.query(r.FormValue("query"), t, t, 1 /* step */)
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	if val.scalar {
+		writePromData(w, promQueryData{ResultType: "scalar", Result: promSample(t, val.series[0].values[0])})
+		return
+	}
+	result := []promVectorSample{}
+	for _, v := range val.series {
+		if v.present[0] {
+			result = append(result, promVectorSample{Metric: v.labels, Value: promSample(t, v.values[0])})
+		}
+	}
+	writePromData(w, promQueryData{ResultType: "vector", Result: result})
+}
+
+func (s *tsDumpStore) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	start, err := parsePromTime(r.FormValue("start"))
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	end, err := parsePromTime(r.FormValue("end"))
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	step, err := parsePromDuration(r.FormValue("step"))
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	switch {
+	case end < start:
+		writePromError(w, errors.New("end timestamp must not be before start time"))
+		return
+	case step <= 0:
+		writePromError(w, errors.New("zero or negative query resolution step widths are not accepted"))
+		return
+	case (end-start)/step >= promMaxPoints:
+		writePromError(w, errors.Newf(
+			"exceeded maximum resolution of %d points per timeseries, try increasing the step", promMaxPoints))
+		return
+	}
+	val, err := s.query(r.FormValue("query"), start, end, step)
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	result := []promMatrixSeries{}
+	for _, v := range val.series {
+		series := promMatrixSeries{Metric: v.labels}
+		if series.Metric == nil {
+			series.Metric = map[string]string{}
+		}
+		for i, present := range v.present {
+			if present {
+				series.Values = append(series.Values, promSample(start+int64(i)*step, v.values[i]))
+			}
+		}
+		if len(series.Values) > 0 {
+			result = append(result, series)
+		}
+	}
+	writePromData(w, promQueryData{ResultType: "matrix", Result: result})
+}
+
+// matchSeries returns the series matched by any of the match[] selectors of a
+// request, or all series if there are none.
+func (s *tsDumpStore) matchSeries(r *http.Request) ([]*promSeries, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	selectors := r.Form["match[]"]
+	if len(selectors) == 0 {
+		return s.sorted, nil
+	}
+	var res []*promSeries
+	for _, ps := range s.sorted {
+		for _, selector := range selectors {
+			matchers, err := parser.ParseMetricSelector(selector)
+			if err != nil {
+				return nil, err
+			}
+			matches := true
+			for _, m := range matchers {
+				if !m.Matches(ps.labels[m.Name]) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				res = append(res, ps)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *tsDumpStore) handleSeries(w http.ResponseWriter, r *http.Request) {
+	series, err := s.matchSeries(r)
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	result := []map[string]string{}
+	for _, ps := range series {
+		result = append(result, ps.labels)
+	}
+	writePromData(w, result)
+}
+
+func (s *tsDumpStore) handleLabels(w http.ResponseWriter, r *http.Request) {
+	series, err := s.matchSeries(r)
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	names := make(map[string]struct{})
+	for _, ps := range series {
+		for name := range ps.labels {
+			names[name] = struct{}{}
+		}
+	}
+	writePromData(w, sortedKeys(names))
+}
+
+func (s *tsDumpStore) handleLabelValues(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/label/"), "/values")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	series, err := s.matchSeries(r)
+	if err != nil {
+		writePromError(w, err)
+		return
+	}
+	values := make(map[string]struct{})
+	for _, ps := range series {
+		if v, ok := ps.labels[name]; ok {
+			values[v] = struct{}{}
+		}
+	}
+	writePromData(w, sortedKeys(values))
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func writePromData(w http.ResponseWriter, data interface{}) {
+	writePromResponse(w, http.StatusOK, promResponse{Status: "success", Data: data})
+}
+
+func writePromError(w http.ResponseWriter, err error) {
+	writePromResponse(w, http.StatusBadRequest, promResponse{
+		Status: "error", ErrorType: "bad_data", Error: err.Error(),
+	})
+}
+
+func writePromResponse(w http.ResponseWriter, code int, res promResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		fmt.Fprintf(stderr, "writing response: %v\n", err)
+	}
+}
+
+// parsePromTime parses a timestamp given either in seconds since the epoch or
+// in RFC3339 format, and returns it in nanoseconds.
+func parsePromTime(s string) (int64, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(math.Round(f * 1e9)), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, errors.Newf("cannot parse %q to a valid timestamp", s)
+	}
+	return t.UnixNano(), nil
+}
+
+// parsePromDuration parses a duration given either in seconds or in Go
+// duration format, and returns it in nanoseconds.
+func parsePromDuration(s string) (int64, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(math.Round(f * 1e9)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Newf("cannot parse %q to a valid duration", s)
+	}
+	return d.Nanoseconds(), nil
+}
@@ -13,6 +13,8 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -252,3 +254,37 @@ func TestTsDumpFormatsDataDriven(t *testing.T) {
 		})
 	})
 }
+
+func TestDebugTimeSeriesServe(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s := newTSDumpStore()
+	h := s.handler()
+	datadriven.RunTest(t, "testdata/tsdump_serve", func(t *testing.T, d *datadriven.TestData) string {
+		var path string
+		switch d.Cmd {
+		case "load":
+			parseTSInput(t, d.Input, s)
+			require.NoError(t, s.Flush())
+			return ""
+		case "query":
+			params := url.Values{"query": {d.Input}}
+			for _, arg := range d.CmdArgs {
+				params.Set(arg.Key, arg.Vals[0])
+			}
+			path = "/api/v1/" + d.Cmd
+			if params.Has("step") {
+				path += "_range"
+			}
+			path += "?" + params.Encode()
+		case "get":
+			path = d.Input
+		default:
+			t.Fatalf("unknown command: %s", d.Cmd)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return fmt.Sprintf("%d %s", rec.Code, rec.Body.String())
+	})
+}
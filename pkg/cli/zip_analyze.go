@@ -0,0 +1,792 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugZipAnalyzeOpts = struct {
+	format string
+}{
+	format: "text",
+}
+
+// Thresholds used by the debug zip analysis rules.
+const (
+	// zipAnalyzeMaxDetails is the maximum number of details listed for a
+	// finding.
+	zipAnalyzeMaxDetails = 10
+	// zipAnalyzeLeaseImbalanceRatio is how far, relative to the mean, the
+	// number of leaseholders of a store can be from the mean before it is
+	// considered imbalanced.
+	zipAnalyzeLeaseImbalanceRatio = 0.5
+	// zipAnalyzeLeaseImbalanceMin is the minimum difference between the number
+	// of leaseholders of a store and the mean to be considered imbalanced, so
+	// that small clusters are not flagged.
+	zipAnalyzeLeaseImbalanceMin = 10
+	// zipAnalyzeClockOffsetWarning and zipAnalyzeClockOffsetCritical are the
+	// mean clock offsets of a node that are flagged. The latter is 80% of the
+	// default maximum offset, beyond which nodes terminate.
+	zipAnalyzeClockOffsetWarning  = 100 * time.Millisecond
+	zipAnalyzeClockOffsetCritical = 400 * time.Millisecond
+	// The L0 sublevel and file counts of a store that are flagged. The critical
+	// thresholds are the ones at which admission control considers a store
+	// overloaded.
+	zipAnalyzeL0SublevelsWarning  = 10
+	zipAnalyzeL0SublevelsCritical = 20
+	zipAnalyzeL0FilesWarning      = 500
+	zipAnalyzeL0FilesCritical     = 1000
+	// zipAnalyzeStuckJobThreshold is how long a job can go without updating
+	// its progress before it is considered stuck.
+	zipAnalyzeStuckJobThreshold = time.Hour
+	// zipAnalyzeLongTxnWarning and zipAnalyzeLongTxnCritical are the ages of
+	// open transactions that are flagged.
+	zipAnalyzeLongTxnWarning  = 5 * time.Minute
+	zipAnalyzeLongTxnCritical = time.Hour
+	// zipAnalyzeHeartbeatFailures is the number of failed liveness heartbeats
+	// of a node that is flagged.
+	zipAnalyzeHeartbeatFailures = 3
+	// zipAnalyzeHotRangeQPS and zipAnalyzeHotRangeCPU are the load of a range
+	// that is flagged. They match the default thresholds for load-based
+	// splitting, so a range above them could not be split.
+	zipAnalyzeHotRangeQPS = 2500
+	zipAnalyzeHotRangeCPU = 500 * time.Millisecond
+	// zipAnalyzeHottestRanges is the number of hottest ranges that are
+	// reported.
+	zipAnalyzeHottestRanges = 5
+)
+
+// zipFindingSeverity is the severity of a finding of the debug zip analysis.
+type zipFindingSeverity int
+
+const (
+	zipFindingInfo zipFindingSeverity = iota
+	zipFindingWarning
+	zipFindingCritical
+)
+
+func (s zipFindingSeverity) String() string {
+	switch s {
+	case zipFindingInfo:
+		return "info"
+	case zipFindingWarning:
+		return "warning"
+	case zipFindingCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s zipFindingSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// zipFinding is a problem found by a rule of the debug zip analysis.
+type zipFinding struct {
+	Rule     string             `json:"rule"`
+	Severity zipFindingSeverity `json:"severity"`
+	Summary  string             `json:"summary"`
+	Details  []string           `json:"details,omitempty"`
+}
+
+// zipSkippedRule is a rule that could not be evaluated, usually because the
+// debug zip does not contain the data it needs.
+type zipSkippedRule struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// zipAnalysisReport is the result of the debug zip analysis.
+type zipAnalysisReport struct {
+	Dir         string           `json:"dir"`
+	Nodes       int              `json:"nodes,omitempty"`
+	CaptureTime *time.Time       `json:"capture_time,omitempty"`
+	Findings    []zipFinding     `json:"findings"`
+	Skipped     []zipSkippedRule `json:"skipped,omitempty"`
+}
+
+// zipAnalysisRule is a check of the debug zip analysis.
+type zipAnalysisRule struct {
+	name        string
+	description string
+	check       func(b *zipBundle) ([]zipFinding, error)
+}
+
+// zipAnalysisRules are the checks performed by `debug zip analyze`. To add a
+// check, add a rule here.
+var zipAnalysisRules = []zipAnalysisRule{
+	{
+		name:        "unavailable-ranges",
+		description: "ranges that lost quorum",
+		check:       checkZipUnavailableRanges,
+	},
+	{
+		name:        "underreplicated-ranges",
+		description: "ranges with fewer replicas than configured",
+		check:       checkZipUnderreplicatedRanges,
+	},
+	{
+		name:        "lease-imbalance",
+		description: "stores holding many more or fewer leases than the others",
+		check:       checkZipLeaseImbalance,
+	},
+	{
+		name:        "clock-offset",
+		description: "nodes whose clock is offset from the rest of the cluster",
+		check:       checkZipClockOffset,
+	},
+	{
+		name:        "lsm-inversion",
+		description: "stores with too many files or sublevels in L0",
+		check:       checkZipLSMInversion,
+	},
+	{
+		name:        "stuck-jobs",
+		description: "jobs that have not made progress recently",
+		check:       checkZipStuckJobs,
+	},
+	{
+		name:        "long-running-transactions",
+		description: "transactions that have been open for a long time",
+		check:       checkZipLongRunningTransactions,
+	},
+	{
+		name:        "liveness-flaps",
+		description: "nodes that failed to heartbeat their liveness record",
+		check:       checkZipLivenessFlaps,
+	},
+	{
+		name:        "hot-ranges",
+		description: "ranges serving the most load",
+		check:       checkZipHotRanges,
+	},
+}
+
+func runDebugZipAnalyze(_ *cobra.Command, args []string) error {
+	switch debugZipAnalyzeOpts.format {
+	case "text", "json":
+	default:
+		return errors.Newf("unknown format %q, expected text or json", debugZipAnalyzeOpts.format)
+	}
+	dir := args[0]
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+	report := analyzeDebugZip(dir)
+	if debugZipAnalyzeOpts.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return report.format(os.Stdout)
+}
+
+// analyzeDebugZip runs all the rules against the extracted debug zip in dir.
+func analyzeDebugZip(dir string) *zipAnalysisReport {
+	b := &zipBundle{dir: dir}
+	report := &zipAnalysisReport{Dir: dir, Findings: []zipFinding{}}
+	if nodes, err := b.nodes(); err == nil {
+		report.Nodes = len(nodes)
+		if t, err := b.captureTime(); err == nil {
+			report.CaptureTime = &t
+		}
+	}
+	for _, rule := range zipAnalysisRules {
+		findings, err := rule.check(b)
+		if err != nil {
+			report.Skipped = append(report.Skipped, zipSkippedRule{Rule: rule.name, Reason: err.Error()})
+			continue
+		}
+		for _, f := range findings {
+			f.Rule = rule.name
+			report.Findings = append(report.Findings, f)
+		}
+	}
+	// Rank the findings by severity, keeping the order of the rules otherwise.
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Severity > report.Findings[j].Severity
+	})
+	return report
+}
+
+func (r *zipAnalysisReport) format(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "debug zip: %s\n", r.Dir)
+	if r.Nodes > 0 {
+		fmt.Fprintf(&b, "nodes: %d\n", r.Nodes)
+	}
+	if r.CaptureTime != nil {
+		fmt.Fprintf(&b, "captured at: %s\n", r.CaptureTime.Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+	if len(r.Findings) == 0 {
+		b.WriteString("no problems found\n")
+	}
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "%-8s  %s: %s\n", strings.ToUpper(f.Severity.String()), f.Rule, f.Summary)
+		for _, d := range f.Details {
+			fmt.Fprintf(&b, "          %s\n", d)
+		}
+	}
+	if len(r.Skipped) > 0 {
+		b.WriteString("\nskipped checks:\n")
+		for _, s := range r.Skipped {
+			fmt.Fprintf(&b, "  %s: %s\n", s.Rule, s.Reason)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// zipNodeStatus contains the fields of a statuspb.NodeStatus in nodes.json
+// that are used by the analysis.
+type zipNodeStatus struct {
+	Desc struct {
+		NodeID int32 `json:"node_id"`
+	} `json:"desc"`
+	UpdatedAt     int64              `json:"updated_at"`
+	Metrics       map[string]float64 `json:"metrics"`
+	StoreStatuses []struct {
+		Desc struct {
+			StoreID int32 `json:"store_id"`
+		} `json:"desc"`
+		Metrics map[string]float64 `json:"metrics"`
+	} `json:"store_statuses"`
+}
+
+// zipRangeInfo contains the fields of a serverpb.RangeInfo in the ranges.json
+// of a node that are used by the analysis.
+type zipRangeInfo struct {
+	Span struct {
+		StartKey string `json:"start_key"`
+		EndKey   string `json:"end_key"`
+	} `json:"span"`
+	State struct {
+		State struct {
+			Desc struct {
+				RangeID int64 `json:"range_id"`
+			} `json:"desc"`
+		} `json:"state"`
+	} `json:"state"`
+	SourceNodeID  int32 `json:"source_node_id"`
+	SourceStoreID int32 `json:"source_store_id"`
+	Problems      struct {
+		Unavailable     bool `json:"unavailable"`
+		Underreplicated bool `json:"underreplicated"`
+	} `json:"problems"`
+	Stats struct {
+		QueriesPerSecond float64 `json:"queries_per_second"`
+		CPUTimePerSecond float64 `json:"cpu_time_per_second"`
+	} `json:"stats"`
+	IsLeaseholder bool `json:"is_leaseholder"`
+}
+
+func (ri *zipRangeInfo) rangeID() int64 {
+	return ri.State.State.Desc.RangeID
+}
+
+// zipBundle gives access to the contents of an extracted debug zip, loading
+// each file at most once.
+type zipBundle struct {
+	dir string
+
+	nodeStatuses struct {
+		loaded bool
+		nodes  []zipNodeStatus
+		err    error
+	}
+	rangeInfos struct {
+		loaded bool
+		ranges []zipRangeInfo
+		err    error
+	}
+}
+
+// open opens a file of the debug zip, returning a readable error if it is
+// missing.
+func (b *zipBundle) open(name string) (*os.File, error) {
+	f, err := os.Open(path.Join(b.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Newf("%s is missing", name)
+	}
+	return f, err
+}
+
+func (b *zipBundle) readJSON(name string, v interface{}) error {
+	f, err := b.open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return errors.Wrapf(json.NewDecoder(f).Decode(v), "parsing %s", name)
+}
+
+// nodes returns the node statuses in nodes.json.
+func (b *zipBundle) nodes() ([]zipNodeStatus, error) {
+	if !b.nodeStatuses.loaded {
+		b.nodeStatuses.loaded = true
+		var res struct {
+			Nodes []zipNodeStatus `json:"nodes"`
+		}
+		b.nodeStatuses.err = b.readJSON(nodesFile, &res)
+		sort.Slice(res.Nodes, func(i, j int) bool {
+			return res.Nodes[i].Desc.NodeID < res.Nodes[j].Desc.NodeID
+		})
+		b.nodeStatuses.nodes = res.Nodes
+	}
+	return b.nodeStatuses.nodes, b.nodeStatuses.err
+}
+
+// captureTime returns when the debug zip was collected, which is approximated
+// by the latest update of the node statuses.
+func (b *zipBundle) captureTime() (time.Time, error) {
+	nodes, err := b.nodes()
+	if err != nil {
+		return time.Time{}, err
+	}
+	var latest int64
+	for _, n := range nodes {
+		latest = max(latest, n.UpdatedAt)
+	}
+	if latest == 0 {
+		return time.Time{}, errors.Newf("%s does not contain the time of the node statuses", nodesFile)
+	}
+	return time.Unix(0, latest).UTC(), nil
+}
+
+// ranges returns the replicas in the ranges.json of all nodes.
+func (b *zipBundle) ranges() ([]zipRangeInfo, error) {
+	if !b.rangeInfos.loaded {
+		b.rangeInfos.loaded = true
+		b.rangeInfos.ranges, b.rangeInfos.err = b.loadRanges()
+	}
+	return b.rangeInfos.ranges, b.rangeInfos.err
+}
+
+func (b *zipBundle) loadRanges() ([]zipRangeInfo, error) {
+	files, err := filepath.Glob(path.Join(b.dir, "nodes", "*", rangesInfoFileName))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.Newf("nodes/*/%s is missing", rangesInfoFileName)
+	}
+	var res []zipRangeInfo
+	for _, file := range files {
+		name, err := filepath.Rel(b.dir, file)
+		if err != nil {
+			return nil, err
+		}
+		var ranges []zipRangeInfo
+		if err := b.readJSON(name, &ranges); err != nil {
+			return nil, err
+		}
+		res = append(res, ranges...)
+	}
+	return res, nil
+}
+
+// table returns the rows of a table dump, each as a map from column name to
+// value.
+func (b *zipBundle) table(name string) ([]map[string]string, error) {
+	f, err := b.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var rows []map[string]string
+	header, iter := makeTableIterator(f)
+	if err := iter(func(line string) error {
+		cols := strings.Split(line, "\t")
+		if len(cols) != len(header) {
+			return errors.Newf("the number of headers is not matching the number of columns in the row")
+		}
+		row := make(map[string]string, len(header))
+		for i, h := range header {
+			row[h] = cols[i]
+		}
+		rows = append(rows, row)
+		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", name)
+	}
+	return rows, nil
+}
+
+// parseZipTimestamp parses a timestamp in a table dump.
+func parseZipTimestamp(s string) (time.Time, error) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999-07:00",
+		"2006-01-02 15:04:05.999999-07",
+		"2006-01-02 15:04:05.999999",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.Newf("cannot parse timestamp %q", s)
+}
+
+// limitZipDetails truncates the details of a finding to
+// zipAnalyzeMaxDetails.
+func limitZipDetails(details []string) []string {
+	if len(details) <= zipAnalyzeMaxDetails {
+		return details
+	}
+	more := len(details) - zipAnalyzeMaxDetails
+	return append(details[:zipAnalyzeMaxDetails:zipAnalyzeMaxDetails], fmt.Sprintf("... and %d more", more))
+}
+
+// checkZipRangeProblem reports the ranges that have a problem according to
+// the ranges.json of the nodes, or, if the debug zip does not contain range
+// information, the number of such ranges according to the metric of the
+// stores.
+func checkZipRangeProblem(
+	b *zipBundle,
+	severity zipFindingSeverity,
+	what string,
+	hasProblem func(ri *zipRangeInfo) bool,
+	metric string,
+) ([]zipFinding, error) {
+	ranges, rangesErr := b.ranges()
+	if rangesErr != nil {
+		nodes, err := b.nodes()
+		if err != nil {
+			return nil, errors.CombineErrors(rangesErr, err)
+		}
+		var count float64
+		var details []string
+		for _, n := range nodes {
+			for _, s := range n.StoreStatuses {
+				if v := s.Metrics[metric]; v > 0 {
+					count += v
+					details = append(details, fmt.Sprintf("s%d on n%d: %.0f", s.Desc.StoreID, n.Desc.NodeID, v))
+				}
+			}
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		return []zipFinding{{
+			Severity: severity,
+			Summary:  fmt.Sprintf("%.0f %s range%s", count, what, util.Pluralize(int64(count))),
+			Details:  limitZipDetails(details),
+		}}, nil
+	}
+
+	seen := make(map[int64]bool)
+	var details []string
+	for i := range ranges {
+		ri := &ranges[i]
+		if !hasProblem(ri) || seen[ri.rangeID()] {
+			continue
+		}
+		seen[ri.rangeID()] = true
+		details = append(details, fmt.Sprintf("r%d [%s, %s) reported by n%d",
+			ri.rangeID(), ri.Span.StartKey, ri.Span.EndKey, ri.SourceNodeID))
+	}
+	if len(seen) == 0 {
+		return nil, nil
+	}
+	return []zipFinding{{
+		Severity: severity,
+		Summary:  fmt.Sprintf("%d %s range%s", len(seen), what, util.Pluralize(int64(len(seen)))),
+		Details:  limitZipDetails(details),
+	}}, nil
+}
+
+func checkZipUnavailableRanges(b *zipBundle) ([]zipFinding, error) {
+	return checkZipRangeProblem(b, zipFindingCritical, "unavailable",
+		func(ri *zipRangeInfo) bool { return ri.Problems.Unavailable }, "ranges.unavailable")
+}
+
+func checkZipUnderreplicatedRanges(b *zipBundle) ([]zipFinding, error) {
+	return checkZipRangeProblem(b, zipFindingWarning, "under-replicated",
+		func(ri *zipRangeInfo) bool { return ri.Problems.Underreplicated }, "ranges.underreplicated")
+}
+
+func checkZipLeaseImbalance(b *zipBundle) ([]zipFinding, error) {
+	nodes, err := b.nodes()
+	if err != nil {
+		return nil, err
+	}
+	type storeLeases struct {
+		nodeID, storeID int32
+		leases          float64
+	}
+	var stores []storeLeases
+	var total float64
+	for _, n := range nodes {
+		for _, s := range n.StoreStatuses {
+			leases := s.Metrics["replicas.leaseholders"]
+			stores = append(stores, storeLeases{nodeID: n.Desc.NodeID, storeID: s.Desc.StoreID, leases: leases})
+			total += leases
+		}
+	}
+	if len(stores) < 2 {
+		return nil, nil
+	}
+	mean := total / float64(len(stores))
+	var details []string
+	for _, s := range stores {
+		if diff := math.Abs(s.leases - mean); diff >= zipAnalyzeLeaseImbalanceMin &&
+			diff > mean*zipAnalyzeLeaseImbalanceRatio {
+			details = append(details, fmt.Sprintf("s%d on n%d: %.0f leases", s.storeID, s.nodeID, s.leases))
+		}
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+	return []zipFinding{{
+		Severity: zipFindingWarning,
+		Summary: fmt.Sprintf("%d of %d stores with an imbalanced number of leases (mean %.0f)",
+			len(details), len(stores), mean),
+		Details: limitZipDetails(details),
+	}}, nil
+}
+
+func checkZipClockOffset(b *zipBundle) ([]zipFinding, error) {
+	nodes, err := b.nodes()
+	if err != nil {
+		return nil, err
+	}
+	var findings []zipFinding
+	for _, n := range nodes {
+		offset := time.Duration(math.Abs(n.Metrics["clock-offset.meannanos"]))
+		severity := zipFindingCritical
+		switch {
+		case offset >= zipAnalyzeClockOffsetCritical:
+		case offset >= zipAnalyzeClockOffsetWarning:
+			severity = zipFindingWarning
+		default:
+			continue
+		}
+		findings = append(findings, zipFinding{
+			Severity: severity,
+			Summary: fmt.Sprintf("n%d has a mean clock offset of %s from the other nodes",
+				n.Desc.NodeID, offset.Round(time.Millisecond)),
+		})
+	}
+	return findings, nil
+}
+
+func checkZipLSMInversion(b *zipBundle) ([]zipFinding, error) {
+	nodes, err := b.nodes()
+	if err != nil {
+		return nil, err
+	}
+	var findings []zipFinding
+	for _, n := range nodes {
+		for _, s := range n.StoreStatuses {
+			sublevels, files := s.Metrics["storage.l0-sublevels"], s.Metrics["storage.l0-num-files"]
+			severity := zipFindingCritical
+			switch {
+			case sublevels >= zipAnalyzeL0SublevelsCritical, files >= zipAnalyzeL0FilesCritical:
+			case sublevels >= zipAnalyzeL0SublevelsWarning, files >= zipAnalyzeL0FilesWarning:
+				severity = zipFindingWarning
+			default:
+				continue
+			}
+			findings = append(findings, zipFinding{
+				Severity: severity,
+				Summary: fmt.Sprintf("s%d on n%d has an inverted LSM: %.0f sublevels and %.0f files in L0",
+					s.Desc.StoreID, n.Desc.NodeID, sublevels, files),
+			})
+		}
+	}
+	return findings, nil
+}
+
+func checkZipStuckJobs(b *zipBundle) ([]zipFinding, error) {
+	now, err := b.captureTime()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := b.table("crdb_internal.jobs.txt")
+	if err != nil {
+		return nil, err
+	}
+	var details []string
+	for _, row := range rows {
+		switch row["status"] {
+		case "running", "reverting", "pause-requested", "cancel-requested":
+		default:
+			continue
+		}
+		modified, err := parseZipTimestamp(row["modified"])
+		if err != nil {
+			return nil, errors.Wrapf(err, "job %s", row["job_id"])
+		}
+		if age := now.Sub(modified); age >= zipAnalyzeStuckJobThreshold {
+			details = append(details, fmt.Sprintf("job %s (%s) is %s and has not been updated for %s",
+				row["job_id"], row["job_type"], row["status"], age.Round(time.Second)))
+		}
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+	return []zipFinding{{
+		Severity: zipFindingWarning,
+		Summary: fmt.Sprintf("%d job%s without progress for over %s",
+			len(details), util.Pluralize(int64(len(details))), zipAnalyzeStuckJobThreshold),
+		Details: limitZipDetails(details),
+	}}, nil
+}
+
+func checkZipLongRunningTransactions(b *zipBundle) ([]zipFinding, error) {
+	now, err := b.captureTime()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := b.table("crdb_internal.cluster_transactions.txt")
+	if err != nil {
+		return nil, err
+	}
+	type txn struct {
+		age  time.Duration
+		desc string
+	}
+	var txns []txn
+	for _, row := range rows {
+		start, err := parseZipTimestamp(row["start"])
+		if err != nil {
+			return nil, errors.Wrapf(err, "transaction %s", row["id"])
+		}
+		if age := now.Sub(start); age >= zipAnalyzeLongTxnWarning {
+			txns = append(txns, txn{age: age, desc: fmt.Sprintf(
+				"transaction %s on n%s (application %q) open for %s, %s statements, %s retries",
+				row["id"], row["node_id"], row["application_name"], age.Round(time.Second),
+				row["num_stmts"], row["num_retries"])})
+		}
+	}
+	if len(txns) == 0 {
+		return nil, nil
+	}
+	sort.Slice(txns, func(i, j int) bool { return txns[i].age > txns[j].age })
+	severity := zipFindingWarning
+	if txns[0].age >= zipAnalyzeLongTxnCritical {
+		severity = zipFindingCritical
+	}
+	details := make([]string, len(txns))
+	for i := range txns {
+		details[i] = txns[i].desc
+	}
+	return []zipFinding{{
+		Severity: severity,
+		Summary: fmt.Sprintf("%d transaction%s open for over %s",
+			len(txns), util.Pluralize(int64(len(txns))), zipAnalyzeLongTxnWarning),
+		Details: limitZipDetails(details),
+	}}, nil
+}
+
+func checkZipLivenessFlaps(b *zipBundle) ([]zipFinding, error) {
+	nodes, err := b.nodes()
+	if err != nil {
+		return nil, err
+	}
+	var findings []zipFinding
+	var epochIncrements float64
+	for _, n := range nodes {
+		epochIncrements += n.Metrics["liveness.epochincrements"]
+		if failures := n.Metrics["liveness.heartbeatfailures"]; failures >= zipAnalyzeHeartbeatFailures {
+			findings = append(findings, zipFinding{
+				Severity: zipFindingWarning,
+				Summary:  fmt.Sprintf("n%d failed to heartbeat its liveness record %.0f times", n.Desc.NodeID, failures),
+			})
+		}
+	}
+	if epochIncrements > 0 {
+		findings = append(findings, zipFinding{
+			Severity: zipFindingWarning,
+			Summary: fmt.Sprintf("the liveness epoch of nodes was incremented %.0f times, "+
+				"so nodes lost their leases after failing to heartbeat", epochIncrements),
+		})
+	}
+	return findings, nil
+}
+
+func checkZipHotRanges(b *zipBundle) ([]zipFinding, error) {
+	ranges, err := b.ranges()
+	if err != nil {
+		return nil, err
+	}
+	// Each range is reported by all its replicas, whose statistics are only
+	// meaningful on the leaseholder.
+	byRangeID := make(map[int64]*zipRangeInfo)
+	for i := range ranges {
+		ri := &ranges[i]
+		if prev, ok := byRangeID[ri.rangeID()]; !ok || (ri.IsLeaseholder && !prev.IsLeaseholder) {
+			byRangeID[ri.rangeID()] = ri
+		}
+	}
+	hottest := make([]*zipRangeInfo, 0, len(byRangeID))
+	for _, ri := range byRangeID {
+		hottest = append(hottest, ri)
+	}
+	sort.Slice(hottest, func(i, j int) bool {
+		if hottest[i].Stats.QueriesPerSecond != hottest[j].Stats.QueriesPerSecond {
+			return hottest[i].Stats.QueriesPerSecond > hottest[j].Stats.QueriesPerSecond
+		}
+		return hottest[i].rangeID() < hottest[j].rangeID()
+	})
+	describe := func(ri *zipRangeInfo) string {
+		return fmt.Sprintf("r%d [%s, %s) on n%d: %.0f queries/s, %s CPU/s",
+			ri.rangeID(), ri.Span.StartKey, ri.Span.EndKey, ri.SourceNodeID, ri.Stats.QueriesPerSecond,
+			time.Duration(ri.Stats.CPUTimePerSecond).Round(time.Millisecond))
+	}
+
+	var findings []zipFinding
+	for _, ri := range hottest {
+		if ri.Stats.QueriesPerSecond >= zipAnalyzeHotRangeQPS ||
+			time.Duration(ri.Stats.CPUTimePerSecond) >= zipAnalyzeHotRangeCPU {
+			findings = append(findings, zipFinding{
+				Severity: zipFindingWarning,
+				Summary:  "range above the load-based splitting threshold",
+				Details:  []string{describe(ri)},
+			})
+		}
+	}
+	var details []string
+	for _, ri := range hottest[:min(len(hottest), zipAnalyzeHottestRanges)] {
+		if ri.Stats.QueriesPerSecond > 0 {
+			details = append(details, describe(ri))
+		}
+	}
+	if len(details) > 0 {
+		findings = append(findings, zipFinding{
+			Severity: zipFindingInfo,
+			Summary:  "hottest ranges by queries per second",
+			Details:  details,
+		})
+	}
+	return findings, nil
+}
+
+// zipAnalysisRuleNames returns the names of the rules of the analysis, for
+// the help text of the command.
+func zipAnalysisRuleNames() string {
+	var b strings.Builder
+	for _, r := range zipAnalysisRules {
+		fmt.Fprintf(&b, "  %-26s %s\n", r.name, r.description)
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/datadriven"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugZipAnalyze(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const dir = "testdata/zip_analyze"
+	datadriven.RunTest(t, filepath.Join(dir, "analyze"), func(t *testing.T, d *datadriven.TestData) string {
+		if d.Cmd != "analyze" {
+			t.Fatalf("unknown command: %s", d.Cmd)
+		}
+		var bundle string
+		d.ScanArgs(t, "bundle", &bundle)
+		report := analyzeDebugZip(filepath.Join(dir, bundle))
+		// Only keep the name of the bundle so that the output does not depend
+		// on where the test runs.
+		report.Dir = bundle
+
+		var buf bytes.Buffer
+		if d.HasArg("json") {
+			enc := json.NewEncoder(&buf)
+			enc.SetIndent("", "  ")
+			require.NoError(t, enc.Encode(report))
+		} else {
+			require.NoError(t, report.format(&buf))
+		}
+		return buf.String()
+	})
+}
@@ -36,3 +36,23 @@ var debugZipUploadCmd = &cobra.Command{
 	Hidden: true,
 	RunE:   clierrorplus.MaybeDecorateError(runDebugZipUpload),
 }
+
+// debugZipAnalyzeCmd checks the contents of an extracted debug.zip for common
+// problems.
+var debugZipAnalyzeCmd = &cobra.Command{
+	Use:   "analyze <path to debug dir>",
+	Short: "check the contents of a debug.zip for common problems",
+	Long: `
+Check the contents of an extracted debug.zip for common problems, such as
+unavailable or under-replicated ranges, lease imbalance, clock offset, LSM
+inversion, stuck jobs, long-running transactions, node liveness flaps and hot
+ranges. The findings are ranked by severity.
+
+The argument is the path to the "debug" directory of the extracted zip file.
+
+The following checks are performed:
+
+` + zipAnalysisRuleNames(),
+	Args: cobra.ExactArgs(1),
+	RunE: clierrorplus.MaybeDecorateError(runDebugZipAnalyze),
+}
@@ -388,6 +388,7 @@ var zipInternalTablesPerCluster = DebugZipTableRegistry{
 			"high_water_timestamp",
 			"coordinator_id",
 			"trace_id",
+			"depends_on",
 		},
 	},
 	"crdb_internal.system_jobs": {
@@ -220,6 +220,15 @@ const (
 	// V25_1_JobsBackfill backfills the new jobs tables and columns.
 	V25_1_JobsBackfill
 
+	// V25_1_WriteBytesLoadDimension enables the write_bytes load based
+	// rebalancing objective, once every node gossips the write bytes of its
+	// stores.
+	V25_1_WriteBytesLoadDimension
+
+	// V25_1_WitnessReplicas enables the WITNESS replica type. Nodes prior to
+	// this version can't handle range descriptors containing witnesses.
+	V25_1_WitnessReplicas
+
 	// *************************************************
 	// Step (1) Add new versions above this comment.
 	// Do not add new versions to a patch release.
@@ -267,6 +276,8 @@ var versionTable = [numKeys]roachpb.Version{
 	V25_1_AddJobsColumns:            {Major: 24, Minor: 3, Internal: 14},
 	V25_1_JobsWritesFence:           {Major: 24, Minor: 3, Internal: 16},
 	V25_1_JobsBackfill:              {Major: 24, Minor: 3, Internal: 18},
+	V25_1_WriteBytesLoadDimension:   {Major: 24, Minor: 3, Internal: 20},
+	V25_1_WitnessReplicas:           {Major: 24, Minor: 3, Internal: 22},
 
 	// *************************************************
 	// Step (2): Add new versions above this comment.
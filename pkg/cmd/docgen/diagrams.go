@@ -788,6 +788,16 @@ var specs = []stmtSpec{
 			"kv_option_list":        "schedule_option"},
 		unlink: []string{"schedule_label", "collection_URI", "crontab", "schedule_option"},
 	},
+	{
+		name:   "create_schedule_for_sql_stmt",
+		inline: []string{"opt_with_schedule_options", "cron_expr"},
+		replace: map[string]string{
+			"schedule_label_spec":               "( 'IF NOT EXISTS' | )  schedule_label",
+			"'SQL' sconst_or_placeholder":       "'SQL' sql_statement",
+			"'RECURRING' sconst_or_placeholder": "'RECURRING' crontab",
+			"kv_option_list":                    "schedule_option"},
+		unlink: []string{"schedule_label", "schedule_option", "sql_statement", "crontab"},
+	},
 	{
 		name:   "create_schedule_for_changefeed_stmt",
 		inline: []string{"opt_with_schedule_options", "changefeed_targets", "table_pattern", "opt_where_clause", "changefeed_target_expr", "cron_expr"},
@@ -30,6 +30,8 @@ const (
 	Constraints            // constraints
 	VoterConstraints       // voter_constraints
 	LeasePreferences       // lease_preferences
+	NumWitnesses           // num_witnesses
+	ColdAfterSeconds       // cold_after_seconds
 
 	// NumFields is the number of fields in the config.
 	NumFields int = iota - 1
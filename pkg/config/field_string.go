@@ -22,6 +22,8 @@ func _() {
 	_ = x[Constraints-7]
 	_ = x[VoterConstraints-8]
 	_ = x[LeasePreferences-9]
+	_ = x[NumWitnesses-10]
+	_ = x[ColdAfterSeconds-11]
 }
 
 func (i Field) String() string {
@@ -44,6 +46,10 @@ func (i Field) String() string {
 		return "voter_constraints"
 	case LeasePreferences:
 		return "lease_preferences"
+	case NumWitnesses:
+		return "num_witnesses"
+	case ColdAfterSeconds:
+		return "cold_after_seconds"
 	default:
 		return "Field(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -328,6 +328,10 @@ func (z *ZoneConfig) ValidateTandemFields() error {
 		return fmt.Errorf("when voter_constraints are set, num_voters must be set as well")
 	}
 
+	if z.NumWitnesses != nil && *z.NumWitnesses > 0 && !numVotersExplicit {
+		return fmt.Errorf("when num_witnesses is set, num_voters must be set as well")
+	}
+
 	if (z.RangeMinBytes != nil || z.RangeMaxBytes != nil) &&
 		(z.RangeMinBytes == nil || z.RangeMaxBytes == nil) {
 		return fmt.Errorf("range_min_bytes and range_max_bytes must be set together")
@@ -400,6 +404,22 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	if z.NumWitnesses != nil {
+		if *z.NumWitnesses < 0 {
+			return fmt.Errorf("num_witnesses cannot be negative")
+		}
+		// Witnesses don't store the range's data, so a quorum of the voters
+		// must be full voters for the range to be able to make progress when a
+		// replica with the data is lost.
+		if numVotersExplicit && *z.NumWitnesses > (*z.NumVoters-1)/2 {
+			return fmt.Errorf("a quorum of num_voters must not be witnesses")
+		}
+	}
+
+	if z.ColdAfterSeconds != nil && *z.ColdAfterSeconds < 0 {
+		return fmt.Errorf("cold_after_seconds cannot be negative")
+	}
+
 	if z.RangeMaxBytes != nil && *z.RangeMaxBytes < minRangeMaxBytes {
 		return fmt.Errorf("RangeMaxBytes %d less than minimum allowed %d",
 			*z.RangeMaxBytes, minRangeMaxBytes)
@@ -572,11 +592,21 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.NumVoters = proto.Int32(*parent.NumVoters)
 		}
 	}
+	if z.NumWitnesses == nil {
+		if parent.NumWitnesses != nil {
+			z.NumWitnesses = proto.Int32(*parent.NumWitnesses)
+		}
+	}
 	if z.GlobalReads == nil {
 		if parent.GlobalReads != nil {
 			z.GlobalReads = proto.Bool(*parent.GlobalReads)
 		}
 	}
+	if z.ColdAfterSeconds == nil {
+		if parent.ColdAfterSeconds != nil {
+			z.ColdAfterSeconds = proto.Int32(*parent.ColdAfterSeconds)
+		}
+	}
 	if z.RangeMinBytes == nil {
 		if parent.RangeMinBytes != nil {
 			z.RangeMinBytes = proto.Int64(*parent.RangeMinBytes)
@@ -621,6 +651,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.NumVoters != nil {
 				z.NumVoters = proto.Int32(*other.NumVoters)
 			}
+		case "num_witnesses":
+			z.NumWitnesses = nil
+			if other.NumWitnesses != nil {
+				z.NumWitnesses = proto.Int32(*other.NumWitnesses)
+			}
 		case "range_min_bytes":
 			z.RangeMinBytes = nil
 			if other.RangeMinBytes != nil {
@@ -636,6 +671,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.GlobalReads != nil {
 				z.GlobalReads = proto.Bool(*other.GlobalReads)
 			}
+		case "cold_after_seconds":
+			z.ColdAfterSeconds = nil
+			if other.ColdAfterSeconds != nil {
+				z.ColdAfterSeconds = proto.Int32(*other.ColdAfterSeconds)
+			}
 		case "gc.ttlseconds":
 			z.GC = nil
 			if other.GC != nil {
@@ -736,6 +776,18 @@ func (z *ZoneConfig) DiffWithZone(
 					Actual:   int32ToString(z.NumVoters),
 				}, nil
 			}
+		case "num_witnesses":
+			if other.NumWitnesses == nil && z.NumWitnesses == nil {
+				continue
+			}
+			if z.NumWitnesses == nil || other.NumWitnesses == nil ||
+				*z.NumWitnesses != *other.NumWitnesses {
+				return false, DiffWithZoneMismatch{
+					Field:    "num_witnesses",
+					Expected: int32ToString(other.NumWitnesses),
+					Actual:   int32ToString(z.NumWitnesses),
+				}, nil
+			}
 		case "range_min_bytes":
 			if other.RangeMinBytes == nil && z.RangeMinBytes == nil {
 				continue
@@ -772,6 +824,18 @@ func (z *ZoneConfig) DiffWithZone(
 					Actual:   boolToString(z.GlobalReads),
 				}, nil
 			}
+		case "cold_after_seconds":
+			if other.ColdAfterSeconds == nil && z.ColdAfterSeconds == nil {
+				continue
+			}
+			if z.ColdAfterSeconds == nil || other.ColdAfterSeconds == nil ||
+				*z.ColdAfterSeconds != *other.ColdAfterSeconds {
+				return false, DiffWithZoneMismatch{
+					Field:    "cold_after_seconds",
+					Expected: int32ToString(other.ColdAfterSeconds),
+					Actual:   int32ToString(z.ColdAfterSeconds),
+				}, nil
+			}
 		case "gc.ttlseconds":
 			if other.GC == nil && z.GC == nil {
 				continue
@@ -1322,10 +1386,17 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.GlobalReads != nil {
 		sc.GlobalReads = *z.GlobalReads
 	}
+	// Data is never tiered by default.
+	if z.ColdAfterSeconds != nil {
+		sc.ColdAfterSeconds = *z.ColdAfterSeconds
+	}
 	sc.NumReplicas = *z.NumReplicas
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
 	}
+	if z.NumWitnesses != nil {
+		sc.NumWitnesses = *z.NumWitnesses
+	}
 
 	toSpanConfigConstraints := func(src []Constraint) ([]roachpb.Constraint, error) {
 		spanConfigConstraints := make([]roachpb.Constraint, len(src))
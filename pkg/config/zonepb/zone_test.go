@@ -277,6 +277,44 @@ func TestZoneConfigValidateNonVoterSpecific(t *testing.T) {
 			},
 			expected: "prohibitive constraint .* conflicts with voter_constraint .*",
 		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(3),
+				NumVoters:    proto.Int32(3),
+				NumWitnesses: proto.Int32(-1),
+			},
+			expected: "num_witnesses cannot be negative",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(3),
+				NumVoters:    proto.Int32(3),
+				NumWitnesses: proto.Int32(1),
+			},
+			expected: "",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(4),
+				NumVoters:    proto.Int32(4),
+				NumWitnesses: proto.Int32(2),
+			},
+			expected: "a quorum of num_voters must not be witnesses",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:      proto.Int32(3),
+				ColdAfterSeconds: proto.Int32(-1),
+			},
+			expected: "cold_after_seconds cannot be negative",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:      proto.Int32(3),
+				ColdAfterSeconds: proto.Int32(3600),
+			},
+			expected: "",
+		},
 	}
 
 	for i, c := range testCases {
@@ -339,6 +377,15 @@ func TestZoneConfigValidateTandemFields(t *testing.T) {
 			expected:   "when voter_constraints are set, num_voters must be set as well",
 			shouldFail: true,
 		},
+		{
+			name: "num_witnesses without num_voters",
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(3),
+				NumWitnesses: proto.Int32(1),
+			},
+			expected:   "when num_witnesses is set, num_voters must be set as well",
+			shouldFail: true,
+		},
 		{
 			name: "lease preferences without constraints",
 			cfg: ZoneConfig{
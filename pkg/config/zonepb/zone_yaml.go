@@ -198,8 +198,10 @@ type marshalableZoneConfig struct {
 	RangeMaxBytes                *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
 	GC                           *GCPolicy         `json:"gc"`
 	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
+	ColdAfterSeconds             *int32            `json:"cold_after_seconds,omitempty" yaml:"cold_after_seconds,omitempty"`
 	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
 	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
+	NumWitnesses                 *int32            `json:"num_witnesses,omitempty" yaml:"num_witnesses,omitempty"`
 	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
 	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
@@ -223,6 +225,9 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.GlobalReads != nil {
 		m.GlobalReads = proto.Bool(*c.GlobalReads)
 	}
+	if c.ColdAfterSeconds != nil && *c.ColdAfterSeconds != 0 {
+		m.ColdAfterSeconds = proto.Int32(*c.ColdAfterSeconds)
+	}
 	if c.NumReplicas != nil && *c.NumReplicas != 0 {
 		m.NumReplicas = proto.Int32(*c.NumReplicas)
 	}
@@ -230,6 +235,9 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.NumVoters != nil && *c.NumVoters != 0 {
 		m.NumVoters = proto.Int32(*c.NumVoters)
 	}
+	if c.NumWitnesses != nil && *c.NumWitnesses != 0 {
+		m.NumWitnesses = proto.Int32(*c.NumWitnesses)
+	}
 	// NB: In order to preserve round-trippability, we're directly using
 	// `NullVoterConstraintsIsEmpty` as opposed to calling
 	// `c.InheritedVoterConstraints()`. This is copacetic as long as the value is
@@ -262,6 +270,9 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.GlobalReads != nil {
 		c.GlobalReads = proto.Bool(*m.GlobalReads)
 	}
+	if m.ColdAfterSeconds != nil {
+		c.ColdAfterSeconds = proto.Int32(*m.ColdAfterSeconds)
+	}
 	if m.NumReplicas != nil {
 		c.NumReplicas = proto.Int32(*m.NumReplicas)
 	}
@@ -270,6 +281,9 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.NumVoters != nil {
 		c.NumVoters = proto.Int32(*m.NumVoters)
 	}
+	if m.NumWitnesses != nil {
+		c.NumWitnesses = proto.Int32(*m.NumWitnesses)
+	}
 	c.VoterConstraints = m.VoterConstraints.Constraints
 	c.NullVoterConstraintsIsEmpty = !m.VoterConstraints.Inherited
 	if m.LeasePreferences != nil {
@@ -8,6 +8,8 @@ package logical
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
@@ -21,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -122,6 +125,7 @@ func createLogicalReplicationStreamPlanHook(
 		}
 
 		hasUDF := len(options.userFunctions) > 0 || options.defaultFunction != nil && options.defaultFunction.FunctionId != 0
+		hasConflictPolicies := len(options.conflictPolicies) > 0
 
 		mode := jobspb.LogicalReplicationDetails_Immediate
 		if m, ok := options.GetMode(); ok {
@@ -130,16 +134,24 @@ func createLogicalReplicationStreamPlanHook(
 				if hasUDF {
 					return pgerror.Newf(pgcode.InvalidParameterValue, "MODE = 'immediate' cannot be used with user-defined functions")
 				}
+				if hasConflictPolicies {
+					return pgerror.Newf(pgcode.InvalidParameterValue, "MODE = 'immediate' cannot be used with conflict policies")
+				}
 			case "validated":
 				mode = jobspb.LogicalReplicationDetails_Validated
 			default:
 				return pgerror.Newf(pgcode.InvalidParameterValue, "unknown mode %q", m)
 			}
-		} else if hasUDF {
-			// UDFs imply applying changes via SQL, which implies validation.
+		} else if hasUDF || hasConflictPolicies {
+			// UDFs and conflict policies imply applying changes via SQL, which
+			// implies validation.
 			mode = jobspb.LogicalReplicationDetails_Validated
 		}
 
+		if hasConflictPolicies && options.defaultFunction != nil && options.defaultFunction.FunctionId != 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "conflict policies cannot be used with a default user-defined function")
+		}
+
 		discard := jobspb.LogicalReplicationDetails_DiscardNothing
 		if m, ok := options.Discard(); ok {
 			switch m {
@@ -274,6 +286,28 @@ func createLogicalReplicationStreamPlanHook(
 				repPairs[i].DstFunctionID = uf[name]
 			}
 		}
+		if cp, ok := options.GetConflictPolicies(); ok {
+			for name := range cp {
+				if !slices.Contains(srcTableNames, name) {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"conflict policy specified for table %s which is not being replicated", name)
+				}
+			}
+			for i, name := range srcTableNames {
+				policies, ok := cp[name]
+				if !ok {
+					continue
+				}
+				if repPairs[i].DstFunctionID != 0 {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"table %s cannot use both a user-defined function and conflict policies", name)
+				}
+				if err := validateConflictPolicies(&spec.ExternalCatalog.Tables[i], policies); err != nil {
+					return err
+				}
+				repPairs[i].ColumnPolicies = policies
+			}
+		}
 		if throwNoTTLWithCDCIgnoreError {
 			return pgerror.Newf(pgcode.InvalidParameterValue, "DISCARD = 'ttl-deletes' specified but no tables have changefeed-excluded TTLs")
 		}
@@ -491,8 +525,13 @@ func createLogicalReplicationStreamTypeCheck(
 	if !ok {
 		return false, nil, nil
 	}
+	conflictPolicies := make(exprutil.Strings, 0, len(stmt.Options.ConflictPolicies))
+	for _, policy := range stmt.Options.ConflictPolicies {
+		conflictPolicies = append(conflictPolicies, policy)
+	}
 	toTypeCheck := []exprutil.ToTypeCheck{
 		exprutil.Strings{stmt.PGURL},
+		conflictPolicies,
 		exprutil.Strings{
 			stmt.Options.Cursor,
 			stmt.Options.DefaultFunction,
@@ -522,7 +561,9 @@ type resolvedLogicalReplicationOptions struct {
 	mode            string
 	defaultFunction *jobspb.LogicalReplicationDetails_DefaultConflictResolution
 	// Mapping of table name to function descriptor
-	userFunctions    map[string]int32
+	userFunctions map[string]int32
+	// Mapping of table name to the conflict policies of its columns
+	conflictPolicies map[string][]jobspb.LogicalReplicationDetails_ColumnConflictPolicy
 	discard          string
 	skipSchemaCheck  bool
 	metricsLabel     string
@@ -610,6 +651,35 @@ func evalLogicalReplicationOptions(
 		}
 	}
 
+	if options.ConflictPolicies != nil {
+		r.conflictPolicies = make(map[string][]jobspb.LogicalReplicationDetails_ColumnConflictPolicy)
+		for col, expr := range options.ConflictPolicies {
+			policyName, err := eval.String(ctx, expr)
+			if err != nil {
+				return nil, err
+			}
+			policy, err := parseColumnConflictPolicy(policyName)
+			if err != nil {
+				return nil, err
+			}
+			// The column name is qualified by the name of the source table, in
+			// the same form used to name that table in the FROM clause.
+			tb := tree.UnresolvedName{NumParts: col.NumParts - 1}
+			copy(tb.Parts[:], col.Parts[1:col.NumParts])
+			r.conflictPolicies[tb.String()] = append(r.conflictPolicies[tb.String()],
+				jobspb.LogicalReplicationDetails_ColumnConflictPolicy{
+					ColumnName: col.Parts[0],
+					Policy:     policy,
+				})
+		}
+		// Sort the policies so that the job details are deterministic.
+		for _, policies := range r.conflictPolicies {
+			sort.Slice(policies, func(i, j int) bool {
+				return policies[i].ColumnName < policies[j].ColumnName
+			})
+		}
+	}
+
 	if options.Discard != nil {
 		discard, err := eval.String(ctx, options.Discard)
 		if err != nil {
@@ -645,6 +715,57 @@ func evalLogicalReplicationOptions(
 	return r, nil
 }
 
+func parseColumnConflictPolicy(
+	name string,
+) (jobspb.LogicalReplicationDetails_ColumnConflictPolicy_Policy, error) {
+	switch strings.ToLower(name) {
+	case "lww", "last_writer_wins":
+		return jobspb.LogicalReplicationDetails_ColumnConflictPolicy_LWW, nil
+	case "additive":
+		return jobspb.LogicalReplicationDetails_ColumnConflictPolicy_Additive, nil
+	case "origin_wins":
+		return jobspb.LogicalReplicationDetails_ColumnConflictPolicy_OriginWins, nil
+	case "destination_wins":
+		return jobspb.LogicalReplicationDetails_ColumnConflictPolicy_DestinationWins, nil
+	case "dlq":
+		return jobspb.LogicalReplicationDetails_ColumnConflictPolicy_DLQ, nil
+	default:
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unknown conflict policy %q", name)
+	}
+}
+
+// validateConflictPolicies checks that each of the given policies names a
+// column of the source table that the policy can be applied to.
+func validateConflictPolicies(
+	td *descpb.TableDescriptor, policies []jobspb.LogicalReplicationDetails_ColumnConflictPolicy,
+) error {
+	desc := tabledesc.NewBuilder(td).BuildImmutableTable()
+	for _, p := range policies {
+		col, err := catalog.MustFindPublicColumnByTreeName(desc, tree.Name(p.ColumnName))
+		if err != nil {
+			return errors.Wrapf(err, "invalid conflict policy for table %s", desc.GetName())
+		}
+		if desc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(col.GetID()) {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"conflict policy cannot be specified for primary key column %s", col.GetName())
+		}
+		if col.IsComputed() {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"conflict policy cannot be specified for computed column %s", col.GetName())
+		}
+		if p.Policy == jobspb.LogicalReplicationDetails_ColumnConflictPolicy_Additive {
+			switch col.GetType().Family() {
+			case types.IntFamily, types.FloatFamily, types.DecimalFamily:
+			default:
+				return pgerror.Newf(pgcode.DatatypeMismatch,
+					"additive conflict policy requires a numeric column, but %s has type %s",
+					col.GetName(), col.GetType().SQLString())
+			}
+		}
+	}
+	return nil
+}
+
 func lookupFunctionID(
 	ctx context.Context, p sql.PlanHookState, u tree.UnresolvedName,
 ) (int32, error) {
@@ -694,6 +815,16 @@ func (r *resolvedLogicalReplicationOptions) GetUserFunctions() (map[string]int32
 	return r.userFunctions, true
 }
 
+func (r *resolvedLogicalReplicationOptions) GetConflictPolicies() (
+	map[string][]jobspb.LogicalReplicationDetails_ColumnConflictPolicy,
+	bool,
+) {
+	if r == nil || r.conflictPolicies == nil {
+		return map[string][]jobspb.LogicalReplicationDetails_ColumnConflictPolicy{}, false
+	}
+	return r.conflictPolicies, true
+}
+
 func (r *resolvedLogicalReplicationOptions) Discard() (string, bool) {
 	if r == nil || r.discard == "" {
 		return "", false
@@ -527,6 +527,7 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 				DestinationParentSchemaName:   scDesc.GetName(),
 				DestinationTableName:          dstTableDesc.GetName(),
 				DestinationFunctionOID:        uint32(fnOID),
+				ColumnPolicies:                pair.ColumnPolicies,
 			}
 			info.destTableBySrcID[descpb.ID(pair.SrcDescriptorID)] = dstTableMetadata{
 				database: dbDesc.GetName(),
@@ -567,6 +568,7 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 		payload.Discard,
 		payload.Mode,
 		payload.MetricsLabel,
+		originHasPriority(payload.SourceClusterID, execCfg.NodeInfo.LogicalClusterID()),
 	)
 	if err != nil {
 		return nil, nil, info, err
@@ -178,8 +178,9 @@ func newLogicalReplicationWriterProcessor(
 	destTableBySrcID := make(map[descpb.ID]dstTableMetadata)
 	for dstTableID, md := range spec.TableMetadataByDestID {
 		procConfigByDestTableID[descpb.ID(dstTableID)] = sqlProcessorTableConfig{
-			srcDesc: tabledesc.NewBuilder(&md.SourceDescriptor).BuildImmutableTable(),
-			dstOID:  md.DestinationFunctionOID,
+			srcDesc:        tabledesc.NewBuilder(&md.SourceDescriptor).BuildImmutableTable(),
+			dstOID:         md.DestinationFunctionOID,
+			columnPolicies: md.ColumnPolicies,
 		}
 		destTableBySrcID[md.SourceDescriptor.GetID()] = dstTableMetadata{
 			database: md.DestinationParentDatabaseName,
@@ -886,6 +887,9 @@ func (lrw *logicalReplicationWriterProcessor) flushBuffer(
 
 	lrw.metrics.KVUpdateTooOld.Inc(stats.kvWriteTooOld)
 	lrw.metrics.KVValueRefreshes.Inc(stats.kvWriteValueRefreshes)
+	lrw.metrics.ConflictPolicyAdditive.Inc(stats.additiveMerges)
+	lrw.metrics.ConflictPolicyOriginWins.Inc(stats.originWins)
+	lrw.metrics.ConflictPolicyDestinationWins.Inc(stats.destinationWins)
 	lrw.metrics.AppliedRowUpdates.Inc(stats.processed.success)
 	lrw.metrics.DLQedRowUpdates.Inc(stats.processed.dlq)
 	if l := lrw.spec.MetricsLabel; l != "" {
@@ -1060,6 +1064,11 @@ func (lrw *logicalReplicationWriterProcessor) shouldRetryLater(
 		return tooOld
 	}
 
+	// Rows rejected by a conflict policy would be rejected again if retried.
+	if errors.Is(err, errConflictPolicyRejected) {
+		return errType
+	}
+
 	// TODO(dt): maybe this should only be constraint violation errors?
 	return retryAllowed
 }
@@ -1099,6 +1108,9 @@ func (lrw *logicalReplicationWriterProcessor) maybeDLQ(
 	case errType:
 		lrw.metrics.DLQedDueToErrType.Inc(1)
 	}
+	if errors.Is(applyErr, errConflictPolicyRejected) {
+		lrw.metrics.ConflictPolicyRejections.Inc(1)
+	}
 	return lrw.dlqClient.Log(ctx, lrw.spec.JobID, event, row, applyErr, eligibility)
 }
 
@@ -1142,12 +1154,18 @@ type batchStats struct {
 	optimisticInsertConflicts int64
 	kvWriteTooOld             int64
 	kvWriteValueRefreshes     int64
+	additiveMerges            int64
+	originWins                int64
+	destinationWins           int64
 }
 
 func (b *batchStats) Add(o batchStats) {
 	b.optimisticInsertConflicts += o.optimisticInsertConflicts
 	b.kvWriteTooOld += o.kvWriteTooOld
 	b.kvWriteValueRefreshes += o.kvWriteValueRefreshes
+	b.additiveMerges += o.additiveMerges
+	b.originWins += o.originWins
+	b.destinationWins += o.destinationWins
 }
 
 type flushStats struct {
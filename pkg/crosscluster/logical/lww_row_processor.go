@@ -212,6 +212,9 @@ func (q *queryBuffer) ApplierQueryForRow(row cdcevent.Row) (queryBuilder, error)
 type sqlProcessorTableConfig struct {
 	srcDesc catalog.TableDescriptor
 	dstOID  uint32
+	// columnPolicies, if non-empty, are the conflict resolution policies for
+	// the table's columns.
+	columnPolicies []jobspb.LogicalReplicationDetails_ColumnConflictPolicy
 }
 
 func makeSQLProcessorFromQuerier(
@@ -404,6 +407,7 @@ const (
 	replicatedInsertOpName           = "replicated-insert"
 	replicatedDeleteOpName           = "replicated-delete"
 	replicatedApplyUDFOpName         = "replicated-apply-udf"
+	replicatedConflictCheckOpName    = "replicated-conflict-check"
 )
 
 func getIEOverride(opName string, jobID jobspb.JobID) sessiondata.InternalExecutorOverride {
@@ -450,11 +454,14 @@ func makeSQLProcessor(
 	spec execinfrapb.LogicalReplicationWriterSpec,
 ) (*sqlRowProcessor, error) {
 
-	needUDFQuerier := false
+	needUDFQuerier, needPolicyQuerier := false, false
 	shouldUseUDF := make(map[catid.DescID]bool, len(tableConfigByDestID))
+	shouldUsePolicies := make(map[catid.DescID]bool, len(tableConfigByDestID))
 	for _, tc := range tableConfigByDestID {
 		shouldUseUDF[tc.srcDesc.GetID()] = tc.dstOID != 0
+		shouldUsePolicies[tc.srcDesc.GetID()] = len(tc.columnPolicies) > 0
 		needUDFQuerier = needUDFQuerier || tc.dstOID != 0
+		needPolicyQuerier = needPolicyQuerier || len(tc.columnPolicies) > 0
 	}
 
 	lwwQuerier := &lwwQuerier{
@@ -471,28 +478,44 @@ func makeSQLProcessor(
 	if needUDFQuerier {
 		udfQuerier = makeApplierQuerier(ctx, settings, tableConfigByDestID, jobID, ie)
 	}
+	var policyQuerier querier
+	if needPolicyQuerier {
+		policyQuerier = makePolicyQuerier(tableConfigByDestID, jobID, lwwQuerier, spec.OriginHasPriority)
+	}
 
 	return makeSQLProcessorFromQuerier(ctx, settings, tableConfigByDestID, db, ie, &muxQuerier{
-		shouldUseUDF: shouldUseUDF,
-		lwwQuerier:   lwwQuerier,
-		udfQuerier:   udfQuerier,
+		shouldUseUDF:      shouldUseUDF,
+		shouldUsePolicies: shouldUsePolicies,
+		lwwQuerier:        lwwQuerier,
+		udfQuerier:        udfQuerier,
+		policyQuerier:     policyQuerier,
 	}, sd, spec)
 
 }
 
-// muxQuerier is a querier that dispatches to either an LWW querier or a UDF
-// querier.
+// muxQuerier is a querier that dispatches to either an LWW querier, a UDF
+// querier, or a conflict policy querier.
 type muxQuerier struct {
-	shouldUseUDF map[catid.DescID]bool
-	lwwQuerier   querier
-	udfQuerier   querier
+	shouldUseUDF      map[catid.DescID]bool
+	shouldUsePolicies map[catid.DescID]bool
+	lwwQuerier        querier
+	udfQuerier        querier
+	policyQuerier     querier
 }
 
-func (m *muxQuerier) AddTable(destDescID int32, tc sqlProcessorTableConfig) error {
-	if m.shouldUseUDF[tc.srcDesc.GetID()] {
-		return m.udfQuerier.AddTable(destDescID, tc)
+// querierFor returns the querier that handles rows of the given source table.
+func (m *muxQuerier) querierFor(id catid.DescID) querier {
+	if m.shouldUseUDF[id] {
+		return m.udfQuerier
 	}
-	return m.lwwQuerier.AddTable(destDescID, tc)
+	if m.shouldUsePolicies[id] {
+		return m.policyQuerier
+	}
+	return m.lwwQuerier
+}
+
+func (m *muxQuerier) AddTable(destDescID int32, tc sqlProcessorTableConfig) error {
+	return m.querierFor(tc.srcDesc.GetID()).AddTable(destDescID, tc)
 }
 
 func (m *muxQuerier) InsertRow(
@@ -503,23 +526,17 @@ func (m *muxQuerier) InsertRow(
 	prevRow *cdcevent.Row,
 	likelyInsert bool,
 ) (batchStats, error) {
-	if m.shouldUseUDF[row.TableID] {
-		return m.udfQuerier.InsertRow(ctx, txn, ie, row, prevRow, likelyInsert)
-	}
-	return m.lwwQuerier.InsertRow(ctx, txn, ie, row, prevRow, likelyInsert)
+	return m.querierFor(row.TableID).InsertRow(ctx, txn, ie, row, prevRow, likelyInsert)
 }
 
 func (m *muxQuerier) DeleteRow(
 	ctx context.Context, txn isql.Txn, ie isql.Executor, row cdcevent.Row, prevRow *cdcevent.Row,
 ) (batchStats, error) {
-	if m.shouldUseUDF[row.TableID] {
-		return m.udfQuerier.DeleteRow(ctx, txn, ie, row, prevRow)
-	}
-	return m.lwwQuerier.DeleteRow(ctx, txn, ie, row, prevRow)
+	return m.querierFor(row.TableID).DeleteRow(ctx, txn, ie, row, prevRow)
 }
 
 func (m *muxQuerier) RequiresParsedBeforeRow(id catid.DescID) bool {
-	return m.shouldUseUDF[id]
+	return m.querierFor(id).RequiresParsedBeforeRow(id)
 }
 
 // lwwQuerier is a querier that implements partial last-write-wins
@@ -134,6 +134,30 @@ var (
 		Measurement: "Events",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaConflictPolicyAdditive = metric.Metadata{
+		Name:        "logical_replication.conflict_policy.additive",
+		Help:        "Row updates applied to columns with the additive conflict policy",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConflictPolicyOriginWins = metric.Metadata{
+		Name:        "logical_replication.conflict_policy.origin_wins",
+		Help:        "Row updates applied to columns with the origin_wins conflict policy",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConflictPolicyDestinationWins = metric.Metadata{
+		Name:        "logical_replication.conflict_policy.destination_wins",
+		Help:        "Row updates applied to columns with the destination_wins conflict policy",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaConflictPolicyRejections = metric.Metadata{
+		Name:        "logical_replication.conflict_policy.rejected",
+		Help:        "Row updates sent to DLQ by columns with the dlq conflict policy",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaScanningRanges = metric.Metadata{
 		Name:        "logical_replication.scanning_ranges",
 		Help:        "Source side ranges undergoing an initial scan (inaccurate with multiple LDR jobs)",
@@ -216,6 +240,12 @@ type Metrics struct {
 	KVValueRefreshes *metric.Counter
 	KVUpdateTooOld   *metric.Counter
 
+	// Numbers of row updates handled by each per-column conflict policy.
+	ConflictPolicyAdditive        *metric.Counter
+	ConflictPolicyOriginWins      *metric.Counter
+	ConflictPolicyDestinationWins *metric.Counter
+	ConflictPolicyRejections      *metric.Counter
+
 	// Labeled export-only metrics.
 	LabeledReplicatedTime *metric.GaugeVec
 	LabeledEventsIngested *metric.CounterVec
@@ -262,6 +292,11 @@ func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 		KVUpdateTooOld:   metric.NewCounter(metaKVUpdateTooOld),
 		KVValueRefreshes: metric.NewCounter(metaKVValueRefreshes),
 
+		ConflictPolicyAdditive:        metric.NewCounter(metaConflictPolicyAdditive),
+		ConflictPolicyOriginWins:      metric.NewCounter(metaConflictPolicyOriginWins),
+		ConflictPolicyDestinationWins: metric.NewCounter(metaConflictPolicyDestinationWins),
+		ConflictPolicyRejections:      metric.NewCounter(metaConflictPolicyRejections),
+
 		ScanningRanges: metric.NewGauge(metaScanningRanges),
 		CatchupRanges:  metric.NewGauge(metaCatchupRanges),
 
@@ -0,0 +1,522 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// errConflictPolicyRejected marks errors returned when a row update is
+// rejected by a column with the DLQ conflict policy. Such rows are sent to the
+// DLQ without being retried.
+var errConflictPolicyRejected = errors.New("row update rejected by conflict policy")
+
+const (
+	insertQueryWithPolicies = `
+INSERT INTO [%d AS t] (%s)
+VALUES (%s)
+ON CONFLICT (%s)
+DO UPDATE SET
+%s
+WHERE %s`
+	conflictCheckQuery = `
+SELECT %s
+FROM [%d AS t]
+WHERE %s`
+	lwwCondition = `((t.crdb_internal_mvcc_timestamp < $%[1]d
+    AND t.crdb_internal_origin_timestamp IS NULL)
+ OR (t.crdb_internal_origin_timestamp < $%[1]d
+    AND t.crdb_internal_origin_timestamp IS NOT NULL))`
+)
+
+// policyQuerier is a querier that resolves conflicting writes on a per-column
+// basis using the conflict policies configured for the table's columns.
+// Columns without a policy use last-writer-wins semantics, as do deletes,
+// which are handled by the embedded lwwQuerier.
+//
+// Known issues:
+//
+//  1. The MVCC and origin timestamps are tracked per row rather than per
+//     column. If an update loses to the existing row under last-writer-wins
+//     but still modifies a column with a different policy, the row's origin
+//     timestamp is set to the timestamp of the losing update.
+//
+//  2. The additive policy applies the difference between the source's new and
+//     previous values. If the source's previous value is not known, as is the
+//     case for the rows sent by the initial scan, the update is only applied if
+//     the row does not exist on the destination; otherwise it is sent to the
+//     DLQ, since adding the new value in full would double count.
+//
+//  3. The origin_wins policy only favors the source of the stream if it has
+//     priority over the destination, which is the case if its cluster ID
+//     sorts before the destination's. Otherwise, a value written locally is
+//     kept as with destination_wins. Since the two streams of a bidirectional
+//     pair make opposite decisions, both clusters converge on the value
+//     written by the cluster with priority.
+type policyQuerier struct {
+	*lwwQuerier
+
+	policyInsertQueries map[catid.DescID]map[catid.FamilyID]policyInsertQuery
+
+	// originHasPriority is whether the source of the stream has priority over
+	// the destination for the columns with the origin_wins policy.
+	originHasPriority bool
+
+	ieOverrideConflictCheck sessiondata.InternalExecutorOverride
+}
+
+// policyInsertQuery holds the pre-generated queries used to apply an insert or
+// update to a single column family of a table with conflict policies.
+type policyInsertQuery struct {
+	// insert holds the optimistic and pessimistic INSERT statements. The
+	// pessimistic statement takes the previous values of prevColumns after
+	// the origin timestamp.
+	insert      queryBuilder
+	prevColumns []string
+
+	// check, if checkColumns is non-empty, returns a boolean for each of
+	// checkColumns indicating whether that column of the existing row was
+	// modified concurrently with the source. It takes the previous values of
+	// checkColumns after its input columns.
+	check        queryBuilder
+	checkColumns []string
+
+	// appliedStats are the stats reported when the pessimistic statement
+	// modifies a row.
+	appliedStats batchStats
+}
+
+func makePolicyQuerier(
+	tableConfigByDestID map[descpb.ID]sqlProcessorTableConfig,
+	jobID jobspb.JobID,
+	lww *lwwQuerier,
+	originHasPriority bool,
+) *policyQuerier {
+	return &policyQuerier{
+		lwwQuerier:              lww,
+		policyInsertQueries:     make(map[catid.DescID]map[catid.FamilyID]policyInsertQuery, len(tableConfigByDestID)),
+		originHasPriority:       originHasPriority,
+		ieOverrideConflictCheck: getIEOverride(replicatedConflictCheckOpName, jobID),
+	}
+}
+
+// originHasPriority returns whether the source cluster of a stream has
+// priority over its destination cluster for the columns with the origin_wins
+// policy. The order is arbitrary but total, so exactly one of the clusters of
+// a bidirectional pair has priority.
+func originHasPriority(sourceClusterID, destClusterID uuid.UUID) bool {
+	return bytes.Compare(sourceClusterID.GetBytes(), destClusterID.GetBytes()) < 0
+}
+
+func (pq *policyQuerier) AddTable(targetDescID int32, tc sqlProcessorTableConfig) error {
+	if err := pq.lwwQuerier.AddTable(targetDescID, tc); err != nil {
+		return err
+	}
+	policies := make(map[string]jobspb.LogicalReplicationDetails_ColumnConflictPolicy_Policy, len(tc.columnPolicies))
+	for _, p := range tc.columnPolicies {
+		policies[p.ColumnName] = p.Policy
+	}
+	var err error
+	pq.policyInsertQueries[tc.srcDesc.GetID()], err = makePolicyInsertQueries(
+		targetDescID, tc.srcDesc, policies, pq.originHasPriority,
+	)
+	return err
+}
+
+// RequiresParsedBeforeRow implements the querier interface. The previous
+// values of the row are needed by the additive and DLQ policies.
+func (pq *policyQuerier) RequiresParsedBeforeRow(catid.DescID) bool {
+	return true
+}
+
+func (pq *policyQuerier) InsertRow(
+	ctx context.Context,
+	txn isql.Txn,
+	ie isql.Executor,
+	row cdcevent.Row,
+	prevRow *cdcevent.Row,
+	likelyInsert bool,
+) (batchStats, error) {
+	var kvTxn *kv.Txn
+	if txn != nil {
+		kvTxn = txn.KV()
+	}
+	q, err := pq.insertQueryForRow(row)
+	if err != nil {
+		return batchStats{}, err
+	}
+	if len(q.prevColumns) > 0 && !hasPrevRow(prevRow) {
+		return pq.insertRowWithoutPrevRow(ctx, kvTxn, ie, q, row)
+	}
+	if len(q.checkColumns) > 0 {
+		if err := pq.checkConflicts(ctx, kvTxn, ie, q, row, prevRow); err != nil {
+			return batchStats{}, err
+		}
+	}
+
+	insertQueryBuilder := q.insert
+	insertQueryBuilder.Reset()
+	if err := insertQueryBuilder.AddRow(row); err != nil {
+		return batchStats{}, err
+	}
+	if err := insertQueryBuilder.addPrevDatums(prevRow, q.prevColumns); err != nil {
+		return batchStats{}, err
+	}
+
+	shouldTryOptimisticInsert := likelyInsert && tryOptimisticInsertEnabled.Get(&pq.settings.SV)
+	var optimisticInsertConflicts int64
+	if shouldTryOptimisticInsert {
+		stmt, datums, err := insertQueryBuilder.Query(insertQueriesOptimisticIndex)
+		if err != nil {
+			return batchStats{}, err
+		}
+
+		sess := pq.ieOverrideOptimisticInsert
+		sess.OriginTimestampForLogicalDataReplication = row.MvccTimestamp
+		if !useLowPriority.Get(&pq.settings.SV) {
+			sess.QualityOfService = nil
+		}
+		if _, err = ie.ExecParsed(ctx, replicatedOptimisticInsertOpName, kvTxn, sess, stmt, datums...); err != nil {
+			if pgerror.GetPGCode(err) != pgcode.UniqueViolation {
+				log.Warningf(ctx, "replicated optimistic insert failed (query: %s): %s", stmt.SQL, err.Error())
+				return batchStats{}, err
+			}
+			optimisticInsertConflicts++
+		} else {
+			// There was no conflict - we're done.
+			return batchStats{}, nil
+		}
+	}
+
+	stmt, datums, err := insertQueryBuilder.Query(insertQueriesPessimisticIndex)
+	if err != nil {
+		return batchStats{}, err
+	}
+	sess := pq.ieOverrideInsert
+	if !useLowPriority.Get(&pq.settings.SV) {
+		sess.QualityOfService = nil
+	}
+	sess.OriginTimestampForLogicalDataReplication = row.MvccTimestamp
+	rowsAffected, err := ie.ExecParsed(ctx, replicatedInsertOpName, kvTxn, sess, stmt, datums...)
+	if err != nil {
+		log.Warningf(ctx, "replicated insert failed (query: %s): %s", stmt.SQL, err.Error())
+		return batchStats{}, err
+	}
+	stats := batchStats{optimisticInsertConflicts: optimisticInsertConflicts}
+	if rowsAffected > 0 {
+		stats.Add(q.appliedStats)
+	}
+	return stats, nil
+}
+
+// insertRowWithoutPrevRow applies an update to a row with additive columns
+// whose previous values on the source are not known. The change the source
+// made to the additive columns cannot be computed, so the update is only
+// applied if the row does not exist on the destination. Otherwise, it is
+// rejected with an error marked with errConflictPolicyRejected.
+func (pq *policyQuerier) insertRowWithoutPrevRow(
+	ctx context.Context, kvTxn *kv.Txn, ie isql.Executor, q policyInsertQuery, row cdcevent.Row,
+) (batchStats, error) {
+	insertQueryBuilder := q.insert
+	insertQueryBuilder.Reset()
+	if err := insertQueryBuilder.AddRow(row); err != nil {
+		return batchStats{}, err
+	}
+	if err := insertQueryBuilder.addPrevDatums(nil /* prevRow */, q.prevColumns); err != nil {
+		return batchStats{}, err
+	}
+	stmt, datums, err := insertQueryBuilder.Query(insertQueriesOptimisticIndex)
+	if err != nil {
+		return batchStats{}, err
+	}
+	sess := pq.ieOverrideOptimisticInsert
+	sess.OriginTimestampForLogicalDataReplication = row.MvccTimestamp
+	if !useLowPriority.Get(&pq.settings.SV) {
+		sess.QualityOfService = nil
+	}
+	if _, err := ie.ExecParsed(ctx, replicatedOptimisticInsertOpName, kvTxn, sess, stmt, datums...); err != nil {
+		if pgerror.GetPGCode(err) != pgcode.UniqueViolation {
+			log.Warningf(ctx, "replicated insert failed (query: %s): %s", stmt.SQL, err.Error())
+			return batchStats{}, err
+		}
+		return batchStats{}, errors.Mark(pgerror.Newf(pgcode.IntegrityConstraintViolation,
+			"previous value of column %s is unknown",
+			lexbase.EscapeSQLIdent(q.prevColumns[0])), errConflictPolicyRejected)
+	}
+	return batchStats{}, nil
+}
+
+// hasPrevRow returns whether the previous values of a row on the source are
+// known.
+func hasPrevRow(prevRow *cdcevent.Row) bool {
+	return prevRow != nil && prevRow.IsInitialized() && !prevRow.IsDeleted()
+}
+
+// checkConflicts returns an error marked with errConflictPolicyRejected if a
+// column with the DLQ policy was modified on the destination concurrently with
+// the source.
+func (pq *policyQuerier) checkConflicts(
+	ctx context.Context,
+	kvTxn *kv.Txn,
+	ie isql.Executor,
+	q policyInsertQuery,
+	row cdcevent.Row,
+	prevRow *cdcevent.Row,
+) error {
+	checkQueryBuilder := q.check
+	checkQueryBuilder.Reset()
+	if err := checkQueryBuilder.AddRow(row); err != nil {
+		return err
+	}
+	if err := checkQueryBuilder.addPrevDatums(prevRow, q.checkColumns); err != nil {
+		return err
+	}
+	stmt, datums, err := checkQueryBuilder.Query(defaultQuery)
+	if err != nil {
+		return err
+	}
+	sess := pq.ieOverrideConflictCheck
+	if !useLowPriority.Get(&pq.settings.SV) {
+		sess.QualityOfService = nil
+	}
+	res, err := ie.QueryRowExParsed(ctx, replicatedConflictCheckOpName, kvTxn, sess, stmt, datums...)
+	if err != nil {
+		log.Warningf(ctx, "replicated conflict check failed (query: %s): %s", stmt.SQL, err.Error())
+		return err
+	}
+	for i, d := range res {
+		if d == tree.DBoolTrue {
+			return errors.Mark(pgerror.Newf(pgcode.IntegrityConstraintViolation,
+				"column %s was modified concurrently on the destination",
+				lexbase.EscapeSQLIdent(q.checkColumns[i])), errConflictPolicyRejected)
+		}
+	}
+	return nil
+}
+
+func (pq *policyQuerier) insertQueryForRow(row cdcevent.Row) (policyInsertQuery, error) {
+	queriesForTable, ok := pq.policyInsertQueries[row.TableID]
+	if !ok {
+		return policyInsertQuery{}, errors.Errorf("no pre-generated insert query for table %d", row.TableID)
+	}
+	q, ok := queriesForTable[row.FamilyID]
+	if !ok {
+		return policyInsertQuery{}, errors.Errorf("no pre-generated insert query for table %d column family %d", row.TableID, row.FamilyID)
+	}
+	return q, nil
+}
+
+// addPrevDatums appends the previous values of the given columns to the
+// query's arguments. NULL is used for every column if there is no previous
+// row.
+func (q *queryBuilder) addPrevDatums(prevRow *cdcevent.Row, columns []string) error {
+	if !hasPrevRow(prevRow) {
+		for range columns {
+			q.scratchDatums = append(q.scratchDatums, tree.DNull)
+		}
+		return nil
+	}
+	it, err := prevRow.DatumsNamed(columns)
+	if err != nil {
+		return err
+	}
+	return it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if dEnum, ok := d.(*tree.DEnum); ok {
+			// Override the type to Unknown to avoid a mismatched type OID error
+			// during execution. Note that Unknown is the type used by default
+			// when a SQL statement is executed without type hints.
+			dEnum.EnumTyp = types.Unknown
+		}
+		q.scratchDatums = append(q.scratchDatums, d)
+		return nil
+	})
+}
+
+func makePolicyInsertQueries(
+	dstTableDescID int32,
+	td catalog.TableDescriptor,
+	policies map[string]jobspb.LogicalReplicationDetails_ColumnConflictPolicy_Policy,
+	originHasPriority bool,
+) (map[catid.FamilyID]policyInsertQuery, error) {
+	pkColumns := td.TableDesc().PrimaryIndex.KeyColumnNames
+	isPKColumn := make(map[string]bool, len(pkColumns))
+	for _, name := range pkColumns {
+		isPKColumn[name] = true
+	}
+
+	queries := make(map[catid.FamilyID]policyInsertQuery, td.NumFamilies())
+	if err := td.ForeachFamily(func(family *descpb.ColumnFamilyDescriptor) error {
+		inputColumnNames, err := insertColumnNamesForFamily(td, family, false)
+		if err != nil {
+			return err
+		}
+		originTSIdx := len(inputColumnNames) + 1
+		lwwCond := fmt.Sprintf(lwwCondition, originTSIdx)
+
+		var q policyInsertQuery
+		// The row is updated if it wins under last-writer-wins or if any of the
+		// columns with a different policy needs to change.
+		var setExprs []string
+		whereExprs := []string{lwwCond}
+		for _, name := range inputColumnNames {
+			colName := lexbase.EscapeSQLIdent(name)
+			if isPKColumn[name] {
+				setExprs = append(setExprs, fmt.Sprintf("%s = excluded.%[1]s", colName))
+				continue
+			}
+			switch policies[name] {
+			case jobspb.LogicalReplicationDetails_ColumnConflictPolicy_LWW:
+				setExprs = append(setExprs, fmt.Sprintf(
+					"%s = CASE WHEN %s THEN excluded.%[1]s ELSE t.%[1]s END", colName, lwwCond))
+			case jobspb.LogicalReplicationDetails_ColumnConflictPolicy_Additive:
+				col, err := catalog.MustFindPublicColumnByTreeName(td, tree.Name(name))
+				if err != nil {
+					return err
+				}
+				prevValue := fmt.Sprintf("$%d::%s", originTSIdx+1+len(q.prevColumns), col.GetType().SQLString())
+				q.prevColumns = append(q.prevColumns, name)
+				setExprs = append(setExprs, fmt.Sprintf(
+					"%s = CASE WHEN excluded.%[1]s IS NULL THEN NULL ELSE COALESCE(t.%[1]s, 0) + (excluded.%[1]s - COALESCE(%s, 0)) END",
+					colName, prevValue))
+				whereExprs = append(whereExprs, fmt.Sprintf("excluded.%s IS DISTINCT FROM %s", colName, prevValue))
+				q.appliedStats.additiveMerges = 1
+			case jobspb.LogicalReplicationDetails_ColumnConflictPolicy_OriginWins:
+				if originHasPriority {
+					setExprs = append(setExprs, fmt.Sprintf("%s = excluded.%[1]s", colName))
+					whereExprs = append(whereExprs, fmt.Sprintf("t.%s IS DISTINCT FROM excluded.%[1]s", colName))
+				} else {
+					setExprs, whereExprs = appendDestinationWinsExprs(setExprs, whereExprs, colName)
+				}
+				q.appliedStats.originWins = 1
+			case jobspb.LogicalReplicationDetails_ColumnConflictPolicy_DestinationWins:
+				setExprs, whereExprs = appendDestinationWinsExprs(setExprs, whereExprs, colName)
+				q.appliedStats.destinationWins = 1
+			case jobspb.LogicalReplicationDetails_ColumnConflictPolicy_DLQ:
+				// Conflicting updates are rejected by the check query, so
+				// any update that gets this far can be applied.
+				setExprs = append(setExprs, fmt.Sprintf("%s = excluded.%[1]s", colName))
+				whereExprs = append(whereExprs, fmt.Sprintf("t.%s IS DISTINCT FROM excluded.%[1]s", colName))
+				q.checkColumns = append(q.checkColumns, name)
+			default:
+				return errors.AssertionFailedf("unknown conflict policy %s for column %s", policies[name], name)
+			}
+		}
+		var columnNames strings.Builder
+		for i, name := range inputColumnNames {
+			if i > 0 {
+				columnNames.WriteString(", ")
+			}
+			columnNames.WriteString(lexbase.EscapeSQLIdent(name))
+		}
+		valStr := valueStringForNumItems(len(inputColumnNames), 1)
+		parsedOptimisticQuery, err := parser.ParseOne(fmt.Sprintf(insertQueryOptimistic,
+			dstTableDescID,
+			columnNames.String(),
+			valStr,
+		))
+		if err != nil {
+			return err
+		}
+		parsedPessimisticQuery, err := parser.ParseOne(fmt.Sprintf(insertQueryWithPolicies,
+			dstTableDescID,
+			columnNames.String(),
+			valStr,
+			sqlEscapedJoin(pkColumns, ","),
+			strings.Join(setExprs, ",\n"),
+			strings.Join(whereExprs, "\n OR "),
+		))
+		if err != nil {
+			return err
+		}
+		q.insert = queryBuilder{
+			stmts: []statements.Statement[tree.Statement]{
+				parsedOptimisticQuery,
+				parsedPessimisticQuery,
+			},
+			needsOriginTimestamp: true,
+			inputColumns:         inputColumnNames,
+			scratchDatums:        make([]interface{}, len(inputColumnNames)+1+len(q.prevColumns)),
+		}
+
+		if len(q.checkColumns) > 0 {
+			q.check, err = makeConflictCheckQuery(dstTableDescID, pkColumns, q.checkColumns)
+			if err != nil {
+				return err
+			}
+		}
+		queries[family.ID] = q
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// appendDestinationWinsExprs appends the expressions which keep the value of a
+// column if the row was last written locally, and take the source's value
+// otherwise.
+func appendDestinationWinsExprs(setExprs, whereExprs []string, colName string) ([]string, []string) {
+	setExprs = append(setExprs, fmt.Sprintf(
+		"%s = CASE WHEN t.crdb_internal_origin_timestamp IS NULL THEN t.%[1]s ELSE excluded.%[1]s END", colName))
+	whereExprs = append(whereExprs, fmt.Sprintf(
+		"(t.crdb_internal_origin_timestamp IS NOT NULL AND t.%s IS DISTINCT FROM excluded.%[1]s)", colName))
+	return setExprs, whereExprs
+}
+
+// makeConflictCheckQuery returns a query that, for the row with the given
+// primary key, returns whether each of the given columns differs from both the
+// source's new value and its previous value.
+func makeConflictCheckQuery(
+	dstTableDescID int32, pkColumns []string, checkColumns []string,
+) (queryBuilder, error) {
+	inputColumns := append(append([]string(nil), pkColumns...), checkColumns...)
+	var whereClause strings.Builder
+	for i, name := range pkColumns {
+		if i > 0 {
+			whereClause.WriteString(" AND ")
+		}
+		fmt.Fprintf(&whereClause, "%s = $%d", lexbase.EscapeSQLIdent(name), i+1)
+	}
+	selectExprs := make([]string, len(checkColumns))
+	for i, name := range checkColumns {
+		newIdx := len(pkColumns) + i + 1
+		prevIdx := len(inputColumns) + i + 1
+		selectExprs[i] = fmt.Sprintf("t.%s IS DISTINCT FROM $%d AND t.%[1]s IS DISTINCT FROM $%d",
+			lexbase.EscapeSQLIdent(name), newIdx, prevIdx)
+	}
+	stmt, err := parser.ParseOne(fmt.Sprintf(conflictCheckQuery,
+		strings.Join(selectExprs, ", "), dstTableDescID, whereClause.String()))
+	if err != nil {
+		return queryBuilder{}, err
+	}
+	return queryBuilder{
+		stmts:         []statements.Statement[tree.Statement]{stmt},
+		inputColumns:  inputColumns,
+		scratchDatums: make([]interface{}, len(inputColumns)+len(checkColumns)),
+	}, nil
+}
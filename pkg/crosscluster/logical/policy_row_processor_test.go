@@ -0,0 +1,224 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/replicationtestutils"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConflictPolicies is a datadriven test of the per-column conflict
+// policies. The test files are in testdata/conflict_policies. The following
+// syntax is provided:
+//
+// - exec-sql
+// Executes the input SQL statements on the destination.
+//
+// - query-sql
+// Executes the input SQL query on the destination and prints the results.
+//
+// - create-processor table=<name> policies=(<column>:<policy>,...) [origin-priority=<bool>]
+// Creates a row processor that replicates into the given table using the given
+// conflict policies. The source of the stream has priority over the destination
+// unless origin-priority=false.
+//
+// - show-queries table=<name>
+// Prints the queries used to apply updates to the table.
+//
+// - apply table=<name> [ts=<offset>]
+// Applies the replicated row updates in the input, one per line, of the form
+// `[(<prev values>)] -> (<values>)`. The updates are given the current time
+// plus the optional offset as their timestamp. Prints the outcome and the
+// conflict policy stats of each update.
+func TestConflictPolicies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	skip.UnderDeadlock(t)
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+	// Always try the optimistic insert first so that the reported stats are
+	// deterministic.
+	runner.Exec(t, "SET CLUSTER SETTING logical_replication.consumer.try_optimistic_insert.enabled = true")
+
+	descsByName := make(map[string]catalog.TableDescriptor)
+	processors := make(map[string]*sqlRowProcessor)
+
+	encodeRow := func(t *testing.T, desc catalog.TableDescriptor, values string) roachpb.KeyValue {
+		values = strings.TrimSpace(values)
+		values = strings.TrimSuffix(strings.TrimPrefix(values, "("), ")")
+		cols := desc.PublicColumns()
+		var datums []interface{}
+		for i, v := range strings.Split(values, ",") {
+			v = strings.TrimSpace(v)
+			if v == "NULL" {
+				datums = append(datums, tree.DNull)
+				continue
+			}
+			d, _, err := tree.ParseAndRequireString(cols[i].GetType(), v, nil /* ctx */)
+			require.NoError(t, err)
+			datums = append(datums, d)
+		}
+		return replicationtestutils.EncodeKV(t, s.Codec(), desc, datums...)
+	}
+
+	datadriven.RunTest(t, datapathutils.TestDataPath(t, "conflict_policies"), func(t *testing.T, d *datadriven.TestData) string {
+		switch d.Cmd {
+		case "exec-sql":
+			runner.Exec(t, d.Input)
+			return ""
+
+		case "query-sql":
+			return sqlutils.MatrixToStr(runner.QueryStr(t, d.Input))
+
+		case "create-processor":
+			var tableName string
+			var policies []string
+			d.ScanArgs(t, "table", &tableName)
+			d.ScanArgs(t, "policies", &policies)
+			originPriority := true
+			if d.HasArg("origin-priority") {
+				d.ScanArgs(t, "origin-priority", &originPriority)
+			}
+			var columnPolicies []jobspb.LogicalReplicationDetails_ColumnConflictPolicy
+			for _, p := range policies {
+				col, name, ok := strings.Cut(p, ":")
+				require.True(t, ok, "expected <column>:<policy>, got %q", p)
+				policy, err := parseColumnConflictPolicy(name)
+				require.NoError(t, err)
+				columnPolicies = append(columnPolicies, jobspb.LogicalReplicationDetails_ColumnConflictPolicy{
+					ColumnName: col,
+					Policy:     policy,
+				})
+			}
+			desc := desctestutils.TestingGetPublicTableDescriptor(s.DB(), s.Codec(), "defaultdb", tableName)
+			sd := sql.NewInternalSessionData(ctx, s.ClusterSettings(), "" /* opName */)
+			rp, err := makeSQLProcessor(ctx, s.ClusterSettings(), map[descpb.ID]sqlProcessorTableConfig{
+				desc.GetID(): {
+					srcDesc:        desc,
+					columnPolicies: columnPolicies,
+				},
+			}, jobspb.JobID(1), s.InternalDB().(descs.DB), s.InternalExecutor().(isql.Executor), sd,
+				execinfrapb.LogicalReplicationWriterSpec{OriginHasPriority: originPriority})
+			require.NoError(t, err)
+			descsByName[tableName] = desc
+			processors[tableName] = rp
+			return ""
+
+		case "show-queries":
+			var tableName string
+			d.ScanArgs(t, "table", &tableName)
+			desc := descsByName[tableName]
+			pq := processors[tableName].querier.(*muxQuerier).policyQuerier.(*policyQuerier)
+			q := pq.policyInsertQueries[desc.GetID()][0]
+
+			var out strings.Builder
+			stmt, _, err := q.insert.Query(insertQueriesPessimisticIndex)
+			require.NoError(t, err)
+			fmt.Fprintf(&out, "%s;\n", strings.TrimSpace(stmt.SQL))
+			if len(q.checkColumns) > 0 {
+				stmt, _, err := q.check.Query(defaultQuery)
+				require.NoError(t, err)
+				fmt.Fprintf(&out, "%s;\n", strings.TrimSpace(stmt.SQL))
+			}
+			return strings.ReplaceAll(out.String(),
+				fmt.Sprintf("[%d AS t]", desc.GetID()), fmt.Sprintf("[%s AS t]", tableName))
+
+		case "apply":
+			var tableName string
+			d.ScanArgs(t, "table", &tableName)
+			desc := descsByName[tableName]
+			ts := timeutil.Now()
+			if d.HasArg("ts") {
+				var offset string
+				d.ScanArgs(t, "ts", &offset)
+				dur, err := time.ParseDuration(offset)
+				require.NoError(t, err)
+				ts = ts.Add(dur)
+			}
+
+			var out strings.Builder
+			for _, line := range strings.Split(d.Input, "\n") {
+				prev, next, ok := strings.Cut(line, "->")
+				require.True(t, ok, "expected [<prev>] -> <row>, got %q", line)
+				keyValue := encodeRow(t, desc, next)
+				keyValue.Value.Timestamp = hlc.Timestamp{WallTime: ts.UnixNano()}
+				var prevValue roachpb.Value
+				if strings.TrimSpace(prev) != "" {
+					prevValue = encodeRow(t, desc, prev).Value
+				}
+				stats, err := processors[tableName].ProcessRow(ctx, nil /* txn */, keyValue, prevValue)
+				switch {
+				case errors.Is(err, errConflictPolicyRejected):
+					fmt.Fprintf(&out, "rejected: %s\n", err)
+					continue
+				case err != nil:
+					fmt.Fprintf(&out, "error: %s\n", err)
+					continue
+				}
+				out.WriteString("ok")
+				if stats.additiveMerges > 0 {
+					fmt.Fprintf(&out, " additive=%d", stats.additiveMerges)
+				}
+				if stats.originWins > 0 {
+					fmt.Fprintf(&out, " origin_wins=%d", stats.originWins)
+				}
+				if stats.destinationWins > 0 {
+					fmt.Fprintf(&out, " destination_wins=%d", stats.destinationWins)
+				}
+				out.WriteString("\n")
+			}
+			return out.String()
+
+		default:
+			return fmt.Sprintf("unknown command: %s", d.Cmd)
+		}
+	})
+}
+
+func TestOriginHasPriority(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	a := uuid.MakeV4()
+	b := uuid.MakeV4()
+	for a == b {
+		b = uuid.MakeV4()
+	}
+	// Exactly one of the clusters of a bidirectional pair has priority.
+	require.NotEqual(t, originHasPriority(a, b), originHasPriority(b, a))
+}
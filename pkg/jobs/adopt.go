@@ -221,7 +221,6 @@ func (r *Registry) resumeJob(
 	ctx context.Context, jobID jobspb.JobID, s sqlliveness.Session,
 ) (retErr error) {
 	ctx = logtags.AddTag(ctx, "job", jobID)
-	log.Infof(ctx, "job %d: resuming execution", jobID)
 
 	job, err := r.loadJobForResume(ctx, jobID, s)
 	if err != nil {
@@ -233,6 +232,7 @@ func (r *Registry) resumeJob(
 	if job == nil {
 		return nil
 	}
+	log.Infof(ctx, "job %d: resuming execution", jobID)
 
 	resumer, err := r.createResumer(job)
 	if err != nil {
@@ -330,7 +330,12 @@ func (r *Registry) loadJobForResume(
 		if !exists {
 			return errors.Wrap(&JobNotFoundError{jobID: jobID}, "job progress not found in system.job_info")
 		}
-		return protoutil.Unmarshal(progressBytes, progress)
+		if err := protoutil.Unmarshal(progressBytes, progress); err != nil {
+			return err
+		}
+
+		job.dependsOn, err = infoStorage.getDependencies(ctx, "loadForResume")
+		return err
 	}); err != nil {
 		return nil, err
 	}
@@ -339,6 +344,15 @@ func (r *Registry) loadJobForResume(
 	job.mu.progress = *progress
 	job.mu.status = status
 	job.session = s
+
+	// A job that depends on other jobs is not resumed until they succeed. It
+	// stays claimed, so the dependencies are checked again on the next
+	// adoption loop.
+	if status == StatusRunning && len(job.dependsOn) > 0 {
+		if ready, err := r.checkDependencies(ctx, job); err != nil || !ready {
+			return nil, err
+		}
+	}
 	return job, nil
 }
 
@@ -363,6 +377,7 @@ func (r *Registry) addAdoptedJob(
 		cancel:  cancel,
 		isIdle:  false,
 		resumer: resumer,
+		resumed: r.clock.PhysicalTime(),
 	}
 	return false
 }
@@ -511,6 +526,7 @@ func (r *Registry) servePauseAndCancelRequests(ctx context.Context, s sqllivenes
 						nil,
 						StatusPauseRequested,
 						StatusPaused)
+					r.maybeNotifyStatusChange(id, StatusPauseRequested, StatusPaused)
 				})
 				log.Infof(ctx, "job %d, session %s: paused", id, s.ID())
 			case StatusReverting:
@@ -0,0 +1,138 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// DependsOnInfoKey is the info_key whose value is the jobspb.JobDependencies
+// of a job that depends on other jobs.
+const DependsOnInfoKey = "depends_on"
+
+// dependencyStatusQuery retrieves the status of the jobs that a job depends on.
+const dependencyStatusQuery = `SELECT id, status FROM system.jobs WHERE id = ANY($1)`
+
+// writeDependencies writes the jobs that the job depends on to the
+// system.job_info table, if there are any. It is called by the registry when
+// any job is created, and fails if one of the jobs does not exist.
+func (i InfoStorage) writeDependencies(ctx context.Context, dependsOn []jobspb.JobID) error {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	if err := validateDependencies(ctx, i.txn, dependsOn); err != nil {
+		return err
+	}
+	value, err := protoutil.Marshal(&jobspb.JobDependencies{JobIDs: dependsOn})
+	if err != nil {
+		return err
+	}
+	return i.Write(ctx, DependsOnInfoKey, value)
+}
+
+// getDependencies returns the jobs that the job depends on, if any.
+func (i InfoStorage) getDependencies(ctx context.Context, opName string) ([]jobspb.JobID, error) {
+	value, exists, err := i.Get(ctx, opName, DependsOnInfoKey)
+	if err != nil || !exists {
+		return nil, err
+	}
+	var deps jobspb.JobDependencies
+	if err := protoutil.Unmarshal(value, &deps); err != nil {
+		return nil, err
+	}
+	return deps.JobIDs, nil
+}
+
+// validateDependencies checks that the jobs with the given IDs exist, so that
+// a job can be created that depends on them.
+func validateDependencies(ctx context.Context, txn isql.Txn, dependsOn []jobspb.JobID) error {
+	statuses, err := dependencyStatuses(ctx, txn, txn.KV(), dependsOn)
+	if err != nil {
+		return err
+	}
+	for _, id := range dependsOn {
+		if _, ok := statuses[id]; !ok {
+			return pgerror.Newf(pgcode.UndefinedObject, "dependency job %d does not exist", id)
+		}
+	}
+	return nil
+}
+
+// dependencyStatuses returns the statuses of the jobs with the given IDs that
+// exist.
+func dependencyStatuses(
+	ctx context.Context, ex isql.Executor, txn *kv.Txn, ids []jobspb.JobID,
+) (map[jobspb.JobID]Status, error) {
+	arr := tree.NewDArray(types.Int)
+	for _, id := range ids {
+		if err := arr.Append(tree.NewDInt(tree.DInt(id))); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := ex.QueryBufferedEx(
+		ctx, "get-job-dependencies", txn,
+		sessiondata.NodeUserSessionDataOverride, dependencyStatusQuery, arr,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query the status of job dependencies")
+	}
+	statuses := make(map[jobspb.JobID]Status, len(rows))
+	for _, row := range rows {
+		statuses[jobspb.JobID(*row[0].(*tree.DInt))] = Status(*row[1].(*tree.DString))
+	}
+	return statuses, nil
+}
+
+// checkDependencies returns whether all the jobs that the job depends on have
+// succeeded, so that the job can be resumed. If one of them failed, was
+// canceled, or no longer exists, the job is requested to be canceled, which in
+// turn cancels the jobs that depend on it.
+func (r *Registry) checkDependencies(ctx context.Context, job *Job) (ready bool, _ error) {
+	statuses, err := dependencyStatuses(ctx, r.db.Executor(), nil /* txn */, job.dependsOn)
+	if err != nil {
+		return false, err
+	}
+	ready = true
+	for _, id := range job.dependsOn {
+		status, ok := statuses[id]
+		switch {
+		case !ok:
+			return false, r.cancelForDependency(ctx, job, errors.Newf(
+				"dependency job %d does not exist", id))
+		case status == StatusSucceeded:
+		case status.Terminal():
+			return false, r.cancelForDependency(ctx, job, errors.Newf(
+				"dependency job %d did not succeed: %s", id, status))
+		default:
+			ready = false
+		}
+	}
+	if !ready {
+		log.VEventf(ctx, 2, "job %d: waiting for the jobs it depends on to succeed", job.ID())
+	}
+	return ready, nil
+}
+
+// cancelForDependency requests the job to be canceled since one of the jobs it
+// depends on did not succeed.
+func (r *Registry) cancelForDependency(ctx context.Context, job *Job, reason error) error {
+	log.Infof(ctx, "job %d: canceling: %v", job.ID(), reason)
+	return job.NoTxn().Update(ctx, func(txn isql.Txn, md JobMetadata, ju *JobUpdater) error {
+		return ju.CancelRequestedWithReason(ctx, md, reason)
+	})
+}
@@ -43,8 +43,14 @@ type Job struct {
 
 	id        jobspb.JobID
 	createdBy *CreatedByInfo
-	session   sqlliveness.Session
-	mu        struct {
+	// dependsOn are the jobs that must succeed before this job is resumed.
+	dependsOn []jobspb.JobID
+	// notificationURI is the channel that notifications about the state
+	// changes of this job are sent to, in addition to the schedule and global
+	// ones.
+	notificationURI string
+	session         sqlliveness.Session
+	mu              struct {
 		syncutil.Mutex
 		payload  jobspb.Payload
 		progress jobspb.Progress
@@ -90,6 +96,13 @@ type Record struct {
 	// MaximumPTSAge specifies the maximum age of PTS record held by a job.
 	// 0 means no limit.
 	MaximumPTSAge time.Duration
+	// DependsOn are the IDs of the jobs that must succeed before this job is
+	// resumed.
+	DependsOn []jobspb.JobID
+	// NotificationURI, if set, is the URI of a channel that notifications are
+	// sent to when the job succeeds, fails, is canceled or paused, or makes no
+	// progress. See RegisterNotificationSender.
+	NotificationURI string
 }
 
 // AppendDescription appends description to this records Description with a
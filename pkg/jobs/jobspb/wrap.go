@@ -46,6 +46,8 @@ var (
 	_ Details = LogicalReplicationDetails{}
 	_ Details = UpdateTableMetadataCacheDetails{}
 	_ Details = StandbyReadTSPollerDetails{}
+	_ Details = ScheduledSQLDetails{}
+	_ Details = BackupCopyDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -76,6 +78,8 @@ var (
 	_ ProgressDetails = LogicalReplicationProgress{}
 	_ ProgressDetails = UpdateTableMetadataCacheProgress{}
 	_ ProgressDetails = StandbyReadTSPollerProgress{}
+	_ ProgressDetails = ScheduledSQLProgress{}
+	_ ProgressDetails = BackupCopyProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -236,6 +240,10 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeUpdateTableMetadataCache, nil
 	case *Payload_StandbyReadTsPollerDetails:
 		return TypeStandbyReadTSPoller, nil
+	case *Payload_ScheduledSQLDetails:
+		return TypeScheduledSQL, nil
+	case *Payload_BackupCopyDetails:
+		return TypeBackupCopy, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -289,6 +297,8 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeLogicalReplication:           LogicalReplicationDetails{},
 	TypeUpdateTableMetadataCache:     UpdateTableMetadataCacheDetails{},
 	TypeStandbyReadTSPoller:          StandbyReadTSPollerDetails{},
+	TypeScheduledSQL:                 ScheduledSQLDetails{},
+	TypeBackupCopy:                   BackupCopyDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -356,6 +366,10 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_TableMetadataCache{TableMetadataCache: &d}
 	case StandbyReadTSPollerProgress:
 		return &Progress_StandbyReadTsPoller{StandbyReadTsPoller: &d}
+	case ScheduledSQLProgress:
+		return &Progress_ScheduledSQL{ScheduledSQL: &d}
+	case BackupCopyProgress:
+		return &Progress_BackupCopy{BackupCopy: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -421,6 +435,10 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.UpdateTableMetadataCacheDetails
 	case *Payload_StandbyReadTsPollerDetails:
 		return *d.StandbyReadTsPollerDetails
+	case *Payload_ScheduledSQLDetails:
+		return *d.ScheduledSQLDetails
+	case *Payload_BackupCopyDetails:
+		return *d.BackupCopyDetails
 	default:
 		return nil
 	}
@@ -486,6 +504,10 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.TableMetadataCache
 	case *Progress_StandbyReadTsPoller:
 		return *d.StandbyReadTsPoller
+	case *Progress_ScheduledSQL:
+		return *d.ScheduledSQL
+	case *Progress_BackupCopy:
+		return *d.BackupCopy
 	default:
 		return nil
 	}
@@ -575,6 +597,10 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_UpdateTableMetadataCacheDetails{UpdateTableMetadataCacheDetails: &d}
 	case StandbyReadTSPollerDetails:
 		return &Payload_StandbyReadTsPollerDetails{StandbyReadTsPollerDetails: &d}
+	case ScheduledSQLDetails:
+		return &Payload_ScheduledSQLDetails{ScheduledSQLDetails: &d}
+	case BackupCopyDetails:
+		return &Payload_BackupCopyDetails{BackupCopyDetails: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -610,7 +636,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 31
+const NumJobTypes = 33
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
@@ -0,0 +1,453 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+)
+
+// NotificationURIInfoKey is the info_key whose value is the URI of the channel
+// that notifications about a job are sent to.
+const NotificationURIInfoKey = "notification_uri"
+
+// notificationMessageKind is the kind of the job messages that record the
+// delivery of notifications about a job.
+const notificationMessageKind = "notification"
+
+var (
+	notificationURISetting = settings.RegisterStringSetting(
+		settings.ApplicationLevel,
+		"jobs.notifications.uri",
+		"the URI of a channel that notifications about the state changes of jobs are sent to; "+
+			"empty disables global notifications",
+		"",
+		settings.WithValidateString(func(_ *settings.Values, uri string) error {
+			if uri == "" {
+				return nil
+			}
+			return ValidateNotificationURI(uri)
+		}),
+		settings.WithReportable(false),
+		settings.Sensitive,
+	)
+
+	notificationJobTypesSetting = settings.RegisterStringSetting(
+		settings.ApplicationLevel,
+		"jobs.notifications.job_types",
+		"the list, comma separated, of job types that notifications are sent to "+
+			"jobs.notifications.uri for; empty means all job types",
+		"",
+	)
+
+	notificationNoProgressTimeoutSetting = settings.RegisterDurationSetting(
+		settings.ApplicationLevel,
+		"jobs.notifications.no_progress_timeout",
+		"the duration after which a no_progress notification is sent for a running job "+
+			"that has not recorded any progress; 0 disables these notifications",
+		0,
+		settings.NonNegativeDuration,
+	)
+)
+
+// notificationRetryOptions control the retries of the delivery of a
+// notification to a channel.
+var notificationRetryOptions = retry.Options{
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	MaxRetries:     5,
+}
+
+// NotificationEvent is the kind of event that a notification is sent for.
+type NotificationEvent string
+
+const (
+	// NotificationEventSucceeded is sent when a job succeeds.
+	NotificationEventSucceeded NotificationEvent = "succeeded"
+	// NotificationEventFailed is sent when a job fails, or fails to revert.
+	NotificationEventFailed NotificationEvent = "failed"
+	// NotificationEventCanceled is sent when a job is canceled.
+	NotificationEventCanceled NotificationEvent = "canceled"
+	// NotificationEventPaused is sent when a job is paused.
+	NotificationEventPaused NotificationEvent = "paused"
+	// NotificationEventNoProgress is sent when a running job has not recorded
+	// any progress for jobs.notifications.no_progress_timeout.
+	NotificationEventNoProgress NotificationEvent = "no_progress"
+)
+
+// notificationEventForStatus returns the event that a notification is sent for
+// when a job moves to the given status, if any.
+func notificationEventForStatus(status Status) (NotificationEvent, bool) {
+	switch status {
+	case StatusSucceeded:
+		return NotificationEventSucceeded, true
+	case StatusFailed, StatusRevertFailed:
+		return NotificationEventFailed, true
+	case StatusCanceled:
+		return NotificationEventCanceled, true
+	case StatusPaused:
+		return NotificationEventPaused, true
+	default:
+		return "", false
+	}
+}
+
+// Notification is the payload sent to a notification channel, encoded as
+// JSON.
+type Notification struct {
+	Event          NotificationEvent `json:"event"`
+	JobID          jobspb.JobID      `json:"job_id"`
+	JobType        string            `json:"job_type"`
+	Description    string            `json:"description"`
+	Status         Status            `json:"status"`
+	PreviousStatus Status            `json:"previous_status,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	ScheduleID     jobspb.ScheduleID `json:"schedule_id,omitempty"`
+	// LastProgress is the time at which the job last recorded progress, for
+	// no_progress notifications.
+	LastProgress *time.Time `json:"last_progress,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
+}
+
+// NotificationSender delivers the encoded notification to the channel
+// identified by uri. It returns an error if the notification could not be
+// delivered, in which case the delivery is retried.
+type NotificationSender func(ctx context.Context, uri string, body []byte) error
+
+// NotificationURIValidator checks the parameters of the URI of a channel
+// before notifications are sent to it.
+type NotificationURIValidator func(uri string) error
+
+var (
+	notificationSenders       = make(map[string]NotificationSender)
+	notificationURIValidators = make(map[string]NotificationURIValidator)
+)
+
+// RegisterNotificationSender registers the sender of notifications to the
+// channels whose URI has the given scheme, along with the validator of these
+// URIs. It should only be called during init.
+func RegisterNotificationSender(
+	scheme string, sender NotificationSender, validate NotificationURIValidator,
+) {
+	notificationSenders[scheme] = sender
+	notificationURIValidators[scheme] = validate
+}
+
+// TestingRegisterNotificationSender is like RegisterNotificationSender but
+// returns a cleanup function that restores the previous sender for the scheme.
+func TestingRegisterNotificationSender(scheme string, sender NotificationSender) func() {
+	prev, ok := notificationSenders[scheme]
+	notificationSenders[scheme] = sender
+	return func() {
+		if ok {
+			notificationSenders[scheme] = prev
+		} else {
+			delete(notificationSenders, scheme)
+		}
+	}
+}
+
+// ValidateNotificationURI checks that notifications can be sent to the
+// channel identified by uri.
+func ValidateNotificationURI(uri string) error {
+	if _, err := notificationSenderFor(uri); err != nil {
+		return err
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return errors.Wrap(err, "invalid notification URI")
+	}
+	if u.Host == "" {
+		return errors.Newf("notification URI %q has no host", u.Redacted())
+	}
+	if validate := notificationURIValidators[u.Scheme]; validate != nil {
+		return validate(uri)
+	}
+	return nil
+}
+
+func notificationSenderFor(uri string) (NotificationSender, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid notification URI")
+	}
+	sender, ok := notificationSenders[u.Scheme]
+	if !ok {
+		return nil, errors.Newf("unsupported notification URI scheme %q", u.Scheme)
+	}
+	return sender, nil
+}
+
+// writeNotificationURI writes the URI of the channel that notifications about
+// the job are sent to to the system.job_info table, if there is one.
+func (i InfoStorage) writeNotificationURI(ctx context.Context, uri string) error {
+	if uri == "" {
+		return nil
+	}
+	return i.Write(ctx, NotificationURIInfoKey, []byte(uri))
+}
+
+// notificationChannel is a channel that a notification is sent to. The name
+// identifies the channel in the delivery log without revealing its URI, which
+// may contain credentials.
+type notificationChannel struct {
+	name string
+	uri  string
+}
+
+// notificationTargetQuery retrieves the fields of the notification about a job
+// that are not known when its status changes, along with the channels that
+// are configured for the job and the schedule that created it.
+const notificationTargetQuery = `
+SELECT j.job_type, j.description, j.error_msg, s.schedule_id, s.schedule_details, i.value
+  FROM system.jobs AS j
+  LEFT JOIN system.scheduled_jobs AS s
+         ON j.created_by_type = '` + CreatedByScheduledJobs + `' AND s.schedule_id = j.created_by_id
+  LEFT JOIN system.job_info AS i
+         ON i.job_id = j.id AND i.info_key = '` + NotificationURIInfoKey + `'
+ WHERE j.id = $1
+ ORDER BY i.written DESC
+ LIMIT 1`
+
+// notificationTarget fills in the details of the notification about the job
+// and returns the channels that it is sent to.
+func (r *Registry) notificationTarget(
+	ctx context.Context, n *Notification,
+) ([]notificationChannel, error) {
+	row, err := r.db.Executor().QueryRowEx(
+		ctx, "job-notification-target", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride, notificationTargetQuery, n.JobID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query the notification channels of the job")
+	}
+	if row == nil {
+		return nil, errors.Errorf("job %d does not exist", n.JobID)
+	}
+	if row[0] != tree.DNull {
+		n.JobType = string(tree.MustBeDString(row[0]))
+	}
+	if row[1] != tree.DNull {
+		n.Description = string(tree.MustBeDString(row[1]))
+	}
+	if row[2] != tree.DNull {
+		n.Error = string(tree.MustBeDString(row[2]))
+	}
+
+	var channels []notificationChannel
+	add := func(name, uri string) {
+		if uri == "" {
+			return
+		}
+		for _, c := range channels {
+			if c.uri == uri {
+				return
+			}
+		}
+		channels = append(channels, notificationChannel{name: name, uri: uri})
+	}
+	if row[5] != tree.DNull {
+		add("job", string(tree.MustBeDBytes(row[5])))
+	}
+	if row[3] != tree.DNull {
+		n.ScheduleID = jobspb.ScheduleID(tree.MustBeDInt(row[3]))
+		if row[4] != tree.DNull {
+			var details jobspb.ScheduleDetails
+			if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[4])), &details); err != nil {
+				return nil, err
+			}
+			add("schedule", details.NotificationURI)
+		}
+	}
+	if notifyJobType(notificationJobTypesSetting.Get(&r.settings.SV), n.JobType) {
+		add("global", notificationURISetting.Get(&r.settings.SV))
+	}
+	return channels, nil
+}
+
+// notifyJobType returns whether global notifications are sent for the given
+// job type, according to the comma separated list of job types.
+func notifyJobType(jobTypes string, jobType string) bool {
+	if jobTypes == "" {
+		return true
+	}
+	for _, t := range strings.Split(jobTypes, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), jobType) {
+			return true
+		}
+	}
+	return false
+}
+
+// maybeNotifyStatusChange sends a notification about the job moving from prev
+// to status, if notifications are sent for that status.
+func (r *Registry) maybeNotifyStatusChange(id jobspb.JobID, prev, status Status) {
+	event, ok := notificationEventForStatus(status)
+	if !ok {
+		return
+	}
+	r.notify(Notification{
+		Event:          event,
+		JobID:          id,
+		Status:         status,
+		PreviousStatus: prev,
+		Timestamp:      r.clock.PhysicalTime(),
+	})
+}
+
+// notify asynchronously sends the notification to the channels configured for
+// the job, retrying failed deliveries, and records the outcome of each
+// delivery in the messages of the job.
+func (r *Registry) notify(n Notification) {
+	if len(notificationSenders) == 0 {
+		return
+	}
+	ctx := r.serverCtx
+	if err := r.stopper.RunAsyncTask(ctx, "jobs/notify", func(ctx context.Context) {
+		ctx, cancel := r.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		channels, err := r.notificationTarget(ctx, &n)
+		if err != nil {
+			log.Warningf(ctx, "job %d: could not send %s notification: %v", n.JobID, n.Event, err)
+			return
+		}
+		if len(channels) == 0 {
+			return
+		}
+		body, err := json.Marshal(n)
+		if err != nil {
+			log.Warningf(ctx, "job %d: could not encode %s notification: %v", n.JobID, n.Event, err)
+			return
+		}
+		for _, c := range channels {
+			msg := fmt.Sprintf("%s notification delivered to the %s channel", n.Event, c.name)
+			if err := deliverNotification(ctx, c.uri, body); err != nil {
+				log.Warningf(ctx, "job %d: could not deliver %s notification to the %s channel: %v",
+					n.JobID, n.Event, c.name, err)
+				msg = fmt.Sprintf("%s notification to the %s channel failed: %v", n.Event, c.name, err)
+			}
+			if err := r.db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+				return MessageStorage(n.JobID).Record(ctx, txn, notificationMessageKind, msg)
+			}); err != nil {
+				log.Warningf(ctx, "job %d: could not record the delivery of a notification: %v", n.JobID, err)
+			}
+		}
+	}); err != nil {
+		log.Warningf(ctx, "job %d: could not send %s notification: %v", n.JobID, n.Event, err)
+	}
+}
+
+// deliverNotification sends the encoded notification to the channel identified
+// by uri, with retries.
+func deliverNotification(ctx context.Context, uri string, body []byte) error {
+	sender, err := notificationSenderFor(uri)
+	if err != nil {
+		return err
+	}
+	attempts := 0
+	for re := retry.StartWithCtx(ctx, notificationRetryOptions); re.Next(); {
+		attempts++
+		if err = sender(ctx, uri, body); err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return errors.Wrapf(err, "after %d attempts", attempts)
+}
+
+// stalledJobsQuery retrieves the running jobs claimed by this instance that
+// have not recorded progress since the time passed as $3, along with the time
+// at which they last did so, if ever.
+const stalledJobsQuery = `
+SELECT j.id, p.written
+  FROM system.jobs AS j
+  LEFT JOIN system.job_progress AS p ON p.job_id = j.id
+ WHERE j.status = '` + string(StatusRunning) + `'
+   AND j.claim_session_id = $1 AND j.claim_instance_id = $2
+   AND (p.written IS NULL OR p.written < $3)`
+
+// maybeNotifyStalledJobs sends a no_progress notification for the running
+// jobs claimed by this instance that have not recorded any progress for
+// jobs.notifications.no_progress_timeout. A job is notified once per stall.
+func (r *Registry) maybeNotifyStalledJobs(ctx context.Context, s sqlliveness.Session) {
+	timeout := notificationNoProgressTimeoutSetting.Get(&r.settings.SV)
+	if timeout == 0 || len(notificationSenders) == 0 {
+		return
+	}
+	now := r.clock.PhysicalTime()
+	rows, err := r.db.Executor().QueryBufferedEx(
+		ctx, "stalled-jobs", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride, stalledJobsQuery,
+		s.ID().UnsafeBytes(), r.ID(), now.Add(-timeout),
+	)
+	if err != nil {
+		log.Errorf(ctx, "could not query stalled jobs: %v", err)
+		return
+	}
+
+	stalled := make(map[jobspb.JobID]time.Time, len(rows))
+	r.mu.Lock()
+	notified := r.mu.stalledJobs
+	resumed := make(map[jobspb.JobID]time.Time, len(rows))
+	for _, row := range rows {
+		id := jobspb.JobID(tree.MustBeDInt(row[0]))
+		if aj, ok := r.mu.adoptedJobs[id]; ok {
+			resumed[id] = aj.resumed
+		}
+	}
+	r.mu.Unlock()
+	for _, row := range rows {
+		id := jobspb.JobID(tree.MustBeDInt(row[0]))
+		// A job that is not running here, e.g. because it waits on its
+		// dependencies, is not stalled. Otherwise, its progress is counted
+		// from the time it was resumed, since it may not have recorded any
+		// yet, or only before it was last resumed.
+		lastProgress, ok := resumed[id]
+		if !ok {
+			continue
+		}
+		if row[1] != tree.DNull {
+			if written := tree.MustBeDTimestampTZ(row[1]).Time; written.After(lastProgress) {
+				lastProgress = written
+			}
+		}
+		if !lastProgress.Before(now.Add(-timeout)) {
+			continue
+		}
+		stalled[id] = lastProgress
+		if t, ok := notified[id]; ok && t.Equal(lastProgress) {
+			continue
+		}
+		r.notify(Notification{
+			Event:        NotificationEventNoProgress,
+			JobID:        id,
+			Status:       StatusRunning,
+			LastProgress: &lastProgress,
+			Timestamp:    now,
+		})
+	}
+	r.mu.Lock()
+	r.mu.stalledJobs = stalled
+	r.mu.Unlock()
+}
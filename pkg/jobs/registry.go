@@ -56,6 +56,8 @@ type adoptedJob struct {
 	resumer Resumer
 	// Calling the func will cancel the context the job was resumed with.
 	cancel context.CancelFunc
+	// resumed is the time at which the job was resumed.
+	resumed time.Time
 }
 
 // adoptionNotice is used by Run to notify the registry to resumeClaimedJobs
@@ -152,6 +154,11 @@ type Registry struct {
 		// ingestingJobs is a map of jobs which are actively ingesting on this node
 		// including via a processor.
 		ingestingJobs map[jobspb.JobID]struct{}
+
+		// stalledJobs maps the jobs claimed by this instance that a no_progress
+		// notification was sent for to the time they last made progress, so
+		// that a stall is only notified once.
+		stalledJobs map[jobspb.JobID]time.Time
 	}
 
 	// drainRequested signaled to indicate that this registry will shut
@@ -331,9 +338,11 @@ func (r *Registry) MakeJobID() jobspb.JobID {
 // newJob creates a new Job.
 func (r *Registry) newJob(ctx context.Context, record Record) (*Job, error) {
 	job := &Job{
-		id:        record.JobID,
-		registry:  r,
-		createdBy: record.CreatedBy,
+		id:              record.JobID,
+		registry:        r,
+		createdBy:       record.CreatedBy,
+		dependsOn:       record.DependsOn,
+		notificationURI: record.NotificationURI,
 	}
 	payload, err := r.makePayload(ctx, &record)
 	if err != nil {
@@ -461,6 +470,12 @@ func batchJobWriteToJobInfo(
 		if err := infoStorage.WriteLegacyProgress(ctx, progressBytes); err != nil {
 			return err
 		}
+		if err := infoStorage.writeDependencies(ctx, j.dependsOn); err != nil {
+			return err
+		}
+		if err := infoStorage.writeNotificationURI(ctx, j.notificationURI); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -635,6 +650,12 @@ func (r *Registry) CreateJobWithTxn(
 		if err := infoStorage.WriteLegacyProgress(ctx, progressBytes); err != nil {
 			return err
 		}
+		if err := infoStorage.writeDependencies(ctx, j.dependsOn); err != nil {
+			return err
+		}
+		if err := infoStorage.writeNotificationURI(ctx, j.notificationURI); err != nil {
+			return err
+		}
 
 		return nil
 	}
@@ -763,6 +784,12 @@ func (r *Registry) CreateAdoptableJobWithTxn(
 		if err := infoStorage.WriteLegacyProgress(ctx, progressBytes); err != nil {
 			return err
 		}
+		if err := infoStorage.writeDependencies(ctx, j.dependsOn); err != nil {
+			return err
+		}
+		if err := infoStorage.writeNotificationURI(ctx, j.notificationURI); err != nil {
+			return err
+		}
 
 		return nil
 	}
@@ -988,6 +1015,7 @@ func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) error {
 		removeClaimsFromDeadSessions(ctx, s)
 		r.maybeCancelJobs(ctx, s)
 		servePauseAndCancelRequests(ctx, s)
+		r.maybeNotifyStalledJobs(ctx, s)
 	})
 	// claimJobs iterates the set of jobs which are not currently claimed and
 	// claims jobs up to maxAdoptionsPerLoop.
@@ -8,6 +8,7 @@ package jobs
 import (
 	"context"
 	gosql "database/sql"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -40,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
@@ -1227,3 +1229,225 @@ func TestGetClaimedResumerFromRegistry(t *testing.T) {
 	require.NoError(t, resumer.OnFailOrCancel(ctx, nil, nil))
 	require.Equal(t, 1, counter)
 }
+
+// blockingImportJobs is a test server whose import jobs are adopted quickly
+// and block once resumed: the ID of each resumed job is sent on resumed, and
+// the job then returns the next error received on resumeErr.
+type blockingImportJobs struct {
+	r         *Registry
+	runner    *sqlutils.SQLRunner
+	resumed   chan jobspb.JobID
+	resumeErr chan error
+}
+
+// startBlockingImportJobs starts a test server for blockingImportJobs. The
+// returned function stops it.
+func startBlockingImportJobs(t *testing.T) (*blockingImportJobs, func()) {
+	intervalOverride := time.Millisecond
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			SpanConfig: &spanconfig.TestingKnobs{
+				ManagerDisableJobCreation: true,
+			},
+			JobsTestingKnobs: &TestingKnobs{
+				IntervalOverrides: TestingIntervalOverrides{
+					Adopt:  &intervalOverride,
+					Cancel: &intervalOverride,
+				},
+			},
+			KeyVisualizer: &keyvisualizer.TestingKnobs{
+				SkipJobBootstrap: true,
+			},
+		},
+	})
+	b := &blockingImportJobs{
+		r:         s.JobRegistry().(*Registry),
+		runner:    sqlutils.MakeSQLRunner(sqlDB),
+		resumed:   make(chan jobspb.JobID),
+		resumeErr: make(chan error),
+	}
+	cleanup := TestingRegisterConstructor(jobspb.TypeImport, func(j *Job, _ *cluster.Settings) Resumer {
+		return jobstest.FakeResumer{
+			OnResume: func(ctx context.Context) error {
+				select {
+				case b.resumed <- j.ID():
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				select {
+				case err := <-b.resumeErr:
+					return err
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		}
+	}, UsesTenantCostControl)
+	return b, func() {
+		cleanup()
+		s.Stopper().Stop(context.Background())
+	}
+}
+
+// TestJobDependencies verifies that a job is not resumed until the jobs it
+// depends on succeed, and that it is canceled if one of them fails.
+func TestJobDependencies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	bj, stop := startBlockingImportJobs(t)
+	defer stop()
+	r, runner, resumed, resumeErr := bj.r, bj.runner, bj.resumed, bj.resumeErr
+
+	createJob := func(dependsOn ...jobspb.JobID) jobspb.JobID {
+		jobID := r.MakeJobID()
+		_, err := r.CreateAdoptableJobWithTxn(ctx, Record{
+			Details:   jobspb.ImportDetails{},
+			Progress:  jobspb.ImportProgress{},
+			Username:  username.TestUserName(),
+			DependsOn: dependsOn,
+		}, jobID, nil /* txn */)
+		require.NoError(t, err)
+		return jobID
+	}
+	expectNotResumed := func() {
+		select {
+		case id := <-resumed:
+			t.Fatalf("job %d resumed before its dependencies succeeded", id)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	// A job cannot depend on a job that does not exist.
+	_, err := r.CreateAdoptableJobWithTxn(ctx, Record{
+		Details:   jobspb.ImportDetails{},
+		Progress:  jobspb.ImportProgress{},
+		Username:  username.TestUserName(),
+		DependsOn: []jobspb.JobID{r.MakeJobID()},
+	}, r.MakeJobID(), nil /* txn */)
+	require.ErrorContains(t, err, "does not exist")
+
+	a := createJob()
+	require.Equal(t, a, <-resumed)
+	b := createJob(a)
+	c := createJob(b)
+	expectNotResumed()
+
+	runner.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT job_id, depends_on FROM [SHOW JOBS] WHERE job_id IN (%d, %d, %d) ORDER BY created`, a, b, c),
+		[][]string{
+			{strconv.Itoa(int(a)), "NULL"},
+			{strconv.Itoa(int(b)), fmt.Sprintf("{%d}", a)},
+			{strconv.Itoa(int(c)), fmt.Sprintf("{%d}", b)},
+		})
+
+	// Once a succeeds, b is resumed, but c still waits for b.
+	resumeErr <- nil
+	require.Equal(t, b, <-resumed)
+	expectNotResumed()
+
+	// When b fails, c is canceled without being resumed.
+	resumeErr <- errors.New("boom")
+	runner.CheckQueryResultsRetry(t,
+		fmt.Sprintf(`SELECT status FROM [SHOW JOB %d]`, c),
+		[][]string{{string(StatusCanceled)}})
+}
+
+func TestJobNotifications(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer func(opts retry.Options) { notificationRetryOptions = opts }(notificationRetryOptions)
+	notificationRetryOptions = retry.Options{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		MaxRetries:     3,
+	}
+
+	type delivery struct {
+		uri string
+		n   Notification
+	}
+	delivered := make(chan delivery, 10)
+	var jobAttempts atomic.Int32
+	defer TestingRegisterNotificationSender("test", func(ctx context.Context, uri string, body []byte) error {
+		// Fail the first delivery to the job channel to exercise the retries.
+		if uri == "test://job" && jobAttempts.Add(1) == 1 {
+			return errors.New("unavailable")
+		}
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			return err
+		}
+		delivered <- delivery{uri: uri, n: n}
+		return nil
+	})()
+
+	ctx := context.Background()
+	bj, stop := startBlockingImportJobs(t)
+	defer stop()
+	r, runner, resumed, resumeErr := bj.r, bj.runner, bj.resumed, bj.resumeErr
+	runner.Exec(t, `SET CLUSTER SETTING jobs.notifications.uri = 'test://global'`)
+	runner.Exec(t, `SET CLUSTER SETTING jobs.notifications.job_types = 'import'`)
+
+	createJob := func(uri string) jobspb.JobID {
+		jobID := r.MakeJobID()
+		_, err := r.CreateAdoptableJobWithTxn(ctx, Record{
+			Details:         jobspb.ImportDetails{},
+			Progress:        jobspb.ImportProgress{},
+			Username:        username.TestUserName(),
+			NotificationURI: uri,
+		}, jobID, nil /* txn */)
+		require.NoError(t, err)
+		require.Equal(t, jobID, <-resumed)
+		return jobID
+	}
+	expectNotifications := func(id jobspb.JobID, event NotificationEvent, uris ...string) {
+		var got []string
+		for range uris {
+			d := <-delivered
+			require.Equal(t, id, d.n.JobID)
+			require.Equal(t, event, d.n.Event)
+			require.Equal(t, "IMPORT", d.n.JobType)
+			got = append(got, d.uri)
+		}
+		require.ElementsMatch(t, uris, got)
+	}
+
+	// A succeeded job notifies both its own channel and the global one, and
+	// the deliveries are logged.
+	a := createJob("test://job")
+	resumeErr <- nil
+	expectNotifications(a, NotificationEventSucceeded, "test://job", "test://global")
+	require.Equal(t, int32(2), jobAttempts.Load())
+	runner.CheckQueryResultsRetry(t,
+		fmt.Sprintf(`SELECT message FROM system.job_message WHERE job_id = %d AND kind = 'notification' ORDER BY message`, a),
+		[][]string{
+			{"succeeded notification delivered to the global channel"},
+			{"succeeded notification delivered to the job channel"},
+		})
+
+	// A running job that makes no progress is notified once.
+	runner.Exec(t, `SET CLUSTER SETTING jobs.notifications.no_progress_timeout = '1ms'`)
+	b := createJob("")
+	expectNotifications(b, NotificationEventNoProgress, "test://global")
+	select {
+	case d := <-delivered:
+		t.Fatalf("unexpected notification: %+v", d)
+	case <-time.After(100 * time.Millisecond):
+	}
+	runner.Exec(t, `RESET CLUSTER SETTING jobs.notifications.no_progress_timeout`)
+
+	// Jobs of other types are not notified on the global channel.
+	runner.Exec(t, `SET CLUSTER SETTING jobs.notifications.job_types = 'backup'`)
+	resumeErr <- errors.New("boom")
+	runner.CheckQueryResultsRetry(t,
+		fmt.Sprintf(`SELECT status FROM [SHOW JOB %d]`, b),
+		[][]string{{string(StatusFailed)}})
+	select {
+	case d := <-delivered:
+		t.Fatalf("unexpected notification: %+v", d)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -192,6 +192,7 @@ WHERE id = $1
 			}
 			// If run stats has been updated, use the updated run stats.
 			LogStatusChangeStructured(ctx, md.ID, p.Type().String(), p, status, ju.md.Status)
+			j.registry.maybeNotifyStatusChange(md.ID, status, ju.md.Status)
 		})
 	}
 	if j.registry.knobs.BeforeUpdate != nil {
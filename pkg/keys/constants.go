@@ -210,6 +210,15 @@ var (
 	LocalStoreCachedSettingsKeyMin = MakeStoreKey(localStoreCachedSettingsSuffix, nil)
 	// LocalStoreCachedSettingsKeyMax is the end of span of possible cached settings keys.
 	LocalStoreCachedSettingsKeyMax = LocalStoreCachedSettingsKeyMin.PrefixEnd()
+	// localStoreColdTieredObjectSuffix stores the names of the external objects
+	// that the store created when moving cold range data to external storage.
+	localStoreColdTieredObjectSuffix = []byte("ctob")
+	// LocalStoreColdTieredObjectKeyMin is the start of span of possible cold
+	// tiered object keys.
+	LocalStoreColdTieredObjectKeyMin = MakeStoreKey(localStoreColdTieredObjectSuffix, nil)
+	// LocalStoreColdTieredObjectKeyMax is the end of span of possible cold
+	// tiered object keys.
+	LocalStoreColdTieredObjectKeyMax = LocalStoreColdTieredObjectKeyMin.PrefixEnd()
 	// localStoreLastUpSuffix stores the last timestamp that a store's node
 	// acknowledged that it was still running. This value will be regularly
 	// refreshed on all stores for a running node; the intention of this value
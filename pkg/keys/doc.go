@@ -208,6 +208,7 @@ var _ = [...]interface{}{
 	//   4. Store local keys: These contain metadata about an individual store.
 	//   They are unreplicated and unaddressable. The typical example is the
 	//   store 'ident' record. They all share `localStorePrefix`.
+	StoreColdTieredObjectKey,         // "ctob"
 	DeprecatedStoreClusterVersionKey, // "cver"
 	StoreGossipKey,                   // "goss"
 	StoreHLCUpperBoundKey,            // "hlcu"
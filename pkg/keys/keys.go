@@ -166,6 +166,37 @@ func DecodeStoreCachedSettingsKey(key roachpb.Key) (settingKey roachpb.Key, err
 	return
 }
 
+// StoreColdTieredObjectKey returns a store-local key recording an external
+// object that the store created to move cold range data off local disk.
+func StoreColdTieredObjectKey(objName string) roachpb.Key {
+	return MakeStoreKey(localStoreColdTieredObjectSuffix, encoding.EncodeStringAscending(nil, objName))
+}
+
+// DecodeStoreColdTieredObjectKey returns the object name of a cold tiered
+// object key.
+func DecodeStoreColdTieredObjectKey(key roachpb.Key) (objName string, err error) {
+	var suffix, detail roachpb.RKey
+	suffix, detail, err = DecodeStoreKey(key)
+	if err != nil {
+		return "", err
+	}
+	if !suffix.Equal(localStoreColdTieredObjectSuffix) {
+		return "", errors.Errorf(
+			"key with suffix %q != %q",
+			suffix,
+			localStoreColdTieredObjectSuffix,
+		)
+	}
+	detail, objName, err = encoding.DecodeUnsafeStringAscendingDeepCopy(detail, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(detail) != 0 {
+		return "", errors.Errorf("invalid key has trailing garbage: %q", detail)
+	}
+	return objName, nil
+}
+
 // StoreLossOfQuorumRecoveryStatusKey is a key used for storing results of loss
 // of quorum recovery plan application.
 func StoreLossOfQuorumRecoveryStatusKey() roachpb.Key {
@@ -54,6 +54,14 @@ func TestStoreCachedSettingsKeyDecode(t *testing.T) {
 	require.True(t, settingKey.Equal(origSettingKey))
 }
 
+func TestStoreColdTieredObjectKeyDecode(t *testing.T) {
+	const origObjName = "cold/n1/s1/r5/obj.sst"
+	actualKey := StoreColdTieredObjectKey(origObjName)
+	objName, err := DecodeStoreColdTieredObjectKey(actualKey)
+	require.NoError(t, err)
+	require.Equal(t, origObjName, objName)
+}
+
 // TestLocalKeySorting is a sanity check to make sure that
 // the non-replicated part of a store sorts before the meta.
 func TestKeySorting(t *testing.T) {
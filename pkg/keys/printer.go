@@ -144,6 +144,7 @@ var constSubKeyDict = []struct {
 	{"/clusterVersion", localStoreClusterVersionSuffix},
 	{"/nodeTombstone", localStoreNodeTombstoneSuffix},
 	{"/cachedSettings", localStoreCachedSettingsSuffix},
+	{"/coldTieredObject", localStoreColdTieredObjectSuffix},
 	{"/lossOfQuorumRecovery/applied", localStoreUnsafeReplicaRecoverySuffix},
 	{"/lossOfQuorumRecovery/status", localStoreLossOfQuorumRecoveryStatusSuffix},
 	{"/lossOfQuorumRecovery/cleanup", localStoreLossOfQuorumRecoveryCleanupActionsSuffix},
@@ -165,6 +166,14 @@ func cachedSettingsKeyPrint(buf *redact.StringBuilder, key roachpb.Key) {
 	buf.Print(settingKey.String())
 }
 
+func coldTieredObjectKeyPrint(buf *redact.StringBuilder, key roachpb.Key) {
+	objName, err := DecodeStoreColdTieredObjectKey(key)
+	if err != nil {
+		buf.Printf("<invalid: %s>", err)
+	}
+	buf.Print(objName)
+}
+
 func localStoreKeyPrint(buf *redact.StringBuilder, _ []encoding.Direction, key roachpb.Key) {
 	for _, v := range constSubKeyDict {
 		if bytes.HasPrefix(key, v.key) {
@@ -179,6 +188,11 @@ func localStoreKeyPrint(buf *redact.StringBuilder, _ []encoding.Direction, key r
 				cachedSettingsKeyPrint(
 					buf, append(roachpb.Key(nil), append(LocalStorePrefix, key...)...),
 				)
+			} else if v.key.Equal(localStoreColdTieredObjectSuffix) {
+				buf.SafeRune('/')
+				coldTieredObjectKeyPrint(
+					buf, append(roachpb.Key(nil), append(LocalStorePrefix, key...)...),
+				)
 			} else if v.key.Equal(localStoreUnsafeReplicaRecoverySuffix) {
 				buf.SafeRune('/')
 				lossOfQuorumRecoveryEntryKeyPrint(
@@ -205,7 +219,8 @@ func localStoreKeyParse(input string) (remainder string, output roachpb.Key) {
 			switch {
 			case
 				s.key.Equal(localStoreNodeTombstoneSuffix),
-				s.key.Equal(localStoreCachedSettingsSuffix):
+				s.key.Equal(localStoreCachedSettingsSuffix),
+				s.key.Equal(localStoreColdTieredObjectSuffix):
 				panic(&ErrUglifyUnsupported{errors.Errorf("cannot parse local store key with suffix %s", s.key)})
 			case s.key.Equal(localStoreUnsafeReplicaRecoverySuffix):
 				recordIDString := input[len(localStoreUnsafeReplicaRecoverySuffix):]
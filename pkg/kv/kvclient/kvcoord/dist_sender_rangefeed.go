@@ -66,6 +66,7 @@ type rangeFeedConfig struct {
 	withFiltering         bool
 	withMetadata          bool
 	withMatchingOriginIDs []uint32
+	rowFilter             *kvpb.RangeFeedRowFilter
 	rangeObserver         RangeObserver
 	consumerID            int64
 
@@ -125,6 +126,15 @@ func WithMatchingOriginIDs(originIDs ...uint32) RangeFeedOption {
 	})
 }
 
+// WithRowFilter opts the rangefeed into server-side filtering of row events.
+// Servers may ignore the filter, so the caller must still filter the events it
+// receives.
+func WithRowFilter(filter *kvpb.RangeFeedRowFilter) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.rowFilter = filter
+	})
+}
+
 // WithRangeObserver is called when the rangefeed starts with a function that
 // can be used to iterate over all the ranges.
 func WithRangeObserver(observer RangeObserver) RangeFeedOption {
@@ -631,6 +641,7 @@ func makeRangeFeedRequest(
 	withDiff bool,
 	withFiltering bool,
 	withMatchingOriginIDs []uint32,
+	rowFilter *kvpb.RangeFeedRowFilter,
 	consumerID int64,
 ) kvpb.RangeFeedRequest {
 	admissionPri := admissionpb.BulkNormalPri
@@ -647,6 +658,7 @@ func makeRangeFeedRequest(
 		WithDiff:              withDiff,
 		WithFiltering:         withFiltering,
 		WithMatchingOriginIDs: withMatchingOriginIDs,
+		RowFilter:             rowFilter,
 		AdmissionHeader: kvpb.AdmissionHeader{
 			// NB: AdmissionHeader is used only at the start of the range feed
 			// stream since the initial catch-up scan is expensive.
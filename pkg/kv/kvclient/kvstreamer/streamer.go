@@ -1518,9 +1518,11 @@ func (w *workerCoordinator) performRequestAsync(
 			// Do admission control after we've finalized the memory accounting.
 			if br != nil && w.responseAdmissionQ != nil {
 				responseAdmission := admission.WorkInfo{
-					TenantID:   roachpb.SystemTenantID,
-					Priority:   admissionpb.WorkPriority(w.requestAdmissionHeader.Priority),
-					CreateTime: w.requestAdmissionHeader.CreateTime,
+					TenantID:            roachpb.SystemTenantID,
+					Priority:            admissionpb.WorkPriority(w.requestAdmissionHeader.Priority),
+					ResourceGroup:       w.requestAdmissionHeader.ResourceGroup,
+					ResourceGroupWeight: w.requestAdmissionHeader.ResourceGroupWeight,
+					CreateTime:          w.requestAdmissionHeader.CreateTime,
 				}
 				if _, err = w.responseAdmissionQ.Admit(ctx, responseAdmission); err != nil {
 					log.VEventf(ctx, 2, "dropping response: admission control: %v", err)
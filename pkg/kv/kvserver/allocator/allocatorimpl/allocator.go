@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/load"
@@ -138,6 +139,8 @@ const (
 	AllocatorConsiderRebalance
 	AllocatorRangeUnavailable
 	AllocatorFinalizeAtomicReplicationChange
+	AllocatorAddWitness
+	AllocatorRemoveWitness
 )
 
 // Add indicates an action adding a replica.
@@ -225,6 +228,8 @@ var allocatorActionNames = map[AllocatorAction]string{
 	AllocatorConsiderRebalance:               "consider rebalance",
 	AllocatorRangeUnavailable:                "range unavailable",
 	AllocatorFinalizeAtomicReplicationChange: "finalize conf change",
+	AllocatorAddWitness:                      "add witness",
+	AllocatorRemoveWitness:                   "remove witness",
 }
 
 func (a AllocatorAction) String() string {
@@ -254,10 +259,14 @@ func (a AllocatorAction) Priority() float64 {
 		return 10000
 	case AllocatorReplaceDecommissioningVoter:
 		return 5000
+	case AllocatorAddWitness:
+		return 1100
 	case AllocatorRemoveDeadVoter:
 		return 1000
 	case AllocatorRemoveDecommissioningVoter:
 		return 900
+	case AllocatorRemoveWitness:
+		return 850
 	case AllocatorRemoveVoter:
 		return 800
 	case AllocatorReplaceDeadNonVoter:
@@ -947,7 +956,29 @@ func (a *Allocator) ComputeAction(
 	}
 
 	return a.computeAction(ctx, storePool, conf, desc.Replicas().VoterDescriptors(),
-		desc.Replicas().NonVoterDescriptors())
+		desc.Replicas().NonVoterDescriptors(), desc.Replicas().WitnessDescriptors())
+}
+
+// GetNeededWitnesses calculates the number of witnesses a range should have
+// given its zone config and the number of voters (including witnesses) it
+// needs. Witnesses never make up a quorum of the voters, so the number of
+// witnesses is reduced when the number of voters is reduced below the zone
+// config's num_voters, e.g. because there aren't enough nodes in the cluster.
+// No witnesses are needed until the cluster version supports them.
+func GetNeededWitnesses(
+	ctx context.Context, st *cluster.Settings, zoneConfigWitnessCount int32, neededVoters int,
+) int {
+	if !st.Version.IsActive(ctx, clusterversion.V25_1_WitnessReplicas) {
+		return 0
+	}
+	need := int(zoneConfigWitnessCount)
+	if maxWitnesses := (neededVoters - 1) / 2; need > maxWitnesses {
+		need = maxWitnesses
+	}
+	if need < 0 {
+		need = 0 // Must be non-negative.
+	}
+	return need
 }
 
 func (a *Allocator) computeAction(
@@ -956,6 +987,7 @@ func (a *Allocator) computeAction(
 	conf *roachpb.SpanConfig,
 	voterReplicas []roachpb.ReplicaDescriptor,
 	nonVoterReplicas []roachpb.ReplicaDescriptor,
+	witnessReplicas []roachpb.ReplicaDescriptor,
 ) (action AllocatorAction, adjustedPriority float64) {
 	// NB: The ordering of the checks in this method is intentional. The order in
 	// which these actions are returned by this method determines the relative
@@ -969,15 +1001,22 @@ func (a *Allocator) computeAction(
 	// first handle operations that correspond to repairing/recovering the range.
 	// After that we handle rebalancing related actions, followed by removal
 	// actions.
+	//
+	// Witnesses are counted towards the quorum, but are otherwise handled
+	// separately from the (full) voters: the voter actions below only consider
+	// the voters that aren't witnesses.
 	haveVoters := len(voterReplicas)
+	haveWitnesses := len(witnessReplicas)
 	decommissioningVoters := storePool.DecommissioningReplicas(voterReplicas)
 	postDecommissionVoters := haveVoters - len(decommissioningVoters)
 	// Node count including dead nodes but excluding
 	// decommissioning/decommissioned nodes.
 	clusterNodes := storePool.ClusterNodeCount()
-	neededVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
-	desiredQuorum := computeQuorum(neededVoters)
-	quorum := computeQuorum(haveVoters)
+	neededWitnesses := GetNeededWitnesses(
+		ctx, a.st, conf.NumWitnesses, GetNeededVoters(conf.GetNumVoters(), clusterNodes))
+	neededVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes) - neededWitnesses
+	desiredQuorum := computeQuorum(neededVoters + neededWitnesses)
+	quorum := computeQuorum(haveVoters + haveWitnesses)
 
 	// TODO(aayush): When haveVoters < neededVoters but we don't have quorum to
 	// actually execute the addition of a new replica, we should be returning a
@@ -1001,15 +1040,16 @@ func (a *Allocator) computeAction(
 	// elsewhere (for a regular rebalance or for decommissioning).
 	const includeSuspectAndDrainingStores = true
 	liveVoters, deadVoters := storePool.LiveAndDeadReplicas(voterReplicas, includeSuspectAndDrainingStores)
+	liveWitnesses, deadWitnesses := storePool.LiveAndDeadReplicas(witnessReplicas, includeSuspectAndDrainingStores)
 
-	if len(liveVoters) < quorum {
+	if len(liveVoters)+len(liveWitnesses) < quorum {
 		// Do not take any replacement/removal action if we do not have a quorum of
 		// live voters. If we're correctly assessing the unavailable state of the
 		// range, we also won't be able to add replicas as we try above, but hope
 		// springs eternal.
 		action = AllocatorRangeUnavailable
-		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v don't meet quorum of %d",
-			liveVoters, quorum)
+		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v and witnesses %v don't meet quorum of %d",
+			liveVoters, liveWitnesses, quorum)
 		return action, action.Priority()
 	}
 
@@ -1032,6 +1072,20 @@ func (a *Allocator) computeAction(
 		return action, action.Priority()
 	}
 
+	// Witness addition / replacement. Dead and decommissioning witnesses are
+	// replaced by adding a new witness first; they're then removed below as the
+	// range is over-replicated.
+	decommissioningWitnesses := storePool.DecommissioningReplicas(witnessReplicas)
+	postDecommissionWitnesses := haveWitnesses - len(decommissioningWitnesses) - len(deadWitnesses)
+	if postDecommissionWitnesses < neededWitnesses {
+		action = AllocatorAddWitness
+		log.KvDistribution.VEventf(ctx, 3,
+			"%s - need=%d, have=%d, num_dead=%d, num_decommissioning=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, len(deadWitnesses), len(decommissioningWitnesses),
+			action.Priority())
+		return action, action.Priority()
+	}
+
 	// Voting replica removal actions follow.
 	// TODO(aayush): There's an additional case related to dead voters that we
 	// should handle above. If there are one or more dead replicas, have < need,
@@ -1067,11 +1121,20 @@ func (a *Allocator) computeAction(
 		return action, adjustedPriority
 	}
 
+	if haveWitnesses > neededWitnesses {
+		// Range has too many witnesses, or has a dead or decommissioning witness
+		// which has already been replaced.
+		action = AllocatorRemoveWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - need=%d, have=%d, priority=%.2f", action,
+			neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
 	// Non-voting replica actions follow.
 	//
 	// Non-voting replica addition / replacement.
 	haveNonVoters := len(nonVoterReplicas)
-	neededNonVoters := GetNeededNonVoters(haveVoters, int(conf.GetNumNonVoters()), clusterNodes)
+	neededNonVoters := GetNeededNonVoters(haveVoters+haveWitnesses, int(conf.GetNumNonVoters()), clusterNodes)
 	if haveNonVoters < neededNonVoters {
 		action = AllocatorAddNonVoter
 		log.KvDistribution.VEventf(ctx, 3, "%s - missing non-voter need=%d, have=%d, priority=%.2f",
@@ -1359,6 +1422,25 @@ func (a *Allocator) AllocateVoter(
 	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, replacing, replicaStatus, VoterTarget)
 }
 
+// AllocateWitness returns a suitable store for a new allocation of a witness.
+// Witnesses are voters, so they have to abide by the voter constraints and are
+// diversified against the existing voters, which are expected to include the
+// range's existing witnesses. Nodes already accommodating existing voters are
+// ruled out as targets.
+func (a *Allocator) AllocateWitness(
+	ctx context.Context,
+	storePool storepool.AllocatorStorePool,
+	conf *roachpb.SpanConfig,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	replicaStatus ReplicaStatus,
+) (roachpb.ReplicationTarget, string, error) {
+	if !a.st.Version.IsActive(ctx, clusterversion.V25_1_WitnessReplicas) {
+		return roachpb.ReplicationTarget{}, "", errors.Errorf(
+			"witness replicas unsupported in mixed-version cluster")
+	}
+	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, nil /* replacing */, replicaStatus, VoterTarget)
+}
+
 // AllocateNonVoter returns a suitable store for a new allocation of a
 // non-voting replica with the required attributes. Nodes already accommodating
 // _any_ existing replicas are ruled out as targets.
@@ -1660,6 +1742,23 @@ func (a Allocator) RemoveVoter(
 	)
 }
 
+// RemoveWitness returns a suitable witness to remove from the provided set of
+// candidates. Like AllocateWitness, the existing voters are expected to
+// include the range's witnesses.
+func (a Allocator) RemoveWitness(
+	ctx context.Context,
+	storePool storepool.AllocatorStorePool,
+	conf *roachpb.SpanConfig,
+	witnessCandidates []roachpb.ReplicaDescriptor,
+	existingVoters []roachpb.ReplicaDescriptor,
+	existingNonVoters []roachpb.ReplicaDescriptor,
+	options ScorerOptions,
+) (roachpb.ReplicationTarget, string, error) {
+	return a.RemoveVoter(
+		ctx, storePool, conf, witnessCandidates, existingVoters, existingNonVoters, options,
+	)
+}
+
 // RemoveNonVoter returns a suitable non-voting replica to remove from the
 // provided set. It first attempts to randomly select a target from the set of
 // stores that have greater than the average number of replicas. Failing that,
@@ -55,6 +55,13 @@ const (
 	// cluster setting defaultMaxDiskUtilizationThreshold.
 	defaultRebalanceToMaxDiskUtilizationThreshold = 0.925
 
+	// defaultRebalanceToMaxWriteBandwidthUtilizationThreshold is the default
+	// maximum fraction of a rebalance target's provisioned disk bandwidth that
+	// may be used by writes after receiving a replica. The value is used as the
+	// default in the cluster setting
+	// rebalanceToMaxWriteBandwidthUtilizationThreshold.
+	defaultRebalanceToMaxWriteBandwidthUtilizationThreshold = 0.8
+
 	// minRangeRebalanceThreshold is the number of replicas by which a store
 	// must deviate from the mean number of replicas to be considered overfull
 	// or underfull. This absolute bound exists to account for deployments
@@ -261,6 +268,20 @@ var rebalanceToMaxDiskUtilizationThreshold = settings.RegisterFloatSetting(
 	settings.FloatInRange(0, 0.99),
 )
 
+// rebalanceToMaxWriteBandwidthUtilizationThreshold: when rebalancing on write
+// bytes, a store will not be used as a rebalance target if its writes would
+// use more than this fraction of its provisioned disk bandwidth after
+// receiving the replica. Stores without a provisioned bandwidth are not
+// limited.
+var rebalanceToMaxWriteBandwidthUtilizationThreshold = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"kv.allocator.rebalance_to_max_write_bandwidth_utilization_threshold",
+	"maximum fraction of a store's provisioned disk bandwidth that may be used by "+
+		"writes after the store receives a replica when rebalancing on write bytes",
+	defaultRebalanceToMaxWriteBandwidthUtilizationThreshold,
+	settings.FloatInRange(0, 1),
+)
+
 // ScorerOptions defines the interface for the two heuristics that trigger
 // replica rebalancing: range count convergence and QPS convergence.
 type ScorerOptions interface {
@@ -668,19 +689,25 @@ func (o *LoadScorerOptions) removalMaximallyConvergesScore(
 type DiskCapacityOptions struct {
 	RebalanceToThreshold     float64
 	ShedAndBlockAllThreshold float64
+	// RebalanceToWriteBandwidthThreshold is the maximum fraction of a store's
+	// provisioned disk bandwidth that may be used by writes after the store
+	// receives a replica when rebalancing on write bytes.
+	RebalanceToWriteBandwidthThreshold float64
 }
 
 func makeDiskCapacityOptions(sv *settings.Values) DiskCapacityOptions {
 	return DiskCapacityOptions{
-		RebalanceToThreshold:     rebalanceToMaxDiskUtilizationThreshold.Get(sv),
-		ShedAndBlockAllThreshold: maxDiskUtilizationThreshold.Get(sv),
+		RebalanceToThreshold:               rebalanceToMaxDiskUtilizationThreshold.Get(sv),
+		ShedAndBlockAllThreshold:           maxDiskUtilizationThreshold.Get(sv),
+		RebalanceToWriteBandwidthThreshold: rebalanceToMaxWriteBandwidthUtilizationThreshold.Get(sv),
 	}
 }
 
 func defaultDiskCapacityOptions() DiskCapacityOptions {
 	return DiskCapacityOptions{
-		RebalanceToThreshold:     defaultRebalanceToMaxDiskUtilizationThreshold,
-		ShedAndBlockAllThreshold: defaultMaxDiskUtilizationThreshold,
+		RebalanceToThreshold:               defaultRebalanceToMaxDiskUtilizationThreshold,
+		ShedAndBlockAllThreshold:           defaultMaxDiskUtilizationThreshold,
+		RebalanceToWriteBandwidthThreshold: defaultRebalanceToMaxWriteBandwidthUtilizationThreshold,
 	}
 }
 
@@ -696,6 +723,19 @@ func (do DiskCapacityOptions) rebalanceToMaxCapacityCheck(store roachpb.StoreDes
 	return store.Capacity.FractionUsed() < do.RebalanceToThreshold
 }
 
+// rebalanceToWriteBandwidthCheck returns true if the store has enough
+// provisioned disk bandwidth to accept a replica with the given write bytes
+// per second. Stores without a provisioned bandwidth always pass the check.
+func (do DiskCapacityOptions) rebalanceToWriteBandwidthCheck(
+	store roachpb.StoreDescriptor, writeBytesPerSecond float64,
+) bool {
+	if store.Capacity.ProvisionedBandwidth <= 0 {
+		return true
+	}
+	return store.Capacity.WriteBytesPerSecond+writeBytesPerSecond <=
+		do.RebalanceToWriteBandwidthThreshold*float64(store.Capacity.ProvisionedBandwidth)
+}
+
 // candidate store for allocation. These are ordered by importance.
 type candidate struct {
 	store           roachpb.StoreDescriptor
@@ -1379,9 +1419,18 @@ func bestStoreToMinimizeLoadDelta(
 	dimension := options.LoadDims[0]
 
 	storeLoadMap := make(map[roachpb.StoreID]load.Load, len(candidates)+1)
+	// targets are the candidates which may receive the lease or replica. When
+	// balancing write bytes, candidates which would exceed their provisioned
+	// disk bandwidth after receiving the replica are excluded.
+	targets := make([]roachpb.StoreID, 0, len(candidates))
 	for _, store := range candidates {
 		if desc, ok := storeDescMap[store]; ok {
 			storeLoadMap[store] = desc.Capacity.Load()
+			if dimension == load.WriteBytes && !options.DiskOptions.rebalanceToWriteBandwidthCheck(
+				*desc, replLoadValue.Dim(load.WriteBytes)) {
+				continue
+			}
+			targets = append(targets, store)
 		}
 	}
 	desc, ok := storeDescMap[existing]
@@ -1399,7 +1448,7 @@ func bestStoreToMinimizeLoadDelta(
 	}
 	domainStoreList := storepool.MakeStoreList(storeDescs)
 
-	bestCandidate = getCandidateWithMinLoad(storeLoadMap, candidates, dimension)
+	bestCandidate = getCandidateWithMinLoad(storeLoadMap, targets, dimension)
 	if bestCandidate == 0 {
 		return 0, noBetterCandidate
 	}
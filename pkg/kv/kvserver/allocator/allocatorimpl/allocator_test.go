@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -6982,6 +6983,109 @@ func TestAllocatorComputeAction(t *testing.T) {
 	}
 }
 
+func TestAllocatorComputeActionWitnesses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	conf := roachpb.SpanConfig{NumReplicas: 3, NumVoters: 3, NumWitnesses: 1}
+	makeDesc := func(types ...roachpb.ReplicaType) roachpb.RangeDescriptor {
+		var desc roachpb.RangeDescriptor
+		for i, typ := range types {
+			desc.InternalReplicas = append(desc.InternalReplicas, roachpb.ReplicaDescriptor{
+				StoreID:   roachpb.StoreID(i + 1),
+				NodeID:    roachpb.NodeID(i + 1),
+				ReplicaID: roachpb.ReplicaID(i + 1),
+				Type:      typ,
+			})
+		}
+		return desc
+	}
+
+	testCases := []struct {
+		desc           roachpb.RangeDescriptor
+		expectedAction AllocatorAction
+	}{
+		// Needs two full voters, has one.
+		{
+			desc:           makeDesc(roachpb.VOTER_FULL, roachpb.WITNESS),
+			expectedAction: AllocatorAddVoter,
+		},
+		// Needs a witness, has none. The witness is added before the extra voter
+		// is removed.
+		{
+			desc:           makeDesc(roachpb.VOTER_FULL, roachpb.VOTER_FULL, roachpb.VOTER_FULL),
+			expectedAction: AllocatorAddWitness,
+		},
+		// Has the desired voters and witnesses.
+		{
+			desc:           makeDesc(roachpb.VOTER_FULL, roachpb.VOTER_FULL, roachpb.WITNESS),
+			expectedAction: AllocatorConsiderRebalance,
+		},
+		// Has an extra full voter.
+		{
+			desc: makeDesc(
+				roachpb.VOTER_FULL, roachpb.VOTER_FULL, roachpb.VOTER_FULL, roachpb.WITNESS),
+			expectedAction: AllocatorRemoveVoter,
+		},
+		// Has an extra witness.
+		{
+			desc: makeDesc(
+				roachpb.VOTER_FULL, roachpb.VOTER_FULL, roachpb.WITNESS, roachpb.WITNESS),
+			expectedAction: AllocatorRemoveWitness,
+		},
+	}
+
+	ctx := context.Background()
+	stopper, _, sp, a, _ := CreateTestAllocator(ctx, 10, false /* deterministic */)
+	defer stopper.Stop(ctx)
+	mockStorePool(sp, []roachpb.StoreID{1, 2, 3, 4, 5}, nil, nil, nil, nil, nil)
+
+	for i, tcase := range testCases {
+		action, _ := a.ComputeAction(ctx, sp, &conf, &tcase.desc)
+		if tcase.expectedAction != action {
+			t.Errorf("Test case %d expected action %q, got action %q",
+				i, allocatorActionNames[tcase.expectedAction], allocatorActionNames[action])
+		}
+	}
+}
+
+func TestAllocatorGetNeededWitnesses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	testCases := []struct {
+		zoneWitnessCount int32
+		neededVoters     int
+		expected         int
+	}{
+		{0, 3, 0},
+		{1, 1, 0},
+		{1, 2, 0},
+		{1, 3, 1},
+		{2, 3, 1},
+		{2, 5, 2},
+		{3, 5, 2},
+		{-1, 5, 0},
+	}
+
+	for _, tc := range testCases {
+		if e, a := tc.expected, GetNeededWitnesses(ctx, st, tc.zoneWitnessCount, tc.neededVoters); e != a {
+			t.Errorf(
+				"GetNeededWitnesses(zone.NumWitnesses=%d, neededVoters=%d) got %d; want %d",
+				tc.zoneWitnessCount, tc.neededVoters, a, e)
+		}
+	}
+
+	// No witnesses are needed until the cluster version supports them.
+	st = cluster.MakeTestingClusterSettingsWithVersions(
+		(clusterversion.V25_1_WitnessReplicas - 1).Version(),
+		clusterversion.MinSupported.Version(),
+		true, /* initializeVersion */
+	)
+	require.Equal(t, 0, GetNeededWitnesses(ctx, st, 2, 5))
+}
+
 func TestAllocatorComputeActionRemoveDead(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
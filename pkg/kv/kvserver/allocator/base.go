@@ -49,6 +49,23 @@ const (
 	// additional friction before taking these actions.
 	MinCPUDifferenceForTransfers = 2 * MinCPUThresholdDifference
 
+	// MinWriteBytesThresholdDifference is the minimum write bytes per second
+	// difference from the cluster mean that this system should care about. The
+	// system won't attempt to take action if a store's write bytes differ from
+	// the mean by less than this amount even if it is greater than the
+	// percentage threshold. This prevents range rebalances in clusters with
+	// little write traffic.
+	MinWriteBytesThresholdDifference = float64(1 << 20) // 1 MiB/s
+
+	// MinWriteBytesDifferenceForTransfers is the minimum write bytes per
+	// second difference that a store rebalancer would care about to reconcile
+	// (via replica rebalancing) between any two stores.
+	//
+	// NB: This is set to be two times the minimum threshold that a store needs
+	// to be above or below the mean to be considered overfull or underfull
+	// respectively, for the same reason as MinCPUDifferenceForTransfers.
+	MinWriteBytesDifferenceForTransfers = 2 * MinWriteBytesThresholdDifference
+
 	// defaultLoadBasedRebalancingInterval is how frequently to check the store-level
 	// balance of the cluster.
 	defaultLoadBasedRebalancingInterval = time.Minute
@@ -120,6 +137,18 @@ var CPURebalanceThreshold = settings.RegisterFloatSetting(
 	settings.WithPublic,
 )
 
+// WriteBytesRebalanceThreshold is the minimum ratio of a store's write bytes
+// per second to the mean write bytes per second at which that store is
+// considered overfull or underfull of write bandwidth usage.
+var WriteBytesRebalanceThreshold = settings.RegisterFloatSetting(
+	settings.SystemOnly,
+	"kv.allocator.store_write_bytes_rebalance_threshold",
+	"minimum fraction away from the mean a store's disk write bandwidth usage can be before it is considered overfull or underfull",
+	0.10,
+	settings.FloatWithMinimum(0.01),
+	settings.WithPublic,
+)
+
 // LoadBasedRebalanceInterval controls how frequently each store checks for
 // load-base lease/replica rebalancing opportunties.
 var LoadBasedRebalanceInterval = settings.RegisterDurationSettingWithExplicitUnit(
@@ -21,6 +21,9 @@ const (
 	Queries Dimension = iota
 	// CPU refers to the cpu time (ns) used in processing.
 	CPU
+	// WriteBytes refers to the bytes written to disk by replicas applying
+	// writes.
+	WriteBytes
 
 	nDimensionsTyped
 	nDimensions = int(nDimensionsTyped)
@@ -33,6 +36,8 @@ func (d Dimension) String() string {
 		return "queries-per-second"
 	case CPU:
 		return "cpu-per-second"
+	case WriteBytes:
+		return "write-bytes-per-second"
 	default:
 		panic(fmt.Sprintf("cannot name: unknown dimension with ordinal %d", d))
 	}
@@ -48,6 +53,8 @@ func (d Dimension) format(value float64) redact.SafeString {
 		return redact.SafeString(fmt.Sprintf("%.1f", value))
 	case CPU:
 		return humanizeutil.Duration(time.Duration(int64(value)))
+	case WriteBytes:
+		return humanizeutil.IBytes(int64(value)) + "/s"
 	default:
 		panic(fmt.Sprintf("cannot format value: unknown dimension with ordinal %d", d))
 	}
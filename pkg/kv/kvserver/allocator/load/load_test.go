@@ -12,5 +12,7 @@ import (
 )
 
 func TestVectorLoadString(t *testing.T) {
-	require.Equal(t, "(queries-per-second=1.0 cpu-per-second=1ms)", Vector{1, 1000000}.String())
+	require.Equal(t,
+		"(queries-per-second=1.0 cpu-per-second=1ms write-bytes-per-second=1.0 KiB/s)",
+		Vector{1, 1000000, 1024}.String())
 }
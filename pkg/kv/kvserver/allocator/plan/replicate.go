@@ -285,6 +285,13 @@ func (rp ReplicaPlanner) PlanOneChange(
 	case allocatorimpl.AllocatorRemoveNonVoter:
 		op, stats, err = rp.removeNonVoter(ctx, repl, desc, conf, voterReplicas, nonVoterReplicas)
 
+	// Add or remove witnesses. Dead and decommissioning witnesses are replaced by
+	// first adding a new witness and then removing the old one.
+	case allocatorimpl.AllocatorAddWitness:
+		op, err = rp.addWitness(ctx, repl, desc, conf, allocatorPrio)
+	case allocatorimpl.AllocatorRemoveWitness:
+		op, err = rp.removeWitness(ctx, repl, desc, conf)
+
 	// Remove decommissioning replicas.
 	//
 	// NB: these two paths will only be hit when the range is over-replicated and
@@ -496,6 +503,110 @@ func (rp ReplicaPlanner) addOrReplaceNonVoters(
 	return op, stats, nil
 }
 
+// addWitness adds a witness to `repl`s range.
+func (rp ReplicaPlanner) addWitness(
+	ctx context.Context,
+	repl AllocatorReplica,
+	desc *roachpb.RangeDescriptor,
+	conf *roachpb.SpanConfig,
+	allocatorPrio float64,
+) (op AllocationOp, _ error) {
+	// Witnesses are diversified against all the voters of the range, including
+	// the other witnesses. Dead and decommissioning witnesses are left out since
+	// they're about to be replaced.
+	existingVoters := desc.Replicas().VoterDescriptors()
+	for _, w := range desc.Replicas().WitnessDescriptors() {
+		if rp.storePool.IsStoreReadyForRoutineReplicaTransfer(ctx, w.StoreID) {
+			existingVoters = append(existingVoters, w)
+		}
+	}
+	existingNonVoters := desc.Replicas().NonVoterDescriptors()
+	newWitness, details, err := rp.allocator.AllocateWitness(
+		ctx, rp.storePool, conf, existingVoters, existingNonVoters, allocatorimpl.Alive)
+	if err != nil {
+		return nil, err
+	}
+	if replDesc, found := desc.GetReplicaDescriptor(newWitness.StoreID); found {
+		// Unlike voters, witnesses can't be created by promoting a non-voter
+		// since they don't keep the range's user data.
+		return nil, errors.Errorf("allocation target %s for a witness already has a replica: %s",
+			newWitness, replDesc)
+	}
+
+	log.KvDistribution.Infof(ctx, "adding witness %+v: %s",
+		newWitness, rangeRaftProgress(repl.RaftStatus(), existingVoters))
+	op = AllocationChangeReplicasOp{
+		LeaseholderStore:  repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, newWitness),
+		AllocatorPriority: allocatorPrio,
+		Reason:            kvserverpb.ReasonRangeUnderReplicated,
+		Details:           details,
+	}
+	return op, nil
+}
+
+// removeWitness removes a witness from `repl`s range, preferring dead and
+// decommissioning witnesses.
+func (rp ReplicaPlanner) removeWitness(
+	ctx context.Context, repl AllocatorReplica, desc *roachpb.RangeDescriptor, conf *roachpb.SpanConfig,
+) (op AllocationOp, _ error) {
+	witnesses := desc.Replicas().WitnessDescriptors()
+	if len(witnesses) == 0 {
+		return nil, errors.AssertionFailedf(
+			"range %s was identified as having too many witnesses, but no witnesses were found", repl)
+	}
+
+	const includeSuspectAndDrainingStores = true
+	_, deadWitnesses := rp.storePool.LiveAndDeadReplicas(witnesses, includeSuspectAndDrainingStores)
+	decommissioningWitnesses := rp.storePool.DecommissioningReplicas(witnesses)
+
+	var target roachpb.ReplicationTarget
+	var reason kvserverpb.RangeLogEventReason
+	var details string
+	switch {
+	case len(deadWitnesses) > 0:
+		target = roachpb.ReplicationTarget{
+			NodeID: deadWitnesses[0].NodeID, StoreID: deadWitnesses[0].StoreID,
+		}
+		reason = kvserverpb.ReasonStoreDead
+	case len(decommissioningWitnesses) > 0:
+		target = roachpb.ReplicationTarget{
+			NodeID: decommissioningWitnesses[0].NodeID, StoreID: decommissioningWitnesses[0].StoreID,
+		}
+		reason = kvserverpb.ReasonStoreDecommissioning
+	default:
+		existingVoters := append(desc.Replicas().VoterDescriptors(), witnesses...)
+		var err error
+		target, details, err = rp.allocator.RemoveWitness(
+			ctx,
+			rp.storePool,
+			conf,
+			witnesses,
+			existingVoters,
+			desc.Replicas().NonVoterDescriptors(),
+			rp.allocator.ScorerOptions(ctx),
+		)
+		if err != nil {
+			return nil, err
+		}
+		reason = kvserverpb.ReasonRangeOverReplicated
+	}
+
+	// NB: Witnesses never hold the lease, so there's no need to transfer it away
+	// from the target first.
+	log.KvDistribution.Infof(ctx, "removing witness %+v (%s)", target, reason)
+	op = AllocationChangeReplicasOp{
+		LeaseholderStore:  repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, target),
+		AllocatorPriority: 0.0, // unused
+		Reason:            reason,
+		Details:           details,
+	}
+	return op, nil
+}
+
 // findRemoveVoter takes a list of voting replicas and picks one to remove,
 // making sure to not remove a newly added voter or to violate the zone configs
 // in the process.
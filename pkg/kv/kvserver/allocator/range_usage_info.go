@@ -25,6 +25,7 @@ type RangeUsageInfo struct {
 	RequestCPUNanosPerSecond float64
 	RequestsPerSecond        float64
 	RaftCPUNanosPerSecond    float64
+	RaftWriteBytesPerSecond  float64
 	RequestLocality          *RangeRequestLocalityInfo
 }
 
@@ -41,6 +42,7 @@ func (r RangeUsageInfo) Load() load.Load {
 	dims := load.Vector{}
 	dims[load.Queries] = r.QueriesPerSecond
 	dims[load.CPU] = r.RequestCPUNanosPerSecond + r.RaftCPUNanosPerSecond
+	dims[load.WriteBytes] = r.RaftWriteBytesPerSecond
 	return dims
 }
 
@@ -58,6 +60,9 @@ func (r RangeUsageInfo) TransferImpact() load.Load {
 	// TODO(kvoli): Look to separate out leaseholder vs replica cpu usage in
 	// accounting to account for follower reads if able.
 	dims[load.CPU] = r.RequestCPUNanosPerSecond
+	// Every replica writes the same bytes to disk when applying raft commands,
+	// so transferring the lease doesn't move any write bytes between stores.
+	dims[load.WriteBytes] = 0
 	return dims
 }
 
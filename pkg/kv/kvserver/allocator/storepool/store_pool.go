@@ -504,6 +504,7 @@ func (sp *StorePool) UpdateLocalStoreAfterRebalance(
 		detail.Desc.Capacity.RangeCount++
 		detail.Desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
 		detail.Desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
+		detail.Desc.Capacity.WriteBytesPerSecond += rangeUsageInfo.RaftWriteBytesPerSecond
 		if detail.Desc.Capacity.CPUPerSecond >= 0 {
 			detail.Desc.Capacity.CPUPerSecond += rangeUsageInfo.RaftCPUNanosPerSecond
 		}
@@ -519,6 +520,11 @@ func (sp *StorePool) UpdateLocalStoreAfterRebalance(
 		} else {
 			detail.Desc.Capacity.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
 		}
+		if detail.Desc.Capacity.WriteBytesPerSecond <= rangeUsageInfo.RaftWriteBytesPerSecond {
+			detail.Desc.Capacity.WriteBytesPerSecond = 0
+		} else {
+			detail.Desc.Capacity.WriteBytesPerSecond -= rangeUsageInfo.RaftWriteBytesPerSecond
+		}
 		// When CPU attribution is unsupported, the store will set the
 		// CPUPerSecond of its store capacity to be -1.
 		if detail.Desc.Capacity.CPUPerSecond >= 0 {
@@ -528,6 +534,28 @@ func (sp *StorePool) UpdateLocalStoreAfterRebalance(
 				detail.Desc.Capacity.CPUPerSecond -= rangeUsageInfo.RaftCPUNanosPerSecond
 			}
 		}
+	case roachpb.ADD_WITNESS:
+		// Witnesses only persist the raft log, so they don't add to the logical
+		// bytes or the request load of the store.
+		detail.Desc.Capacity.RangeCount++
+		detail.Desc.Capacity.WriteBytesPerSecond += rangeUsageInfo.RaftWriteBytesPerSecond
+		if detail.Desc.Capacity.CPUPerSecond >= 0 {
+			detail.Desc.Capacity.CPUPerSecond += rangeUsageInfo.RaftCPUNanosPerSecond
+		}
+	case roachpb.REMOVE_WITNESS:
+		detail.Desc.Capacity.RangeCount--
+		if detail.Desc.Capacity.WriteBytesPerSecond <= rangeUsageInfo.RaftWriteBytesPerSecond {
+			detail.Desc.Capacity.WriteBytesPerSecond = 0
+		} else {
+			detail.Desc.Capacity.WriteBytesPerSecond -= rangeUsageInfo.RaftWriteBytesPerSecond
+		}
+		if detail.Desc.Capacity.CPUPerSecond >= 0 {
+			if detail.Desc.Capacity.CPUPerSecond <= rangeUsageInfo.RaftCPUNanosPerSecond {
+				detail.Desc.Capacity.CPUPerSecond = 0
+			} else {
+				detail.Desc.Capacity.CPUPerSecond -= rangeUsageInfo.RaftCPUNanosPerSecond
+			}
+		}
 	default:
 		return
 	}
@@ -564,6 +592,7 @@ func (sp *StorePool) UpdateLocalStoreAfterRelocate(
 		for _, target := range targets {
 			if toDetail := sp.GetStoreDetailLocked(target.StoreID); toDetail.Desc != nil {
 				toDetail.Desc.Capacity.RangeCount++
+				toDetail.Desc.Capacity.WriteBytesPerSecond += rangeUsageInfo.RaftWriteBytesPerSecond
 				if toDetail.Desc.Capacity.CPUPerSecond >= 0 {
 					toDetail.Desc.Capacity.CPUPerSecond += rangeUsageInfo.RaftCPUNanosPerSecond
 				}
@@ -574,6 +603,11 @@ func (sp *StorePool) UpdateLocalStoreAfterRelocate(
 		for _, old := range previous {
 			if toDetail := sp.GetStoreDetailLocked(old.StoreID); toDetail.Desc != nil {
 				toDetail.Desc.Capacity.RangeCount--
+				if toDetail.Desc.Capacity.WriteBytesPerSecond <= rangeUsageInfo.RaftWriteBytesPerSecond {
+					toDetail.Desc.Capacity.WriteBytesPerSecond = 0
+				} else {
+					toDetail.Desc.Capacity.WriteBytesPerSecond -= rangeUsageInfo.RaftWriteBytesPerSecond
+				}
 				// When CPU attribution is unsupported, the store will set the
 				// CPUPerSecond of its store capacity to be -1.
 				if toDetail.Desc.Capacity.CPUPerSecond < 0 {
@@ -953,6 +987,10 @@ type StoreList struct {
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond Stat
 
+	// CandidateWriteBytes tracks write-bytes-per-second stats for Stores that
+	// are eligible to be rebalance targets.
+	CandidateWriteBytes Stat
+
 	// CandidateIOOverloadScores tracks the IO overload stats for Stores that are
 	// eligible to be rebalance candidates.
 	CandidateIOOverloadScores Stat
@@ -973,6 +1011,7 @@ func MakeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.CandidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
 		sl.CandidateCPU.update(desc.Capacity.CPUPerSecond)
+		sl.CandidateWriteBytes.update(desc.Capacity.WriteBytesPerSecond)
 		score, _ := desc.Capacity.IOThreshold.Score()
 		sl.CandidateIOOverloadScores.update(score)
 		maxScore, _ := desc.Capacity.IOThresholdMax.Score()
@@ -1043,6 +1082,7 @@ func (sl StoreList) LoadMeans() load.Load {
 	dims := load.Vector{}
 	dims[load.Queries] = sl.CandidateQueriesPerSecond.Mean
 	dims[load.CPU] = sl.CandidateCPU.Mean
+	dims[load.WriteBytes] = sl.CandidateWriteBytes.Mean
 	return dims
 }
 
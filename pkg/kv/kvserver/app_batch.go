@@ -6,8 +6,10 @@
 package kvserver
 
 import (
+	"bytes"
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
@@ -17,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
 	"golang.org/x/time/rate"
 )
 
@@ -33,12 +36,16 @@ type appBatchStats struct {
 	numEntriesProcessedBytes   int64
 	numEmptyEntries            int
 	numAddSST, numAddSSTCopies int
+	// numMutationBytes is the number of bytes written, both via WriteBatch
+	// and AddSST.
+	numMutationBytes int64
 
 	// NB: update `merge` when adding a new field.
 }
 
 func (s *appBatchStats) merge(ss appBatchStats) {
 	s.numMutations += ss.numMutations
+	s.numMutationBytes += ss.numMutationBytes
 	s.numEntriesProcessed += ss.numEntriesProcessed
 	s.numEntriesProcessedBytes += ss.numEntriesProcessedBytes
 	ss.numEmptyEntries += ss.numEmptyEntries
@@ -138,17 +145,70 @@ func (b *appBatch) addWriteBatch(
 	} else {
 		b.numMutations += mutations
 	}
+	b.numMutationBytes += int64(len(wb.Data))
 	if err := batch.ApplyBatchRepr(wb.Data, false); err != nil {
 		return errors.Wrapf(err, "unable to apply WriteBatch")
 	}
 	return nil
 }
 
+// addWitnessWriteBatch is like addWriteBatch, but only adds the command's
+// writes to range-local keys. It's used by witnesses, which don't store the
+// range's user data.
+func (b *appBatch) addWitnessWriteBatch(
+	ctx context.Context, batch storage.Batch, cmd *replicatedCmd,
+) error {
+	wb := cmd.Cmd.WriteBatch
+	if wb == nil {
+		return nil
+	}
+	r, err := storage.NewBatchReader(wb.Data)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read header of committed WriteBatch")
+	}
+	for r.Next() {
+		// Engine keys start with the roachpb.Key, so local keys can be
+		// identified without decoding them.
+		if !bytes.HasPrefix(r.Key(), keys.LocalPrefix) {
+			continue
+		}
+		b.numMutations++
+		switch kind := r.KeyKind(); kind {
+		case pebble.InternalKeyKindDelete, pebble.InternalKeyKindSingleDelete:
+			ik := pebble.MakeInternalKey(r.Key(), 0 /* seqNum */, kind)
+			err = batch.PutInternalPointKey(&ik, nil)
+		case pebble.InternalKeyKindRangeDelete:
+			var end []byte
+			if end, err = r.EndKey(); err == nil {
+				err = batch.ClearRawEncodedRange(r.Key(), end)
+			}
+		case pebble.InternalKeyKindRangeKeySet, pebble.InternalKeyKindRangeKeyUnset,
+			pebble.InternalKeyKindRangeKeyDelete:
+			// MVCC range keys are only ever written to user keys.
+			err = errors.AssertionFailedf("unexpected range key entry on local key %x", r.Key())
+		default:
+			ik := pebble.MakeInternalKey(r.Key(), 0 /* seqNum */, kind)
+			err = batch.PutInternalPointKey(&ik, r.Value())
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to apply WriteBatch")
+		}
+	}
+	if err := r.Error(); err != nil {
+		return errors.Wrapf(err, "unable to apply WriteBatch")
+	}
+	b.numMutationBytes += int64(len(wb.Data))
+	return nil
+}
+
 type postAddEnv struct {
 	st          *cluster.Settings
 	eng         storage.Engine
 	sideloaded  logstore.SideloadStorage
 	bulkLimiter *rate.Limiter
+	// witness is set if the replica applying the command is a witness, in which
+	// case ingestions of user data are skipped.
+	witness bool
 }
 
 func (b *appBatch) runPostAddTriggers(
@@ -169,6 +229,12 @@ func (b *appBatch) runPostAddTriggers(
 	// NB: any command which has an AddSSTable is non-trivial and will be
 	// applied in its own batch so it's not possible that any other commands
 	// which precede this command can shadow writes from this SSTable.
+	if env.witness {
+		// Witnesses don't store the range's user data. Note that the sideloaded
+		// payload is still part of the raft log, and thus removed when the log is
+		// truncated.
+		return nil
+	}
 	if res.AddSSTable != nil {
 		copied := addSSTablePreApply(
 			ctx,
@@ -178,6 +244,7 @@ func (b *appBatch) runPostAddTriggers(
 			*res.AddSSTable,
 		)
 		b.numAddSST++
+		b.numMutationBytes += int64(len(res.AddSSTable.Data))
 		if copied {
 			b.numAddSSTCopies++
 		}
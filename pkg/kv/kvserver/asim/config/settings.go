@@ -8,26 +8,28 @@ package config
 import "time"
 
 const (
-	defaultTickInteval             = 500 * time.Millisecond
-	defaultMetricsInterval         = 10 * time.Second
-	defaultReplicaChangeBaseDelay  = 100 * time.Millisecond
-	defaultReplicaAddDelayFactor   = 16
-	defaultSplitQueueDelay         = 100 * time.Millisecond
-	defaultRangeSizeSplitThreshold = 512 * 1024 * 1024 // 512mb
-	defaultRangeRebalanceThreshold = 0.05
-	defaultPacerLoopInterval       = 10 * time.Minute
-	defaultPacerMinIterInterval    = 10 * time.Millisecond
-	defaultPacerMaxIterIterval     = 1 * time.Second
-	defaultStateExchangeInterval   = 10 * time.Second
-	defaultStateExchangeDelay      = 500 * time.Millisecond
-	defaultSplitQPSThreshold       = 2500
-	defaultSplitStatRetention      = 10 * time.Minute
-	defaultSeed                    = 42
-	defaultLBRebalancingMode       = 2 // Leases and replicas.
-	defaultLBRebalancingInterval   = time.Minute
-	defaultLBRebalanceQPSThreshold = 0.1
-	defaultLBMinRequiredQPSDiff    = 200
-	defaultLBRebalancingObjective  = 0 // QPS
+	defaultTickInteval                    = 500 * time.Millisecond
+	defaultMetricsInterval                = 10 * time.Second
+	defaultReplicaChangeBaseDelay         = 100 * time.Millisecond
+	defaultReplicaAddDelayFactor          = 16
+	defaultSplitQueueDelay                = 100 * time.Millisecond
+	defaultRangeSizeSplitThreshold        = 512 * 1024 * 1024 // 512mb
+	defaultRangeRebalanceThreshold        = 0.05
+	defaultPacerLoopInterval              = 10 * time.Minute
+	defaultPacerMinIterInterval           = 10 * time.Millisecond
+	defaultPacerMaxIterIterval            = 1 * time.Second
+	defaultStateExchangeInterval          = 10 * time.Second
+	defaultStateExchangeDelay             = 500 * time.Millisecond
+	defaultSplitQPSThreshold              = 2500
+	defaultSplitStatRetention             = 10 * time.Minute
+	defaultSeed                           = 42
+	defaultLBRebalancingMode              = 2 // Leases and replicas.
+	defaultLBRebalancingInterval          = time.Minute
+	defaultLBRebalanceQPSThreshold        = 0.1
+	defaultLBMinRequiredQPSDiff           = 200
+	defaultLBRebalancingObjective         = 0 // QPS
+	defaultLBRebalanceWriteBytesThreshold = 0.1
+	defaultLBMinRequiredWriteBytesDiff    = 2 << 20 // 2 MiB/s
 )
 
 var (
@@ -110,32 +112,42 @@ type SimulationSettings struct {
 	// rebalancer would care to reconcile (via lease or replica rebalancing) between
 	// any two stores.
 	LBMinRequiredQPSDiff float64
+	// LBRebalanceWriteBytesThreshold is the fraction above or below the mean
+	// store write bytes per second, that a store is considered overfull or
+	// underfull. It is only used when the rebalancing objective is write bytes.
+	LBRebalanceWriteBytesThreshold float64
+	// LBMinRequiredWriteBytesDiff is the minimum write bytes per second
+	// difference that the store rebalancer would care to reconcile between any
+	// two stores. It is only used when the rebalancing objective is write bytes.
+	LBMinRequiredWriteBytesDiff float64
 }
 
 // DefaultSimulationSettings returns a set of default settings for simulation.
 func DefaultSimulationSettings() *SimulationSettings {
 	return &SimulationSettings{
-		StartTime:               defaultStartTime,
-		TickInterval:            defaultTickInteval,
-		MetricsInterval:         defaultMetricsInterval,
-		Seed:                    defaultSeed,
-		ReplicaChangeBaseDelay:  defaultReplicaChangeBaseDelay,
-		ReplicaAddRate:          defaultReplicaAddDelayFactor,
-		SplitQueueDelay:         defaultSplitQueueDelay,
-		RangeSizeSplitThreshold: defaultRangeSizeSplitThreshold,
-		RangeRebalanceThreshold: defaultRangeRebalanceThreshold,
-		PacerLoopInterval:       defaultPacerLoopInterval,
-		PacerMinIterInterval:    defaultPacerMinIterInterval,
-		PacerMaxIterIterval:     defaultPacerMaxIterIterval,
-		StateExchangeInterval:   defaultStateExchangeInterval,
-		StateExchangeDelay:      defaultStateExchangeDelay,
-		SplitQPSThreshold:       defaultSplitQPSThreshold,
-		SplitStatRetention:      defaultSplitStatRetention,
-		LBRebalancingMode:       defaultLBRebalancingMode,
-		LBRebalancingObjective:  defaultLBRebalancingObjective,
-		LBRebalancingInterval:   defaultLBRebalancingInterval,
-		LBRebalanceQPSThreshold: defaultLBRebalanceQPSThreshold,
-		LBMinRequiredQPSDiff:    defaultLBMinRequiredQPSDiff,
+		StartTime:                      defaultStartTime,
+		TickInterval:                   defaultTickInteval,
+		MetricsInterval:                defaultMetricsInterval,
+		Seed:                           defaultSeed,
+		ReplicaChangeBaseDelay:         defaultReplicaChangeBaseDelay,
+		ReplicaAddRate:                 defaultReplicaAddDelayFactor,
+		SplitQueueDelay:                defaultSplitQueueDelay,
+		RangeSizeSplitThreshold:        defaultRangeSizeSplitThreshold,
+		RangeRebalanceThreshold:        defaultRangeRebalanceThreshold,
+		PacerLoopInterval:              defaultPacerLoopInterval,
+		PacerMinIterInterval:           defaultPacerMinIterInterval,
+		PacerMaxIterIterval:            defaultPacerMaxIterIterval,
+		StateExchangeInterval:          defaultStateExchangeInterval,
+		StateExchangeDelay:             defaultStateExchangeDelay,
+		SplitQPSThreshold:              defaultSplitQPSThreshold,
+		SplitStatRetention:             defaultSplitStatRetention,
+		LBRebalancingMode:              defaultLBRebalancingMode,
+		LBRebalancingObjective:         defaultLBRebalancingObjective,
+		LBRebalancingInterval:          defaultLBRebalancingInterval,
+		LBRebalanceQPSThreshold:        defaultLBRebalanceQPSThreshold,
+		LBMinRequiredQPSDiff:           defaultLBMinRequiredQPSDiff,
+		LBRebalanceWriteBytesThreshold: defaultLBRebalanceWriteBytesThreshold,
+		LBMinRequiredWriteBytesDiff:    defaultLBMinRequiredWriteBytesDiff,
 	}
 }
 
@@ -33,6 +33,7 @@ func MakeTS(metrics [][]StoreMetrics) map[string][][]float64 {
 	ret["replica_b_sent"] = make([][]float64, stores)
 	ret["range_splits"] = make([][]float64, stores)
 	ret["disk_fraction_used"] = make([][]float64, stores)
+	ret["write_bytes_per_second"] = make([][]float64, stores)
 
 	for _, sms := range metrics {
 		for i, sm := range sms {
@@ -49,6 +50,7 @@ func MakeTS(metrics [][]StoreMetrics) map[string][][]float64 {
 			ret["replica_b_sent"][i] = append(ret["replica_b_sent"][i], float64(sm.RebalanceSentBytes))
 			ret["range_splits"][i] = append(ret["range_splits"][i], float64(sm.RangeSplits))
 			ret["disk_fraction_used"][i] = append(ret["disk_fraction_used"][i], sm.DiskFractionUsed)
+			ret["write_bytes_per_second"][i] = append(ret["write_bytes_per_second"][i], float64(sm.WriteBytesPerSecond))
 		}
 	}
 	return ret
@@ -38,6 +38,9 @@ type StoreMetrics struct {
 	RebalanceRcvdBytes int64
 	RangeSplits        int64
 	DiskFractionUsed   float64
+	// WriteBytesPerSecond is the rate at which replicas on the store write
+	// bytes to disk, as reported in the store's capacity.
+	WriteBytesPerSecond int64
 }
 
 // the MetricsTracker to report new store metrics for a tick.
@@ -101,21 +104,22 @@ func (mt *Tracker) Tick(ctx context.Context, tick time.Time, s state.State) {
 		desc := store.Descriptor()
 
 		sm := StoreMetrics{
-			Tick:               tick,
-			StoreID:            int64(storeID),
-			QPS:                int64(desc.Capacity.QueriesPerSecond),
-			WriteKeys:          u.WriteKeys,
-			WriteBytes:         u.WriteBytes,
-			ReadKeys:           u.ReadKeys,
-			ReadBytes:          u.ReadBytes,
-			Replicas:           int64(desc.Capacity.RangeCount),
-			Leases:             int64(desc.Capacity.LeaseCount),
-			LeaseTransfers:     u.LeaseTransfers,
-			Rebalances:         u.Rebalances,
-			RebalanceSentBytes: u.RebalanceSentBytes,
-			RebalanceRcvdBytes: u.RebalanceRcvdBytes,
-			RangeSplits:        u.RangeSplits,
-			DiskFractionUsed:   desc.Capacity.FractionUsed(),
+			Tick:                tick,
+			StoreID:             int64(storeID),
+			QPS:                 int64(desc.Capacity.QueriesPerSecond),
+			WriteKeys:           u.WriteKeys,
+			WriteBytes:          u.WriteBytes,
+			ReadKeys:            u.ReadKeys,
+			ReadBytes:           u.ReadBytes,
+			Replicas:            int64(desc.Capacity.RangeCount),
+			Leases:              int64(desc.Capacity.LeaseCount),
+			LeaseTransfers:      u.LeaseTransfers,
+			Rebalances:          u.Rebalances,
+			RebalanceSentBytes:  u.RebalanceSentBytes,
+			RebalanceRcvdBytes:  u.RebalanceRcvdBytes,
+			RangeSplits:         u.RangeSplits,
+			DiskFractionUsed:    desc.Capacity.FractionUsed(),
+			WriteBytesPerSecond: int64(desc.Capacity.WriteBytesPerSecond),
 		}
 		sms = append(sms, sm)
 	}
@@ -0,0 +1,137 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package replay
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/history"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// StoreComparison compares the recorded and simulated replica and lease
+// counts of a store at the end of the replay.
+type StoreComparison struct {
+	// StoreID is the store's ID in the recorded cluster.
+	StoreID                             roachpb.StoreID
+	RecordedReplicas, SimulatedReplicas int64
+	RecordedLeases, SimulatedLeases     int64
+}
+
+// Comparison compares the replica and lease moves that happened in the
+// recorded cluster with those that happened in a simulation of it.
+type Comparison struct {
+	Stores []StoreComparison
+	// RecordedReplicaAdds is the number of replicas that the range log
+	// recorded as added during the replay.
+	RecordedReplicaAdds int64
+	// SimulatedRebalances is the number of replica rebalances that happened in
+	// the simulation.
+	SimulatedRebalances int64
+	// RecordedLeaseTransfers is the number of lease transfers recorded in the
+	// tsdump during the replay. It is only set if HasRecordedLeaseTransfers.
+	RecordedLeaseTransfers    int64
+	HasRecordedLeaseTransfers bool
+	// SimulatedLeaseTransfers is the number of lease transfers that happened
+	// in the simulation.
+	SimulatedLeaseTransfers int64
+}
+
+// Compare compares the given simulation of the replay with the recorded
+// history.
+//
+// The recorded replica and lease counts of each store at the end of the replay
+// are taken from the tsdump when it was loaded, and otherwise from the ranges
+// in the debug zip, which assumes that the debug zip was taken at the end of
+// the replay.
+func (r *Replay) Compare(sim history.History) Comparison {
+	var c Comparison
+	recordedReplicas, recordedLeases := r.recordedCountsAtEnd()
+	simByStore := map[int64]metrics.StoreMetrics{}
+	if n := len(sim.Recorded); n > 0 {
+		for _, sm := range sim.Recorded[n-1] {
+			simByStore[sm.StoreID] = sm
+			c.SimulatedRebalances += sm.Rebalances
+			c.SimulatedLeaseTransfers += sm.LeaseTransfers
+		}
+	}
+	for _, s := range r.h.Stores {
+		sm := simByStore[int64(r.storeIDs[s.StoreID])]
+		c.Stores = append(c.Stores, StoreComparison{
+			StoreID:           s.StoreID,
+			RecordedReplicas:  recordedReplicas[s.StoreID],
+			SimulatedReplicas: sm.Replicas,
+			RecordedLeases:    recordedLeases[s.StoreID],
+			SimulatedLeases:   sm.Leases,
+		})
+	}
+
+	for _, ev := range r.h.RangeLog {
+		if ev.Timestamp.Before(r.start) || !ev.Timestamp.Before(r.end) {
+			continue
+		}
+		switch ev.EventType {
+		case kvserverpb.RangeLogEventType_add_voter, kvserverpb.RangeLogEventType_add_non_voter:
+			c.RecordedReplicaAdds++
+		}
+	}
+	if byStore, ok := r.h.StoreSeries[MetricLeaseTransfers]; ok {
+		c.HasRecordedLeaseTransfers = true
+		for _, s := range byStore {
+			c.RecordedLeaseTransfers += int64(s.At(r.end) - s.At(r.start))
+		}
+	}
+	return c
+}
+
+// recordedCountsAtEnd returns the recorded number of replicas and leases of
+// each store at the end of the replay.
+func (r *Replay) recordedCountsAtEnd() (replicas, leases map[roachpb.StoreID]int64) {
+	replicas, leases = map[roachpb.StoreID]int64{}, map[roachpb.StoreID]int64{}
+	replicaSeries, haveReplicas := r.h.StoreSeries[MetricReplicas]
+	leaseSeries, haveLeases := r.h.StoreSeries[MetricLeases]
+	for storeID, s := range replicaSeries {
+		replicas[storeID] = int64(s.At(r.end))
+	}
+	for storeID, s := range leaseSeries {
+		leases[storeID] = int64(s.At(r.end))
+	}
+	for _, rng := range r.h.Ranges {
+		if !haveReplicas {
+			for _, repl := range rng.Desc.Replicas().Descriptors() {
+				replicas[repl.StoreID]++
+			}
+		}
+		if !haveLeases && rng.Leaseholder != 0 {
+			leases[rng.Leaseholder]++
+		}
+	}
+	return replicas, leases
+}
+
+func (c Comparison) String() string {
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 2, 1, 2, ' ', 0)
+	fmt.Fprintf(w, "store\treplicas (recorded)\treplicas (simulated)\tleases (recorded)\tleases (simulated)\n")
+	for _, s := range c.Stores {
+		fmt.Fprintf(w, "s%d\t%d\t%d\t%d\t%d\n", s.StoreID,
+			s.RecordedReplicas, s.SimulatedReplicas, s.RecordedLeases, s.SimulatedLeases)
+	}
+	_ = w.Flush()
+	fmt.Fprintf(&buf, "replica additions: recorded=%d simulated rebalances=%d\n",
+		c.RecordedReplicaAdds, c.SimulatedRebalances)
+	recordedTransfers := "unknown"
+	if c.HasRecordedLeaseTransfers {
+		recordedTransfers = fmt.Sprint(c.RecordedLeaseTransfers)
+	}
+	fmt.Fprintf(&buf, "lease transfers: recorded=%s simulated=%d\n",
+		recordedTransfers, c.SimulatedLeaseTransfers)
+	return buf.String()
+}
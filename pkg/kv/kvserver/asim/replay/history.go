@@ -0,0 +1,472 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/ts"
+	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The store-level time series that are read from a tsdump. The names omit the
+// "cr.store." prefix.
+const (
+	// MetricReplicas is the number of replicas on a store.
+	MetricReplicas = "replicas"
+	// MetricLeases is the number of leaseholders on a store.
+	MetricLeases = "replicas.leaseholders"
+	// MetricQPS is the queries per second served by a store's leaseholders.
+	MetricQPS = "rebalancing.queriespersecond"
+	// MetricWriteBytes is the bytes written per second by a store's
+	// leaseholders.
+	MetricWriteBytes = "rebalancing.writebytespersecond"
+	// MetricReadBytes is the bytes read per second by a store's leaseholders.
+	MetricReadBytes = "rebalancing.readbytespersecond"
+	// MetricLeaseTransfers is the cumulative number of successful lease
+	// transfers initiated by a store.
+	MetricLeaseTransfers = "leases.transfers.success"
+)
+
+const storeMetricPrefix = "cr.store."
+
+var replayedMetrics = map[string]struct{}{
+	MetricReplicas:       {},
+	MetricLeases:         {},
+	MetricQPS:            {},
+	MetricWriteBytes:     {},
+	MetricReadBytes:      {},
+	MetricLeaseTransfers: {},
+}
+
+// Store is a store of the recorded cluster.
+type Store struct {
+	NodeID   roachpb.NodeID
+	StoreID  roachpb.StoreID
+	Locality roachpb.Locality
+	// Capacity is the store's disk capacity in bytes, zero if unknown.
+	Capacity int64
+}
+
+// RangeLoad is the load of a range, as reported by its leaseholder.
+type RangeLoad struct {
+	ReadsPerSecond      float64
+	WritesPerSecond     float64
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+}
+
+// QPS returns the number of requests per second served by the range.
+func (rl RangeLoad) QPS() float64 {
+	return rl.ReadsPerSecond + rl.WritesPerSecond
+}
+
+// Range is a range of the recorded cluster, as of the time the debug zip was
+// taken.
+type Range struct {
+	Desc roachpb.RangeDescriptor
+	// Leaseholder is the store holding the range's lease, zero if unknown.
+	Leaseholder roachpb.StoreID
+	// LeaseHistory contains the range's most recent leases, oldest first.
+	LeaseHistory []roachpb.Lease
+	// Bytes is the range's logical size.
+	Bytes int64
+	Load  RangeLoad
+}
+
+// Sample is a single datapoint of a time series.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a time series, ordered by time.
+type Series []Sample
+
+// At returns the value of the series at the given time, which is the value of
+// the latest sample taken at or before that time. The first sample is used
+// for times before the start of the series.
+func (s Series) At(t time.Time) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	idx := sort.Search(len(s), func(i int) bool { return s[i].Time.After(t) })
+	if idx == 0 {
+		return s[0].Value
+	}
+	return s[idx-1].Value
+}
+
+// History is the recorded history of a real cluster. It is assembled from the
+// contents of a debug zip, which describe the cluster's stores and ranges at
+// the time the zip was taken along with the range log, and optionally a
+// tsdump, which describes how the store-level load and replica counts evolved
+// over time.
+type History struct {
+	Stores []Store
+	// Ranges are the ranges of the cluster, ordered by start key.
+	Ranges []Range
+	// SpanConfigs are the span configs of the cluster, ordered by start key.
+	SpanConfigs []roachpb.SpanConfigEntry
+	// RangeLog contains the range log events, ordered by time.
+	RangeLog []kvserverpb.RangeLogEvent
+	// StoreSeries contains the store-level time series read from a tsdump,
+	// keyed by the metric name (e.g. MetricQPS) and store ID.
+	StoreSeries map[string]map[roachpb.StoreID]Series
+}
+
+// The names of the files read from a debug zip, relative to its debug
+// directory.
+const (
+	nodesFile       = "nodes.json"
+	rangesFile      = "ranges.json"
+	rangeLogFile    = "rangelog.json"
+	spanConfigsFile = "system.span_configurations.txt"
+)
+
+// LoadDebugZip returns the history contained in an extracted debug zip. The
+// path is the zip's top-level "debug" directory.
+//
+// The store and range information is taken from nodes.json and the per-node
+// ranges.json files; the range log and span configs are read from
+// rangelog.json and system.span_configurations.txt, if present.
+func LoadDebugZip(dir string) (*History, error) {
+	h := &History{StoreSeries: map[string]map[roachpb.StoreID]Series{}}
+	if err := h.loadRanges(dir); err != nil {
+		return nil, err
+	}
+	if err := h.loadStores(dir); err != nil {
+		return nil, err
+	}
+	if err := h.loadRangeLog(dir); err != nil {
+		return nil, err
+	}
+	if err := h.loadSpanConfigs(dir); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func readJSONFile(path string, v interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return errors.Wrapf(err, "decoding %s", path)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// loadRanges reads the ranges.json file of every node. Every replica of a
+// range reports it, so for each range the report of the leaseholder is
+// preferred, falling back to the report with the newest descriptor.
+func (h *History) loadRanges(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "nodes", "*", rangesFile))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errors.Newf("no %s files found in %s", rangesFile, filepath.Join(dir, "nodes"))
+	}
+	byID := map[roachpb.RangeID]serverpb.RangeInfo{}
+	for _, path := range paths {
+		var infos []serverpb.RangeInfo
+		if err := readJSONFile(path, &infos); err != nil {
+			return err
+		}
+		for _, info := range infos {
+			desc := info.State.Desc
+			if desc == nil || !desc.IsInitialized() {
+				continue
+			}
+			prev, ok := byID[desc.RangeID]
+			if !ok || (!prev.IsLeaseholder &&
+				(info.IsLeaseholder || prev.State.Desc.Generation < desc.Generation)) {
+				byID[desc.RangeID] = info
+			}
+		}
+	}
+	for _, info := range byID {
+		r := Range{
+			Desc:         *info.State.Desc,
+			LeaseHistory: info.LeaseHistory,
+			Load: RangeLoad{
+				ReadsPerSecond:      info.Stats.ReadsPerSecond,
+				WritesPerSecond:     info.Stats.WritesPerSecond,
+				ReadBytesPerSecond:  info.Stats.ReadBytesPerSecond,
+				WriteBytesPerSecond: info.Stats.WriteBytesPerSecond,
+			},
+		}
+		if lease := info.State.Lease; lease != nil {
+			r.Leaseholder = lease.Replica.StoreID
+		}
+		if stats := info.State.Stats; stats != nil {
+			r.Bytes = stats.Total()
+		}
+		sort.Slice(r.LeaseHistory, func(i, j int) bool {
+			return r.LeaseHistory[i].Start.Less(r.LeaseHistory[j].Start)
+		})
+		h.Ranges = append(h.Ranges, r)
+	}
+	sort.Slice(h.Ranges, func(i, j int) bool {
+		return h.Ranges[i].Desc.StartKey.Less(h.Ranges[j].Desc.StartKey)
+	})
+	return nil
+}
+
+// loadStores reads the store descriptors from nodes.json. Redacted debug zips
+// don't contain them, in which case the stores are derived from the replicas
+// of the loaded ranges, without localities or capacities.
+func (h *History) loadStores(dir string) error {
+	stores := map[roachpb.StoreID]Store{}
+	if path := filepath.Join(dir, nodesFile); fileExists(path) {
+		var nodes struct {
+			Nodes []statuspb.NodeStatus `json:"nodes"`
+		}
+		if err := readJSONFile(path, &nodes); err != nil {
+			return err
+		}
+		for _, n := range nodes.Nodes {
+			for _, ss := range n.StoreStatuses {
+				stores[ss.Desc.StoreID] = Store{
+					NodeID:   ss.Desc.Node.NodeID,
+					StoreID:  ss.Desc.StoreID,
+					Locality: ss.Desc.Node.Locality,
+					Capacity: ss.Desc.Capacity.Capacity,
+				}
+			}
+		}
+	}
+	for _, r := range h.Ranges {
+		for _, repl := range r.Desc.Replicas().Descriptors() {
+			if _, ok := stores[repl.StoreID]; !ok {
+				stores[repl.StoreID] = Store{NodeID: repl.NodeID, StoreID: repl.StoreID}
+			}
+		}
+	}
+	for _, s := range stores {
+		h.Stores = append(h.Stores, s)
+	}
+	sort.Slice(h.Stores, func(i, j int) bool {
+		if h.Stores[i].NodeID != h.Stores[j].NodeID {
+			return h.Stores[i].NodeID < h.Stores[j].NodeID
+		}
+		return h.Stores[i].StoreID < h.Stores[j].StoreID
+	})
+	return nil
+}
+
+func (h *History) loadRangeLog(dir string) error {
+	path := filepath.Join(dir, rangeLogFile)
+	if !fileExists(path) {
+		return nil
+	}
+	var resp serverpb.RangeLogResponse
+	if err := readJSONFile(path, &resp); err != nil {
+		return err
+	}
+	for _, ev := range resp.Events {
+		h.RangeLog = append(h.RangeLog, ev.Event)
+	}
+	sort.SliceStable(h.RangeLog, func(i, j int) bool {
+		return h.RangeLog[i].Timestamp.Before(h.RangeLog[j].Timestamp)
+	})
+	return nil
+}
+
+// loadSpanConfigs reads the system.span_configurations table dump, whose rows
+// contain the hex encoded start key, end key and span config.
+func (h *History) loadSpanConfigs(dir string) error {
+	path := filepath.Join(dir, spanConfigsFile)
+	if !fileExists(path) {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for first := true; sc.Scan(); first = false {
+		if first {
+			// Skip the header.
+			continue
+		}
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) < 3 {
+			return errors.Newf("%s: unexpected row %q", path, sc.Text())
+		}
+		var entry roachpb.SpanConfigEntry
+		var raw [3][]byte
+		for i := range raw {
+			if raw[i], err = decodeBytes(fields[i]); err != nil {
+				return errors.Wrapf(err, "%s: decoding row %q", path, sc.Text())
+			}
+		}
+		entry.Target = roachpb.SpanConfigTarget{
+			Union: &roachpb.SpanConfigTarget_Span{Span: &roachpb.Span{Key: raw[0], EndKey: raw[1]}},
+		}
+		if err := protoutil.Unmarshal(raw[2], &entry.Config); err != nil {
+			return errors.Wrapf(err, "%s: decoding span config", path)
+		}
+		h.SpanConfigs = append(h.SpanConfigs, entry)
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	sort.Slice(h.SpanConfigs, func(i, j int) bool {
+		return h.SpanConfigs[i].Target.GetSpan().Key.Compare(h.SpanConfigs[j].Target.GetSpan().Key) < 0
+	})
+	return nil
+}
+
+// decodeBytes decodes a BYTES column of a table dump, which is formatted as
+// "\x" followed by the hex encoded value.
+func decodeBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, `\x`))
+}
+
+// spanConfigFor returns the span config that applies to the given key, if
+// any.
+func (h *History) spanConfigFor(key roachpb.RKey) (roachpb.SpanConfig, bool) {
+	idx := sort.Search(len(h.SpanConfigs), func(i int) bool {
+		return key.AsRawKey().Compare(h.SpanConfigs[i].Target.GetSpan().Key) < 0
+	})
+	if idx == 0 {
+		return roachpb.SpanConfig{}, false
+	}
+	entry := h.SpanConfigs[idx-1]
+	if !entry.Target.GetSpan().ContainsKey(key.AsRawKey()) {
+		return roachpb.SpanConfig{}, false
+	}
+	return entry.Config, true
+}
+
+// ReadTSDump adds the store-level time series contained in a raw tsdump, as
+// produced by `cockroach debug tsdump --format=raw`, to the history. Only the
+// series listed above are retained.
+func (h *History) ReadTSDump(r io.Reader) error {
+	dec := gob.NewDecoder(bufio.NewReader(r))
+	gob.Register(&roachpb.KeyValue{})
+	for {
+		var kv roachpb.KeyValue
+		if err := dec.Decode(&kv); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return errors.Wrap(err, "decoding tsdump")
+		}
+		if !bytes.Contains(kv.Key, []byte(storeMetricPrefix)) {
+			// Cheaply skip the node-level series.
+			continue
+		}
+		if err := (ts.DefaultDumper{Send: h.addSeries}).Dump(&kv); err != nil {
+			return errors.Wrap(err, "decoding tsdump")
+		}
+	}
+	for _, byStore := range h.StoreSeries {
+		for _, s := range byStore {
+			sort.Slice(s, func(i, j int) bool { return s[i].Time.Before(s[j].Time) })
+		}
+	}
+	return nil
+}
+
+func (h *History) addSeries(data *tspb.TimeSeriesData) error {
+	name, ok := strings.CutPrefix(data.Name, storeMetricPrefix)
+	if !ok {
+		return nil
+	}
+	if _, ok := replayedMetrics[name]; !ok {
+		return nil
+	}
+	storeID, err := strconv.Atoi(data.Source)
+	if err != nil {
+		// Series of secondary tenants have composite sources; they aren't
+		// interesting here.
+		return nil //nolint:returnerrcheck
+	}
+	if h.StoreSeries == nil {
+		h.StoreSeries = map[string]map[roachpb.StoreID]Series{}
+	}
+	byStore, ok := h.StoreSeries[name]
+	if !ok {
+		byStore = map[roachpb.StoreID]Series{}
+		h.StoreSeries[name] = byStore
+	}
+	for _, dp := range data.Datapoints {
+		byStore[roachpb.StoreID(storeID)] = append(byStore[roachpb.StoreID(storeID)], Sample{
+			Time:  time.Unix(0, dp.TimestampNanos).UTC(),
+			Value: dp.Value,
+		})
+	}
+	return nil
+}
+
+// ClusterSeries returns the sum of the given metric's series over all stores
+// at the given time, and whether the series was recorded at all.
+func (h *History) ClusterSeries(name string, t time.Time) (float64, bool) {
+	byStore, ok := h.StoreSeries[name]
+	if !ok || len(byStore) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, s := range byStore {
+		sum += s.At(t)
+	}
+	return sum, true
+}
+
+// Interval returns the time interval covered by the recorded time series and
+// range log.
+func (h *History) Interval() (start, end time.Time) {
+	observe := func(t time.Time) {
+		if start.IsZero() || t.Before(start) {
+			start = t
+		}
+		if t.After(end) {
+			end = t
+		}
+	}
+	for _, byStore := range h.StoreSeries {
+		for _, s := range byStore {
+			if len(s) > 0 {
+				observe(s[0].Time)
+				observe(s[len(s)-1].Time)
+			}
+		}
+	}
+	if n := len(h.RangeLog); n > 0 {
+		observe(h.RangeLog[0].Timestamp)
+		observe(h.RangeLog[n-1].Timestamp)
+	}
+	return start, end
+}
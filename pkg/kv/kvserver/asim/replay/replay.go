@@ -0,0 +1,444 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package replay
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// keysPerRange is the size of the simulated keyspace assigned to each
+// recorded range. The simulator's keys are integers, so the recorded ranges
+// are laid out in key order, each covering keysPerRange keys.
+const keysPerRange = 1000
+
+// defaultStoreCapacity is the capacity of stores whose capacity wasn't
+// recorded, matching the predefined single region cluster configurations.
+const defaultStoreCapacity = 1024 << 30 // 1 TiB
+
+// Replay replays the recorded history of a cluster in the simulator, starting
+// from the cluster's state at the start of the replayed interval.
+//
+// The initial state is reconstructed from the ranges in the debug zip by
+// undoing the replica changes that the range log recorded after the start of
+// the interval, and by picking the leases in effect at the start from the
+// ranges' lease histories. Splits and merges aren't undone, so the simulated
+// cluster starts out with the range boundaries as of the time the debug zip
+// was taken.
+//
+// The per-range load is the load reported by each range's leaseholder when
+// the debug zip was taken. When a tsdump was loaded, the load is scaled over
+// time so that the cluster-wide load tracks the recorded store-level QPS and
+// read/write bandwidth.
+type Replay struct {
+	h          *History
+	start, end time.Time
+	// storeIDs maps recorded store IDs to simulated store IDs.
+	storeIDs map[roachpb.StoreID]state.StoreID
+	// ranges are the replayed ranges in key order, along with their state at
+	// the start of the replay.
+	ranges []replayedRange
+}
+
+type replayedRange struct {
+	rangeID     roachpb.RangeID
+	startKey    state.Key
+	voters      []roachpb.StoreID
+	nonVoters   []roachpb.StoreID
+	leaseholder roachpb.StoreID
+	config      roachpb.SpanConfig
+	bytes       int64
+	load        RangeLoad
+}
+
+// NewReplay returns a Replay of the given history over the interval [start,
+// end). If either is zero, the interval covered by the history is used
+// instead.
+func NewReplay(h *History, start, end time.Time) *Replay {
+	hStart, hEnd := h.Interval()
+	if start.IsZero() {
+		start = hStart
+	}
+	if end.IsZero() {
+		end = hEnd
+	}
+	r := &Replay{
+		h:        h,
+		start:    start,
+		end:      end,
+		storeIDs: map[roachpb.StoreID]state.StoreID{},
+	}
+	// Stores are added to the simulated cluster in the order of the history,
+	// and are assigned IDs sequentially.
+	for i, s := range h.Stores {
+		r.storeIDs[s.StoreID] = state.StoreID(i + 1)
+	}
+	r.ranges = r.rewind()
+	return r
+}
+
+// Duration returns the duration of the replayed interval.
+func (r *Replay) Duration() time.Duration {
+	return r.end.Sub(r.start)
+}
+
+// Start returns the start of the replayed interval.
+func (r *Replay) Start() time.Time {
+	return r.start
+}
+
+// StoreID returns the simulated store ID of the given recorded store.
+func (r *Replay) StoreID(storeID roachpb.StoreID) (state.StoreID, bool) {
+	id, ok := r.storeIDs[storeID]
+	return id, ok
+}
+
+// rewind computes the state of each recorded range at the start of the replay.
+func (r *Replay) rewind() []replayedRange {
+	byID := map[roachpb.RangeID]*replayedRange{}
+	ranges := make([]replayedRange, len(r.h.Ranges))
+	for i, rng := range r.h.Ranges {
+		rr := replayedRange{
+			rangeID:     rng.Desc.RangeID,
+			startKey:    state.MinKey + state.Key(i*keysPerRange),
+			leaseholder: rng.Leaseholder,
+			bytes:       rng.Bytes,
+			load:        rng.Load,
+		}
+		for _, repl := range rng.Desc.Replicas().Descriptors() {
+			if repl.IsVoterNewConfig() {
+				rr.voters = append(rr.voters, repl.StoreID)
+			} else if repl.Type == roachpb.NON_VOTER {
+				rr.nonVoters = append(rr.nonVoters, repl.StoreID)
+			}
+		}
+		conf, ok := r.h.spanConfigFor(rng.Desc.StartKey)
+		if !ok {
+			// Without a recorded span config, assume that the range's
+			// replication factor was satisfied.
+			conf = state.DefaultSpanConfig()
+			conf.NumVoters = int32(len(rr.voters))
+			conf.NumReplicas = int32(len(rr.voters) + len(rr.nonVoters))
+		}
+		rr.config = conf
+		ranges[i] = rr
+		byID[rr.rangeID] = &ranges[i]
+	}
+
+	// Undo the replica changes recorded after the start of the replay, newest
+	// first.
+	for i := len(r.h.RangeLog) - 1; i >= 0; i-- {
+		ev := r.h.RangeLog[i]
+		if ev.Timestamp.Before(r.start) {
+			break
+		}
+		rr, ok := byID[ev.RangeID]
+		if !ok || ev.Info == nil {
+			continue
+		}
+		voters, nonVoters := rr.voters, rr.nonVoters
+		switch ev.EventType {
+		case kvserverpb.RangeLogEventType_add_voter:
+			voters = removeStore(voters, ev.Info.AddedReplica.StoreID)
+		case kvserverpb.RangeLogEventType_add_non_voter:
+			nonVoters = removeStore(nonVoters, ev.Info.AddedReplica.StoreID)
+		case kvserverpb.RangeLogEventType_remove_voter:
+			voters = addStore(voters, ev.Info.RemovedReplica.StoreID)
+		case kvserverpb.RangeLogEventType_remove_non_voter:
+			nonVoters = addStore(nonVoters, ev.Info.RemovedReplica.StoreID)
+		default:
+			continue
+		}
+		if len(voters) == 0 {
+			// The range log is incomplete; keep the replicas as they are.
+			continue
+		}
+		rr.voters, rr.nonVoters = voters, nonVoters
+	}
+
+	for i := range ranges {
+		rr := &ranges[i]
+		rng := r.h.Ranges[i]
+		// Use the lease that was in effect at the start of the replay, if it is
+		// still in the range's lease history.
+		for j := len(rng.LeaseHistory) - 1; j >= 0; j-- {
+			if lease := rng.LeaseHistory[j]; lease.Start.ToTimestamp().GoTime().Before(r.start) {
+				rr.leaseholder = lease.Replica.StoreID
+				break
+			}
+		}
+		if !containsStore(rr.voters, rr.leaseholder) {
+			rr.leaseholder = rr.voters[0]
+		}
+	}
+	return ranges
+}
+
+func containsStore(stores []roachpb.StoreID, storeID roachpb.StoreID) bool {
+	for _, s := range stores {
+		if s == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeStore(stores []roachpb.StoreID, storeID roachpb.StoreID) []roachpb.StoreID {
+	ret := make([]roachpb.StoreID, 0, len(stores))
+	for _, s := range stores {
+		if s != storeID {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func addStore(stores []roachpb.StoreID, storeID roachpb.StoreID) []roachpb.StoreID {
+	if containsStore(stores, storeID) {
+		return stores
+	}
+	return append(append([]roachpb.StoreID(nil), stores...), storeID)
+}
+
+// Cluster returns a generator of the recorded cluster's nodes and stores.
+func (r *Replay) Cluster() Cluster {
+	return Cluster{r: r}
+}
+
+// Ranges returns a generator of the recorded cluster's ranges, as of the
+// start of the replay.
+func (r *Replay) Ranges() Ranges {
+	return Ranges{r: r}
+}
+
+// Load returns a generator of the recorded cluster's load.
+func (r *Replay) Load() Load {
+	return Load{r: r}
+}
+
+// Cluster implements the gen.ClusterGen interface.
+type Cluster struct {
+	r *Replay
+}
+
+func (c Cluster) String() string {
+	return fmt.Sprintf("replayed cluster with stores=%d", len(c.r.h.Stores))
+}
+
+// Generate returns a new simulator state containing the recorded nodes and
+// stores, with their localities. Stores with an unknown capacity are assigned
+// the default capacity.
+func (c Cluster) Generate(seed int64, settings *config.SimulationSettings) state.State {
+	s := state.LoadClusterInfo(state.ClusterInfo{}, settings)
+	var node state.Node
+	var lastNodeID roachpb.NodeID
+	for _, rs := range c.r.h.Stores {
+		if node == nil || rs.NodeID != lastNodeID {
+			node = s.AddNode()
+			s.SetNodeLocality(node.NodeID(), rs.Locality)
+			lastNodeID = rs.NodeID
+		}
+		store, ok := s.AddStore(node.NodeID())
+		if !ok {
+			panic(fmt.Sprintf("unable to add store s%d", rs.StoreID))
+		}
+		if want := c.r.storeIDs[rs.StoreID]; store.StoreID() != want {
+			panic(fmt.Sprintf("replayed store s%d was assigned ID %d instead of %d",
+				rs.StoreID, store.StoreID(), want))
+		}
+		capacity := rs.Capacity
+		if capacity <= 0 {
+			capacity = defaultStoreCapacity
+		}
+		s.SetStoreCapacity(store.StoreID(), capacity)
+	}
+	return s
+}
+
+// Regions returns the regions and zones of the recorded cluster, as given by
+// the "region" and "zone" tiers of the stores' localities.
+func (c Cluster) Regions() []state.Region {
+	var regions []state.Region
+	regionIdx := map[string]int{}
+	zoneIdx := map[[2]string]int{}
+	seenNodes := map[roachpb.NodeID]struct{}{}
+	for _, rs := range c.r.h.Stores {
+		var region, zone string
+		for _, tier := range rs.Locality.Tiers {
+			switch tier.Key {
+			case "region":
+				region = tier.Value
+			case "zone":
+				zone = tier.Value
+			}
+		}
+		ri, ok := regionIdx[region]
+		if !ok {
+			ri = len(regions)
+			regionIdx[region] = ri
+			regions = append(regions, state.Region{Name: region})
+		}
+		zi, ok := zoneIdx[[2]string{region, zone}]
+		if !ok {
+			zi = len(regions[ri].Zones)
+			zoneIdx[[2]string{region, zone}] = zi
+			regions[ri].Zones = append(regions[ri].Zones, state.Zone{Name: zone, StoresPerNode: 1})
+		}
+		if _, ok := seenNodes[rs.NodeID]; !ok {
+			seenNodes[rs.NodeID] = struct{}{}
+			regions[ri].Zones[zi].NodeCount++
+		}
+	}
+	return regions
+}
+
+// Ranges implements the gen.RangeGen interface.
+type Ranges struct {
+	r *Replay
+}
+
+func (rg Ranges) String() string {
+	return fmt.Sprintf("replayed ranges with ranges=%d", len(rg.r.ranges))
+}
+
+// Generate returns an updated simulator state, where the cluster is loaded
+// with the recorded ranges, their replicas, leaseholders and span configs, as
+// of the start of the replay.
+func (rg Ranges) Generate(
+	seed int64, settings *config.SimulationSettings, s state.State,
+) state.State {
+	infos := make(state.RangesInfo, 0, len(rg.r.ranges))
+	for i := range rg.r.ranges {
+		rr := &rg.r.ranges[i]
+		voters := make([]state.StoreID, len(rr.voters))
+		for j, storeID := range rr.voters {
+			voters[j] = rg.r.storeIDs[storeID]
+		}
+		nonVoters := make([]state.StoreID, len(rr.nonVoters))
+		for j, storeID := range rr.nonVoters {
+			nonVoters[j] = rg.r.storeIDs[storeID]
+		}
+		conf := rr.config
+		info := state.RangeInfoWithReplicas(
+			rr.startKey, voters, nonVoters, rg.r.storeIDs[rr.leaseholder], &conf)
+		info.Size = rr.bytes
+		infos = append(infos, info)
+	}
+	state.LoadRangeInfo(s, infos...)
+	return s
+}
+
+// Load implements the gen.LoadGen interface.
+type Load struct {
+	r *Replay
+}
+
+func (l Load) String() string {
+	return fmt.Sprintf("replayed load from %s to %s", l.r.start, l.r.end)
+}
+
+// Generate returns a workload generator which replays the recorded load.
+func (l Load) Generate(seed int64, settings *config.SimulationSettings) []workload.Generator {
+	g := &generator{
+		r:        l.r,
+		rand:     rand.New(rand.NewSource(seed)),
+		simStart: settings.StartTime,
+		lastRun:  settings.StartTime,
+		carry:    make([]carry, len(l.r.ranges)),
+	}
+	for _, rr := range l.r.ranges {
+		g.base.ReadsPerSecond += rr.load.ReadsPerSecond
+		g.base.WritesPerSecond += rr.load.WritesPerSecond
+		g.base.ReadBytesPerSecond += rr.load.ReadBytesPerSecond
+		g.base.WriteBytesPerSecond += rr.load.WriteBytesPerSecond
+	}
+	return []workload.Generator{g}
+}
+
+// carry accumulates the load of a range that hasn't been emitted yet, so that
+// ranges with a low rate still see load over time.
+type carry struct {
+	reads, writes         float64
+	readBytes, writeBytes float64
+}
+
+// generator implements the workload.Generator interface. On every tick it
+// generates the load of every range since the last tick, at a random key
+// within the range.
+type generator struct {
+	r        *Replay
+	rand     *rand.Rand
+	simStart time.Time
+	lastRun  time.Time
+	// base is the cluster-wide load at the time the debug zip was taken.
+	base  RangeLoad
+	carry []carry
+}
+
+// scale returns the factor by which the ranges' load is scaled at the given
+// recorded time, so that the cluster-wide load tracks the recorded load.
+func (g *generator) scale(t time.Time, metric string, base float64) float64 {
+	if base <= 0 {
+		return 1
+	}
+	recorded, ok := g.r.h.ClusterSeries(metric, t)
+	if !ok {
+		return 1
+	}
+	return recorded / base
+}
+
+// Tick implements the workload.Generator interface.
+func (g *generator) Tick(maxTime time.Time) workload.LoadBatch {
+	elapsed := maxTime.Sub(g.lastRun).Seconds()
+	if elapsed <= 0 {
+		return workload.LoadBatch{}
+	}
+	g.lastRun = maxTime
+	recorded := g.r.start.Add(maxTime.Sub(g.simStart))
+	qpsScale := g.scale(recorded, MetricQPS, g.base.QPS())
+	readBytesScale := g.scale(recorded, MetricReadBytes, g.base.ReadBytesPerSecond)
+	writeBytesScale := g.scale(recorded, MetricWriteBytes, g.base.WriteBytesPerSecond)
+
+	batch := make(workload.LoadBatch, 0, len(g.r.ranges))
+	for i, rr := range g.r.ranges {
+		c := &g.carry[i]
+		c.reads += rr.load.ReadsPerSecond * qpsScale * elapsed
+		c.writes += rr.load.WritesPerSecond * qpsScale * elapsed
+		c.readBytes += rr.load.ReadBytesPerSecond * readBytesScale * elapsed
+		c.writeBytes += rr.load.WriteBytesPerSecond * writeBytesScale * elapsed
+		reads, writes := int64(c.reads), int64(c.writes)
+		if reads == 0 && writes == 0 {
+			continue
+		}
+		ev := workload.LoadEvent{
+			Key:    int64(rr.startKey) + g.rand.Int63n(keysPerRange),
+			Reads:  reads,
+			Writes: writes,
+		}
+		c.reads -= float64(reads)
+		c.writes -= float64(writes)
+		if reads > 0 {
+			ev.ReadSize = int64(c.readBytes)
+			c.readBytes -= float64(ev.ReadSize)
+		}
+		if writes > 0 {
+			ev.WriteSize = int64(c.writeBytes)
+			c.writeBytes -= float64(ev.WriteSize)
+		}
+		batch = append(batch, ev)
+	}
+	sort.Sort(batch)
+	return batch
+}
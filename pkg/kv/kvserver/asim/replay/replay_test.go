@@ -0,0 +1,231 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/gen"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/ts"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	b, err := json.MarshalIndent(v, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0644))
+}
+
+func replicas(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+	var ret []roachpb.ReplicaDescriptor
+	for i, storeID := range storeIDs {
+		ret = append(ret, roachpb.ReplicaDescriptor{
+			NodeID:    roachpb.NodeID(storeID),
+			StoreID:   storeID,
+			ReplicaID: roachpb.ReplicaID(i + 1),
+			Type:      roachpb.VOTER_FULL,
+		})
+	}
+	return ret
+}
+
+// writeTestDebugZip writes a debug zip of a four node cluster with two ranges.
+// After the start of the replay, r2 was rebalanced from s3 to s4, and its
+// lease was transferred from s1 to s2.
+func writeTestDebugZip(t *testing.T) string {
+	dir := t.TempDir()
+
+	var nodes struct {
+		Nodes []statuspb.NodeStatus `json:"nodes"`
+	}
+	for i := 1; i <= 4; i++ {
+		nodeDesc := roachpb.NodeDescriptor{
+			NodeID: roachpb.NodeID(i),
+			Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: "us-east1"},
+				{Key: "zone", Value: "us-east1-" + string(rune('a'+i-1))},
+			}},
+		}
+		nodes.Nodes = append(nodes.Nodes, statuspb.NodeStatus{
+			Desc: nodeDesc,
+			StoreStatuses: []statuspb.StoreStatus{{Desc: roachpb.StoreDescriptor{
+				StoreID:  roachpb.StoreID(i),
+				Node:     nodeDesc,
+				Capacity: roachpb.StoreCapacity{Capacity: 512 << 30},
+			}}},
+		})
+	}
+	writeJSON(t, filepath.Join(dir, nodesFile), nodes)
+
+	lease := func(storeID roachpb.StoreID, start time.Time) roachpb.Lease {
+		return roachpb.Lease{
+			Replica: roachpb.ReplicaDescriptor{NodeID: roachpb.NodeID(storeID), StoreID: storeID},
+			Start:   hlc.ClockTimestamp{WallTime: start.UnixNano()},
+		}
+	}
+	rangeInfo := func(
+		rangeID roachpb.RangeID, start, end string, repls []roachpb.ReplicaDescriptor,
+		leases []roachpb.Lease, qps float64,
+	) serverpb.RangeInfo {
+		desc := roachpb.RangeDescriptor{
+			RangeID:          rangeID,
+			StartKey:         roachpb.RKey(start),
+			EndKey:           roachpb.RKey(end),
+			InternalReplicas: repls,
+			NextReplicaID:    roachpb.ReplicaID(len(repls) + 1),
+		}
+		curLease := leases[len(leases)-1]
+		return serverpb.RangeInfo{
+			State: kvserverpb.RangeInfo{ReplicaState: kvserverpb.ReplicaState{
+				Desc:  &desc,
+				Lease: &curLease,
+				Stats: &enginepb.MVCCStats{KeyBytes: 1 << 20, ValBytes: 31 << 20},
+			}},
+			LeaseHistory:  leases,
+			IsLeaseholder: true,
+			Stats: serverpb.RangeStatistics{
+				ReadsPerSecond:      qps * 0.9,
+				WritesPerSecond:     qps * 0.1,
+				WriteBytesPerSecond: qps * 100,
+			},
+		}
+	}
+	writeJSON(t, filepath.Join(dir, "nodes", "1", rangesFile), []serverpb.RangeInfo{
+		rangeInfo(1, "a", "m", replicas(1, 2, 3),
+			[]roachpb.Lease{lease(1, testStart.Add(-time.Hour))}, 100),
+		rangeInfo(2, "m", "z", replicas(1, 2, 4),
+			[]roachpb.Lease{lease(1, testStart.Add(-time.Hour)), lease(2, testStart.Add(time.Minute))}, 500),
+	})
+
+	rangeLogEvent := func(
+		at time.Time, typ kvserverpb.RangeLogEventType, added, removed roachpb.StoreID,
+	) serverpb.RangeLogResponse_Event {
+		ev := kvserverpb.RangeLogEvent{
+			Timestamp: at,
+			RangeID:   2,
+			StoreID:   1,
+			EventType: typ,
+			Info:      &kvserverpb.RangeLogEvent_Info{},
+		}
+		if added != 0 {
+			ev.Info.AddedReplica = &roachpb.ReplicaDescriptor{StoreID: added}
+		}
+		if removed != 0 {
+			ev.Info.RemovedReplica = &roachpb.ReplicaDescriptor{StoreID: removed}
+		}
+		return serverpb.RangeLogResponse_Event{Event: ev}
+	}
+	writeJSON(t, filepath.Join(dir, rangeLogFile), serverpb.RangeLogResponse{
+		Events: []serverpb.RangeLogResponse_Event{
+			rangeLogEvent(testStart.Add(2*time.Minute), kvserverpb.RangeLogEventType_remove_voter, 0, 3),
+			rangeLogEvent(testStart.Add(time.Minute), kvserverpb.RangeLogEventType_add_voter, 4, 0),
+		},
+	})
+	return dir
+}
+
+// makeTestTSDump returns a raw tsdump containing the QPS and replica count of
+// every store over the first five minutes of the replay.
+func makeTestTSDump(t *testing.T) []byte {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	const sampleDuration = 10 * time.Second
+	slabStart := testStart.Truncate(time.Hour).UnixNano()
+	addSeries := func(name, source string, value func(offset int32) float64) {
+		data := roachpb.InternalTimeSeriesData{
+			StartTimestampNanos: slabStart,
+			SampleDurationNanos: int64(sampleDuration),
+		}
+		for ts := testStart; ts.Before(testStart.Add(5 * time.Minute)); ts = ts.Add(sampleDuration) {
+			offset := int32((ts.UnixNano() - slabStart) / int64(sampleDuration))
+			data.Samples = append(data.Samples, roachpb.InternalTimeSeriesSample{
+				Offset: offset, Count: 1, Sum: value(offset),
+			})
+		}
+		kv := roachpb.KeyValue{Key: ts.MakeDataKey(name, source, ts.Resolution10s, slabStart)}
+		require.NoError(t, kv.Value.SetProto(&data))
+		require.NoError(t, enc.Encode(&kv))
+	}
+	for _, source := range []string{"1", "2", "3", "4"} {
+		addSeries("cr.store."+MetricQPS, source, func(int32) float64 { return 150 })
+		addSeries("cr.store."+MetricReplicas, source, func(int32) float64 { return 2 })
+		// Node-level series are ignored.
+		addSeries("cr.node.sql.query.count", source, func(int32) float64 { return 1 })
+	}
+	return buf.Bytes()
+}
+
+func TestLoadDebugZip(t *testing.T) {
+	h, err := LoadDebugZip(writeTestDebugZip(t))
+	require.NoError(t, err)
+	require.NoError(t, h.ReadTSDump(bytes.NewReader(makeTestTSDump(t))))
+
+	require.Len(t, h.Stores, 4)
+	require.Equal(t, "region=us-east1,zone=us-east1-c", h.Stores[2].Locality.String())
+	require.Len(t, h.Ranges, 2)
+	require.Equal(t, roachpb.RangeID(1), h.Ranges[0].Desc.RangeID)
+	require.Equal(t, roachpb.StoreID(2), h.Ranges[1].Leaseholder)
+	require.Equal(t, int64(32<<20), h.Ranges[1].Bytes)
+	require.Equal(t, float64(500), h.Ranges[1].Load.QPS())
+	require.Len(t, h.RangeLog, 2)
+	require.Equal(t, kvserverpb.RangeLogEventType_add_voter, h.RangeLog[0].EventType)
+
+	require.Len(t, h.StoreSeries, 2)
+	qps, ok := h.ClusterSeries(MetricQPS, testStart.Add(time.Minute))
+	require.True(t, ok)
+	require.Equal(t, float64(600), qps)
+	start, end := h.Interval()
+	require.Equal(t, testStart, start)
+	require.Equal(t, testStart.Add(5*time.Minute-10*time.Second), end)
+}
+
+func TestReplay(t *testing.T) {
+	h, err := LoadDebugZip(writeTestDebugZip(t))
+	require.NoError(t, err)
+	require.NoError(t, h.ReadTSDump(bytes.NewReader(makeTestTSDump(t))))
+	r := NewReplay(h, time.Time{}, time.Time{})
+
+	// The rebalance of r2 and its lease transfer are undone.
+	require.Equal(t, []roachpb.StoreID{1, 2, 3}, r.ranges[1].voters)
+	require.Equal(t, roachpb.StoreID(1), r.ranges[1].leaseholder)
+	require.Equal(t, int32(3), r.ranges[1].config.NumReplicas)
+
+	settings := config.DefaultSimulationSettings()
+	sim := gen.GenerateSimulation(
+		r.Duration(), r.Cluster(), r.Ranges(), r.Load(),
+		gen.StaticSettings{Settings: settings}, gen.NewStaticEventsWithNoEvents(), 42,
+	)
+	sim.RunSim(context.Background())
+	c := r.Compare(sim.History())
+	require.Len(t, c.Stores, 4)
+	require.Equal(t, int64(1), c.RecordedReplicaAdds)
+	require.False(t, c.HasRecordedLeaseTransfers)
+	for _, s := range c.Stores {
+		require.Equal(t, int64(2), s.RecordedReplicas)
+	}
+	var simReplicas int64
+	for _, s := range c.Stores {
+		simReplicas += s.SimulatedReplicas
+	}
+	require.Equal(t, int64(6), simReplicas)
+}
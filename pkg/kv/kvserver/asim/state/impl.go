@@ -267,6 +267,7 @@ func (s *state) capacity(storeID StoreID) roachpb.StoreCapacity {
 	capacity := store.desc.Capacity
 	capacity.QueriesPerSecond = 0
 	capacity.WritesPerSecond = 0
+	capacity.WriteBytesPerSecond = 0
 	capacity.LogicalBytes = 0
 	capacity.LeaseCount = 0
 	capacity.RangeCount = 0
@@ -288,6 +289,9 @@ func (s *state) capacity(storeID StoreID) roachpb.StoreCapacity {
 			capacity.LogicalBytes += usage.LogicalBytes
 			capacity.LeaseCount++
 		}
+		// Every replica applies the range's writes, regardless of where the
+		// lease is.
+		capacity.WriteBytesPerSecond += s.load[rangeID].Load().RaftWriteBytesPerSecond
 		capacity.RangeCount++
 	}
 
@@ -1043,7 +1047,12 @@ func (s *state) RangeUsageInfo(rangeID RangeID, storeID StoreID) allocator.Range
 	// should also track non leaseholder load. See load.go for more. Return an
 	// empty initialized load counter here.
 	if store.StoreID() != storeID {
-		return allocator.RangeUsageInfo{LogicalBytes: r.Size()}
+		// Every replica applies the range's writes, so the raft write bytes are
+		// the same as the leaseholder's.
+		return allocator.RangeUsageInfo{
+			LogicalBytes:            r.Size(),
+			RaftWriteBytesPerSecond: s.load[rangeID].Load().RaftWriteBytesPerSecond,
+		}
 	}
 
 	usage := s.load[rangeID].Load()
@@ -68,6 +68,7 @@ func (rl *ReplicaLoadCounter) ApplyLoad(le workload.LoadEvent) {
 	rl.WriteKeys += le.Writes
 
 	rl.loadStats.RecordBatchRequests(LoadEventQPS(le), 0)
+	rl.loadStats.RecordRaftWriteBytes(float64(le.WriteSize))
 	// TODO(kvoli): Recording the load on every load counter is horribly
 	// inefficient at the moment. It multiplies the time taken per test almost
 	// linearly by the number of load stats counters we bump. The other load
@@ -81,8 +82,9 @@ func (rl *ReplicaLoadCounter) Load() allocator.RangeUsageInfo {
 	stats := rl.loadStats.Stats()
 
 	return allocator.RangeUsageInfo{
-		QueriesPerSecond: stats.QueriesPerSecond,
-		WritesPerSecond:  float64(rl.WriteKeys),
+		QueriesPerSecond:        stats.QueriesPerSecond,
+		WritesPerSecond:         float64(rl.WriteKeys),
+		RaftWriteBytesPerSecond: stats.RaftWriteBytesPerSecond,
 	}
 }
 
@@ -123,15 +125,16 @@ type CapacityOverride roachpb.StoreCapacity
 // NewCapacityOverride returns a capacity override where no overrides are set.
 func NewCapacityOverride() CapacityOverride {
 	return CapacityOverride{
-		Capacity:         capacityOverrideSentinel,
-		Available:        capacityOverrideSentinel,
-		Used:             capacityOverrideSentinel,
-		LogicalBytes:     capacityOverrideSentinel,
-		RangeCount:       capacityOverrideSentinel,
-		LeaseCount:       capacityOverrideSentinel,
-		QueriesPerSecond: capacityOverrideSentinel,
-		WritesPerSecond:  capacityOverrideSentinel,
-		CPUPerSecond:     capacityOverrideSentinel,
+		Capacity:             capacityOverrideSentinel,
+		Available:            capacityOverrideSentinel,
+		Used:                 capacityOverrideSentinel,
+		LogicalBytes:         capacityOverrideSentinel,
+		RangeCount:           capacityOverrideSentinel,
+		LeaseCount:           capacityOverrideSentinel,
+		QueriesPerSecond:     capacityOverrideSentinel,
+		WritesPerSecond:      capacityOverrideSentinel,
+		CPUPerSecond:         capacityOverrideSentinel,
+		ProvisionedBandwidth: capacityOverrideSentinel,
 		IOThresholdMax: admissionpb.IOThreshold{
 			L0NumSubLevels:           capacityOverrideSentinel,
 			L0NumSubLevelsThreshold:  capacityOverrideSentinel,
@@ -146,7 +149,8 @@ func NewCapacityOverride() CapacityOverride {
 func (co CapacityOverride) String() string {
 	return fmt.Sprintf(
 		"capacity=%d, available=%d, used=%d, logical_bytes=%d, range_count=%d, lease_count=%d, "+
-			"queries_per_sec=%.2f, writes_per_sec=%.2f, cpu_per_sec=%.2f, io_threshold_max=%v",
+			"queries_per_sec=%.2f, writes_per_sec=%.2f, cpu_per_sec=%.2f, provisioned_bandwidth=%d, "+
+			"io_threshold_max=%v",
 		co.Capacity,
 		co.Available,
 		co.Used,
@@ -156,6 +160,7 @@ func (co CapacityOverride) String() string {
 		co.QueriesPerSecond,
 		co.WritesPerSecond,
 		co.CPUPerSecond,
+		co.ProvisionedBandwidth,
 		co.IOThresholdMax,
 	)
 }
@@ -191,6 +196,9 @@ func mergeOverride(
 	if override.CPUPerSecond != capacityOverrideSentinel {
 		ret.CPUPerSecond = override.CPUPerSecond
 	}
+	if override.ProvisionedBandwidth != capacityOverrideSentinel {
+		ret.ProvisionedBandwidth = override.ProvisionedBandwidth
+	}
 	if override.IOThresholdMax.L0NumFiles != capacityOverrideSentinel {
 		ret.IOThresholdMax.L0NumFiles = override.IOThresholdMax.L0NumFiles
 	}
@@ -351,6 +351,12 @@ var defaultSpanConfig roachpb.SpanConfig = roachpb.SpanConfig{
 	NumVoters:     3,
 }
 
+// DefaultSpanConfig returns a copy of the span config that is used for ranges
+// that aren't configured with one.
+func DefaultSpanConfig() roachpb.SpanConfig {
+	return defaultSpanConfig
+}
+
 // FirstRangeID is the constant for the ID assigned to the first range within
 // the keyspace.
 const FirstRangeID = 1
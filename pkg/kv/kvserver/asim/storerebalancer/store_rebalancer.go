@@ -128,14 +128,27 @@ func (s simRebalanceObjectiveProvider) Objective() kvserver.LBRebalancingObjecti
 }
 
 func (src *storeRebalancerControl) scorerOptions() *allocatorimpl.LoadScorerOptions {
+	// The simulator doesn't track replica CPU, so every objective other than
+	// write bytes is balanced on QPS.
+	dim := load.Queries
+	threshold := allocatorimpl.MakeQPSOnlyDim(src.settings.LBRebalanceQPSThreshold)
+	minDiff := allocatorimpl.MakeQPSOnlyDim(src.settings.LBMinRequiredQPSDiff)
+	if kvserver.LBRebalancingObjective(src.settings.LBRebalancingObjective) ==
+		kvserver.LBRebalancingWriteBytes {
+		dim = load.WriteBytes
+		thresholds, minDiffs := load.Vector{}, load.Vector{}
+		thresholds[load.WriteBytes] = src.settings.LBRebalanceWriteBytesThreshold
+		minDiffs[load.WriteBytes] = src.settings.LBMinRequiredWriteBytesDiff
+		threshold, minDiff = thresholds, minDiffs
+	}
 	return &allocatorimpl.LoadScorerOptions{
 		IOOverloadOptions:            src.allocator.IOOverloadOptions(),
 		DiskOptions:                  src.allocator.DiskOptions(),
 		Deterministic:                true,
-		LoadDims:                     []load.Dimension{load.Queries},
-		LoadThreshold:                allocatorimpl.MakeQPSOnlyDim(src.settings.LBRebalanceQPSThreshold),
-		MinLoadThreshold:             allocatorimpl.LoadMinThresholds(load.Queries),
-		MinRequiredRebalanceLoadDiff: allocatorimpl.MakeQPSOnlyDim(src.settings.LBMinRequiredQPSDiff),
+		LoadDims:                     []load.Dimension{dim},
+		LoadThreshold:                threshold,
+		MinLoadThreshold:             allocatorimpl.LoadMinThresholds(dim),
+		MinRequiredRebalanceLoadDiff: minDiff,
 	}
 }
 
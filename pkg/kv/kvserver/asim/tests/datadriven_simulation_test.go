@@ -121,13 +121,21 @@ import (
 //     over-replicated(over), unavailable(unavailable) and violating
 //     constraints(violating) at the end of the evaluation.
 //
-//   - "setting" [rebalance_mode=<int>] [rebalance_interval=<duration>]
-//     [rebalance_qps_threshold=<float>] [split_qps_threshold=<float>]
-//     [rebalance_range_threshold=<float>] [gossip_delay=<duration>]
+//   - set_capacity store=<int> [io_threshold=<float>] [capacity=<int>]
+//     [available=<int>] [provisioned_bandwidth=<int>] [delay=<duration>]
+//     Override the capacity of the store with ID StoreID. The provisioned
+//     bandwidth is given in bytes per second. This applies at the start of the
+//     simulation or with some delay after the simulation starts, if specified.
+//
+//   - "setting" [rebalance_mode=<int>] [rebalance_objective=<int>]
+//     [rebalance_interval=<duration>] [rebalance_qps_threshold=<float>]
+//     [split_qps_threshold=<float>] [rebalance_range_threshold=<float>]
+//     [gossip_delay=<duration>]
 //     Configure the simulation's various settings. The default values are:
-//     rebalance_mode=2 (leases and replicas) rebalance_interval=1m (1 minute)
-//     rebalance_qps_threshold=0.1 split_qps_threshold=2500
-//     rebalance_range_threshold=0.05 gossip_delay=500ms.
+//     rebalance_mode=2 (leases and replicas) rebalance_objective=0 (qps)
+//     rebalance_interval=1m (1 minute) rebalance_qps_threshold=0.1
+//     split_qps_threshold=2500 rebalance_range_threshold=0.05
+//     gossip_delay=500ms.
 //
 //   - "eval" [duration=<string>] [samples=<int>] [seed=<int>]
 //     Run samples (e.g. samples=5) number of simulations for duration (e.g.
@@ -301,18 +309,20 @@ func TestDataDriven(t *testing.T) {
 			case "set_capacity":
 				var store int
 				var ioThreshold float64 = -1
-				var capacity, available int64 = -1, -1
+				var capacity, available, provisionedBandwidth int64 = -1, -1, -1
 				var delay time.Duration
 
 				scanArg(t, d, "store", &store)
 				scanIfExists(t, d, "io_threshold", &ioThreshold)
 				scanIfExists(t, d, "capacity", &capacity)
 				scanIfExists(t, d, "available", &available)
+				scanIfExists(t, d, "provisioned_bandwidth", &provisionedBandwidth)
 				scanIfExists(t, d, "delay", &delay)
 
 				capacityOverride := state.NewCapacityOverride()
 				capacityOverride.Capacity = capacity
 				capacityOverride.Available = available
+				capacityOverride.ProvisionedBandwidth = provisionedBandwidth
 				if ioThreshold != -1 {
 					capacityOverride.IOThresholdMax = allocatorimpl.TestingIOThresholdWithScore(ioThreshold)
 				}
@@ -432,6 +442,7 @@ func TestDataDriven(t *testing.T) {
 				return ""
 			case "setting":
 				scanIfExists(t, d, "rebalance_mode", &settingsGen.Settings.LBRebalancingMode)
+				scanIfExists(t, d, "rebalance_objective", &settingsGen.Settings.LBRebalancingObjective)
 				scanIfExists(t, d, "rebalance_interval", &settingsGen.Settings.LBRebalancingInterval)
 				scanIfExists(t, d, "rebalance_qps_threshold", &settingsGen.Settings.LBRebalanceQPSThreshold)
 				scanIfExists(t, d, "split_qps_threshold", &settingsGen.Settings.SplitQPSThreshold)
@@ -324,6 +324,8 @@ func TestCheckCanReceiveLease(t *testing.T) {
 
 		{leaseholderType: roachpb.LEARNER, anotherReplicaType: none, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
 		{leaseholderType: roachpb.NON_VOTER, anotherReplicaType: none, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
+		{leaseholderType: roachpb.WITNESS, anotherReplicaType: none, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
+		{leaseholderType: roachpb.WITNESS, anotherReplicaType: roachpb.VOTER_INCOMING, expIfWasLastLeaseholderTrue: false, expIfWasLastLeaseholderFalse: false},
 	} {
 		t.Run(tc.leaseholderType.String(), func(t *testing.T) {
 			repDesc := roachpb.ReplicaDescriptor{
@@ -0,0 +1,522 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvserver
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/spanconfig"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/objstorage/remote"
+)
+
+const (
+	// coldTieringQueueTimerDuration is the duration between tierings of queued
+	// replicas. Each tiering uploads a range's worth of data, so this paces the
+	// queue somewhat.
+	coldTieringQueueTimerDuration = time.Second
+
+	// coldTieringQueueConcurrency is the number of replicas that are tiered
+	// concurrently.
+	coldTieringQueueConcurrency = 1
+
+	// coldTieredObjectGCInterval is the interval at which a store deletes the
+	// cold tiered objects that it no longer references.
+	coldTieredObjectGCInterval = 10 * time.Minute
+)
+
+// ColdTieringExternalStorageURI is the external storage location that data of
+// ranges configured with a cold_after_seconds zone config field is moved to
+// once it has gone cold. Cold tiering is disabled while it is unset.
+var ColdTieringExternalStorageURI = settings.RegisterStringSetting(
+	settings.SystemOnly,
+	"kv.cold_tiering.external_storage_uri",
+	"external storage URI that cold range data is moved to; "+
+		"if empty, cold data is not moved off local disk",
+	"",
+	settings.WithReportable(false),
+	settings.Sensitive,
+)
+
+// ColdTieringMinLocalBytes is the minimum number of bytes a replica needs to
+// store on local disk for it to be tiered. This avoids creating many tiny
+// external objects.
+var ColdTieringMinLocalBytes = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.cold_tiering.min_local_bytes",
+	"minimum number of bytes of a cold range stored on local disk before "+
+		"they are moved to external storage",
+	1<<20, // 1 MiB
+	settings.NonNegativeInt,
+)
+
+// coldTieringRate is the rate that is assumed when computing the processing
+// timeout of the cold tiering queue.
+var coldTieringRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.cold_tiering.min_rate",
+	"the minimum expected rate (bytes/sec) at which cold range data is moved "+
+		"to external storage; used to compute the timeout for moving a range",
+	8<<20, // 8 MiB
+	settings.PositiveInt,
+)
+
+// coldTieringQueue moves the user data of replicas whose span config sets
+// ColdAfterSeconds, and which haven't been written to for that long, from
+// local disk to external storage.
+//
+// Tiering is a store-local operation: every replica of a range, not just the
+// leaseholder, writes the contents of its replicated user keyspace to an
+// sstable in external storage and then replaces the local copy by ingesting
+// that sstable as an external file while excising the range's user span. The
+// data stays readable through Pebble, which reads external files through the
+// store's secondary cache. Subsequent writes to the range are stored locally
+// again, and compactions gradually pull the cold data back onto local disk.
+//
+// Every object is recorded in the store-local keyspace before it is created.
+// Objects that the store no longer references, e.g. because the range was
+// tiered again, or was rebalanced away, are deleted by coldTieredObjects.gc.
+type coldTieringQueue struct {
+	*baseQueue
+}
+
+var _ queueImpl = &coldTieringQueue{}
+
+// newColdTieringQueue returns a new instance of coldTieringQueue.
+func newColdTieringQueue(store *Store) *coldTieringQueue {
+	ctq := &coldTieringQueue{}
+	ctq.baseQueue = newBaseQueue(
+		"coldTiering", ctq, store,
+		queueConfig{
+			maxSize:              defaultQueueMaxSize,
+			maxConcurrency:       coldTieringQueueConcurrency,
+			processTimeoutFunc:   makeRateLimitedTimeoutFunc(coldTieringRate),
+			needsLease:           false,
+			needsSpanConfigs:     true,
+			acceptsUnsplitRanges: false,
+			successes:            store.metrics.ColdTieringQueueSuccesses,
+			failures:             store.metrics.ColdTieringQueueFailures,
+			storeFailures:        store.metrics.StoreFailures,
+			pending:              store.metrics.ColdTieringQueuePending,
+			processingNanos:      store.metrics.ColdTieringQueueProcessingNanos,
+			disabledConfig:       kvserverbase.ColdTieringQueueEnabled,
+		},
+	)
+	return ctq
+}
+
+func (ctq *coldTieringQueue) shouldQueue(
+	ctx context.Context, now hlc.ClockTimestamp, repl *Replica, _ spanconfig.StoreReader,
+) (shouldQueue bool, priority float64) {
+	localBytes, ok, err := repl.shouldTierToColdStorage(now)
+	if err != nil {
+		log.VErrEventf(ctx, 2, "unable to determine whether to tier replica: %v", err)
+		return false, 0
+	}
+	if !ok {
+		return false, 0
+	}
+	// Move the replicas that take up the most local disk space first.
+	return true, float64(localBytes)
+}
+
+// shouldTierToColdStorage returns whether the replica's data is cold and
+// should be moved to external storage, along with the number of bytes it
+// currently stores on local disk.
+func (r *Replica) shouldTierToColdStorage(
+	now hlc.ClockTimestamp,
+) (localBytes uint64, _ bool, _ error) {
+	st := r.ClusterSettings()
+	if ColdTieringExternalStorageURI.Get(&st.SV) == "" {
+		return 0, false, nil
+	}
+	desc, conf := r.DescAndSpanConfig()
+	if conf.ColdAfterSeconds <= 0 {
+		return 0, false, nil
+	}
+	if repDesc, ok := desc.GetReplicaDescriptor(r.StoreID()); !ok || repDesc.IsWitness() {
+		// Witnesses don't store user data.
+		return 0, false, nil
+	}
+	// The MVCC stats are updated by every write to the range, so their age is a
+	// (conservative) approximation of when the range was last written to.
+	ms := r.GetMVCCStats()
+	if now.WallTime-ms.LastUpdateNanos < conf.ColdAfter().Nanoseconds() {
+		return 0, false, nil
+	}
+	span := desc.KeySpan().AsRawSpanWithNoLocals()
+	totalBytes, remoteBytes, externalBytes, err := r.store.StateEngine().ApproximateDiskBytes(
+		span.Key, span.EndKey)
+	if err != nil {
+		return 0, false, err
+	}
+	if nonLocalBytes := remoteBytes + externalBytes; nonLocalBytes < totalBytes {
+		localBytes = totalBytes - nonLocalBytes
+	}
+	if localBytes == 0 || localBytes < uint64(ColdTieringMinLocalBytes.Get(&st.SV)) {
+		return localBytes, false, nil
+	}
+	return localBytes, true, nil
+}
+
+func (ctq *coldTieringQueue) process(
+	ctx context.Context, repl *Replica, _ spanconfig.StoreReader,
+) (processed bool, err error) {
+	// The replica may have been written to since it was queued.
+	if _, ok, err := repl.shouldTierToColdStorage(repl.Clock().NowAsClockTimestamp()); err != nil || !ok {
+		return false, err
+	}
+	uri := ColdTieringExternalStorageURI.Get(&repl.ClusterSettings().SV)
+	tieredBytes, err := repl.tierToColdStorage(ctx, remote.Locator(uri))
+	if err != nil {
+		return false, err
+	}
+	if tieredBytes == 0 {
+		return false, nil
+	}
+	repl.store.metrics.ColdTieringBytes.Inc(int64(tieredBytes))
+	log.VEventf(ctx, 1, "moved %s of cold data to external storage",
+		humanizeutil.IBytes(int64(tieredBytes)))
+	return true, nil
+}
+
+// tierToColdStorage writes the replica's replicated user data to a new object
+// at the given external storage location, and then replaces the local copy of
+// the data with a reference to that object. It returns the size of the
+// uploaded object, or zero if nothing was moved.
+//
+// The upload happens without holding raftMu. If the replica applies any
+// command in the meantime, the uploaded object is deleted and the tiering
+// needs to be retried later.
+func (r *Replica) tierToColdStorage(ctx context.Context, locator remote.Locator) (uint64, error) {
+	eng := r.store.StateEngine()
+	objs := &r.store.coldTieredObjects
+
+	r.raftMu.Lock()
+	desc := r.Desc()
+	appliedIndex := r.getRaftAppliedIndex()
+	snap := eng.NewSnapshot()
+	r.raftMu.Unlock()
+	defer snap.Close()
+
+	objName := fmt.Sprintf("cold/n%d/s%d/r%d/%s.sst",
+		r.NodeID(), r.StoreID(), desc.RangeID, uuid.MakeV4())
+	if err := objs.begin(ctx, locator, objName); err != nil {
+		return 0, err
+	}
+	defer objs.end(objName)
+	ingested := false
+	defer func() {
+		if !ingested {
+			objs.discard(ctx, locator, objName)
+		}
+	}()
+	writable, err := eng.CreateExternalObject(ctx, locator, objName)
+	if err != nil {
+		return 0, err
+	}
+	sst := storage.MakeIngestionSSTWriter(ctx, r.ClusterSettings(), writable)
+	defer sst.Close()
+
+	empty := true
+	err = rditer.IterateReplicaKeySpans(ctx, desc, snap, true, /* replicatedOnly */
+		rditer.ReplicatedSpansUserOnly,
+		func(iter storage.EngineIterator, _ roachpb.Span) error {
+			var err error
+			for ok := true; ok && err == nil; ok, err = iter.NextEngineKey() {
+				hasPoint, hasRange := iter.HasPointAndRange()
+				if hasRange && iter.RangeKeyChanged() {
+					bounds, err := iter.EngineRangeBounds()
+					if err != nil {
+						return err
+					}
+					for _, rkv := range iter.EngineRangeKeys() {
+						empty = false
+						if err := sst.PutEngineRangeKey(bounds.Key, bounds.EndKey, rkv.Version, rkv.Value); err != nil {
+							return err
+						}
+					}
+				}
+				if hasPoint {
+					empty = false
+					key, err := iter.UnsafeEngineKey()
+					if err != nil {
+						return err
+					}
+					v, err := iter.UnsafeValue()
+					if err != nil {
+						return err
+					}
+					if err := sst.PutEngineKey(key, v); err != nil {
+						return err
+					}
+				}
+			}
+			return err
+		})
+	if err != nil || empty {
+		// Abort the upload before the deferred Close, which would otherwise
+		// finish the partially written (or empty) object.
+		writable.Abort()
+		return 0, err
+	}
+	if err := sst.Finish(); err != nil {
+		return 0, errors.Wrapf(err, "writing %s", objName)
+	}
+
+	r.raftMu.Lock()
+	defer r.raftMu.Unlock()
+	if _, err := r.IsDestroyed(); err != nil {
+		return 0, err
+	}
+	if newDesc := r.Desc(); newDesc.Generation != desc.Generation ||
+		!newDesc.KeySpan().Equal(desc.KeySpan()) || r.getRaftAppliedIndex() != appliedIndex {
+		log.VEventf(ctx, 2, "replica changed while moving it to external storage; retrying later")
+		return 0, nil
+	}
+	span := desc.KeySpan().AsRawSpanWithNoLocals()
+	if _, err := eng.IngestAndExciseFiles(ctx, nil /* paths */, nil /* shared */, []pebble.ExternalFile{{
+		Locator:           locator,
+		ObjName:           objName,
+		Size:              sst.Meta.Size,
+		StartKey:          storage.EngineKey{Key: span.Key}.Encode(),
+		EndKey:            storage.EngineKey{Key: span.EndKey}.Encode(),
+		EndKeyIsInclusive: false,
+		HasPointKey:       sst.Meta.HasPointKeys,
+		HasRangeKey:       sst.Meta.HasRangeKeys,
+	}}, span, false /* sstsContainExciseTombstone */); err != nil {
+		return 0, errors.Wrapf(err, "while ingesting %s and excising %s-%s", objName, span.Key, span.EndKey)
+	}
+	ingested = true
+	return sst.Meta.Size, nil
+}
+
+// coldTieredObjects tracks the external objects that a store created to move
+// cold range data off local disk, so that they can be deleted once the store
+// no longer references them.
+//
+// Each object is recorded under a keys.StoreColdTieredObjectKey, with the
+// locator of its external storage as the value, before it is created, and is
+// only referenced by the store that created it: stores that track objects
+// don't send external replication snapshots (see
+// Replica.followerSendSnapshot), so other stores receive a copy of the data
+// instead.
+type coldTieredObjects struct {
+	eng storage.Engine
+	// tracked is set once the store has recorded an object. It is never reset,
+	// as the object may be referenced until it is deleted.
+	tracked atomic.Bool
+	mu      struct {
+		syncutil.Mutex
+		// inFlight contains the objects that are being written or ingested.
+		// They aren't referenced by the engine yet, but must not be deleted by
+		// gc. gc holds mu for its whole duration, so an object that is removed
+		// from inFlight once ingested is guaranteed to be seen as referenced.
+		inFlight map[string]struct{}
+	}
+}
+
+// init initializes the tracker with the store's engine.
+func (c *coldTieredObjects) init(ctx context.Context, eng storage.Engine) error {
+	c.eng = eng
+	c.mu.inFlight = make(map[string]struct{})
+	var found bool
+	if _, err := storage.MVCCIterate(ctx, eng, keys.LocalStoreColdTieredObjectKeyMin,
+		keys.LocalStoreColdTieredObjectKeyMax, hlc.Timestamp{}, storage.MVCCScanOptions{},
+		func(roachpb.KeyValue) error {
+			found = true
+			return iterutil.StopIteration()
+		}); err != nil {
+		return err
+	}
+	c.tracked.Store(found)
+	return nil
+}
+
+// hasTracked returns whether the store may reference any object that it
+// created.
+func (c *coldTieredObjects) hasTracked() bool {
+	return c.tracked.Load()
+}
+
+// begin durably records an object that is about to be created. The caller
+// must call end once the object was either ingested or discarded.
+func (c *coldTieredObjects) begin(
+	ctx context.Context, locator remote.Locator, objName string,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tracked.Store(true)
+	batch := c.eng.NewBatch()
+	defer batch.Close()
+	if _, err := storage.MVCCPut(ctx, batch, keys.StoreColdTieredObjectKey(objName),
+		hlc.Timestamp{}, roachpb.MakeValueFromString(string(locator)),
+		storage.MVCCWriteOptions{}); err != nil {
+		return err
+	}
+	// The record needs to be durable before the object is created, or the
+	// object could leak if the node crashed.
+	if err := batch.Commit(true /* sync */); err != nil {
+		return err
+	}
+	c.mu.inFlight[objName] = struct{}{}
+	return nil
+}
+
+// end marks the end of the ingestion of an object recorded by begin.
+func (c *coldTieredObjects) end(objName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.mu.inFlight, objName)
+}
+
+// discard deletes an object recorded by begin that was not ingested. If the
+// object can't be deleted, its record is left in place and gc retries later.
+func (c *coldTieredObjects) discard(ctx context.Context, locator remote.Locator, objName string) {
+	if _, ok := c.eng.ReferencedExternalObjects(locator)[objName]; ok {
+		// The ingestion failed after the object was added to the LSM.
+		return
+	}
+	if err := c.deleteObject(ctx, locator, objName); err != nil {
+		log.Warningf(ctx, "unable to delete cold tiered object %s: %v", objName, err)
+	}
+}
+
+// deleteObject deletes an object and its record.
+func (c *coldTieredObjects) deleteObject(
+	ctx context.Context, locator remote.Locator, objName string,
+) error {
+	if err := c.eng.DeleteExternalObject(ctx, locator, objName); err != nil {
+		return err
+	}
+	batch := c.eng.NewBatch()
+	defer batch.Close()
+	if _, _, err := storage.MVCCDelete(ctx, batch, keys.StoreColdTieredObjectKey(objName),
+		hlc.Timestamp{}, storage.MVCCWriteOptions{}); err != nil {
+		return err
+	}
+	return batch.Commit(false /* sync */)
+}
+
+// gc deletes the recorded objects that the store doesn't reference any more,
+// and returns the number of deleted objects.
+func (c *coldTieredObjects) gc(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	type object struct {
+		locator remote.Locator
+		name    string
+	}
+	var unreferenced []object
+	referenced := make(map[remote.Locator]map[string]struct{})
+	if _, err := storage.MVCCIterate(ctx, c.eng, keys.LocalStoreColdTieredObjectKeyMin,
+		keys.LocalStoreColdTieredObjectKeyMax, hlc.Timestamp{}, storage.MVCCScanOptions{},
+		func(kv roachpb.KeyValue) error {
+			objName, err := keys.DecodeStoreColdTieredObjectKey(kv.Key)
+			if err != nil {
+				return err
+			}
+			if _, ok := c.mu.inFlight[objName]; ok {
+				return nil
+			}
+			b, err := kv.Value.GetBytes()
+			if err != nil {
+				return err
+			}
+			locator := remote.Locator(b)
+			objs, ok := referenced[locator]
+			if !ok {
+				objs = c.eng.ReferencedExternalObjects(locator)
+				referenced[locator] = objs
+			}
+			if _, ok := objs[objName]; !ok {
+				unreferenced = append(unreferenced, object{locator: locator, name: objName})
+			}
+			return nil
+		}); err != nil {
+		return 0, err
+	}
+	for i, obj := range unreferenced {
+		if err := c.deleteObject(ctx, obj.locator, obj.name); err != nil {
+			return i, errors.Wrapf(err, "deleting cold tiered object %s", obj.name)
+		}
+	}
+	return len(unreferenced), nil
+}
+
+// startColdTieredObjectGC starts a worker that periodically deletes the cold
+// tiered objects that the store no longer references.
+func (s *Store) startColdTieredObjectGC(ctx context.Context) {
+	_ /* err */ = s.stopper.RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "cold-tiered-object-gc",
+		SpanOpt:  stop.SterileRootSpan,
+	}, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+
+		ticker := time.NewTicker(coldTieredObjectGCInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if !s.coldTieredObjects.hasTracked() {
+					continue
+				}
+				deleted, err := s.coldTieredObjects.gc(ctx)
+				if err != nil {
+					log.Warningf(ctx, "unable to delete cold tiered objects: %v", err)
+				}
+				if deleted > 0 {
+					log.Infof(ctx, "deleted %d unreferenced cold tiered objects", deleted)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// getRaftAppliedIndex returns the index of the last applied raft command.
+func (r *Replica) getRaftAppliedIndex() kvpb.RaftIndex {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.shMu.state.RaftAppliedIndex
+}
+
+func (*coldTieringQueue) postProcessScheduled(
+	ctx context.Context, replica replicaInQueue, priority float64,
+) {
+}
+
+func (*coldTieringQueue) timer(_ time.Duration) time.Duration {
+	return coldTieringQueueTimerDuration
+}
+
+func (*coldTieringQueue) purgatoryChan() <-chan time.Time {
+	return nil
+}
+
+func (*coldTieringQueue) updateChan() <-chan time.Time {
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvserver_test
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// coldTieringTestReplica returns the store's replica of the given table's
+// range, once it has picked up the expected cold_after_seconds zone config
+// field.
+func coldTieringTestReplica(
+	t *testing.T, store *kvserver.Store, tdb *sqlutils.SQLRunner, table string, coldAfter int32,
+) *kvserver.Replica {
+	var repl *kvserver.Replica
+	testutils.SucceedsSoon(t, func() error {
+		var rangeID roachpb.RangeID
+		tdb.QueryRow(t, `SELECT range_id FROM [SHOW RANGES FROM TABLE `+table+`]`).Scan(&rangeID)
+		r, err := store.GetReplica(rangeID)
+		if err != nil {
+			return err
+		}
+		if _, conf := r.DescAndSpanConfig(); conf.ColdAfterSeconds != coldAfter {
+			return errors.Newf("r%d has cold_after_seconds=%d, expected %d",
+				rangeID, conf.ColdAfterSeconds, coldAfter)
+		}
+		repl = r
+		return nil
+	})
+	return repl
+}
+
+// countColdTieredObjects returns the number of sstables below dir.
+func countColdTieredObjects(t *testing.T, dir string) int {
+	var n int
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".sst") {
+			n++
+		}
+		return nil
+	}))
+	return n
+}
+
+// TestShouldTierToColdStorage tests the decision of whether a replica's data
+// is cold and should be moved to external storage.
+func TestShouldTierToColdStorage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	tc := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			Settings: st,
+			Knobs: base.TestingKnobs{
+				Store: &kvserver.StoreTestingKnobs{DisableMergeQueue: true},
+			},
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+	store := tc.GetFirstStoreFromServer(t, 0)
+	tdb := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	tdb.Exec(t, `CREATE TABLE cold (k INT PRIMARY KEY, v STRING)`)
+	tdb.Exec(t, `CREATE TABLE hot (k INT PRIMARY KEY, v STRING)`)
+	for _, table := range []string{"cold", "hot"} {
+		tdb.Exec(t, `INSERT INTO `+table+` SELECT i, repeat('x', 100) FROM generate_series(1, 1000) AS g(i)`)
+	}
+	tdb.Exec(t, `ALTER TABLE cold CONFIGURE ZONE USING cold_after_seconds = 3600`)
+	coldRepl := coldTieringTestReplica(t, store, tdb, "cold", 3600)
+	hotRepl := coldTieringTestReplica(t, store, tdb, "hot", 0)
+	// Only data in sstables counts towards the local bytes.
+	require.NoError(t, store.TODOEngine().Flush())
+
+	now := store.Clock().NowAsClockTimestamp()
+	later := now
+	later.WallTime += time.Hour.Nanoseconds()
+
+	for _, testCase := range []struct {
+		name     string
+		repl     *kvserver.Replica
+		uri      string
+		minBytes int64
+		now      hlc.ClockTimestamp
+		exp      bool
+	}{
+		{name: "no external storage", repl: coldRepl, minBytes: 1, now: later},
+		{name: "no cold_after_seconds", repl: hotRepl, uri: "nodelocal://1/cold", minBytes: 1, now: later},
+		{name: "recently written", repl: coldRepl, uri: "nodelocal://1/cold", minBytes: 1, now: now},
+		{name: "too small", repl: coldRepl, uri: "nodelocal://1/cold", minBytes: 1 << 30, now: later},
+		{name: "cold", repl: coldRepl, uri: "nodelocal://1/cold", minBytes: 1, now: later, exp: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			kvserver.ColdTieringExternalStorageURI.Override(ctx, &st.SV, testCase.uri)
+			kvserver.ColdTieringMinLocalBytes.Override(ctx, &st.SV, testCase.minBytes)
+			localBytes, ok, err := testCase.repl.ShouldTierToColdStorage(testCase.now)
+			require.NoError(t, err)
+			require.Equal(t, testCase.exp, ok)
+			if testCase.exp {
+				require.NotZero(t, localBytes)
+			}
+		})
+	}
+}
+
+// TestColdTieringReadBack tests that a cold range's data is moved to external
+// storage, remains readable, and that objects that the store no longer
+// references are deleted.
+func TestColdTieringReadBack(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	defer nodelocal.ReplaceNodeLocalForTesting(dir)()
+
+	st := cluster.MakeTestingClusterSettings()
+	kvserver.ColdTieringExternalStorageURI.Override(ctx, &st.SV, "nodelocal://1/cold")
+	kvserver.ColdTieringMinLocalBytes.Override(ctx, &st.SV, 1)
+	tc := testcluster.StartTestCluster(t, 1, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			Settings: st,
+			Knobs: base.TestingKnobs{
+				Store: &kvserver.StoreTestingKnobs{DisableMergeQueue: true},
+			},
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+	store := tc.GetFirstStoreFromServer(t, 0)
+	tdb := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	tdb.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	tdb.Exec(t, `ALTER TABLE t CONFIGURE ZONE USING cold_after_seconds = 1`)
+	repl := coldTieringTestReplica(t, store, tdb, "t", 1)
+
+	tierRange := func(rows int) {
+		tdb.Exec(t, `UPSERT INTO t SELECT i, repeat('x', 100) FROM generate_series(1, $1) AS g(i)`, rows)
+		require.NoError(t, store.TODOEngine().Flush())
+		exp := tdb.QueryStr(t, `SELECT * FROM t ORDER BY k`)
+
+		tieredBefore := store.Metrics().ColdTieringBytes.Count()
+		testutils.SucceedsSoon(t, func() error {
+			// The tiering is retried if the replica applied any command
+			// while its data was uploaded.
+			processErr, enqueueErr := store.Enqueue(
+				ctx, "coldTiering", repl, false /* skipShouldQueue */, false, /* async */
+			)
+			if err := errors.CombineErrors(processErr, enqueueErr); err != nil {
+				return err
+			}
+			if store.Metrics().ColdTieringBytes.Count() == tieredBefore {
+				return errors.New("range not tiered yet")
+			}
+			return nil
+		})
+
+		span := repl.Desc().KeySpan().AsRawSpanWithNoLocals()
+		_, _, externalBytes, err := store.TODOEngine().ApproximateDiskBytes(span.Key, span.EndKey)
+		require.NoError(t, err)
+		require.NotZero(t, externalBytes)
+		require.Equal(t, exp, tdb.QueryStr(t, `SELECT * FROM t ORDER BY k`))
+	}
+
+	tierRange(1000)
+	require.Equal(t, 1, countColdTieredObjects(t, dir))
+	// The object is still referenced by the store.
+	deleted, err := store.GCColdTieredObjects(ctx)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	// Tiering the range again replaces the first object, which is
+	// subsequently deleted.
+	tierRange(2000)
+	testutils.SucceedsSoon(t, func() error {
+		if _, err := store.GCColdTieredObjects(ctx); err != nil {
+			return err
+		}
+		if n := countColdTieredObjects(t, dir); n != 1 {
+			return errors.Newf("found %d cold tiered objects, expected 1", n)
+		}
+		return nil
+	})
+	require.Len(t, tdb.QueryStr(t, `SELECT * FROM t`), 2000)
+}
@@ -223,6 +223,17 @@ func (s *Store) ManualReplicaGC(repl *Replica) error {
 	return manualQueue(s, s.replicaGCQueue, repl)
 }
 
+// GCColdTieredObjects deletes the cold tiered objects that the store no longer
+// references, and returns their number.
+func (s *Store) GCColdTieredObjects(ctx context.Context) (int, error) {
+	return s.coldTieredObjects.gc(ctx)
+}
+
+// ShouldTierToColdStorage exports shouldTierToColdStorage for testing.
+func (r *Replica) ShouldTierToColdStorage(now hlc.ClockTimestamp) (uint64, bool, error) {
+	return r.shouldTierToColdStorage(now)
+}
+
 // ManualRaftSnapshot will manually send a raft snapshot to the target replica.
 func (s *Store) ManualRaftSnapshot(repl *Replica, target roachpb.ReplicaID) error {
 	_, err := s.raftSnapshotQueue.processRaftSnapshot(context.Background(), repl, target)
@@ -232,6 +232,7 @@ var _ Controller = &controllerImpl{}
 // cooperative scheduling with elastic CPU granters).
 type Handle struct {
 	tenantID             roachpb.TenantID
+	resourceGroup        string
 	storeAdmissionQ      *admission.StoreWorkQueue
 	storeWorkHandle      admission.StoreWorkHandle
 	elasticCPUWorkHandle *admission.ElasticCPUWorkHandle
@@ -319,11 +320,14 @@ func (n *controllerImpl) AdmitKVWork(
 		createTime = timeutil.Now().UnixNano()
 	}
 	admissionInfo := admission.WorkInfo{
-		TenantID:        tenantID,
-		Priority:        admissionpb.WorkPriority(ba.AdmissionHeader.Priority),
-		CreateTime:      createTime,
-		BypassAdmission: bypassAdmission,
+		TenantID:            tenantID,
+		Priority:            admissionpb.WorkPriority(ba.AdmissionHeader.Priority),
+		ResourceGroup:       ba.AdmissionHeader.ResourceGroup,
+		ResourceGroupWeight: ba.AdmissionHeader.ResourceGroupWeight,
+		CreateTime:          createTime,
+		BypassAdmission:     bypassAdmission,
 	}
+	ah.resourceGroup = admissionInfo.ResourceGroup
 
 	admissionEnabled := true
 	// Don't subject HeartbeatTxnRequest to the storeAdmissionQ. Even though
@@ -466,7 +470,7 @@ func (n *controllerImpl) AdmittedKVWorkDone(ah Handle, writeBytes *StoreWriteByt
 			}
 			cpuTime = 1
 		}
-		n.kvAdmissionQ.AdmittedWorkDone(ah.tenantID, cpuTime)
+		n.kvAdmissionQ.AdmittedWorkDone(ah.tenantID, ah.resourceGroup, cpuTime)
 	}
 	if ah.storeAdmissionQ != nil {
 		var doneInfo admission.StoreWorkDoneInfo
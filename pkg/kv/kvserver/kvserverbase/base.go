@@ -112,6 +112,15 @@ var MVCCGCQueueEnabled = settings.RegisterBoolSetting(
 	true,
 )
 
+// ColdTieringQueueEnabled is a setting that controls whether the cold tiering
+// queue is enabled.
+var ColdTieringQueueEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.cold_tiering_queue.enabled",
+	"whether the cold tiering queue is enabled",
+	true,
+)
+
 // RangeFeedRefreshInterval is injected from kvserver to avoid import cycles
 // when accessed from kvcoord.
 var RangeFeedRefreshInterval *settings.DurationSetting
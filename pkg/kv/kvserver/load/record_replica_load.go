@@ -47,3 +47,8 @@ func (rl *ReplicaLoad) RecordRaftCPUNanos(val float64) {
 func (rl *ReplicaLoad) RecordReqCPUNanos(val float64) {
 	rl.record(ReqCPUNanos, val, 0 /* nodeID */)
 }
+
+// RecordRaftWriteBytes records the value given for raft write bytes.
+func (rl *ReplicaLoad) RecordRaftWriteBytes(val float64) {
+	rl.record(RaftWriteBytes, val, 0 /* nodeID */)
+}
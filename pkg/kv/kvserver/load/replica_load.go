@@ -31,8 +31,9 @@ const (
 	ReadBytes
 	RaftCPUNanos
 	ReqCPUNanos
+	RaftWriteBytes
 
-	numLoadStats = 8
+	numLoadStats = 9
 )
 
 // ReplicaLoadStats contains per-second average statistics for load upon a
@@ -76,6 +77,11 @@ type ReplicaLoadStats struct {
 	// RequestCPUNanos is the replica's time spent on-processor for requests
 	// averaged per second.
 	RequestCPUNanosPerSecond float64
+	// RaftWriteBytesPerSecond is the replica's average bytes written to disk
+	// per second when applying raft commands. Unlike WriteBytesPerSecond, which
+	// is only recorded on the leaseholder, this is recorded on every replica,
+	// including ingested SSTs.
+	RaftWriteBytesPerSecond float64
 }
 
 // ReplicaLoad tracks a sliding window of throughput on a replica.
@@ -196,6 +202,7 @@ func (rl *ReplicaLoad) Stats() ReplicaLoadStats {
 		ReadBytesPerSecond:       rl.getLocked(ReadBytes),
 		RequestCPUNanosPerSecond: rl.getLocked(ReqCPUNanos),
 		RaftCPUNanosPerSecond:    rl.getLocked(RaftCPUNanos),
+		RaftWriteBytesPerSecond:  rl.getLocked(RaftWriteBytes),
 	}
 }
 
@@ -318,10 +318,11 @@ func (mq *mergeQueue) process(
 	}
 	leftRepls, rightRepls := lhsDesc.Replicas().Descriptors(), rhsDesc.Replicas().Descriptors()
 
-	// Defensive sanity check that the ranges involved only have either VOTER_FULL
-	// and NON_VOTER replicas.
+	// Defensive sanity check that the ranges involved only have either VOTER_FULL,
+	// NON_VOTER and WITNESS replicas.
 	for i := range leftRepls {
-		if typ := leftRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER) {
+		if typ := leftRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER ||
+			typ == roachpb.WITNESS) {
 			return false,
 				errors.AssertionFailedf(
 					`cannot merge because lhs is either in a joint state or has learner replicas: %v`,
@@ -333,11 +334,20 @@ func (mq *mergeQueue) process(
 	// Range merges require that the set of stores that contain a replica for the
 	// RHS range be equal to the set of stores that contain a replica for the LHS
 	// range. The LHS and RHS ranges' leaseholders do not need to be co-located
-	// and types of the replicas (voting or non-voting) do not matter. Even if
-	// replicas are collocated, the RHS might still be in a joint config, and
-	// calling AdminRelocateRange will fix this.
+	// and types of the replicas (voting or non-voting) do not matter, except
+	// that witnesses must be collocated with witnesses. Even if replicas are
+	// collocated, the RHS might still be in a joint config, and calling
+	// AdminRelocateRange will fix this.
 	if !replicasCollocated(leftRepls, rightRepls) ||
 		rhsDesc.Replicas().InAtomicReplicationChange() {
+		// AdminRelocateRange doesn't place witnesses, so it can't collocate the
+		// witnesses of the ranges. Don't merge them until they happen to be.
+		if len(lhsDesc.Replicas().WitnessDescriptors()) > 0 ||
+			len(rhsDesc.Replicas().WitnessDescriptors()) > 0 {
+			log.VEventf(ctx, 2, "skipping merge: witnesses are not collocated: %s != %s",
+				lhsDesc.Replicas(), rhsDesc.Replicas())
+			return false, nil
+		}
 		// TODO(aayush): We enable merges to proceed even when LHS and/or RHS are in
 		// violation of their constraints (by adding or removing replicas on the RHS
 		// as needed). We could instead choose to check constraints conformance of
@@ -382,7 +392,8 @@ func (mq *mergeQueue) process(
 		rightRepls = rhsDesc.Replicas().Descriptors()
 	}
 	for i := range rightRepls {
-		if typ := rightRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER) {
+		if typ := rightRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER ||
+			typ == roachpb.WITNESS) {
 			log.Infof(ctx, "RHS Type: %s", typ)
 			return false,
 				errors.AssertionFailedf(
@@ -1948,6 +1948,36 @@ The messages are dropped to help these replicas to recover from I/O overload.`,
 		Measurement: "Processing Time",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaColdTieringQueueSuccesses = metric.Metadata{
+		Name:        "queue.coldtiering.process.success",
+		Help:        "Number of replicas successfully processed by the cold tiering queue",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaColdTieringQueueFailures = metric.Metadata{
+		Name:        "queue.coldtiering.process.failure",
+		Help:        "Number of replicas which failed processing in the cold tiering queue",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaColdTieringQueuePending = metric.Metadata{
+		Name:        "queue.coldtiering.pending",
+		Help:        "Number of pending replicas in the cold tiering queue",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaColdTieringQueueProcessingNanos = metric.Metadata{
+		Name:        "queue.coldtiering.processingnanos",
+		Help:        "Nanoseconds spent processing replicas in the cold tiering queue",
+		Measurement: "Processing Time",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaColdTieringBytes = metric.Metadata{
+		Name:        "queue.coldtiering.bytes",
+		Help:        "Number of bytes of cold data written to external storage by the cold tiering queue",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
 	metaReplicaGCQueueSuccesses = metric.Metadata{
 		Name:        "queue.replicagc.process.success",
 		Help:        "Number of replicas successfully processed by the replica GC queue",
@@ -2897,6 +2927,11 @@ type StoreMetrics struct {
 	ConsistencyQueueFailures                  *metric.Counter
 	ConsistencyQueuePending                   *metric.Gauge
 	ConsistencyQueueProcessingNanos           *metric.Counter
+	ColdTieringQueueSuccesses                 *metric.Counter
+	ColdTieringQueueFailures                  *metric.Counter
+	ColdTieringQueuePending                   *metric.Gauge
+	ColdTieringQueueProcessingNanos           *metric.Counter
+	ColdTieringBytes                          *metric.Counter
 	LeaseQueueSuccesses                       *metric.Counter
 	LeaseQueueFailures                        *metric.Counter
 	LeaseQueuePending                         *metric.Gauge
@@ -3667,6 +3702,11 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		ConsistencyQueueFailures:                  metric.NewCounter(metaConsistencyQueueFailures),
 		ConsistencyQueuePending:                   metric.NewGauge(metaConsistencyQueuePending),
 		ConsistencyQueueProcessingNanos:           metric.NewCounter(metaConsistencyQueueProcessingNanos),
+		ColdTieringQueueSuccesses:                 metric.NewCounter(metaColdTieringQueueSuccesses),
+		ColdTieringQueueFailures:                  metric.NewCounter(metaColdTieringQueueFailures),
+		ColdTieringQueuePending:                   metric.NewGauge(metaColdTieringQueuePending),
+		ColdTieringQueueProcessingNanos:           metric.NewCounter(metaColdTieringQueueProcessingNanos),
+		ColdTieringBytes:                          metric.NewCounter(metaColdTieringBytes),
 		LeaseQueueSuccesses:                       metric.NewCounter(metaLeaseQueueSuccesses),
 		LeaseQueueFailures:                        metric.NewCounter(metaLeaseQueueFailures),
 		LeaseQueuePending:                         metric.NewGauge(metaLeaseQueuePending),
@@ -78,6 +78,7 @@ func newBufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *RowFilter,
 	bufferSz int,
 	blockWhenFull bool,
 	metrics *Metrics,
@@ -92,6 +93,7 @@ func newBufferedRegistration(
 			withDiff:               withDiff,
 			withFiltering:          withFiltering,
 			withOmitRemote:         withOmitRemote,
+			rowFilter:              rowFilter,
 			removeRegFromProcessor: removeRegFromProcessor,
 		},
 		metrics:       metrics,
@@ -308,7 +310,8 @@ func (br *bufferedRegistration) maybeRunCatchUpScan(ctx context.Context) error {
 		br.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
 
-	return catchUpIter.CatchUpScan(ctx, br.stream.SendUnbuffered, br.withDiff, br.withFiltering, br.withOmitRemote)
+	return catchUpIter.CatchUpScan(ctx, br.stream.SendUnbuffered, br.withDiff, br.withFiltering, br.withOmitRemote,
+		br.rowFilter)
 }
 
 // Wait for this registration to completely process its internal
@@ -141,6 +141,7 @@ func (i *CatchUpIterator) CatchUpScan(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *RowFilter,
 ) error {
 	var a bufalloc.ByteAllocator
 	// MVCCIterator will encounter historical values for each key in
@@ -343,8 +344,10 @@ func (i *CatchUpIterator) CatchUpScan(
 			// of the conditions is met: 1) the value has the OmitInRangefeeds flag,
 			// and this iterator has opted into filtering; 2) the value is from a
 			// remote cluster (non zero originID), and the iterator has opted into
-			// omitting remote values.
-			if (mvccVal.OmitInRangefeeds && withFiltering) || (mvccVal.OriginID != 0 && withOmitRemote) {
+			// omitting remote values; 3) the value is for a row which doesn't pass
+			// the row filter.
+			if (mvccVal.OmitInRangefeeds && withFiltering) || (mvccVal.OriginID != 0 && withOmitRemote) ||
+				!rowFilter.Matches(key, roachpb.Value{RawBytes: val}) {
 				i.Next()
 				continue
 			}
@@ -54,7 +54,7 @@ func runCatchUpBenchmark(b *testing.B, emk engineMaker, opts benchOptions) (numE
 			err = iter.CatchUpScan(ctx, func(*kvpb.RangeFeedEvent) error {
 				counter++
 				return nil
-			}, opts.withDiff, false /* withFiltering */, false /* withOmitRemote */)
+			}, opts.withDiff, false /* withFiltering */, false /* withOmitRemote */, nil /* rowFilter */)
 			if err != nil {
 				b.Fatalf("failed catchUp scan: %+v", err)
 			}
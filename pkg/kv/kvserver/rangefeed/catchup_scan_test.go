@@ -160,7 +160,7 @@ func TestCatchupScan(t *testing.T) {
 				require.NoError(t, iter.CatchUpScan(ctx, func(e *kvpb.RangeFeedEvent) error {
 					events = append(events, *e.Val)
 					return nil
-				}, withDiff, withFiltering, false /* withOmitRemote */))
+				}, withDiff, withFiltering, false /* withOmitRemote */, nil /* rowFilter */))
 				if !(withFiltering && omitInRangefeeds) {
 					require.Equal(t, 7, len(events))
 				} else {
@@ -238,7 +238,7 @@ func TestCatchupScanOriginID(t *testing.T) {
 		require.NoError(t, iter.CatchUpScan(ctx, func(e *kvpb.RangeFeedEvent) error {
 			events = append(events, *e.Val)
 			return nil
-		}, false /* withDiff */, false /* withFiltering */, omitRemote))
+		}, false /* withDiff */, false /* withFiltering */, omitRemote, nil /* rowFilter */))
 		if omitRemote {
 			require.Equal(t, 1, len(events))
 		} else {
@@ -269,7 +269,7 @@ func TestCatchupScanInlineError(t *testing.T) {
 	require.NoError(t, err)
 	defer iter.Close()
 
-	err = iter.CatchUpScan(ctx, nil, false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */)
+	err = iter.CatchUpScan(ctx, nil, false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* rowFilter */)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "unexpected inline value")
 }
@@ -317,7 +317,7 @@ func TestCatchupScanSeesOldIntent(t *testing.T) {
 	require.NoError(t, iter.CatchUpScan(ctx, func(e *kvpb.RangeFeedEvent) error {
 		keys[string(e.Val.Key)] = struct{}{}
 		return nil
-	}, true /* withDiff */, false /* withFiltering */, false /* withOmitRemote */))
+	}, true /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* rowFilter */))
 	require.Equal(t, map[string]struct{}{
 		"b": {},
 		"e": {},
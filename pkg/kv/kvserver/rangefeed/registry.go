@@ -63,6 +63,8 @@ type registration interface {
 	getWithFiltering() bool
 	// getWithOmitRemote returns the withOmitRemote field of the registration.
 	getWithOmitRemote() bool
+	// getRowFilter returns the rowFilter field of the registration.
+	getRowFilter() *RowFilter
 	// Range returns the keys field of the registration.
 	Range() interval.Range
 	// ID returns the id field of the registration as a uintptr.
@@ -86,6 +88,9 @@ type baseRegistration struct {
 	withDiff       bool
 	withFiltering  bool
 	withOmitRemote bool
+	// rowFilter, if set, is used to drop value events for rows which the
+	// consumer isn't interested in.
+	rowFilter *RowFilter
 	// removeRegFromProcessor is called to remove the registration from its
 	// processor. This is provided by the creator of the registration and called
 	// during disconnect(). Since it is called during disconnect it must be
@@ -136,6 +141,10 @@ func (r *baseRegistration) getWithOmitRemote() bool {
 	return r.withOmitRemote
 }
 
+func (r *baseRegistration) getRowFilter() *RowFilter {
+	return r.rowFilter
+}
+
 func (r *baseRegistration) shouldUnregister() bool {
 	return r.shouldUnreg.Load()
 }
@@ -342,12 +351,15 @@ func (reg *registry) PublishToOverlapping(
 		log.Fatalf(ctx, "unexpected RangeFeedEvent variant: %v", t)
 	}
 
+	value, _ := event.GetValue().(*kvpb.RangeFeedValue)
 	reg.forOverlappingRegs(ctx, span, func(r registration) (bool, *kvpb.Error) {
 		// Don't publish events if they:
 		// 1. are equal to or less than the registration's starting timestamp, or
 		// 2. have OmitInRangefeeds = true and this registration has opted into filtering, or
-		// 3. have OmitRemote = true and this value is from a remote cluster.
-		if r.getCatchUpTimestamp().Less(minTS) && !(r.getWithFiltering() && valueMetadata.omitInRangefeeds) && (!r.getWithOmitRemote() || valueMetadata.originID == 0) {
+		// 3. have OmitRemote = true and this value is from a remote cluster, or
+		// 4. are values for rows which don't pass the registration's row filter.
+		if r.getCatchUpTimestamp().Less(minTS) && !(r.getWithFiltering() && valueMetadata.omitInRangefeeds) && (!r.getWithOmitRemote() || valueMetadata.originID == 0) &&
+			(value == nil || r.getRowFilter().Matches(value.Key, value.Value)) {
 			r.publish(ctx, event, alloc)
 		}
 		return false, nil
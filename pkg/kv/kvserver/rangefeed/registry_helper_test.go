@@ -361,6 +361,7 @@ func newTestRegistration(s *testStream, opts ...registrationOption) testRegistra
 			cfg.withDiff,
 			cfg.withFiltering,
 			cfg.withOmitRemote,
+			nil, /* rowFilter */
 			5,
 			false, /* blockWhenFull */
 			cfg.metrics,
@@ -376,6 +377,7 @@ func newTestRegistration(s *testStream, opts ...registrationOption) testRegistra
 			cfg.withDiff,
 			cfg.withFiltering,
 			cfg.withOmitRemote,
+			nil, /* rowFilter */
 			5,
 			cfg.metrics,
 			&testBufferedStream{Stream: s},
@@ -0,0 +1,218 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package rangefeed
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// RowFilter is a compiled kvpb.RangeFeedRowFilter. It is used by registrations
+// to drop value events for rows which the consumer isn't interested in, both
+// during catch-up scans and when publishing live events.
+//
+// The filter operates on the raw KV encoding of rows and must never drop an
+// event the consumer may need. Whenever an event can't be evaluated, it is
+// emitted.
+type RowFilter struct {
+	prefix     roachpb.Key
+	families   []uint32
+	conditions []rowCondition
+}
+
+// rowCondition is a compiled kvpb.RangeFeedRowFilter_Condition.
+type rowCondition struct {
+	familyID uint32
+	columnID uint32
+	op       kvpb.RangeFeedRowFilter_Op
+	datum    rowDatum
+}
+
+// rowDatum is a decoded INT, BYTES or BOOL value.
+type rowDatum struct {
+	typ encoding.Type
+	i   int64
+	b   []byte
+}
+
+// NewRowFilter compiles the provided filter. It returns nil if the filter is
+// nil or doesn't filter anything.
+func NewRowFilter(f *kvpb.RangeFeedRowFilter) (*RowFilter, error) {
+	if f == nil || (len(f.Families) == 0 && len(f.Conditions) == 0) {
+		return nil, nil
+	}
+	if len(f.Prefix) == 0 {
+		return nil, errors.Errorf("rangefeed row filter must specify a key prefix")
+	}
+	rf := &RowFilter{
+		prefix:     f.Prefix,
+		families:   f.Families,
+		conditions: make([]rowCondition, 0, len(f.Conditions)),
+	}
+	for _, c := range f.Conditions {
+		if _, ok := kvpb.RangeFeedRowFilter_Op_name[int32(c.Op)]; !ok {
+			return nil, errors.Errorf("unknown rangefeed row filter operator %d", c.Op)
+		}
+		d, err := decodeRowDatum(c.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding value of condition on column %d", c.ColumnID)
+		}
+		rf.conditions = append(rf.conditions, rowCondition{
+			familyID: c.FamilyID,
+			columnID: c.ColumnID,
+			op:       c.Op,
+			datum:    d,
+		})
+	}
+	return rf, nil
+}
+
+// decodeRowDatum decodes a single value encoded datum.
+func decodeRowDatum(b []byte) (rowDatum, error) {
+	_, _, _, typ, err := encoding.DecodeValueTag(b)
+	if err != nil {
+		return rowDatum{}, err
+	}
+	switch typ {
+	case encoding.Int:
+		_, i, err := encoding.DecodeIntValue(b)
+		return rowDatum{typ: typ, i: i}, err
+	case encoding.Bytes:
+		_, data, err := encoding.DecodeBytesValue(b)
+		return rowDatum{typ: typ, b: data}, err
+	case encoding.True, encoding.False:
+		_, v, err := encoding.DecodeBoolValue(b)
+		d := rowDatum{typ: encoding.True}
+		if v {
+			d.i = 1
+		}
+		return d, err
+	default:
+		return rowDatum{}, errors.Errorf("unsupported value type %s", typ)
+	}
+}
+
+// compare returns -1, 0 or 1 if d is respectively less than, equal to or
+// greater than o. ok is false if the datums aren't comparable.
+func (d rowDatum) compare(o rowDatum) (cmp int, ok bool) {
+	if d.typ != o.typ {
+		return 0, false
+	}
+	switch d.typ {
+	case encoding.Bytes:
+		return bytes.Compare(d.b, o.b), true
+	default:
+		switch {
+		case d.i < o.i:
+			return -1, true
+		case d.i > o.i:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+}
+
+// Matches returns false if the event for the provided key and value can be
+// dropped. It is safe to call on a nil RowFilter.
+func (f *RowFilter) Matches(key roachpb.Key, value roachpb.Value) bool {
+	if f == nil || !value.IsPresent() || !bytes.HasPrefix(key, f.prefix) {
+		// Deletions are always emitted, since the consumer may need to observe
+		// the removal of a row which previously matched.
+		return true
+	}
+	familyID, err := keys.DecodeFamilyKey(key)
+	if err != nil {
+		return true
+	}
+	if len(f.families) > 0 {
+		found := false
+		for _, id := range f.families {
+			if id == familyID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.conditions) == 0 {
+		return true
+	}
+	// Only tuple encoded family values can be evaluated. Single column families
+	// don't store the ID of their column.
+	tuple, err := value.GetTuple()
+	if err != nil {
+		return true
+	}
+	for i := range f.conditions {
+		c := &f.conditions[i]
+		if c.familyID != familyID {
+			continue
+		}
+		if !c.eval(tuple) {
+			return false
+		}
+	}
+	return true
+}
+
+// eval returns false if the tuple encoded family value definitely fails the
+// condition.
+func (c *rowCondition) eval(tuple []byte) bool {
+	var colID uint32
+	for len(tuple) > 0 {
+		_, _, colIDDelta, _, err := encoding.DecodeValueTag(tuple)
+		if err != nil {
+			return true
+		}
+		colID += colIDDelta
+		if colID > c.columnID {
+			// Columns are encoded in increasing order of their IDs. NULL columns
+			// aren't encoded at all, but the column may also be absent because it
+			// was dropped or rewritten by a schema change, so treat it as unknown.
+			return true
+		}
+		_, n, err := encoding.PeekValueLength(tuple)
+		if err != nil {
+			return true
+		}
+		if colID == c.columnID {
+			d, err := decodeRowDatum(tuple[:n])
+			if err != nil {
+				return true
+			}
+			cmp, ok := d.compare(c.datum)
+			if !ok {
+				return true
+			}
+			switch c.op {
+			case kvpb.RangeFeedRowFilter_EQ:
+				return cmp == 0
+			case kvpb.RangeFeedRowFilter_NE:
+				return cmp != 0
+			case kvpb.RangeFeedRowFilter_LT:
+				return cmp < 0
+			case kvpb.RangeFeedRowFilter_LE:
+				return cmp <= 0
+			case kvpb.RangeFeedRowFilter_GT:
+				return cmp > 0
+			case kvpb.RangeFeedRowFilter_GE:
+				return cmp >= 0
+			default:
+				return true
+			}
+		}
+		tuple = tuple[n:]
+	}
+	return true
+}
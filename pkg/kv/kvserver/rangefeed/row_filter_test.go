@@ -0,0 +1,219 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package rangefeed
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	prefix := keys.SystemSQLCodec.IndexPrefix(104, 1)
+	rowKey := func(pk int64, familyID uint32) roachpb.Key {
+		k := encoding.EncodeVarintAscending(append(roachpb.Key(nil), prefix...), pk)
+		return keys.MakeFamilyKey(k, familyID)
+	}
+	// Row values of family 0 have an INT column 2, a STRING column 3 and a
+	// BOOL column 4. Nil columns are NULL and thus omitted.
+	rowValue := func(i *int64, s *string, b *bool) roachpb.Value {
+		var tuple []byte
+		var lastColID uint32
+		if i != nil {
+			tuple = encoding.EncodeIntValue(tuple, 2-lastColID, *i)
+			lastColID = 2
+		}
+		if s != nil {
+			tuple = encoding.EncodeBytesValue(tuple, 3-lastColID, []byte(*s))
+			lastColID = 3
+		}
+		if b != nil {
+			tuple = encoding.EncodeBoolValue(tuple, 4-lastColID, *b)
+		}
+		var v roachpb.Value
+		v.SetTuple(tuple)
+		return v
+	}
+	intPtr := func(i int64) *int64 { return &i }
+	strPtr := func(s string) *string { return &s }
+	boolPtr := func(b bool) *bool { return &b }
+
+	intCond := func(op kvpb.RangeFeedRowFilter_Op, v int64) kvpb.RangeFeedRowFilter_Condition {
+		return kvpb.RangeFeedRowFilter_Condition{
+			ColumnID: 2, Op: op, Value: encoding.EncodeIntValue(nil, encoding.NoColumnID, v),
+		}
+	}
+	strCond := func(op kvpb.RangeFeedRowFilter_Op, v string) kvpb.RangeFeedRowFilter_Condition {
+		return kvpb.RangeFeedRowFilter_Condition{
+			ColumnID: 3, Op: op, Value: encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte(v)),
+		}
+	}
+	boolCond := func(v bool) kvpb.RangeFeedRowFilter_Condition {
+		return kvpb.RangeFeedRowFilter_Condition{
+			ColumnID: 4, Op: kvpb.RangeFeedRowFilter_EQ, Value: encoding.EncodeBoolValue(nil, encoding.NoColumnID, v),
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		families   []uint32
+		conditions []kvpb.RangeFeedRowFilter_Condition
+		key        roachpb.Key
+		value      roachpb.Value
+		expected   bool
+	}{
+		{
+			name:       "int eq match",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        rowKey(1, 0),
+			value:      rowValue(intPtr(5), nil, nil),
+			expected:   true,
+		},
+		{
+			name:       "int eq mismatch",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        rowKey(1, 0),
+			value:      rowValue(intPtr(6), nil, nil),
+			expected:   false,
+		},
+		{
+			name:       "int lt",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_LT, 5)},
+			key:        rowKey(1, 0),
+			value:      rowValue(intPtr(-3), strPtr("a"), nil),
+			expected:   true,
+		},
+		{
+			name:       "int ge mismatch",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_GE, 5)},
+			key:        rowKey(1, 0),
+			value:      rowValue(intPtr(4), strPtr("a"), boolPtr(true)),
+			expected:   false,
+		},
+		{
+			name:       "string ne",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{strCond(kvpb.RangeFeedRowFilter_NE, "a")},
+			key:        rowKey(1, 0),
+			value:      rowValue(intPtr(1), strPtr("a"), nil),
+			expected:   false,
+		},
+		{
+			name:       "string gt",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{strCond(kvpb.RangeFeedRowFilter_GT, "abc")},
+			key:        rowKey(1, 0),
+			value:      rowValue(nil, strPtr("abd"), boolPtr(false)),
+			expected:   true,
+		},
+		{
+			name:       "bool",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{boolCond(true)},
+			key:        rowKey(1, 0),
+			value:      rowValue(intPtr(1), strPtr("a"), boolPtr(false)),
+			expected:   false,
+		},
+		{
+			name: "conjunction",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{
+				intCond(kvpb.RangeFeedRowFilter_GT, 0), strCond(kvpb.RangeFeedRowFilter_EQ, "b"),
+			},
+			key:      rowKey(1, 0),
+			value:    rowValue(intPtr(1), strPtr("a"), nil),
+			expected: false,
+		},
+		{
+			name:       "null column is unknown",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        rowKey(1, 0),
+			value:      rowValue(nil, strPtr("a"), nil),
+			expected:   true,
+		},
+		{
+			name:       "type mismatch is unknown",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{{ColumnID: 3, Value: encoding.EncodeIntValue(nil, encoding.NoColumnID, 1)}},
+			key:        rowKey(1, 0),
+			value:      rowValue(nil, strPtr("a"), nil),
+			expected:   true,
+		},
+		{
+			name:       "deletion",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        rowKey(1, 0),
+			expected:   true,
+		},
+		{
+			name:       "key outside prefix",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        keys.MakeFamilyKey(keys.SystemSQLCodec.IndexPrefix(105, 1), 0),
+			value:      rowValue(intPtr(6), nil, nil),
+			expected:   true,
+		},
+		{
+			name:       "condition on other family",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        rowKey(1, 1),
+			value:      rowValue(intPtr(6), nil, nil),
+			expected:   true,
+		},
+		{
+			name:     "projected family",
+			families: []uint32{1},
+			key:      rowKey(1, 1),
+			value:    rowValue(intPtr(6), nil, nil),
+			expected: true,
+		},
+		{
+			name:     "unprojected family",
+			families: []uint32{1},
+			key:      rowKey(1, 0),
+			value:    rowValue(intPtr(6), nil, nil),
+			expected: false,
+		},
+		{
+			name:       "non-tuple value",
+			conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+			key:        rowKey(1, 0),
+			value:      roachpb.MakeValueFromString("foo"),
+			expected:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewRowFilter(&kvpb.RangeFeedRowFilter{
+				Prefix:     prefix,
+				Families:   tc.families,
+				Conditions: tc.conditions,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, f)
+			require.Equal(t, tc.expected, f.Matches(tc.key, tc.value))
+		})
+	}
+
+	t.Run("nil filter", func(t *testing.T) {
+		f, err := NewRowFilter(&kvpb.RangeFeedRowFilter{Prefix: prefix})
+		require.NoError(t, err)
+		require.Nil(t, f)
+		require.True(t, f.Matches(rowKey(1, 0), rowValue(intPtr(1), nil, nil)))
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := NewRowFilter(&kvpb.RangeFeedRowFilter{
+			Conditions: []kvpb.RangeFeedRowFilter_Condition{intCond(kvpb.RangeFeedRowFilter_EQ, 5)},
+		})
+		require.Error(t, err)
+		_, err = NewRowFilter(&kvpb.RangeFeedRowFilter{
+			Prefix:     prefix,
+			Conditions: []kvpb.RangeFeedRowFilter_Condition{{ColumnID: 2, Value: encoding.EncodeFloatValue(nil, encoding.NoColumnID, 1.5)}},
+		})
+		require.Error(t, err)
+	})
+}
@@ -222,7 +222,7 @@ func TestStreamManagerErrorHandling(t *testing.T) {
 				defer stopper.Stop(ctx)
 				stream := sm.NewStream(sID, rID)
 				registered, d, _ := p.Register(ctx, h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-					false /* withDiff */, false /* withFiltering */, false, /* withOmitRemote */
+					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil, /* rowFilter */
 					stream)
 				require.True(t, registered)
 				go p.StopWithErr(disconnectErr)
@@ -236,7 +236,7 @@ func TestStreamManagerErrorHandling(t *testing.T) {
 			p, h, stopper := newTestProcessor(t, withRangefeedTestType(rt))
 			defer stopper.Stop(ctx)
 			registered, d, _ := p.Register(ctx, h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-				false /* withDiff */, false /* withFiltering */, false, /* withOmitRemote */
+				false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil, /* rowFilter */
 				stream)
 			require.True(t, registered)
 			sm.AddStream(sID, d)
@@ -251,7 +251,7 @@ func TestStreamManagerErrorHandling(t *testing.T) {
 			p, h, stopper := newTestProcessor(t, withRangefeedTestType(rt))
 			defer stopper.Stop(ctx)
 			registered, d, _ := p.Register(ctx, h.span, hlc.Timestamp{}, nil, /* catchUpIter */
-				false /* withDiff */, false /* withFiltering */, false, /* withOmitRemote */
+				false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil, /* rowFilter */
 				stream)
 			require.True(t, registered)
 			sm.AddStream(sID, d)
@@ -97,6 +97,7 @@ func newUnbufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *RowFilter,
 	bufferSz int,
 	metrics *Metrics,
 	stream BufferedStream,
@@ -110,6 +111,7 @@ func newUnbufferedRegistration(
 			withDiff:               withDiff,
 			withFiltering:          withFiltering,
 			withOmitRemote:         withOmitRemote,
+			rowFilter:              rowFilter,
 			removeRegFromProcessor: removeRegFromProcessor,
 		},
 		metrics: metrics,
@@ -359,7 +361,7 @@ func (ubr *unbufferedRegistration) maybeRunCatchUpScan(ctx context.Context) erro
 	}()
 
 	return catchUpIter.CatchUpScan(ctx, ubr.stream.SendUnbuffered, ubr.withDiff, ubr.withFiltering,
-		ubr.withOmitRemote)
+		ubr.withOmitRemote, ubr.rowFilter)
 }
 
 // Used for testing only.
@@ -22,12 +22,14 @@ import (
 // LBRebalancingObjective controls the objective of load based rebalancing.
 // This is used to both (1) define the types of load considered when
 // determining how balanced the cluster is, and (2) select actions that improve
-// balancing the given objective. Currently there are only two possible
+// balancing the given objective. Currently there are three possible
 // objectives:
 //   - qps which is the original default setting and looks at the number of batch
 //     requests on a range and store.
 //   - cpu which is added in 23.1 and looks at the cpu usage of a range and
 //     store.
+//   - write_bytes which looks at the bytes written to disk by a range and
+//     store.
 type LBRebalancingObjective int64
 
 const (
@@ -85,13 +87,36 @@ const (
 	// process cpu approach. The sum of impact over available actions is equal
 	// to the store value being balanced, similar to LBRebalancingQueries.
 	LBRebalancingCPU
+
+	// LBRebalancingWriteBytes is a rebalance objective that aims to balance the
+	// disk write bandwidth used by stores. The write bytes per-store is
+	// calculated as the sum of every replica's write bytes on the store. The
+	// write bytes per-replica is calculated as the average number of bytes
+	// written to disk per second when applying raft commands, including
+	// ingested SSTs, over the last 30 minutes, or replica lifetime, whichever
+	// is shorter. Unlike QPS, the write bytes are recorded on every replica of
+	// a range, not only on the leaseholder.
+	//
+	// When searching for rebalance actions, this objective estimates the
+	// impact of replica rebalancing by using the write bytes of the replica
+	// involved. Lease transfers have no impact, since every replica writes the
+	// same bytes to disk regardless of which holds the lease. Stores are not
+	// chosen as rebalance targets when receiving the replica would use more
+	// than a fraction of their provisioned disk bandwidth, see
+	// kv.allocator.rebalance_to_max_write_bandwidth_utilization_threshold.
+	//
+	// This objective is intended for write heavy workloads, such as bulk
+	// ingestion, where disk bandwidth rather than cpu is the saturated
+	// resource.
+	LBRebalancingWriteBytes
 )
 
 // LoadBasedRebalancingObjectiveMap maps the LoadBasedRebalancingObjective enum
 // value to a string.
 var LoadBasedRebalancingObjectiveMap = map[LBRebalancingObjective]string{
-	LBRebalancingQueries: "qps",
-	LBRebalancingCPU:     "cpu",
+	LBRebalancingQueries:    "qps",
+	LBRebalancingCPU:        "cpu",
+	LBRebalancingWriteBytes: "write_bytes",
 }
 
 func (lbro LBRebalancingObjective) String() string {
@@ -105,7 +130,9 @@ var LoadBasedRebalancingObjective = settings.RegisterEnumSetting(
 	"kv.allocator.load_based_rebalancing.objective",
 	"what objective does the cluster use to rebalance; if set to `qps` "+
 		"the cluster will attempt to balance qps among stores, if set to "+
-		"`cpu` the cluster will attempt to balance cpu usage among stores",
+		"`cpu` the cluster will attempt to balance cpu usage among stores, if set "+
+		"to `write_bytes` the cluster will attempt to balance disk write bandwidth "+
+		"usage among stores",
 	"cpu",
 	LoadBasedRebalancingObjectiveMap,
 	settings.WithPublic)
@@ -125,6 +152,8 @@ func (d LBRebalancingObjective) ToDimension() load.Dimension {
 		return load.Queries
 	case LBRebalancingCPU:
 		return load.CPU
+	case LBRebalancingWriteBytes:
+		return load.WriteBytes
 	default:
 		panic("unknown dimension")
 	}
@@ -261,6 +290,17 @@ func ResolveLBRebalancingObjective(
 	if set == LBRebalancingQueries {
 		return LBRebalancingQueries
 	}
+	// Nodes prior to V25_1_WriteBytesLoadDimension don't gossip the write
+	// bytes of their stores, nor do they know how to balance them. Fall back
+	// to cpu balancing, or qps should cpu also be unsupported, until every
+	// node is upgraded.
+	if set == LBRebalancingWriteBytes {
+		if st.Version.IsActive(ctx, clusterversion.V25_1_WriteBytesLoadDimension) {
+			return LBRebalancingWriteBytes
+		}
+		log.Infof(ctx, "write bytes unsupported by the cluster version, reverting to cpu balance objective")
+		set = LBRebalancingCPU
+	}
 	// When the cpu timekeeping utility is unsupported on this aarch, the cpu
 	// usage cannot be gathered. Fall back to QPS balancing.
 	if !grunning.Supported() {
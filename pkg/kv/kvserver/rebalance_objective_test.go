@@ -9,6 +9,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -115,6 +116,12 @@ func TestLoadBasedRebalancingObjective(t *testing.T) {
 			ResolveLBRebalancingObjective(ctx, st, gossipStoreDescProvider.GetStores()),
 		)
 
+		LoadBasedRebalancingObjective.Override(ctx, &st.SV, LBRebalancingWriteBytes)
+		require.Equal(t,
+			LBRebalancingWriteBytes,
+			ResolveLBRebalancingObjective(ctx, st, gossipStoreDescProvider.GetStores()),
+		)
+
 		LoadBasedRebalancingObjective.Override(ctx, &st.SV, LBRebalancingQueries)
 		require.Equal(t,
 			LBRebalancingQueries,
@@ -122,6 +129,29 @@ func TestLoadBasedRebalancingObjective(t *testing.T) {
 		)
 	})
 
+	t.Run("write bytes unsupported prior to version", func(t *testing.T) {
+		st := cluster.MakeTestingClusterSettingsWithVersions(
+			(clusterversion.V25_1_WriteBytesLoadDimension - 1).Version(),
+			clusterversion.MinSupported.Version(),
+			true, /* initializeVersion */
+		)
+		// The cluster hasn't upgraded to a version where every node gossips
+		// write bytes, so the objective should revert to LBRebalancingCPU, or
+		// to LBRebalancingQueries if a store doesn't support cpu.
+		gossipStoreDescProvider := testMakeProviderNotifier(allPositiveCPUMap)
+		LoadBasedRebalancingObjective.Override(ctx, &st.SV, LBRebalancingWriteBytes)
+		require.Equal(t,
+			LBRebalancingCPU,
+			ResolveLBRebalancingObjective(ctx, st, gossipStoreDescProvider.GetStores()),
+		)
+
+		gossipStoreDescProvider = testMakeProviderNotifier(oneNegativeCPUMap)
+		require.Equal(t,
+			LBRebalancingQueries,
+			ResolveLBRebalancingObjective(ctx, st, gossipStoreDescProvider.GetStores()),
+		)
+	})
+
 	t.Run("remote node set cpu to -1, signalling no support", func(t *testing.T) {
 		st := cluster.MakeTestingClusterSettings()
 		// The store with StoreID 3 has a -1 CPUPerSecond value, this indicates
@@ -141,6 +171,14 @@ func TestLoadBasedRebalancingObjective(t *testing.T) {
 			ResolveLBRebalancingObjective(ctx, st, gossipStoreDescProvider.GetStores()),
 		)
 
+		// Write bytes don't depend on cpu attribution, so the objective is
+		// unaffected.
+		LoadBasedRebalancingObjective.Override(ctx, &st.SV, LBRebalancingWriteBytes)
+		require.Equal(t,
+			LBRebalancingWriteBytes,
+			ResolveLBRebalancingObjective(ctx, st, gossipStoreDescProvider.GetStores()),
+		)
+
 		LoadBasedRebalancingObjective.Override(ctx, &st.SV, LBRebalancingQueries)
 		require.Equal(t,
 			LBRebalancingQueries,
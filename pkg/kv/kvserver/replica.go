@@ -2548,6 +2548,7 @@ func (r *Replica) RangeUsageInfo() allocator.RangeUsageInfo {
 		ReadBytesPerSecond:       loadStats.ReadBytesPerSecond,
 		RaftCPUNanosPerSecond:    loadStats.RaftCPUNanosPerSecond,
 		RequestCPUNanosPerSecond: loadStats.RequestCPUNanosPerSecond,
+		RaftWriteBytesPerSecond:  loadStats.RaftWriteBytesPerSecond,
 		RequestsPerSecond:        loadStats.RequestsPerSecond,
 		RequestLocality: &allocator.RangeRequestLocalityInfo{
 			Counts:   localityInfo.LocalityCounts,
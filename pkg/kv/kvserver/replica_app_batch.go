@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvstorage"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -136,8 +137,15 @@ func (b *replicaAppBatch) Stage(
 		return nil, err
 	}
 
-	// Stage the command's write batch in the application batch.
-	if err := b.ab.addWriteBatch(ctx, b.batch, cmd); err != nil {
+	// Stage the command's write batch in the application batch. Witnesses
+	// only apply the writes to range-local keys, as they don't store the
+	// range's user data.
+	witness := b.isWitness()
+	if witness {
+		if err := b.ab.addWitnessWriteBatch(ctx, b.batch, cmd); err != nil {
+			return nil, err
+		}
+	} else if err := b.ab.addWriteBatch(ctx, b.batch, cmd); err != nil {
 		return nil, err
 	}
 
@@ -150,6 +158,7 @@ func (b *replicaAppBatch) Stage(
 		eng:         b.r.store.TODOEngine(),
 		sideloaded:  b.r.raftMu.sideloaded,
 		bulkLimiter: b.r.store.limiters.BulkIOWriteRate,
+		witness:     witness,
 	}); err != nil {
 		return nil, err
 	}
@@ -189,6 +198,19 @@ func changeRemovesStore(
 	return !existsInChange
 }
 
+// changePromotesStoreToWitness returns true if the change promotes the replica
+// on storeID to a witness.
+func changePromotesStoreToWitness(
+	desc *roachpb.RangeDescriptor, change *kvserverpb.ChangeReplicas, storeID roachpb.StoreID,
+) bool {
+	prev, ok := desc.GetReplicaDescriptor(storeID)
+	if !ok || prev.IsWitness() {
+		return false
+	}
+	next, ok := change.Desc.GetReplicaDescriptor(storeID)
+	return ok && next.IsWitness()
+}
+
 // runPreAddTriggersReplicaOnly is like (appBatch).runPreAddTriggers (and is
 // called right after it), except that it must only contain ephemeral side
 // effects that have no influence on durable state. It is not invoked during
@@ -209,6 +231,13 @@ func (b *replicaAppBatch) runPreAddTriggersReplicaOnly(
 	return nil
 }
 
+// isWitness returns whether the replica is a witness according to the
+// batch's view of the range descriptor.
+func (b *replicaAppBatch) isWitness() bool {
+	repDesc, ok := b.state.Desc.GetReplicaDescriptorByID(b.r.replicaID)
+	return ok && repDesc.IsWitness()
+}
+
 // runPostAddTriggersReplicaOnly runs any triggers that must fire
 // before a command is applied to the state machine but after the command is
 // staged in the replicaAppBatch's write batch.
@@ -525,6 +554,23 @@ func (b *replicaAppBatch) runPostAddTriggersReplicaOnly(
 		}
 	}
 
+	// Detect if this command promotes us to a witness. While we were a learner,
+	// we may have applied writes to user keys, or received a snapshot with user
+	// data from the raft snapshot queue (see lockLearnerSnapshot). Witnesses
+	// don't store the range's user data, so we clear it along with the
+	// promotion.
+	if change := res.ChangeReplicas; change != nil && !b.changeRemovesReplica &&
+		changePromotesStoreToWitness(b.state.Desc, change, b.r.store.StoreID()) {
+		for _, span := range rditer.MakeReplicatedKeySpansUserOnly(b.state.Desc) {
+			if err := storage.ClearRangeWithHeuristic(
+				ctx, b.batch, b.batch, span.Key, span.EndKey,
+				kvstorage.ClearRangeThresholdPointKeys, kvstorage.ClearRangeThresholdRangeKeys,
+			); err != nil {
+				return errors.Wrapf(err, "unable to clear user data of witness")
+			}
+		}
+	}
+
 	// Provide the command's corresponding logical operations to the Replica's
 	// rangefeed. Only do so if the WriteBatch is non-nil, in which case the
 	// rangefeed requires there to be a corresponding logical operation log or
@@ -685,6 +731,8 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 
 	// Record the number of keys written to the replica.
 	b.r.loadStats.RecordWriteKeys(float64(b.ab.numMutations))
+	// Record the number of bytes written to disk by the replica.
+	b.r.loadStats.RecordRaftWriteBytes(float64(b.ab.numMutationBytes))
 
 	now := timeutil.Now()
 	if needsSplitBySize && r.splitQueueThrottle.ShouldProcess(now) {
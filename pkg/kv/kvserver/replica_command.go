@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -790,11 +791,15 @@ func (r *Replica) AdminMerge(
 		// queues should fix things up quickly).
 		lReplicas, rReplicas := origLeftDesc.Replicas(), rightDesc.Replicas()
 
-		if len(lReplicas.VoterFullAndNonVoterDescriptors()) != len(lReplicas.Descriptors()) {
+		// Witnesses may be merged, as long as they are collocated with the
+		// witnesses of the other range.
+		if len(lReplicas.VoterFullAndNonVoterDescriptors())+len(lReplicas.WitnessDescriptors()) !=
+			len(lReplicas.Descriptors()) {
 			return errors.Errorf("cannot merge ranges when lhs is in a joint state or has learners: %s",
 				lReplicas)
 		}
-		if len(rReplicas.VoterFullAndNonVoterDescriptors()) != len(rReplicas.Descriptors()) {
+		if len(rReplicas.VoterFullAndNonVoterDescriptors())+len(rReplicas.WitnessDescriptors()) !=
+			len(rReplicas.Descriptors()) {
 			return errors.Errorf("cannot merge ranges when rhs is in a joint state or has learners: %s",
 				rReplicas)
 		}
@@ -1226,6 +1231,14 @@ func (r *Replica) changeReplicasImpl(
 		return nil, errors.Mark(err, errMarkInvalidReplicationChange)
 	}
 	targets := SynthesizeTargetsByChangeType(chgs)
+	// Nodes prior to V25_1_WitnessReplicas can't handle range descriptors
+	// containing witnesses.
+	if len(targets.WitnessAdditions) > 0 &&
+		!r.ClusterSettings().Version.IsActive(ctx, clusterversion.V25_1_WitnessReplicas) {
+		return nil, errors.Mark(
+			errors.Errorf("witness replicas unsupported in mixed-version cluster"),
+			errMarkInvalidReplicationChange)
+	}
 
 	// NB: As of the time of this writing,`AdminRelocateRange` will only execute
 	// replication changes one by one. Thus, the order in which we execute the
@@ -1236,14 +1249,16 @@ func (r *Replica) changeReplicasImpl(
 	// 1. Promotions / demotions / swaps between voters and non-voters
 	// 2. Voter additions
 	// 3. Voter removals
-	// 4. Non-voter additions
-	// 5. Non-voter removals
+	// 4. Witness additions
+	// 5. Witness removals
+	// 6. Non-voter additions
+	// 7. Non-voter removals
 	//
 	// This order is meant to be symmetric with how the allocator prioritizes
 	// these actions. Broadly speaking, we first want to add a missing voter (and
 	// promoting an existing non-voter, or swapping with one, is the fastest way
-	// to do that). Then, we consider rebalancing/removing voters. Finally, we
-	// handle non-voter additions & removals.
+	// to do that). Then, we consider rebalancing/removing voters and witnesses.
+	// Finally, we handle non-voter additions & removals.
 
 	// We perform promotions of non-voting replicas to voting replicas, and
 	// likewise, demotions of voting replicas to non-voting replicas. If both
@@ -1312,6 +1327,42 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	// Like voters, witnesses are first added as LEARNER replicas, which are
+	// promoted once they have received their initial snapshot. Witnesses are
+	// added one at a time, so that each promotion is a simple config change.
+	for _, add := range targets.WitnessAdditions {
+		desc, err = r.initializeRaftLearners(
+			ctx, desc, senderName, senderQueuePriority, reason, details,
+			[]roachpb.ReplicationTarget{add}, roachpb.WITNESS,
+		)
+		if err != nil {
+			return nil, err
+		}
+		desc, err = r.execReplicationChangesForWitness(
+			ctx, desc, reason, details, internalChangeTypePromoteLearnerToWitness, add,
+		)
+		if err != nil {
+			if fn := r.store.cfg.TestingKnobs.ReplicaAddSkipLearnerRollback; fn != nil && fn() {
+				return nil, err
+			}
+			// Don't leave a learner replica lying around if we didn't succeed in
+			// promoting it to a witness.
+			log.Infof(ctx, "could not promote %v to witness, rolling back: %v", add, err)
+			r.tryRollbackRaftLearner(ctx, r.Desc(), add, reason, details)
+			return nil, err
+		}
+	}
+	// Witnesses never hold the lease, so they're removed directly, one at a
+	// time, without being demoted first.
+	for _, rem := range targets.WitnessRemovals {
+		desc, err = r.execReplicationChangesForWitness(
+			ctx, desc, reason, details, internalChangeTypeRemoveWitness, rem,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if adds := targets.NonVoterAdditions; len(adds) > 0 {
 		// Add all non-voters and send them initial snapshots since some callers of
 		// `AdminChangeReplicas` (notably the mergeQueue, via `AdminRelocateRange`)
@@ -1365,6 +1416,7 @@ type TargetsForReplicationChanges struct {
 	VoterDemotions, NonVoterPromotions  []roachpb.ReplicationTarget
 	VoterAdditions, VoterRemovals       []roachpb.ReplicationTarget
 	NonVoterAdditions, NonVoterRemovals []roachpb.ReplicationTarget
+	WitnessAdditions, WitnessRemovals   []roachpb.ReplicationTarget
 }
 
 // SynthesizeTargetsByChangeType groups replication changes in the
@@ -1390,6 +1442,8 @@ func SynthesizeTargetsByChangeType(
 	result.VoterRemovals = subtractTargets(chgs.VoterRemovals(), chgs.NonVoterAdditions())
 	result.NonVoterAdditions = subtractTargets(chgs.NonVoterAdditions(), chgs.VoterRemovals())
 	result.NonVoterRemovals = subtractTargets(chgs.NonVoterRemovals(), chgs.VoterAdditions())
+	result.WitnessAdditions = chgs.WitnessAdditions()
+	result.WitnessRemovals = chgs.WitnessRemovals()
 
 	return result
 }
@@ -1649,6 +1703,11 @@ func validateAdditionsPerStore(
 					return errors.AssertionFailedf(
 						"trying to add a non-voter to a store that already has a %s", t)
 				}
+			case roachpb.WITNESS:
+				// Witnesses don't have the range's user data, so they can't be
+				// promoted or demoted to any other type of replica.
+				return errors.AssertionFailedf(
+					"trying to add(%+v) to a store that already has a %s", chg, t)
 			default:
 				return errors.AssertionFailedf("store(%d) being added to already contains a"+
 					" replica of an unexpected type: %s", storeID, t)
@@ -1683,6 +1742,11 @@ func validateRemovals(desc *roachpb.RangeDescriptor, chgsByStoreID changesByStor
 					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
 						" expectation for change: %+v", t, chg)
 				}
+			case roachpb.WITNESS:
+				if chg.ChangeType != roachpb.REMOVE_WITNESS {
+					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
+						" expectation for change: %+v", t, chg)
+				}
 			default:
 				return errors.AssertionFailedf("unexpected replica type for removal %+v: %s", chg, t)
 			}
@@ -1885,6 +1949,10 @@ func getChangesByNodeID(chgs kvpb.ReplicationChanges) changesByNodeID {
 // that snapshot. Otherwise, if we get any errors trying to add or upreplicate
 // any of these learners, this function will clean up after itself by rolling all
 // of them back.
+//
+// If replicaType is WITNESS, LEARNERs are added and sent a snapshot of only the
+// range-local replicated state. The caller is responsible for promoting them to
+// witnesses.
 func (r *Replica) initializeRaftLearners(
 	ctx context.Context,
 	desc *roachpb.RangeDescriptor,
@@ -1896,11 +1964,19 @@ func (r *Replica) initializeRaftLearners(
 	replicaType roachpb.ReplicaType,
 ) (afterDesc *roachpb.RangeDescriptor, err error) {
 	var iChangeType internalChangeType
+	// addedType is the type of the replicas added by the config change.
+	addedType := replicaType
 	switch replicaType {
 	case roachpb.LEARNER:
 		iChangeType = internalChangeTypeAddLearner
 	case roachpb.NON_VOTER:
 		iChangeType = internalChangeTypeAddNonVoter
+	case roachpb.WITNESS:
+		// Witnesses are added as learners, and promoted by the caller once
+		// they've received their initial snapshot. The snapshot only contains
+		// the range-local replicated state.
+		iChangeType = internalChangeTypeAddLearner
+		addedType = roachpb.LEARNER
 	default:
 		log.Fatalf(ctx, "unexpected replicaType %s", replicaType)
 	}
@@ -2012,7 +2088,7 @@ func (r *Replica) initializeRaftLearners(
 			return nil, errors.Errorf("programming error: replica %v not found in %v", target, desc)
 		}
 
-		if rDesc.Type != replicaType {
+		if rDesc.Type != addedType {
 			return nil, errors.Errorf("programming error: cannot promote replica of type %s", rDesc.Type)
 		}
 
@@ -2036,7 +2112,9 @@ func (r *Replica) initializeRaftLearners(
 		// orphaned learner. Second, this tickled some bugs in etcd/raft around
 		// switching between StateSnapshot and StateProbe. Even if we worked through
 		// these, it would be susceptible to future similar issues.
-		if err := r.sendSnapshotUsingDelegate(ctx, rDesc, senderName, senderQueuePriority); err != nil {
+		if err := r.sendSnapshotUsingDelegate(
+			ctx, rDesc, replicaType == roachpb.WITNESS, senderName, senderQueuePriority,
+		); err != nil {
 			return nil, err
 		}
 	}
@@ -2134,6 +2212,29 @@ func (r *Replica) execReplicationChangesForVoters(
 	return desc, err
 }
 
+// execReplicationChangesForWitness executes a single change of the given type
+// for the witness identified by the target. The change is always a simple
+// config change.
+func (r *Replica) execReplicationChangesForWitness(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	reason kvserverpb.RangeLogEventReason,
+	details string,
+	typ internalChangeType,
+	target roachpb.ReplicationTarget,
+) (*roachpb.RangeDescriptor, error) {
+	iChgs := []internalReplicationChange{{target: target, typ: typ}}
+	return execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs, changeReplicasTxnArgs{
+		db:                  r.store.DB(),
+		liveAndDeadReplicas: r.store.cfg.StorePool.LiveAndDeadReplicas,
+		logChange:           r.store.logChange,
+		// NB: there is no incoming or outgoing witness replica type, so
+		// witnesses can't be changed in a joint config even if forced by
+		// testing knobs.
+		testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+	})
+}
+
 // tryRollbackRaftLearner attempts to remove a learner specified by the target.
 // If no such learner is found in the descriptor (including when it is a voter
 // instead), no action is taken. Otherwise, a single time-limited best-effort
@@ -2227,6 +2328,13 @@ const (
 	// https://github.com/cockroachdb/cockroach/pull/40268
 	internalChangeTypeRemoveLearner
 	internalChangeTypeRemoveNonVoter
+	// internalChangeTypePromoteLearnerToWitness promotes a learner, which has
+	// received its initial snapshot, to a witness. Since there is no incoming
+	// witness replica type, this is always a simple config change.
+	internalChangeTypePromoteLearnerToWitness
+	// internalChangeTypeRemoveWitness removes a witness. Witnesses never hold
+	// the lease, so they can be removed directly without being demoted first.
+	internalChangeTypeRemoveWitness
 )
 
 // internalReplicationChange is a replication target together with an internal
@@ -2280,6 +2388,16 @@ func prepareChangeReplicasTrigger(
 			case internalChangeTypeAddNonVoter:
 				added = append(added,
 					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.NON_VOTER))
+			case internalChangeTypePromoteLearnerToWitness:
+				if useJoint {
+					return nil, errors.AssertionFailedf("witnesses can't be promoted in a joint config")
+				}
+				rDesc, prevTyp, ok := updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.WITNESS)
+				if !ok || prevTyp != roachpb.LEARNER {
+					return nil, errors.Errorf("cannot promote target %v which is missing as LEARNER",
+						chg.target)
+				}
+				added = append(added, rDesc)
 			case internalChangeTypePromoteLearner:
 				typ := roachpb.VOTER_FULL
 				if useJoint {
@@ -2312,6 +2430,16 @@ func prepareChangeReplicasTrigger(
 						prevTyp, chg.target)
 				}
 				removed = append(removed, rDesc)
+			case internalChangeTypeRemoveWitness:
+				rDesc, ok := updatedDesc.RemoveReplica(chg.target.NodeID, chg.target.StoreID)
+				if !ok {
+					return nil, errors.Errorf("target %v not found", chg.target)
+				}
+				if prevTyp := rDesc.Type; prevTyp != roachpb.WITNESS {
+					return nil, errors.Errorf("cannot remove %s target %v, not a WITNESS",
+						prevTyp, chg.target)
+				}
+				removed = append(removed, rDesc)
 			case internalChangeTypeDemoteVoterToLearner:
 				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
 				if !ok {
@@ -2700,16 +2828,21 @@ func recordRangeEventsInLog(
 ) error {
 	for _, repDesc := range repDescs {
 		isNonVoter := repDesc.Type == roachpb.NON_VOTER
+		isWitness := repDesc.Type == roachpb.WITNESS
 		var typ roachpb.ReplicaChangeType
 		if added {
 			typ = roachpb.ADD_VOTER
 			if isNonVoter {
 				typ = roachpb.ADD_NON_VOTER
+			} else if isWitness {
+				typ = roachpb.ADD_WITNESS
 			}
 		} else {
 			typ = roachpb.REMOVE_VOTER
 			if isNonVoter {
 				typ = roachpb.REMOVE_NON_VOTER
+			} else if isWitness {
+				typ = roachpb.REMOVE_WITNESS
 			}
 		}
 		if err := logChange(
@@ -2735,6 +2868,23 @@ func (r *Replica) getSenderReplicas(
 		return nil, err
 	}
 
+	// A witness doesn't store the range's user data, so it can only send
+	// snapshots to other witnesses. Otherwise, one of the replicas that do
+	// store the data has to send the snapshot on its behalf.
+	if coordinator.IsWitness() && !recipient.IsWitness() {
+		storePool := r.store.cfg.StorePool
+		candidates := r.Desc().Replicas().Filter(
+			func(rDesc roachpb.ReplicaDescriptor) bool {
+				return rDesc.ReplicaID != recipient.ReplicaID && storePool.IsStoreHealthy(rDesc.StoreID)
+			},
+		).VoterAndNonVoterDescriptors()
+		if len(candidates) == 0 {
+			return nil, errors.Errorf(
+				"no replica available to send snapshot to %s on behalf of witness %s", recipient, coordinator)
+		}
+		return candidates, nil
+	}
+
 	// Check follower snapshots, if zero just self-delegate.
 	numFollowers := int(NumDelegateLimit.Get(&r.ClusterSettings().SV))
 	if numFollowers == 0 {
@@ -2947,9 +3097,14 @@ func (r *Replica) getSenderReplicas(
 // callers of `shouldAcceptSnapshotData` return an error so that we no longer
 // have to worry about racing with a second snapshot. See the comment on
 // ReplicaPlaceholder for details.
+//
+// witness is set if the recipient is, or is about to be promoted to, a
+// witness, in which case the snapshot only contains the range-local
+// replicated state.
 func (r *Replica) sendSnapshotUsingDelegate(
 	ctx context.Context,
 	recipient roachpb.ReplicaDescriptor,
+	witness bool,
 	senderQueueName kvserverpb.SnapshotRequest_QueueName,
 	senderQueuePriority float64,
 ) (retErr error) {
@@ -3013,6 +3168,7 @@ func (r *Replica) sendSnapshotUsingDelegate(
 		DescriptorGeneration: r.Desc().Generation,
 		QueueOnDelegateLen:   MaxQueueOnDelegateLimit.Get(&r.ClusterSettings().SV),
 		SnapId:               snapUUID,
+		Witness:              witness,
 	}
 
 	// Get the list of senders in order.
@@ -3307,13 +3463,21 @@ func (r *Replica) followerSendSnapshot(
 	// a snapshot for a non-system range. This allows us to send metadata of
 	// sstables in shared storage as opposed to streaming their contents. Keys
 	// in higher levels of the LSM are still streamed in the snapshot.
+	//
+	// Witnesses don't store the range's user data, so neither shared nor
+	// external replication is used for them.
 	nonSystemRange := snap.State.Desc.StartKey.AsRawKey().Compare(keys.TableDataMin) >= 0
-	sharedReplicate := r.store.cfg.SharedStorageEnabled && nonSystemRange
+	toWitness := req.Witness
+	sharedReplicate := r.store.cfg.SharedStorageEnabled && nonSystemRange && !toWitness
 
 	// Use external replication if we aren't using shared
 	// replication, are dealing with a non-system range, are on at
 	// least 24.1, and our store has external files.
-	externalReplicate := !sharedReplicate && nonSystemRange &&
+	//
+	// Stores that created cold tiered objects delete them once they stop
+	// referencing them, so they must not hand them out to other stores.
+	externalReplicate := !sharedReplicate && nonSystemRange && !toWitness &&
+		!r.store.coldTieredObjects.hasTracked() &&
 		externalFileSnapshotting.Get(&r.store.ClusterSettings().SV)
 	if externalReplicate {
 		start := snap.State.Desc.StartKey.AsRawKey()
@@ -3350,6 +3514,7 @@ func (r *Replica) followerSendSnapshot(
 		SharedReplicate:     sharedReplicate,
 		ExternalReplicate:   externalReplicate,
 		RangeKeysInOrder:    true,
+		Witness:             toWitness,
 	}
 	newBatchFn := func() storage.WriteBatch {
 		return r.store.TODOEngine().NewWriteBatch()
@@ -3417,18 +3582,27 @@ func (r *Replica) followerSendSnapshot(
 
 // replicasCollocated is used in AdminMerge to ensure that the ranges are
 // all collocate on the same set of replicas.
+//
+// A witness must be collocated with a witness: witnesses don't store the
+// range's user data, so merging a witness of one range with a full replica of
+// the other would lose the latter's data on that store. Voters and
+// non-voters both store the data, so their types may differ.
 func replicasCollocated(a, b []roachpb.ReplicaDescriptor) bool {
 	if len(a) != len(b) {
 		return false
 	}
 
-	set := make(map[roachpb.StoreID]int)
+	type storeReplica struct {
+		storeID roachpb.StoreID
+		witness bool
+	}
+	set := make(map[storeReplica]int)
 	for _, replica := range a {
-		set[replica.StoreID]++
+		set[storeReplica{replica.StoreID, replica.IsWitness()}]++
 	}
 
 	for _, replica := range b {
-		set[replica.StoreID]--
+		set[storeReplica{replica.StoreID, replica.IsWitness()}]--
 	}
 
 	for _, value := range set {
@@ -302,7 +302,11 @@ func (r *Replica) runConsistencyCheck(
 	}
 	ccRes := res.(*kvpb.ComputeChecksumResponse)
 
-	replicas := r.Desc().Replicas().Descriptors()
+	// Witnesses don't store the range's user data, so their checksums aren't
+	// comparable to those of the other replicas.
+	replicas := r.Desc().Replicas().Filter(func(rDesc roachpb.ReplicaDescriptor) bool {
+		return !rDesc.IsWitness()
+	}).Descriptors()
 	resultCh := make(chan ConsistencyCheckResult, len(replicas))
 	results := make([]ConsistencyCheckResult, 0, len(replicas))
 
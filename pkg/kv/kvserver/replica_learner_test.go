@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
@@ -27,7 +28,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
 	"github.com/cockroachdb/cockroach/pkg/raft/raftpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
@@ -35,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
@@ -2466,3 +2470,164 @@ func TestAddVotersWithoutRaftQueue(t *testing.T) {
 	tc.AddVotersOrFatal(t, key, tc.Target(1))
 	tc.AddVotersOrFatal(t, key, tc.Target(2))
 }
+
+// TestWitnessReplicas verifies that a witness is added through the learner
+// state, doesn't store the range's user data, and counts towards the quorum
+// so that the range remains available after losing a full voter.
+func TestWitnessReplicas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var witnessSnapshots int64
+	knobs, ltk := makeReplicationTestKnobs()
+	ltk.storeKnobs.ReceiveSnapshot = func(_ context.Context, h *kvserverpb.SnapshotRequest_Header) error {
+		if h.Witness {
+			atomic.AddInt64(&witnessSnapshots, 1)
+		}
+		return nil
+	}
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ServerArgs:      base.TestServerArgs{Knobs: knobs},
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	key := tc.ScratchRange(t)
+	desc := tc.AddVotersOrFatal(t, key, tc.Target(1))
+	desc2, err := tc.Server(0).DB().AdminChangeReplicas(
+		ctx, key, desc, kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, tc.Target(2)),
+	)
+	require.NoError(t, err)
+	desc = *desc2
+	require.Len(t, desc.Replicas().VoterDescriptors(), 2)
+	require.Len(t, desc.Replicas().WitnessDescriptors(), 1)
+	require.Len(t, desc.Replicas().LearnerDescriptors(), 0)
+
+	// The witness was added as a learner, which received an initial snapshot
+	// of the range-local state only.
+	require.Equal(t, int64(1), getFirstStoreMetric(t, tc.Server(2), `range.snapshots.applied-initial`))
+	require.Equal(t, int64(1), atomic.LoadInt64(&witnessSnapshots))
+
+	// Witnesses can't hold the lease.
+	require.Error(t, tc.TransferRangeLease(desc, tc.Target(2)))
+
+	// getUserValue returns the value of the user key k on server i's store,
+	// once the store caught up with the leaseholder.
+	getUserValue := func(i int, k roachpb.Key) *roachpb.Value {
+		t.Helper()
+		leaseholder := tc.GetFirstStoreFromServer(t, 0).LookupReplica(roachpb.RKey(key))
+		store := tc.GetFirstStoreFromServer(t, i)
+		repl := store.LookupReplica(roachpb.RKey(key))
+		testutils.SucceedsSoon(t, func() error {
+			if lai, exp := repl.GetLeaseAppliedIndex(), leaseholder.GetLeaseAppliedIndex(); lai < exp {
+				return errors.Errorf("s%d at lease applied index %d, want %d", store.StoreID(), lai, exp)
+			}
+			return nil
+		})
+		res, err := storage.MVCCGet(ctx, store.TODOEngine(), k, hlc.MaxTimestamp, storage.MVCCGetOptions{})
+		require.NoError(t, err)
+		return res.Value
+	}
+
+	// Writes are replicated to the full voter, but the witness only applies
+	// their range-local state.
+	require.NoError(t, tc.Server(0).DB().Put(ctx, key, "a"))
+	require.NotNil(t, getUserValue(1, key))
+	require.Nil(t, getUserValue(2, key))
+
+	// Lose the full voter. The leaseholder and the witness still make up a
+	// quorum, so the range remains available.
+	tc.StopServer(1)
+	putCtx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+	defer cancel()
+	require.NoError(t, tc.Server(0).DB().Put(putCtx, key.Next(), "b"))
+	require.NotNil(t, getUserValue(0, key.Next()))
+	require.Nil(t, getUserValue(2, key.Next()))
+}
+
+// TestWitnessReplicasMerge verifies that ranges are only merged if their
+// witnesses are collocated with witnesses: a witness doesn't store the user
+// data of its range, so merging it with a full voter would lose the data of
+// its range on the store.
+func TestWitnessReplicasMerge(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	knobs, _ := makeReplicationTestKnobs()
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ServerArgs:      base.TestServerArgs{Knobs: knobs},
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+	db := tc.Server(0).DB()
+
+	key := tc.ScratchRange(t)
+	desc := tc.AddVotersOrFatal(t, key, tc.Target(1))
+	_, err := db.AdminChangeReplicas(
+		ctx, key, desc, kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, tc.Target(2)),
+	)
+	require.NoError(t, err)
+
+	// Both sides of the split have a witness on s3, so they can be merged.
+	splitKey := key.Next()
+	_, rhsDesc := tc.SplitRangeOrFatal(t, splitKey)
+	require.Len(t, rhsDesc.Replicas().WitnessDescriptors(), 1)
+	require.NoError(t, db.AdminMerge(ctx, key))
+	mergedDesc := tc.LookupRangeOrFatal(t, key)
+	require.Equal(t, roachpb.RKey(key), mergedDesc.StartKey)
+	require.Len(t, mergedDesc.Replicas().WitnessDescriptors(), 1)
+
+	// Replace the witness of the RHS with a full voter. The LHS still has a
+	// witness on s3, so the ranges can't be merged.
+	_, rhsDesc = tc.SplitRangeOrFatal(t, splitKey)
+	desc2, err := db.AdminChangeReplicas(
+		ctx, splitKey, rhsDesc, kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, tc.Target(2)),
+	)
+	require.NoError(t, err)
+	rhsDesc = tc.AddVotersOrFatal(t, splitKey, tc.Target(2))
+	require.Len(t, rhsDesc.Replicas().WitnessDescriptors(), 0)
+	require.Len(t, rhsDesc.Replicas().VoterDescriptors(), 3)
+	require.Equal(t, desc2.RangeID, rhsDesc.RangeID)
+	err = db.AdminMerge(ctx, key)
+	require.ErrorContains(t, err, "ranges not collocated")
+	// The ranges are still split.
+	require.Equal(t, roachpb.RKey(splitKey), tc.LookupRangeOrFatal(t, splitKey).StartKey)
+}
+
+// TestWitnessReplicasVersionGate verifies that witnesses can't be added until
+// the cluster version supports them.
+func TestWitnessReplicasVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	startV := (clusterversion.V25_1_WitnessReplicas - 1).Version()
+	endV := clusterversion.Latest.Version()
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 2, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			Settings: cluster.MakeTestingClusterSettingsWithVersions(endV, startV, false),
+			Knobs: base.TestingKnobs{
+				Server: &server.TestingKnobs{
+					ClusterVersionOverride:         startV,
+					DisableAutomaticVersionUpgrade: make(chan struct{}),
+				},
+			},
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	key := tc.ScratchRange(t)
+	desc := tc.LookupRangeOrFatal(t, key)
+	chgs := kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, tc.Target(1))
+	_, err := tc.Server(0).DB().AdminChangeReplicas(ctx, key, desc, chgs)
+	require.ErrorContains(t, err, "witness replicas unsupported in mixed-version cluster")
+
+	sqlutils.MakeSQLRunner(tc.ServerConn(0)).Exec(t, `SET CLUSTER SETTING version = $1`, endV.String())
+	desc = tc.LookupRangeOrFatal(t, key)
+	_, err = tc.Server(0).DB().AdminChangeReplicas(ctx, key, desc, chgs)
+	require.NoError(t, err)
+}
@@ -2401,7 +2401,14 @@ func (r *Replica) maybeCampaignOnWakeLocked(ctx context.Context) {
 	// method were to be called on an uninitialized replica (which
 	// has no state and thus an empty raft config), this might cause
 	// problems.
-	if _, currentMember := r.shMu.state.Desc.GetReplicaDescriptorByID(r.replicaID); !currentMember {
+	replDesc, currentMember := r.shMu.state.Desc.GetReplicaDescriptorByID(r.replicaID)
+	if !currentMember {
+		return
+	}
+	// Witnesses can't hold the lease, so there's no point in them campaigning.
+	// They still vote, and may win an election after the election timeout if no
+	// other replica campaigns.
+	if replDesc.IsWitness() {
 		return
 	}
 
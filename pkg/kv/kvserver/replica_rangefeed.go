@@ -263,6 +263,11 @@ func (r *Replica) RangeFeed(
 		return nil, errors.Errorf("multiple origin IDs and OriginID != 0 not supported yet")
 	}
 
+	rowFilter, err := rangefeed.NewRowFilter(args.RowFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the RangeFeed is performing a catch-up scan then it will observe all
 	// values above args.Timestamp. If the RangeFeed is requesting previous
 	// values for every update then it will also need to look for the version
@@ -347,7 +352,8 @@ func (r *Replica) RangeFeed(
 	}
 
 	p, disconnector, err := r.registerWithRangefeedRaftMuLocked(
-		streamCtx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, args.WithFiltering, omitRemote, stream,
+		streamCtx, rSpan, args.Timestamp, catchUpIter, args.WithDiff, args.WithFiltering, omitRemote,
+		rowFilter, stream,
 	)
 	r.raftMu.Unlock()
 
@@ -444,6 +450,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *rangefeed.RowFilter,
 	stream rangefeed.Stream,
 ) (rangefeed.Processor, rangefeed.Disconnector, error) {
 	defer logSlowRangefeedRegistration(streamCtx)()
@@ -465,7 +472,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 
 	if p != nil {
 		reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpIter, withDiff, withFiltering, withOmitRemote,
-			stream)
+			rowFilter, stream)
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -547,7 +554,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
 	reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpIter, withDiff,
-		withFiltering, withOmitRemote, stream)
+		withFiltering, withOmitRemote, rowFilter, stream)
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
@@ -455,9 +455,11 @@ func (metrics *ReplicateQueueMetrics) trackSuccessByAllocatorAction(
 	ctx context.Context, action allocatorimpl.AllocatorAction,
 ) {
 	switch action {
-	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter:
+	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveWitness:
 		metrics.RemoveReplicaSuccessCount.Inc(1)
-	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter:
+	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorAddWitness:
 		metrics.AddReplicaSuccessCount.Inc(1)
 	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter:
 		metrics.ReplaceDeadReplicaSuccessCount.Inc(1)
@@ -483,9 +485,11 @@ func (metrics *ReplicateQueueMetrics) trackErrorByAllocatorAction(
 	ctx context.Context, action allocatorimpl.AllocatorAction,
 ) {
 	switch action {
-	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter:
+	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveWitness:
 		metrics.RemoveReplicaErrorCount.Inc(1)
-	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter:
+	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorAddWitness:
 		metrics.AddReplicaErrorCount.Inc(1)
 	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter:
 		metrics.ReplaceDeadReplicaErrorCount.Inc(1)
@@ -900,6 +900,8 @@ type Store struct {
 	tsMaintenanceQueue  *timeSeriesMaintenanceQueue // Time series maintenance queue
 	scanner             *replicaScanner             // Replica scanner
 	consistencyQueue    *consistencyQueue           // Replica consistency check queue
+	coldTieringQueue    *coldTieringQueue           // Cold data tiering queue
+	coldTieredObjects   coldTieredObjects           // Objects created by the coldTieringQueue
 	consistencyLimiter  *quotapool.RateLimiter      // Rate limits consistency checks
 	metrics             *StoreMetrics
 	intentResolver      *intentresolver.IntentResolver
@@ -1155,6 +1157,11 @@ type Store struct {
 
 	// diskMonitor provides metrics for the disk associated with this store.
 	diskMonitor *disk.Monitor
+
+	// provisionedBandwidthOverride is the disk bandwidth (bytes/s) provisioned
+	// for this store using the --store flag. When zero, the
+	// kvadmission.store.provisioned_bandwidth cluster setting is used instead.
+	provisionedBandwidthOverride atomic.Int64
 }
 
 var _ kv.Sender = &Store{}
@@ -1763,12 +1770,14 @@ func NewStore(
 		s.raftLogQueue = newRaftLogQueue(s, s.db)
 		s.raftSnapshotQueue = newRaftSnapshotQueue(s)
 		s.consistencyQueue = newConsistencyQueue(s)
+		s.coldTieringQueue = newColdTieringQueue(s)
 		// NOTE: If more queue types are added, please also add them to the list of
 		// queues on the EnqueueRange debug page as defined in
 		// pkg/ui/src/views/reports/containers/enqueueRange/index.tsx
 		s.scanner.AddQueues(
 			s.mvccGCQueue, s.mergeQueue, s.splitQueue, s.replicateQueue, s.replicaGCQueue,
-			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue, s.leaseQueue)
+			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue, s.leaseQueue,
+			s.coldTieringQueue)
 		tsDS := s.cfg.TimeSeriesDataStore
 		if s.cfg.TestingKnobs.TimeSeriesDataStore != nil {
 			tsDS = s.cfg.TestingKnobs.TimeSeriesDataStore
@@ -2152,6 +2161,10 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		return err
 	}
 
+	if err := s.coldTieredObjects.init(ctx, s.StateEngine()); err != nil {
+		return err
+	}
+
 	{
 		m := rangefeed.NewSchedulerMetrics(s.cfg.HistogramWindowInterval)
 		rfs := rangefeed.NewScheduler(rangefeed.SchedulerConfig{
@@ -2422,6 +2435,8 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 
 	s.startRangefeedTxnPushNotifier(ctx)
 
+	s.startColdTieredObjectGC(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings, s.rebalanceObjManager)
@@ -2760,6 +2775,16 @@ func (s *Store) GossipStore(ctx context.Context, useCached bool) error {
 	return s.storeGossip.GossipStore(ctx, useCached)
 }
 
+// provisionedBandwidth returns the disk bandwidth (bytes/s) provisioned for
+// the store, preferring the value given by the --store flag over the cluster
+// setting. It returns 0 when no bandwidth is provisioned.
+func (s *Store) provisionedBandwidth() int64 {
+	if bandwidth := s.provisionedBandwidthOverride.Load(); bandwidth > 0 {
+		return bandwidth
+	}
+	return kvadmission.ProvisionedBandwidth.Get(&s.cfg.Settings.SV)
+}
+
 // UpdateIOThreshold updates the IOThreshold and IOThresholdMax reported in the
 // StoreDescriptor.
 func (s *Store) UpdateIOThreshold(ioThreshold *admissionpb.IOThreshold) {
@@ -3148,13 +3173,14 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
 	var totalStoreCPUTimePerSecond float64
+	var totalWriteBytesPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
-	// We wish to track both CPU and QPS, due to different usecases between UI
-	// and rebalancing. By default rebalancing uses CPU whilst the UI will use
-	// QPS.
-	rankingsAccumulator := NewReplicaAccumulator(load.CPU, load.Queries)
+	// We wish to track CPU, QPS and write bytes, due to different usecases
+	// between UI and rebalancing. By default rebalancing uses CPU whilst the UI
+	// will use QPS.
+	rankingsAccumulator := NewReplicaAccumulator(load.CPU, load.Queries, load.WriteBytes)
 	// rankingsByTenantAccumulator collects top replicas by QPS only as far as it is
 	// used in Db Console only.
 	rankingsByTenantAccumulator := NewTenantReplicaAccumulator(load.Queries)
@@ -3175,6 +3201,7 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 		totalStoreCPUTimePerSecond += usage.RequestCPUNanosPerSecond + usage.RaftCPUNanosPerSecond
 		totalQueriesPerSecond += usage.QueriesPerSecond
 		totalWritesPerSecond += usage.WritesPerSecond
+		totalWriteBytesPerSecond += usage.RaftWriteBytesPerSecond
 		writesPerReplica = append(writesPerReplica, usage.WritesPerSecond)
 		cr := candidateReplica{
 			Replica: r,
@@ -3203,6 +3230,8 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	capacity.CPUPerSecond = totalStoreCPUTimePerSecond
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.WriteBytesPerSecond = totalWriteBytesPerSecond
+	capacity.ProvisionedBandwidth = s.provisionedBandwidth()
 	goNow := now.ToTimestamp().GoTime()
 	{
 		s.ioThreshold.Lock()
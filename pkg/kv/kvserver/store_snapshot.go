@@ -1043,6 +1043,10 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 	if sharedReplicate || externalReplicate {
 		replicatedFilter = rditer.ReplicatedSpansExcludeUser
 	}
+	// Witnesses only receive the range-local replicated state.
+	if header.Witness {
+		replicatedFilter = rditer.ReplicatedSpansExcludeUser
+	}
 
 	iterateRKSpansVisitor := func(iter storage.EngineIterator, _ roachpb.Span) error {
 		timingTag.start("iter")
@@ -319,6 +319,19 @@ func (ls *Stores) RegisterDiskMonitors(diskMonitors map[roachpb.StoreID]DiskStat
 	})
 }
 
+// RegisterProvisionedBandwidths sets the disk bandwidth (bytes/s) provisioned
+// for each store using the --store flag, which is gossiped in the store's
+// capacity. Stores without an entry use the
+// kvadmission.store.provisioned_bandwidth cluster setting.
+func (ls *Stores) RegisterProvisionedBandwidths(bandwidths map[roachpb.StoreID]int64) error {
+	return ls.VisitStores(func(s *Store) error {
+		if bandwidth, ok := bandwidths[s.StoreID()]; ok {
+			s.provisionedBandwidthOverride.Store(bandwidth)
+		}
+		return nil
+	})
+}
+
 func (ls *Stores) CloseDiskMonitors() {
 	_ = ls.VisitStores(func(s *Store) error {
 		if s.diskMonitor != nil {
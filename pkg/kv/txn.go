@@ -1790,6 +1790,17 @@ func (txn *Txn) AdmissionHeader() kvpb.AdmissionHeader {
 	return h
 }
 
+// SetResourceGroup sets the resource group, and its CPU weight, used for
+// admission control of the work done in this transaction. It must be called
+// before the transaction sends any requests.
+func (txn *Txn) SetResourceGroup(name string, cpuWeight uint32) {
+	if txn.typ != RootTxn {
+		panic(errors.AssertionFailedf("SetResourceGroup() called on leaf txn"))
+	}
+	txn.admissionHeader.ResourceGroup = name
+	txn.admissionHeader.ResourceGroupWeight = cpuWeight
+}
+
 // OnePCNotAllowedError signifies that a request had the Require1PC flag set,
 // but 1PC evaluation was not possible for one reason or another.
 type OnePCNotAllowedError struct{}
@@ -1747,6 +1747,12 @@ func confChangeImpl(
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
 			}
+		case WITNESS:
+			// Witnesses never hold the lease, so they can be removed directly
+			// (through a simple config change) without being demoted first.
+			if err := checkNotExists(rDesc); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, errors.Errorf("removal of %v unsafe, demote to LEARNER first", rDesc.Type)
 		}
@@ -1769,6 +1775,11 @@ func confChangeImpl(
 			// We're adding a voter, but will transition into a joint config
 			// first.
 			changeType = raftpb.ConfChangeAddNode
+		case WITNESS:
+			// We're promoting a learner, which has received its initial snapshot,
+			// to a witness. Witnesses can't be part of a joint config, so this is
+			// always a simple config change.
+			changeType = raftpb.ConfChangeAddNode
 		case LEARNER, NON_VOTER:
 			// We're adding a learner or non-voter.
 			// Note that we're guaranteed by virtue of the upstream ChangeReplicas txn
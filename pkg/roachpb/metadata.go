@@ -419,9 +419,11 @@ func (r ReplicaSet) GetReplicaDescriptorByID(id ReplicaID) (repDesc ReplicaDescr
 // config (or, simply is a voter if the range is not in a joint-config state).
 // Can be used as a filter for
 // ReplicaDescriptors.Filter(ReplicaDescriptor.IsVoterOldConfig).
+//
+// Witnesses are voters in this sense; use IsWitness to tell them apart.
 func (r ReplicaDescriptor) IsVoterOldConfig() bool {
 	switch r.Type {
-	case VOTER_FULL, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER:
+	case VOTER_FULL, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER, WITNESS:
 		return true
 	default:
 		return false
@@ -432,9 +434,11 @@ func (r ReplicaDescriptor) IsVoterOldConfig() bool {
 // config (or, simply is a voter if the range is not in a joint-config state).
 // Can be used as a filter for
 // ReplicaDescriptors.Filter(ReplicaDescriptor.IsVoterOldConfig).
+//
+// Witnesses are voters in this sense; use IsWitness to tell them apart.
 func (r ReplicaDescriptor) IsVoterNewConfig() bool {
 	switch r.Type {
-	case VOTER_FULL, VOTER_INCOMING:
+	case VOTER_FULL, VOTER_INCOMING, WITNESS:
 		return true
 	default:
 		return false
@@ -446,13 +450,20 @@ func (r ReplicaDescriptor) IsVoterNewConfig() bool {
 // for ReplicaDescriptors.Filter(ReplicaDescriptor.IsVoterOldConfig).
 func (r ReplicaDescriptor) IsAnyVoter() bool {
 	switch r.Type {
-	case VOTER_FULL, VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER:
+	case VOTER_FULL, VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER,
+		WITNESS:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsWitness returns true if the replica is a witness. Can be used as a filter
+// for ReplicaDescriptors.Filter.
+func (r ReplicaDescriptor) IsWitness() bool {
+	return r.Type == WITNESS
+}
+
 // IsNonVoter returns true if the replica is a non-voter. Can be used as a
 // filter for ReplicaDescriptors.Filter.
 func (r ReplicaDescriptor) IsNonVoter() bool {
@@ -558,6 +569,7 @@ func (sc StoreCapacity) Load() load.Load {
 	dims := load.Vector{}
 	dims[load.Queries] = sc.QueriesPerSecond
 	dims[load.CPU] = sc.CPUPerSecond
+	dims[load.WriteBytes] = sc.WriteBytesPerSecond
 	return dims
 
 }
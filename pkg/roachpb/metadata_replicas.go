@@ -84,6 +84,10 @@ func predNonVoter(rDesc ReplicaDescriptor) bool {
 	return rDesc.Type == NON_VOTER
 }
 
+func predWitness(rDesc ReplicaDescriptor) bool {
+	return rDesc.Type == WITNESS
+}
+
 func predVoterOrNonVoter(rDesc ReplicaDescriptor) bool {
 	return predVoterFullOrIncoming(rDesc) || predNonVoter(rDesc)
 }
@@ -221,6 +225,31 @@ func (d ReplicaSet) NonVoterDescriptors() []ReplicaDescriptor {
 	return d.FilterToDescriptors(predNonVoter)
 }
 
+// Witnesses returns a ReplicaSet containing only the witnesses in `d`.
+//
+// Witnesses are voters at the raft level: they vote in elections, persist the
+// log and count towards the quorum(s). Unlike the other voters, they don't
+// store the range's user data, so they're not returned by Voters(). In
+// particular:
+//
+//   - Witnesses can't hold the lease (see CheckCanReceiveLease), and thus
+//     never serve requests. DistSender and the various oracles don't send
+//     traffic to them.
+//   - Witnesses only receive snapshots of the range-local replicated state, and
+//     never act as the source of a snapshot.
+//   - When applying commands, witnesses drop the writes to user keys.
+//
+// The number of witnesses of a range is controlled by the num_witnesses zone
+// config field. Witnesses count towards num_voters.
+func (d ReplicaSet) Witnesses() ReplicaSet {
+	return d.Filter(predWitness)
+}
+
+// WitnessDescriptors returns the witness replica descriptors in the set.
+func (d ReplicaSet) WitnessDescriptors() []ReplicaDescriptor {
+	return d.FilterToDescriptors(predWitness)
+}
+
 // VoterFullAndNonVoterDescriptors returns the descriptors of
 // VOTER_FULL/NON_VOTER replicas in the set. This set will not contain learners
 // or, during an atomic replication change, incoming or outgoing voters.
@@ -309,7 +338,7 @@ func (d ReplicaSet) InAtomicReplicationChange() bool {
 		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_LEARNER,
 			VOTER_DEMOTING_NON_VOTER:
 			return true
-		case VOTER_FULL, LEARNER, NON_VOTER:
+		case VOTER_FULL, LEARNER, NON_VOTER, WITNESS:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.Type))
 		}
@@ -327,7 +356,7 @@ func (d ReplicaSet) ConfState() raftpb.ConfState {
 	for _, rep := range d.wrapped {
 		id := raftpb.PeerID(rep.ReplicaID)
 		switch rep.Type {
-		case VOTER_FULL:
+		case VOTER_FULL, WITNESS:
 			cs.Voters = append(cs.Voters, id)
 			if joint {
 				cs.VotersOutgoing = append(cs.VotersOutgoing, id)
@@ -493,9 +522,9 @@ func (d ReplicaSet) Subtract(o ReplicaSet) []ReplicaDescriptor {
 // IsAddition returns true if `c` refers to a replica addition operation.
 func (c ReplicaChangeType) IsAddition() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return true
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return false
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -505,9 +534,9 @@ func (c ReplicaChangeType) IsAddition() bool {
 // IsRemoval returns true if `c` refers a replica removal operation.
 func (c ReplicaChangeType) IsRemoval() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return false
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return true
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -563,6 +592,10 @@ func CheckCanReceiveLease(
 		return errors.AssertionFailedf("node ID mismatch: %d != %d",
 			repDesc.NodeID, wouldbeLeaseholder.NodeID)
 	}
+	if repDesc.IsWitness() {
+		// Witnesses don't store the range's data, so they can't serve requests.
+		return ErrReplicaCannotHoldLease
+	}
 	if !(repDesc.IsVoterNewConfig() ||
 		(repDesc.IsVoterOldConfig() && replDescs.containsVoterIncoming() && wasLastLeaseholder)) {
 		// We allow a demoting / incoming voter to receive the lease if there's an incoming voter.
@@ -77,6 +77,26 @@ func TestVotersLearnersAll(t *testing.T) {
 	}
 }
 
+func TestReplicaDescriptorsWitnesses(t *testing.T) {
+	r := MakeReplicaSet([]ReplicaDescriptor{
+		rd(VOTER_FULL, 1), rd(WITNESS, 2), rd(NON_VOTER, 3), rd(VOTER_FULL, 4), rd(LEARNER, 5),
+	})
+	require.Equal(t, []ReplicaDescriptor{rd(WITNESS, 2)}, r.WitnessDescriptors())
+	require.Equal(t, []ReplicaDescriptor{rd(WITNESS, 2)}, r.Witnesses().Descriptors())
+	// Witnesses don't store user data, so they're not returned as voters.
+	require.Equal(t, []ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 4)}, r.VoterDescriptors())
+	require.Equal(t,
+		[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(NON_VOTER, 3), rd(VOTER_FULL, 4)},
+		r.VoterAndNonVoterDescriptors())
+	require.False(t, r.InAtomicReplicationChange())
+
+	w := rd(WITNESS, 2)
+	require.True(t, w.IsWitness())
+	require.True(t, w.IsVoterOldConfig())
+	require.True(t, w.IsVoterNewConfig())
+	require.True(t, w.IsAnyVoter())
+}
+
 func TestReplicaDescriptorsRemove(t *testing.T) {
 	tests := []struct {
 		replicas []ReplicaDescriptor
@@ -177,6 +197,17 @@ func TestReplicaDescriptorsConfState(t *testing.T) {
 			[]ReplicaDescriptor{rd(VOTER_OUTGOING, 1), rd(VOTER_DEMOTING_LEARNER, 2), rd(VOTER_INCOMING, 3), rd(VOTER_INCOMING, 4), rd(LEARNER, 5)},
 			"Voters:[3 4] VotersOutgoing:[1 2] Learners:[5] LearnersNext:[2] AutoLeave:false",
 		},
+		// Witnesses are voters at the raft level.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2), rd(WITNESS, 3)},
+			"Voters:[1 2 3] VotersOutgoing:[] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
+		// A witness remains a voter in both configs during an atomic replication
+		// change.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_OUTGOING, 2), rd(VOTER_INCOMING, 3), rd(WITNESS, 4)},
+			"Voters:[1 3 4] VotersOutgoing:[1 2 4] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
 	}
 
 	for _, test := range tests {
@@ -58,6 +58,12 @@ func (s *SpanConfig) TTL() time.Duration {
 	return time.Duration(s.GCPolicy.TTLSeconds) * time.Second
 }
 
+// ColdAfter returns the duration after which unwritten data is considered
+// cold, as a time.Duration. Zero means data is never considered cold.
+func (s *SpanConfig) ColdAfter() time.Duration {
+	return time.Duration(s.ColdAfterSeconds) * time.Second
+}
+
 // ValidateSystemTargetSpanConfig ensures that only protection policies
 // (GCPolicy.ProtectionPolicies) field is set on the underlying
 // roachpb.SpanConfig.
@@ -83,6 +89,12 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.NumVoters != 0 {
 		return errors.AssertionFailedf("NumVoters set on system span config")
 	}
+	if s.NumWitnesses != 0 {
+		return errors.AssertionFailedf("NumWitnesses set on system span config")
+	}
+	if s.ColdAfterSeconds != 0 {
+		return errors.AssertionFailedf("ColdAfterSeconds set on system span config")
+	}
 	if len(s.Constraints) != 0 {
 		return errors.AssertionFailedf("Constraints set on system span config")
 	}
@@ -110,6 +122,12 @@ func (s *SpanConfig) GetNumVoters() int32 {
 	return s.NumReplicas
 }
 
+// GetNumFullVoters returns the number of voting replicas which store the
+// range's data, i.e. the voters that aren't witnesses.
+func (s *SpanConfig) GetNumFullVoters() int32 {
+	return s.GetNumVoters() - s.NumWitnesses
+}
+
 // GetNumNonVoters returns the number of non-voting replicas as defined in the
 // span config.
 func (s *SpanConfig) GetNumNonVoters() int32 {
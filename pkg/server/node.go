@@ -1354,6 +1354,13 @@ func (n *Node) registerEnginesForDiskStatsMap(
 	if err := n.stores.RegisterDiskMonitors(pmp.diskStatsMap.diskMonitors); err != nil {
 		return nil, err
 	}
+	provisionedBandwidths := make(map[roachpb.StoreID]int64, len(pmp.diskStatsMap.provisionedRate))
+	for id, spec := range pmp.diskStatsMap.provisionedRate {
+		provisionedBandwidths[id] = spec.ProvisionedBandwidth
+	}
+	if err := n.stores.RegisterProvisionedBandwidths(provisionedBandwidths); err != nil {
+		return nil, err
+	}
 	return pmp, nil
 }
 
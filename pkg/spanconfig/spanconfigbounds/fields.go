@@ -43,8 +43,10 @@ var fields = [config.NumFields]Field{
 	rangeMaxBytes,
 	globalReads,
 	numVoters,
+	numWitnesses,
 	numReplicas,
 	gcTTLSeconds,
+	coldAfterSeconds,
 	constraints,
 	voterConstraints,
 	leasePreferences,
@@ -56,7 +58,9 @@ const (
 	globalReads      = boolField(config.GlobalReads)
 	numReplicas      = int32Field(config.NumReplicas)
 	numVoters        = int32Field(config.NumVoters)
+	numWitnesses     = int32Field(config.NumWitnesses)
 	gcTTLSeconds     = int32Field(config.GCTTL)
+	coldAfterSeconds = int32Field(config.ColdAfterSeconds)
 	constraints      = constraintsConjunctionField(config.Constraints)
 	voterConstraints = constraintsConjunctionField(config.VoterConstraints)
 	leasePreferences = leasePreferencesField(config.LeasePreferences)
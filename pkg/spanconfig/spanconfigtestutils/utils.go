@@ -637,6 +637,12 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.NumVoters != defaultConf.NumVoters {
 		diffs = append(diffs, fmt.Sprintf("num_voters=%d", conf.NumVoters))
 	}
+	if conf.NumWitnesses != defaultConf.NumWitnesses {
+		diffs = append(diffs, fmt.Sprintf("num_witnesses=%d", conf.NumWitnesses))
+	}
+	if conf.ColdAfterSeconds != defaultConf.ColdAfterSeconds {
+		diffs = append(diffs, fmt.Sprintf("cold_after_seconds=%d", conf.ColdAfterSeconds))
+	}
 	if conf.RangefeedEnabled != defaultConf.RangefeedEnabled {
 		diffs = append(diffs, fmt.Sprintf("rangefeed_enabled=%t", conf.RangefeedEnabled))
 	}
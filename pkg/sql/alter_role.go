@@ -397,6 +397,10 @@ func (p *planner) processSetOrResetClause(
 	// here since it doesn't make sense to set as a default anyway.
 	case "database", "role", "tracing":
 		return unknown, "", sessionVar{}, nil, newCannotChangeParameterError(varName)
+	case "resource_group":
+		if err := p.checkResourceGroupPrivilege(ctx); err != nil {
+			return unknown, "", sessionVar{}, nil, err
+		}
 	}
 	_, sVar, err = getSessionVar(varName, false /* missingOk */)
 	if err != nil {
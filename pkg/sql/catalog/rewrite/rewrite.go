@@ -67,6 +67,9 @@ func TableDescs(
 		table.ID = tableRewrite.ID
 		table.UnexposedParentSchemaID = tableRewrite.ParentSchemaID
 		table.ParentID = tableRewrite.ParentID
+		if tableRewrite.NewTableName != "" {
+			table.Name = tableRewrite.NewTableName
+		}
 
 		// Rewrite CHECK constraints before function IDs in expressions are
 		// rewritten. Check constraint mutations are also dropped if any function
@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// ValidateRowFilter verifies that an expression is a valid row filter for the
+// given table, as used by RESTORE TABLE ... AS ... WHERE and
+// crdb_internal.backup_rows. If the expression is valid, it returns the serialized expression with the columns dequalified.
+//
+// A row filter is valid if it is a valid partial index predicate that only
+// references columns stored in the primary index, i.e. no virtual computed
+// columns, and no columns of user-defined types, since the filter is evaluated
+// before those types are restored.
+func ValidateRowFilter(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	e tree.Expr,
+	tn *tree.TableName,
+	semaCtx *tree.SemaContext,
+	version clusterversion.ClusterVersion,
+) (string, error) {
+	expr, _, cols, err := DequalifyAndValidateExpr(
+		ctx,
+		desc,
+		e,
+		types.Bool,
+		tree.RestoreRowFilterExpr,
+		semaCtx,
+		volatility.Immutable,
+		tn,
+		version,
+	)
+	if err != nil {
+		return "", err
+	}
+	cols.ForEach(func(colID descpb.ColumnID) {
+		if err != nil {
+			return
+		}
+		var col catalog.Column
+		col, err = catalog.MustFindColumnByID(desc, colID)
+		if err != nil {
+			return
+		}
+		if col.IsVirtual() {
+			err = pgerror.Newf(pgcode.FeatureNotSupported,
+				"row filter cannot reference virtual computed column %q", col.GetName())
+		} else if col.GetType().UserDefined() {
+			err = pgerror.Newf(pgcode.FeatureNotSupported,
+				"row filter cannot reference column %q of user-defined type", col.GetName())
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return expr, nil
+}
+
+// MakeRowFilterExpr turns a row filter previously validated with
+// ValidateRowFilter into a TypedExpr. It also returns the columns referenced
+// by the filter; the indexed vars of the returned expression refer to
+// ordinals in that slice.
+func MakeRowFilterExpr(
+	ctx context.Context,
+	table catalog.TableDescriptor,
+	filter string,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, []catalog.Column, error) {
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	colIDs, err := ExtractColumnIDs(table, expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	cols := make([]catalog.Column, 0, colIDs.Len())
+	for _, col := range table.PublicColumns() {
+		if colIDs.Contains(col.GetID()) {
+			cols = append(cols, col)
+		}
+	}
+	nr := newNameResolver(table.GetID(), tree.NewUnqualifiedTableName(tree.Name(table.GetName())), cols)
+	nr.addIVarContainerToSemaCtx(semaCtx)
+	expr, err = nr.resolveNames(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, types.Bool)
+	if err != nil {
+		return nil, nil, err
+	}
+	var txCtx transform.ExprTransformContext
+	typedExpr, err = txCtx.NormalizeExpr(ctx, evalCtx, typedExpr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return typedExpr, cols, nil
+}
@@ -10,9 +10,12 @@ import (
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
@@ -89,6 +92,29 @@ func init() {
 			RequiredType: types.Int,
 			Setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) },
 		},
+		{
+			Field:        config.NumWitnesses,
+			RequiredType: types.Int,
+			Setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumWitnesses = proto.Int32(int32(tree.MustBeDInt(d))) },
+			CheckAllowed: func(ctx context.Context, settings *cluster.Settings, d tree.Datum) error {
+				if tree.MustBeDInt(d) == 0 {
+					// Always allow the value to be unset.
+					return nil
+				}
+				if !settings.Version.IsActive(ctx, clusterversion.V25_1_WitnessReplicas) {
+					return pgerror.New(pgcode.FeatureNotSupported,
+						"num_witnesses unsupported in mixed-version cluster")
+				}
+				return nil
+			},
+		},
+		{
+			Field:        config.ColdAfterSeconds,
+			RequiredType: types.Int,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.ColdAfterSeconds = proto.Int32(int32(tree.MustBeDInt(d)))
+			},
+		},
 		{
 			Field:        config.GCTTL,
 			RequiredType: types.Int,
@@ -69,6 +69,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
@@ -383,6 +384,10 @@ type Server struct {
 
 	idxRecommendationsCache *idxrecommendations.IndexRecCache
 
+	// resourceGroupLimiter limits the number of concurrently executing
+	// statements of each resource group on this node.
+	resourceGroupLimiter *admission.ResourceGroupLimiter
+
 	mu struct {
 		syncutil.Mutex
 		connectionCount     int64
@@ -479,6 +484,7 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 			cfg.Settings,
 			&serverMetrics.ContentionSubsystemMetrics),
 		idxRecommendationsCache: idxrecommendations.NewIndexRecommendationsCache(cfg.Settings),
+		resourceGroupLimiter:    admission.NewResourceGroupLimiter(&cfg.Settings.SV),
 	}
 
 	telemetryLoggingMetrics := newTelemetryLoggingMetrics(cfg.TelemetryLoggingTestingKnobs, cfg.Settings)
@@ -1516,6 +1522,11 @@ type connExecutor struct {
 		// executed so far.
 		numDDL int
 
+		// releaseResourceGroupSlot, if set, releases the slot of the session's
+		// resource group that is held by the transaction. See
+		// acquireResourceGroupSlot.
+		releaseResourceGroupSlot func()
+
 		// numRows keeps track of the number of rows that have been observed by this
 		// transaction. This is simply the summation of number of rows observed by
 		// comprising statements.
@@ -1813,6 +1824,10 @@ type connExecutor struct {
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
 
+	// tempTableOnCommit tracks the temporary tables of the session which have
+	// an ON COMMIT action to carry out whenever a transaction commits.
+	tempTableOnCommit tempTableOnCommitActions
+
 	// stmtDiagnosticsRecorder is used to track which queries need to have
 	// information collected.
 	stmtDiagnosticsRecorder *stmtdiagnostics.Registry
@@ -2136,6 +2151,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 	ex.extraTxnState.upgradedToSerializable = false
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
 	ex.extraTxnState.createdSequences = nil
+	ex.tempTableOnCommit.resetWritten()
 
 	if ex.extraTxnState.skipResettingSchemaObjects {
 		if ex.extraTxnState.shouldResetSyntheticDescriptors {
@@ -2172,6 +2188,10 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 			ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.savepoints.clear()
+		if release := ex.extraTxnState.releaseResourceGroupSlot; release != nil {
+			ex.extraTxnState.releaseResourceGroupSlot = nil
+			release()
+		}
 		ex.onTxnFinish(ctx, ev, payloadErr)
 	case txnRestart:
 		ex.onTxnRestart(ctx)
@@ -3782,6 +3802,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		TxnModesSetter:       ex,
 		jobs:                 ex.extraTxnState.jobs,
 		validateDbZoneConfig: &ex.extraTxnState.validateDbZoneConfig,
+		tempTableOnCommit:    &ex.tempTableOnCommit,
 		statsProvider:        ex.server.sqlStats,
 		indexUsageStats:      ex.indexUsageStats,
 		statementPreparer:    ex,
@@ -4009,6 +4030,7 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 		if err := ex.maybeSetSQLLivenessSession(); err != nil {
 			return advanceInfo{}, err
 		}
+		ex.maybeSetResourceGroup()
 	case txnCommit:
 		if res.Err() != nil {
 			// See https://github.com/cockroachdb/errors/issues/86.
@@ -4200,6 +4222,51 @@ func (ex *connExecutor) maybeSetSQLLivenessSession() error {
 	return nil
 }
 
+// maybeSetResourceGroup sets the resource group of the session, if it is
+// defined, on the KV transaction, so that the work done by the transaction is
+// admitted as part of the resource group.
+func (ex *connExecutor) maybeSetResourceGroup() {
+	name := ex.sessionData().ResourceGroup
+	if name == "" || ex.executorType == executorTypeInternal {
+		return
+	}
+	group, ok := ex.server.resourceGroupLimiter.Lookup(name)
+	if !ok {
+		return
+	}
+	ex.state.mu.Lock()
+	defer ex.state.mu.Unlock()
+	ex.state.mu.txn.SetResourceGroup(group.Name, group.CPUWeight)
+}
+
+// acquireResourceGroupSlot waits until the transaction can execute under the
+// max_concurrency limit of the resource group of the session. The slot is
+// acquired by the first statement of the transaction, and held until the
+// transaction commits or rolls back (see resetExtraTxnState): if each statement
+// acquired a slot, a transaction holding locks could wait for a slot held by a
+// transaction waiting on those locks.
+func (ex *connExecutor) acquireResourceGroupSlot(ctx context.Context, ast tree.Statement) error {
+	if ex.extraTxnState.releaseResourceGroupSlot != nil {
+		return nil
+	}
+	name := ex.sessionData().ResourceGroup
+	if name == "" || ex.executorType == executorTypeInternal {
+		return nil
+	}
+	switch ast.(type) {
+	case *tree.CommitTransaction, *tree.RollbackTransaction,
+		*tree.ReleaseSavepoint, *tree.RollbackToSavepoint:
+		// Don't make statements that finish a transaction wait.
+		return nil
+	}
+	release, err := ex.server.resourceGroupLimiter.Acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+	ex.extraTxnState.releaseResourceGroupSlot = release
+	return nil
+}
+
 // initStatementResult initializes res according to a query.
 //
 // cols represents the columns of the result rows. Should be nil if
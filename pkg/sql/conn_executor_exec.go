@@ -144,6 +144,11 @@ func (ex *connExecutor) execStmt(
 		ev, payload = ex.execStmtInNoTxnState(ctx, parserStmt, res)
 
 	case stateOpen:
+		if err = ex.acquireResourceGroupSlot(ctx, ast); err != nil {
+			ev, payload = ex.makeErrEvent(err, ast)
+			err = nil
+			break
+		}
 		var preparedStmt *PreparedStatement
 		if portal != nil {
 			preparedStmt = portal.Stmt
@@ -2341,6 +2346,9 @@ func (ex *connExecutor) resetTransactionOnSchemaChangeRetry(ctx context.Context)
 	if omitInRangefeeds {
 		newTxn.SetOmitInRangefeeds()
 	}
+	if h := ex.state.mu.txn.AdmissionHeader(); h.ResourceGroup != "" {
+		newTxn.SetResourceGroup(h.ResourceGroup, h.ResourceGroupWeight)
+	}
 	ex.state.mu.txn = newTxn
 	return nil
 }
@@ -2457,6 +2465,12 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) (retEr
 		ex.state.mu.txn.ConfigureStepping(ctx, prevSteppingMode)
 	}
 
+	// ON COMMIT DROP may queue a schema change job, so this must happen before
+	// the jobs are created.
+	if err := ex.runTempTableOnCommitActions(ctx); err != nil {
+		return err
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -2021,6 +2021,70 @@ func TestSessionTotalActiveTime(t *testing.T) {
 	}
 }
 
+// TestResourceGroupConcurrencyPerTransaction verifies that the
+// max_concurrency limit of a resource group is applied per transaction: a
+// transaction keeps its slot until it finishes, so that a transaction holding
+// locks doesn't wait for a slot held by a transaction waiting on those locks.
+func TestResourceGroupConcurrencyPerTransaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING admission.resource_groups = 'g:max_concurrency=1'`)
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v INT)`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 0)`)
+
+	conn1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+	for _, conn := range []*gosql.Conn{conn1, conn2} {
+		_, err := conn.ExecContext(ctx, `SET resource_group = 'g'`)
+		require.NoError(t, err)
+	}
+
+	// The explicit transaction takes the slot of the group with its first
+	// statement, and a lock on the row.
+	tx, err := conn1.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, `UPDATE t SET v = 1 WHERE k = 1`)
+	require.NoError(t, err)
+
+	// The implicit transaction waits for the slot, without taking the lock.
+	updated := make(chan error, 1)
+	go func() {
+		_, err := conn2.ExecContext(ctx, `UPDATE t SET v = 2 WHERE k = 1`)
+		updated <- err
+	}()
+	testutils.SucceedsSoon(t, func() error {
+		var n int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW CLUSTER QUERIES] WHERE query LIKE 'UPDATE t SET v = 2%'`).Scan(&n)
+		if n != 1 {
+			return errors.Newf("expected the UPDATE to be running, found %d", n)
+		}
+		return nil
+	})
+	select {
+	case err := <-updated:
+		t.Fatalf("expected the UPDATE to wait for the resource group, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// The explicit transaction can keep executing statements, and lets the
+	// waiting transaction run when it commits.
+	_, err = tx.ExecContext(ctx, `UPDATE t SET v = v + 1 WHERE k = 1`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, <-updated)
+	sqlDB.CheckQueryResults(t, `SELECT v FROM t`, [][]string{{"2"}})
+}
+
 // dynamicRequestFilter exposes a filter method which is a
 // kvserverbase.ReplicaRequestFilter but can be set dynamically.
 type dynamicRequestFilter struct {
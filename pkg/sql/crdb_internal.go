@@ -1172,6 +1172,7 @@ CREATE TABLE crdb_internal.jobs (
   trace_id              INT,
   execution_errors      STRING[],
   execution_events      JSONB,
+  depends_on            INT[],
   INDEX(job_id),
   INDEX(status),
   INDEX(job_type)
@@ -1409,6 +1410,7 @@ func makeLegacyJobsTableRows(
 			traceID,
 			executionErrors,
 			executionEvents,
+			tree.DNull, // depends_on is only read from system.job_info.
 		); err != nil {
 			return matched, err
 		}
@@ -1443,10 +1445,12 @@ s.status, j.created::timestamptz, j.finished, greatest(j.created, j.finished, p.
 p.fraction,
 p.resolved,
 j.error_msg,
-j.claim_instance_id
+j.claim_instance_id,
+d.value
 FROM system.public.jobs AS j
 LEFT OUTER JOIN system.public.job_progress AS p ON j.id = p.job_id
 LEFT OUTER JOIN system.public.job_status AS s ON j.id = s.job_id  
+LEFT OUTER JOIN system.public.job_info AS d ON j.id = d.job_id AND d.info_key = '` + jobs.DependsOnInfoKey + `'
 	` + whereClause
 
 	it, err := p.InternalSQLTxn().QueryIteratorEx(
@@ -1484,13 +1488,17 @@ LEFT OUTER JOIN system.public.job_status AS s ON j.id = s.job_id
 		// use when emitting our output row. If we need to synthesize rows for jobs
 		// pending creation in the session, we'll do so in those same named vars to
 		// keep things organized.
-		//   0,      1,    2,        3,     4,      5,       6,        7,        8,        9,       10,       11,         12
-		var id, typStr, desc, ownerStr, state, status, created, finished, modified, fraction, resolved, errorMsg, instanceID tree.Datum
+		//   0,      1,    2,        3,     4,      5,       6,        7,        8,        9,       10,       11,         12,        13
+		var id, typStr, desc, ownerStr, state, status, created, finished, modified, fraction, resolved, errorMsg, instanceID, dependsOn tree.Datum
 
 		if ok {
 			r := it.Cur()
 			id, typStr, desc, ownerStr, state, status, created, finished, modified, fraction, resolved, errorMsg, instanceID =
 				r[0], r[1], r[2], r[3], r[4], r[5], r[6], r[7], r[8], r[9], r[10], r[11], r[12]
+			dependsOn, err = makeJobDependenciesDatum(r[13])
+			if err != nil {
+				return emitted, err
+			}
 
 			owner := username.MakeSQLUsernameFromPreNormalizedString(string(tree.MustBeDString(ownerStr)))
 			jobID := jobspb.JobID(tree.MustBeDInt(id))
@@ -1562,6 +1570,10 @@ LEFT OUTER JOIN system.public.job_status AS s ON j.id = s.job_id
 				tree.DZeroDecimal,
 				tree.DNull,
 				tree.NewDInt(tree.DInt(p.extendedEvalCtx.ExecCfg.JobRegistry.ID()))
+			dependsOn, err = makeJobIDsArray(job.DependsOn)
+			if err != nil {
+				return emitted, err
+			}
 		}
 
 		if err = addRow(
@@ -1584,6 +1596,7 @@ LEFT OUTER JOIN system.public.job_status AS s ON j.id = s.job_id
 			tree.DNull, // deprecated "trace_id" field.
 			tree.DNull, // deprecated "executionErrors" field.
 			tree.DNull, // deprecated "executionEvents" field.
+			dependsOn,
 		); err != nil {
 			return emitted, err
 		}
@@ -1591,6 +1604,35 @@ LEFT OUTER JOIN system.public.job_status AS s ON j.id = s.job_id
 	}
 }
 
+// makeJobDependenciesDatum returns the IDs of the jobs in the
+// jobspb.JobDependencies stored in the given job_info value, or NULL if the
+// job has no dependencies.
+func makeJobDependenciesDatum(value tree.Datum) (tree.Datum, error) {
+	if value == tree.DNull {
+		return tree.DNull, nil
+	}
+	var deps jobspb.JobDependencies
+	if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(value)), &deps); err != nil {
+		return nil, err
+	}
+	return makeJobIDsArray(deps.JobIDs)
+}
+
+// makeJobIDsArray returns an INT[] of the given job IDs, or NULL if there are
+// none.
+func makeJobIDsArray(ids []jobspb.JobID) (tree.Datum, error) {
+	if len(ids) == 0 {
+		return tree.DNull, nil
+	}
+	arr := tree.NewDArray(types.Int)
+	for _, id := range ids {
+		if err := arr.Append(tree.NewDInt(tree.DInt(id))); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
 const crdbInternalKVProtectedTSTableQuery = `
 	SELECT id, ts, meta_type, meta, num_spans, spans, verified, target,
 		crdb_internal.pb_to_json(
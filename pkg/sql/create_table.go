@@ -250,6 +250,12 @@ func hasPrimaryKeySerialType(params runParams, colDef *tree.ColumnTableDef) (boo
 	return false, nil
 }
 
+// hasOnCommitAction returns whether the ON COMMIT setting of a temporary
+// table requires the session to act when a transaction commits.
+func hasOnCommitAction(s tree.CreateTableOnCommitSetting) bool {
+	return s == tree.CreateTableOnCommitDeleteRows || s == tree.CreateTableOnCommitDrop
+}
+
 func (n *createTableNode) startExec(params runParams) error {
 	// Check if the parent object is a replicated PCR descriptor, which will block
 	// schema changes.
@@ -322,12 +328,15 @@ func (n *createTableNode) startExec(params runParams) error {
 	if n.n.Persistence.IsTemporary() {
 		telemetry.Inc(sqltelemetry.CreateTempTableCounter)
 
-		// TODO(#46556): support ON COMMIT DROP and DELETE ROWS on TEMPORARY TABLE.
-		// If we do this, the n.n.OnCommit variable should probably be stored on the
-		// table descriptor.
-		// Note UNSET / PRESERVE ROWS behave the same way so we do not need to do that for now.
+		// Note UNSET / PRESERVE ROWS behave the same way. DELETE ROWS and DROP
+		// are tracked by the session, which carries them out on commit.
 		switch n.n.OnCommit {
 		case tree.CreateTableOnCommitUnset, tree.CreateTableOnCommitPreserveRows:
+		case tree.CreateTableOnCommitDeleteRows, tree.CreateTableOnCommitDrop:
+			if params.extendedEvalCtx.tempTableOnCommit == nil {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"ON COMMIT DELETE ROWS and ON COMMIT DROP are not supported in this context")
+			}
 		default:
 			return errors.AssertionFailedf("ON COMMIT value %d is unrecognized", n.n.OnCommit)
 		}
@@ -406,8 +415,10 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 
 		// If we have a single statement txn we want to run CTAS async, and
-		// consequently ensure it gets queued as a SchemaChange.
-		if params.extendedEvalCtx.TxnIsSingleStmt {
+		// consequently ensure it gets queued as a SchemaChange. Temporary tables
+		// with an ON COMMIT action are populated synchronously, since the action
+		// applies as soon as the statement's transaction commits.
+		if params.extendedEvalCtx.TxnIsSingleStmt && !hasOnCommitAction(n.n.OnCommit) {
 			desc.State = descpb.DescriptorState_ADD
 		}
 	} else {
@@ -463,6 +474,9 @@ func (n *createTableNode) startExec(params runParams) error {
 	); err != nil {
 		return err
 	}
+	if hasOnCommitAction(n.n.OnCommit) {
+		params.extendedEvalCtx.tempTableOnCommit.add(desc.GetID(), n.n.OnCommit)
+	}
 
 	for _, updated := range affected {
 		if err := params.p.writeSchemaChange(
@@ -577,6 +591,7 @@ func (n *createTableNode) startExec(params runParams) error {
 			if err := ti.init(params.ctx, params.p.txn, params.p.EvalContext()); err != nil {
 				return err
 			}
+			params.p.noteTempTableWrite(desc)
 
 			// Prepare the buffer for row values. At this point, one more column has
 			// been added by ensurePrimaryKey() to the list of columns in input, if
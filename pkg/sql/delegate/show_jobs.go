@@ -30,7 +30,7 @@ func constructSelectQuery(n *tree.ShowJobs) string {
 	baseQuery.WriteString(`user_name, status, running_status, `)
 	baseQuery.WriteString(`date_trunc('second', created) as created, date_trunc('second', started) as started, `)
 	baseQuery.WriteString(`date_trunc('second', finished) as finished, date_trunc('second', modified) as modified, `)
-	baseQuery.WriteString(`fraction_completed, error, coordinator_id`)
+	baseQuery.WriteString(`fraction_completed, error, coordinator_id, depends_on`)
 
 	if n.Jobs != nil {
 		baseQuery.WriteString(`, trace_id, execution_errors`)
@@ -307,6 +307,14 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Unlisten(ctx, n)
 	case *pgrepltree.IdentifySystem:
 		return p.IdentifySystem(ctx, n)
+	case *tree.ScheduledSQL:
+		// CREATE SCHEDULE FOR SQL is planned by a plan hook registered outside
+		// of pkg/sql.
+		plan, err := p.maybePlanHook(ctx, stmt)
+		if plan == nil && err == nil {
+			return nil, errors.AssertionFailedf("no plan hook registered for %T", stmt)
+		}
+		return plan, err
 	case tree.CCLOnlyStatement:
 		plan, err := p.maybePlanHook(ctx, stmt)
 		if plan == nil && err == nil {
@@ -410,6 +418,7 @@ func init() {
 		&tree.RevokeRole{},
 		&tree.RollbackPrepared{},
 		&tree.Scatter{},
+		&tree.ScheduledSQL{},
 		&tree.Scrub{},
 		&tree.SetClusterSetting{},
 		&tree.SetZoneConfig{},
@@ -444,7 +453,9 @@ func init() {
 		&tree.AlterTenantReplication{},
 		&tree.AlterTenantReset{},
 		&tree.Backup{},
+		&tree.BackupCopy{},
 		&tree.ShowBackup{},
+		&tree.ShowBackupDiff{},
 		&tree.Restore{},
 		&tree.CreateChangefeed{},
 		&tree.ScheduledChangefeed{},
@@ -1392,6 +1392,9 @@ func (ef *execFactory) ConstructInsert(
 	// Derive insert table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	if ef.planner.noteTempTableWrite(tabDesc) {
+		autoCommit = false
+	}
 	cols := makeColList(table, insertColOrdSet)
 
 	// Create the table inserter, which does the bulk of the work.
@@ -1468,6 +1471,9 @@ func (ef *execFactory) ConstructInsertFastPath(
 	// Derive insert table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	if ef.planner.noteTempTableWrite(tabDesc) {
+		autoCommit = false
+	}
 	cols := makeColList(table, insertColOrdSet)
 
 	// Create the table inserter, which does the bulk of the work.
@@ -1572,6 +1578,9 @@ func (ef *execFactory) ConstructUpdate(
 	// Derive table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	if ef.planner.noteTempTableWrite(tabDesc) {
+		autoCommit = false
+	}
 	fetchCols := makeColList(table, fetchColOrdSet)
 
 	// Add each column to update as a sourceSlot. The CBO only uses scalarSlot,
@@ -1666,6 +1675,9 @@ func (ef *execFactory) ConstructUpsert(
 	// Derive table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	if ef.planner.noteTempTableWrite(tabDesc) {
+		autoCommit = false
+	}
 	insertCols := makeColList(table, insertColOrdSet)
 	fetchCols := makeColList(table, fetchColOrdSet)
 	updateCols := makeColList(table, updateColOrdSet)
@@ -1766,6 +1778,9 @@ func (ef *execFactory) ConstructDelete(
 	// Derive table and column descriptors.
 	rowsNeeded := !returnColOrdSet.Empty()
 	tabDesc := table.(*optTable).desc
+	if ef.planner.noteTempTableWrite(tabDesc) {
+		autoCommit = false
+	}
 	fetchCols := makeColList(table, fetchColOrdSet)
 
 	// Create the table deleter, which does the bulk of the work. In the HP,
@@ -1831,6 +1846,9 @@ func (ef *execFactory) ConstructDeleteRange(
 	autoCommit bool,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
+	if ef.planner.noteTempTableWrite(tabDesc) {
+		autoCommit = false
+	}
 	var sb span.Builder
 	sb.Init(ef.planner.EvalContext(), ef.planner.ExecCfg().Codec, tabDesc, tabDesc.GetPrimaryIndex())
 
@@ -204,7 +204,7 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 			}
 		}
 
-	case NOT, WITH, AS, GENERATED, NULLS, RESET, ROLE, USER, ON, TENANT, CLUSTER, SET:
+	case NOT, WITH, AS, GENERATED, NULLS, RESET, ROLE, USER, ON, TENANT, CLUSTER, SET, BACKUP:
 		nextToken := sqlSymType{}
 		if l.lastPos+1 < len(l.tokens) {
 			nextToken = l.tokens[l.lastPos+1]
@@ -286,6 +286,15 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 			case ALL:
 				lval.id = CLUSTER_ALL
 			}
+		case BACKUP:
+			// COPY BACKUP FROM '<collection>' TO ... copies a backup collection,
+			// while COPY backup FROM STDIN copies into a table named backup.
+			if l.lastPos > 0 && l.tokens[l.lastPos-1].id == COPY && nextToken.id == FROM {
+				switch secondToken.id {
+				case SCONST, PLACEHOLDER:
+					lval.id = BACKUP_LA
+				}
+			}
 		case SET:
 			switch nextToken.id {
 			case TRACING:
@@ -741,6 +741,9 @@ func (u *sqlSymUnion) copyOptions() *tree.CopyOptions {
 func (u *sqlSymUnion) showJobOptions() *tree.ShowJobOptions {
   return u.val.(*tree.ShowJobOptions)
 }
+func (u *sqlSymUnion) backupDiffSource() *tree.BackupDiffSource {
+  return u.val.(*tree.BackupDiffSource)
+}
 func (u *sqlSymUnion) showBackupDetails() tree.ShowBackupDetails {
   return u.val.(tree.ShowBackupDetails)
 }
@@ -987,8 +990,8 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEC DECIMAL DEFAULT DEFAULTS DEFINER
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
-%token <str> DISABLE DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DEPENDS_ON DESC DESTINATION DETACHED DETAILS
+%token <str> DIFF DISABLE DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DROP
 
 %token <str> EACH ELSE ENABLE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
@@ -1101,8 +1104,10 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 // references.
 // - TENANT_ALL is used to differentiate `ALTER TENANT <id>` from
 // `ALTER TENANT ALL`. Ditto `CLUSTER_ALL` and `CLUSTER ALL`.
+// - BACKUP_LA is used to differentiate `COPY BACKUP FROM '<collection>'` from
+// `COPY backup FROM STDIN`, which copies into a table named backup.
 %token NOT_LA NULLS_LA WITH_LA AS_LA GENERATED_ALWAYS GENERATED_BY_DEFAULT RESET_ALL ROLE_ALL
-%token USER_ALL ON_LA TENANT_ALL CLUSTER_ALL SET_TRACING
+%token USER_ALL ON_LA TENANT_ALL CLUSTER_ALL SET_TRACING BACKUP_LA
 
 %union {
   id    int32
@@ -1252,6 +1257,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_schedule_stmt
 %type <tree.Statement> create_changefeed_stmt create_schedule_for_changefeed_stmt
+%type <tree.Statement> create_schedule_for_sql_stmt
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_extension_stmt
@@ -1436,6 +1442,7 @@ func (u *sqlSymUnion) doBlockOption() tree.DoBlockOption {
 %type <*tree.RestoreOptions> opt_with_restore_options restore_options restore_options_list
 %type <*tree.TenantReplicationOptions> opt_with_replication_options replication_options replication_options_list
 %type <tree.ShowBackupDetails> show_backup_details
+%type <*tree.BackupDiffSource> backup_diff_source
 %type <*tree.ShowJobOptions> show_job_options show_job_options_list
 %type <*tree.ShowBackupOptions> opt_with_show_backup_options show_backup_options show_backup_options_list
 %type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list copy_generic_options copy_generic_options_list
@@ -3401,6 +3408,10 @@ opt_clear_data:
 //        [ AS OF SYSTEM TIME <expr> ]
 //				[ WITH <option> [= <value>] [, ...] ]
 //
+// Copy the backups of a collection that are missing from another destination
+// COPY BACKUP FROM <destination> TO <destination>
+//				[ WITH <option> [= <value>] [, ...] ]
+//
 // Targets:
 //    Empty targets list: backup full cluster.
 //    TABLE <pattern> [, ...]
@@ -3416,6 +3427,13 @@ opt_clear_data:
 //    detached: execute backup job asynchronously, without waiting for its completion
 //    incremental_location: specify a different path to store the incremental backup
 //    include_all_virtual_clusters: enable backups of all virtual clusters during a cluster backup
+//    depends_on=(<job_id> [, ...]): with detached, wait for the given jobs to succeed before running
+//
+// COPY BACKUP options:
+//    encryption_passphrase="secret": passphrase of the source backups, used to verify the copy
+//    kms="[kms_provider]://...": KMS URI of the source backups, used to verify the copy
+//    new_kms="[kms_provider]://...": re-wrap the encryption key of the copy with another KMS
+//    detached: execute the copy job asynchronously, without waiting for its completion
 //
 // %SeeAlso: RESTORE, WEBDOCS/backup.html
 backup_stmt:
@@ -3550,6 +3568,10 @@ backup_options:
   {
     $$.val = &tree.BackupOptions{UpdatesClusterMonitoringMetrics: $3.expr()}
   }
+| DEPENDS_ON '=' '(' expr_list ')'
+  {
+    $$.val = &tree.BackupOptions{DependsOn: $4.exprs()}
+  }
 
 include_all_clusters:
   INCLUDE_ALL_SECONDARY_TENANTS { /* SKIP DOC */ }
@@ -3908,6 +3930,11 @@ drop_external_connection_stmt:
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 // or
+// RESTORE TABLE <tablename> AS <newname> FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WHERE <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+// or
 // RESTORE SYSTEM USERS FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
@@ -3964,6 +3991,18 @@ restore_stmt:
       Options: *($8.restoreOptions()),
     }
   }
+| RESTORE TABLE table_name AS table_name FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_where_clause opt_with_restore_options
+  {
+    $$.val = &tree.Restore{
+      Targets: tree.BackupTargetList{Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{$3.unresolvedObjectName().ToUnresolvedName()}}},
+      NewTableName: $5.unresolvedObjectName(),
+      Subdir: $7.expr(),
+      From: $9.stringOrPlaceholderOptList(),
+      AsOf: $10.asOfClause(),
+      Where: tree.NewWhere(tree.AstWhere, $11.expr()),
+      Options: *($12.restoreOptions()),
+    }
+  }
 | RESTORE SYSTEM USERS FROM error
   {
     setErr(sqllex, errors.New("The `RESTORE <targets> FROM <backupURI>` syntax is no longer supported. Please use `RESTORE <targets> FROM <subdirectory> IN <collectionURI>`."))
@@ -4297,6 +4336,15 @@ copy_stmt:
        Options: *$6.copyOptions(),
     }
   }
+| COPY BACKUP_LA FROM string_or_placeholder TO string_or_placeholder opt_with_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.BackupCopy{
+      From: $4.expr(),
+      To: $6.expr(),
+      Options: $7.kvOptions(),
+    }
+  }
 | COPY table_name opt_column_list FROM error
   {
     return unimplemented(sqllex, "copy from unsupported format")
@@ -4861,6 +4909,10 @@ logical_replication_options:
   {
      $$.val = &tree.LogicalReplicationOptions{UserFunctions: map[tree.UnresolvedName]tree.RoutineName{*$5.unresolvedObjectName().ToUnresolvedName():$2.unresolvedObjectName().ToRoutineName()}}
   }
+| CONFLICT POLICY string_or_placeholder FOR COLUMN prefixed_column_path
+  {
+     $$.val = &tree.LogicalReplicationOptions{ConflictPolicies: map[tree.UnresolvedName]tree.Expr{*$6.unresolvedName(): $3.expr()}}
+  }
  | DISCARD '=' string_or_placeholder
   {
     $$.val = &tree.LogicalReplicationOptions{Discard: $3.expr()}
@@ -5001,12 +5053,61 @@ replication_options:
 // %Category: Group
 // %Text:
 // CREATE SCHEDULE FOR BACKUP,
-// CREATE SCHEDULE FOR CHANGEFEED
+// CREATE SCHEDULE FOR CHANGEFEED,
+// CREATE SCHEDULE FOR SQL
 create_schedule_stmt:
   create_schedule_for_changefeed_stmt // EXTEND WITH HELP: CREATE SCHEDULE FOR CHANGEFEED
 | create_schedule_for_backup_stmt     // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
+| create_schedule_for_sql_stmt        // EXTEND WITH HELP: CREATE SCHEDULE FOR SQL
 | CREATE SCHEDULE error               // SHOW HELP: CREATE SCHEDULE
 
+// %Help: CREATE SCHEDULE FOR SQL - execute a SQL statement periodically
+// %Category: Misc
+// %Text:
+// CREATE SCHEDULE [IF NOT EXISTS]
+// [<description>]
+// FOR SQL <statement>
+// RECURRING <crontab>
+// [WITH SCHEDULE OPTIONS <schedule_option>[= <value>] [, ...] ]
+//
+// The statement is executed as the owner of the schedule, in the current
+// database at the time the schedule was created. Every execution runs in a
+// job, which can be listed with SHOW JOBS FOR SCHEDULE.
+//
+// Description:
+//   Optional description (or name) for this schedule
+//
+// statement:
+//   The SQL statement to execute, usually dollar-quoted:
+//     $$REFRESH MATERIALIZED VIEW daily_totals$$
+//
+// RECURRING <crontab>:
+//   The RECURRING expression specifies when the statement runs
+//   Schedule specified as a string in crontab format.
+//   All times in UTC.
+//     "5 0 * * *": run schedule 5 minutes past midnight.
+//     "@daily": run daily, at midnight
+//   See https://en.wikipedia.org/wiki/Cron
+//
+// SCHEDULE OPTIONS:
+//   first_run: TIMESTAMPTZ to specify when the statement runs for the first time
+//   on_execution_failure: [retry|reschedule|pause]
+//   on_previous_running: [start|skip|wait]
+//
+// %SeeAlso: SHOW SCHEDULES, SHOW CREATE SCHEDULES, PAUSE SCHEDULES, DROP SCHEDULES
+create_schedule_for_sql_stmt:
+  CREATE SCHEDULE /*$3=*/schedule_label_spec FOR SQL /*$6=*/sconst_or_placeholder
+  /*$7=*/cron_expr /*$8=*/opt_with_schedule_options
+  {
+    $$.val = &tree.ScheduledSQL{
+      ScheduleLabelSpec: *($3.scheduleLabelSpec()),
+      Statement:         $6.expr(),
+      Recurrence:        $7.expr(),
+      ScheduleOptions:   $8.kvOptions(),
+    }
+  }
+| CREATE SCHEDULE schedule_label_spec FOR SQL error // SHOW HELP: CREATE SCHEDULE FOR SQL
+
 // %Help: CREATE EXTENSION - pseudo-statement for PostgreSQL compatibility
 // %Category: Cfg
 // %Text: CREATE EXTENSION [IF NOT EXISTS] name
@@ -8682,7 +8783,15 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// SHOW BACKUP DIFF FOR TABLE <tablename> FROM <source> TO <source>
+//
+// <source> is either a backup, read as of its end time unless a time is given:
+//    <subdirectory> IN <collectionURI> [AS OF SYSTEM TIME <expr>]
+//    <backupURI> [AS OF SYSTEM TIME <expr>]
+// or the live table as of a time:
+//    AS OF SYSTEM TIME <expr>
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
   SHOW BACKUPS IN string_or_placeholder_opt_list
@@ -8710,6 +8819,14 @@ show_backup_stmt:
 			Options: *$6.showBackupOptions(),
 		}
 	}
+| SHOW BACKUP DIFF FOR TABLE table_name FROM backup_diff_source TO backup_diff_source
+	{
+		$$.val = &tree.ShowBackupDiff{
+			Table: $6.unresolvedObjectName(),
+			From:  *$8.backupDiffSource(),
+			To:    *$10.backupDiffSource(),
+		}
+	}
 | SHOW BACKUP string_or_placeholder opt_with_show_backup_options error
 	{
     setErr(sqllex, errors.New("The `SHOW BACKUP` syntax without the `IN` keyword is no longer supported. Please use `SHOW BACKUP FROM <subdirectory> IN <collectionURI>`."))
@@ -8771,6 +8888,20 @@ show_backup_details:
 	$$.val = tree.BackupValidateDetails
 	}
 
+backup_diff_source:
+  string_or_placeholder IN string_or_placeholder opt_as_of_clause
+  {
+    $$.val = &tree.BackupDiffSource{Path: $1.expr(), InCollection: $3.expr(), AsOf: $4.asOfClause()}
+  }
+| string_or_placeholder opt_as_of_clause
+  {
+    $$.val = &tree.BackupDiffSource{Path: $1.expr(), AsOf: $2.asOfClause()}
+  }
+| as_of_clause
+  {
+    $$.val = &tree.BackupDiffSource{AsOf: $1.asOfClause()}
+  }
+
 opt_with_show_backup_options:
   WITH show_backup_options_list
   {
@@ -9334,6 +9465,10 @@ opt_schedule_executor_type:
 	{
 		$$.val = tree.ScheduledChangefeedExecutor
 	}
+| FOR SQL
+  {
+    $$.val = tree.ScheduledSQLExecutor
+  }
 
 // %Help: SHOW TRACE - display an execution trace
 // %Category: Misc
@@ -10700,13 +10835,13 @@ opt_create_table_on_commit:
   {
     $$.val = tree.CreateTableOnCommitPreserveRows
   }
-| ON COMMIT DELETE ROWS error
+| ON COMMIT DELETE ROWS
   {
-    return unimplementedWithIssueDetail(sqllex, 46556, "delete rows")
+    $$.val = tree.CreateTableOnCommitDeleteRows
   }
-| ON COMMIT DROP error
+| ON COMMIT DROP
   {
-    return unimplementedWithIssueDetail(sqllex, 46556, "drop")
+    $$.val = tree.CreateTableOnCommitDrop
   }
 
 storage_parameter_key:
@@ -18102,9 +18237,11 @@ unreserved_keyword:
 | DEFINER
 | DELIMITER
 | DEPENDS
+| DEPENDS_ON
 | DESTINATION
 | DETACHED
 | DETAILS
+| DIFF
 | DISABLE
 | DISCARD
 | DOMAIN
@@ -18631,10 +18768,12 @@ bare_label_keywords:
 | DELETE
 | DELIMITER
 | DEPENDS
+| DEPENDS_ON
 | DESC
 | DESTINATION
 | DETACHED
 | DETAILS
+| DIFF
 | DISABLE
 | DISCARD
 | DISTINCT
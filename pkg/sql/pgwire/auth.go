@@ -202,6 +202,12 @@ func (c *conn) handleAuthentication(
 		return connClose, c.sendError(ctx, err)
 	}
 
+	// Only admins may choose their resource group when connecting. This needs
+	// to be checked before the role defaults are added below.
+	if err := sql.CheckResourceGroupSessionArg(c.sessionArgs, isSuperuser); err != nil {
+		return connClose, c.sendError(ctx, err)
+	}
+
 	// Add all the defaults to this session's defaults. If there is an
 	// error (e.g., a setting that no longer exists, or bad input),
 	// log a warning instead of preventing login.
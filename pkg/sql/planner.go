@@ -108,6 +108,10 @@ type extendedEvalContext struct {
 
 	// validateDbZoneConfig should the DB zone config on commit.
 	validateDbZoneConfig *bool
+
+	// tempTableOnCommit refers to the ON COMMIT actions of the session's
+	// temporary tables. It is nil for internal planners.
+	tempTableOnCommit *tempTableOnCommitActions
 }
 
 // copyFromExecCfg copies relevant fields from an ExecutorConfig.
@@ -762,9 +762,11 @@ func (f *txnKVFetcher) maybeAdmitBatchResponse(ctx context.Context, br *kvpb.Bat
 		}
 	} else if f.responseAdmissionQ != nil {
 		responseAdmission := admission.WorkInfo{
-			TenantID:   roachpb.SystemTenantID,
-			Priority:   admissionpb.WorkPriority(f.requestAdmissionHeader.Priority),
-			CreateTime: f.requestAdmissionHeader.CreateTime,
+			TenantID:            roachpb.SystemTenantID,
+			Priority:            admissionpb.WorkPriority(f.requestAdmissionHeader.Priority),
+			ResourceGroup:       f.requestAdmissionHeader.ResourceGroup,
+			ResourceGroupWeight: f.requestAdmissionHeader.ResourceGroupWeight,
+			CreateTime:          f.requestAdmissionHeader.CreateTime,
 		}
 		if _, err := f.responseAdmissionQ.Admit(ctx, responseAdmission); err != nil {
 			return err
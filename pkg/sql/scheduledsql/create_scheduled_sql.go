@@ -0,0 +1,260 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package scheduledsql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+const opName = "CREATE SCHEDULE FOR SQL"
+
+const (
+	optFirstRun          = "first_run"
+	optOnExecFailure     = "on_execution_failure"
+	optOnPreviousRunning = "on_previous_running"
+)
+
+var expectValues = map[string]exprutil.KVStringOptValidate{
+	optFirstRun:          exprutil.KVStringOptRequireValue,
+	optOnExecFailure:     exprutil.KVStringOptRequireValue,
+	optOnPreviousRunning: exprutil.KVStringOptRequireValue,
+}
+
+var headerCols = colinfo.ResultColumns{
+	{Name: "schedule_id", Typ: types.Int},
+	{Name: "label", Typ: types.String},
+	{Name: "status", Typ: types.String},
+	{Name: "first_run", Typ: types.TimestampTZ},
+	{Name: "schedule", Typ: types.String},
+	{Name: "statement", Typ: types.String},
+}
+
+// scheduledSQLSpec is a representation of tree.ScheduledSQL, prepared for
+// evaluation.
+type scheduledSQLSpec struct {
+	*tree.ScheduledSQL
+
+	scheduleLabel *string
+	recurrence    *string
+	statement     string
+	scheduleOpts  map[string]string
+}
+
+func makeScheduledSQLSpec(
+	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledSQL,
+) (*scheduledSQLSpec, error) {
+	exprEval := p.ExprEvaluator(opName)
+	spec := &scheduledSQLSpec{ScheduledSQL: schedule}
+
+	if schedule.ScheduleLabelSpec.Label != nil {
+		label, err := exprEval.String(ctx, schedule.ScheduleLabelSpec.Label)
+		if err != nil {
+			return nil, err
+		}
+		spec.scheduleLabel = &label
+	}
+
+	if schedule.Recurrence == nil {
+		// Sanity check: recurrence must be specified.
+		return nil, errors.New("RECURRING clause required")
+	}
+	rec, err := exprEval.String(ctx, schedule.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	spec.recurrence = &rec
+
+	spec.statement, err = exprEval.String(ctx, schedule.Statement)
+	if err != nil {
+		return nil, err
+	}
+	// The statement is only executed when the schedule runs, so make sure that
+	// it is at least a single statement that parses.
+	if _, err := parser.ParseOne(spec.statement); err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid scheduled statement")
+	}
+
+	spec.scheduleOpts, err = exprEval.KVOptions(ctx, schedule.ScheduleOptions, expectValues)
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func makeScheduleDetails(opts map[string]string) (jobspb.ScheduleDetails, error) {
+	var details jobspb.ScheduleDetails
+	if v, ok := opts[optOnExecFailure]; ok {
+		if err := schedulebase.ParseOnError(v, &details); err != nil {
+			return details, err
+		}
+	}
+	if v, ok := opts[optOnPreviousRunning]; ok {
+		if err := schedulebase.ParseWaitBehavior(v, &details); err != nil {
+			return details, err
+		}
+	}
+	return details, nil
+}
+
+// doCreateScheduledSQL creates the requested schedule. The schedule is owned
+// by the user creating it, and its statement runs as that user in the current
+// database of the session.
+func doCreateScheduledSQL(
+	ctx context.Context, p sql.PlanHookState, spec *scheduledSQLSpec, resultsCh chan<- tree.Datums,
+) error {
+	env := sql.JobSchedulerEnv(p.ExecCfg().JobsKnobs())
+	if knobs, ok := p.ExecCfg().DistSQLSrv.TestingKnobs.JobsTestingKnobs.(*jobs.TestingKnobs); ok {
+		if knobs.JobSchedulerEnv != nil {
+			env = knobs.JobSchedulerEnv
+		}
+	}
+
+	recurrence, err := schedulebase.ComputeScheduleRecurrence(env.Now(), spec.recurrence)
+	if err != nil {
+		return err
+	}
+
+	var scheduleLabel string
+	if spec.scheduleLabel != nil {
+		if spec.ScheduleLabelSpec.IfNotExists {
+			exists, err := schedulebase.CheckScheduleAlreadyExists(ctx, p, *spec.scheduleLabel)
+			if err != nil {
+				return err
+			}
+			if exists {
+				p.BufferClientNotice(ctx,
+					pgnotice.Newf("schedule %q already exists, skipping", *spec.scheduleLabel),
+				)
+				return nil
+			}
+		}
+		scheduleLabel = *spec.scheduleLabel
+	} else {
+		scheduleLabel = fmt.Sprintf("SQL %d", env.Now().Unix())
+	}
+
+	evalCtx := &p.ExtendedEvalContext().Context
+	details, err := makeScheduleDetails(spec.scheduleOpts)
+	if err != nil {
+		return err
+	}
+	details.ClusterID = evalCtx.ClusterID
+	details.CreationClusterVersion = p.ExecCfg().Settings.Version.ActiveVersion(ctx)
+
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(scheduleLabel)
+	sj.SetOwner(p.User())
+	if err := sj.SetScheduleAndNextRun(recurrence.Cron); err != nil {
+		return err
+	}
+	sj.SetScheduleDetails(details)
+
+	args, err := pbtypes.MarshalAny(&jobspb.ScheduledSQLExecutionArgs{
+		Statement: spec.statement,
+		Database:  p.CurrentDatabase(),
+	})
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledSQLExecutor.InternalName(), jobspb.ExecutionArguments{Args: args},
+	)
+
+	if v, ok := spec.scheduleOpts[optFirstRun]; ok {
+		firstRun, _, err := tree.ParseDTimestampTZ(evalCtx, v, time.Microsecond)
+		if err != nil {
+			return err
+		}
+		sj.SetNextRun(firstRun.Time)
+	}
+
+	if err := jobs.ScheduledJobTxn(p.InternalSQLTxn()).Create(ctx, sj); err != nil {
+		return err
+	}
+
+	nextRun, err := tree.MakeDTimestampTZ(sj.NextRun(), time.Microsecond)
+	if err != nil {
+		return err
+	}
+	resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(sj.ScheduleID())),
+		tree.NewDString(sj.ScheduleLabel()),
+		tree.NewDString("ACTIVE"),
+		nextRun,
+		tree.NewDString(sj.ScheduleExpr()),
+		tree.NewDString(spec.statement),
+	}
+	telemetry.Count("scheduled-sql.create.success")
+	return nil
+}
+
+func createScheduledSQLHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledSQL)
+	if !ok {
+		return nil, nil, false, nil
+	}
+
+	spec, err := makeScheduledSQLSpec(ctx, p, schedule)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		if err := doCreateScheduledSQL(ctx, p, spec, resultsCh); err != nil {
+			telemetry.Count("scheduled-sql.create.failed")
+			return err
+		}
+		return nil
+	}
+	return fn, headerCols, false, nil
+}
+
+func createScheduledSQLTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	schedule, ok := stmt.(*tree.ScheduledSQL)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(ctx, opName, p.SemaCtx(),
+		exprutil.Strings{
+			schedule.Statement,
+			schedule.Recurrence,
+			schedule.ScheduleLabelSpec.Label,
+		},
+		&exprutil.KVOptions{
+			KVOptions:  schedule.ScheduleOptions,
+			Validation: expectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, headerCols, nil
+}
+
+func init() {
+	sql.AddPlanHook("schedule sql", createScheduledSQLHook, createScheduledSQLTypeCheck)
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package scheduledsql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// scheduledSQLExecutor executes the schedules created by CREATE SCHEDULE FOR
+// SQL. Every execution creates a job that runs the statement of the schedule.
+type scheduledSQLExecutor struct {
+	metrics *jobs.ExecutorMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = (*scheduledSQLExecutor)(nil)
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (s *scheduledSQLExecutor) ExecuteJob(
+	ctx context.Context,
+	txn isql.Txn,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) error {
+	if err := s.createJob(ctx, txn, cfg, sj); err != nil {
+		s.metrics.NumFailed.Inc(1)
+		return err
+	}
+	s.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (s *scheduledSQLExecutor) createJob(
+	ctx context.Context, txn isql.Txn, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob,
+) error {
+	args, err := unmarshalArgs(sj)
+	if err != nil {
+		return err
+	}
+
+	p, cleanup := cfg.PlanHookMaker(ctx, "invoke-scheduled-sql", txn.KV(), sj.Owner())
+	defer cleanup()
+	jr := p.(sql.PlanHookState).ExecCfg().JobRegistry
+	record := jobs.Record{
+		Description: args.Statement,
+		Statements:  []string{args.Statement},
+		Username:    sj.Owner(),
+		Details: jobspb.ScheduledSQLDetails{
+			Statement: args.Statement,
+			Database:  args.Database,
+		},
+		Progress: jobspb.ScheduledSQLProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			Name: jobs.CreatedByScheduledJobs,
+			ID:   int64(sj.ScheduleID()),
+		},
+	}
+	jobID, err := jr.CreateAdoptableJobWithTxn(ctx, record, jr.MakeJobID(), txn)
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "scheduled SQL %d started job %d", sj.ScheduleID(), jobID)
+	return nil
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (s *scheduledSQLExecutor) NotifyJobTermination(
+	ctx context.Context,
+	txn isql.Txn,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+) error {
+	if jobStatus == jobs.StatusSucceeded {
+		s.metrics.NumSucceeded.Inc(1)
+		schedule.SetScheduleStatus(string(jobStatus))
+		return nil
+	}
+
+	s.metrics.NumFailed.Inc(1)
+	jobs.DefaultHandleFailedRun(schedule, "scheduled SQL job %d failed with status %s", jobID, jobStatus)
+	return nil
+}
+
+// Metrics implements jobs.ScheduledJobExecutor interface.
+func (s *scheduledSQLExecutor) Metrics() metric.Struct {
+	return s.metrics
+}
+
+// GetCreateScheduleStatement implements jobs.ScheduledJobExecutor interface.
+func (s *scheduledSQLExecutor) GetCreateScheduleStatement(
+	ctx context.Context, txn isql.Txn, env scheduledjobs.JobSchedulerEnv, sj *jobs.ScheduledJob,
+) (string, error) {
+	args, err := unmarshalArgs(sj)
+	if err != nil {
+		return "", err
+	}
+
+	wait, err := schedulebase.ParseOnPreviousRunningOption(sj.ScheduleDetails().Wait)
+	if err != nil {
+		return "", err
+	}
+	onError, err := schedulebase.ParseOnErrorOption(sj.ScheduleDetails().OnError)
+	if err != nil {
+		return "", err
+	}
+
+	node := &tree.ScheduledSQL{
+		ScheduleLabelSpec: tree.LabelSpec{
+			Label: tree.NewStrVal(sj.ScheduleLabel()),
+		},
+		Statement:  tree.NewStrVal(args.Statement),
+		Recurrence: tree.NewStrVal(sj.ScheduleExpr()),
+		ScheduleOptions: tree.KVOptions{
+			tree.KVOption{
+				Key:   optOnExecFailure,
+				Value: tree.NewDString(onError),
+			},
+			tree.KVOption{
+				Key:   optOnPreviousRunning,
+				Value: tree.NewDString(wait),
+			},
+		},
+	}
+	return tree.AsString(node), nil
+}
+
+// unmarshalArgs returns the execution arguments of the SQL schedule.
+func unmarshalArgs(sj *jobs.ScheduledJob) (*jobspb.ScheduledSQLExecutionArgs, error) {
+	args := &jobspb.ScheduledSQLExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return nil, errors.Wrap(err, "un-marshaling args")
+	}
+	return args, nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(tree.ScheduledSQLExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledSQLExecutor.UserName())
+			return &scheduledSQLExecutor{
+				metrics: &m,
+			}, nil
+		})
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package scheduledsql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// executionStartedInfoKey is the job info key written before the statement of
+// a scheduled SQL job is executed.
+const executionStartedInfoKey = "scheduled_sql_execution_started"
+
+// scheduledSQLResumer executes the statement of a SQL schedule. A job is
+// created for every execution of the schedule, so the jobs created by a
+// schedule, and their payload and progress in the job info storage, are the
+// history of its runs.
+type scheduledSQLResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*scheduledSQLResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r *scheduledSQLResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	details := r.job.Details().(jobspb.ScheduledSQLDetails)
+	owner := r.job.Payload().UsernameProto.Decode()
+
+	// The statement need not be idempotent, so it is executed at most once: if
+	// the job is resumed after an earlier attempt started to execute it, e.g.
+	// because the job was paused or adopted by another node, the statement may
+	// have committed and the job fails instead.
+	if err := p.ExecCfg().InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		infoStorage := r.job.InfoStorage(txn)
+		_, started, err := infoStorage.Get(ctx, "scheduled-sql-get-started", executionStartedInfoKey)
+		if err != nil {
+			return err
+		}
+		if started {
+			return jobs.MarkAsPermanentJobError(errors.Newf(
+				"job %d was interrupted while executing its statement, "+
+					"which is not executed again since it may have committed", r.job.ID()))
+		}
+		return infoStorage.Write(ctx, executionStartedInfoKey, []byte{})
+	}); err != nil {
+		return err
+	}
+
+	// The statement is executed in an implicit transaction, as if the owner of
+	// the schedule had sent it from a session connected to the database the
+	// schedule was created in.
+	log.Infof(ctx, "executing scheduled SQL as %s: %s", owner, details.Statement)
+	rowsAffected, err := p.ExecCfg().InternalDB.Executor().ExecEx(
+		ctx, "scheduled-sql", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: owner, Database: details.Database},
+		details.Statement,
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress
+		progress.GetScheduledSQL().RowsAffected = int64(rowsAffected)
+		progress.RunningStatus = fmt.Sprintf("%d rows affected", rowsAffected)
+		ju.UpdateProgress(progress)
+		return nil
+	})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *scheduledSQLResumer) OnFailOrCancel(context.Context, interface{}, error) error {
+	return nil
+}
+
+// CollectProfile is part of the jobs.Resumer interface.
+func (r *scheduledSQLResumer) CollectProfile(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeScheduledSQL,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &scheduledSQLResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
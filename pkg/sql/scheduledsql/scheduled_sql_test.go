@@ -0,0 +1,176 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package scheduledsql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobstest"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type execSchedulesFn = func(ctx context.Context, maxSchedules int64) error
+
+func TestScheduledSQL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	env := jobstest.NewJobSchedulerTestEnv(
+		jobstest.UseSystemTables, timeutil.Now(), tree.ScheduledSQLExecutor)
+	var executeSchedules execSchedulesFn
+	var args base.TestServerArgs
+	args.Knobs.JobsTestingKnobs = &jobs.TestingKnobs{
+		JobSchedulerEnv: env,
+		TakeOverJobsScheduling: func(fn execSchedulesFn) {
+			executeSchedules = fn
+		},
+	}
+	srv, db, _ := serverutils.StartServer(t, args)
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	tdb := sqlutils.MakeSQLRunner(db)
+
+	tdb.Exec(t, `CREATE DATABASE d`)
+	tdb.Exec(t, `USE d`)
+	tdb.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v INT)`)
+	tdb.Exec(t, `INSERT INTO t SELECT i, 0 FROM generate_series(1, 5) AS g(i)`)
+
+	// runSchedule executes the schedule and waits for the job it created to
+	// reach a terminal state, returning the ID of that job.
+	runSchedule := func(t *testing.T, scheduleID jobspb.ScheduleID) jobspb.JobID {
+		t.Helper()
+		tdb.Exec(t, `UPDATE system.scheduled_jobs SET next_run = $1 WHERE schedule_id = $2`,
+			env.Now(), scheduleID)
+		env.AdvanceTime(time.Minute)
+		require.NoError(t, executeSchedules(ctx, 0 /* maxSchedules */))
+		var jobID jobspb.JobID
+		testutils.SucceedsSoon(t, func() error {
+			s.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+			var status string
+			if err := db.QueryRow(fmt.Sprintf(`
+SELECT job_id, status FROM [SHOW JOBS FOR SCHEDULE %d]
+ORDER BY created DESC LIMIT 1`, scheduleID)).Scan(&jobID, &status); err != nil {
+				return err
+			}
+			if status != string(jobs.StatusSucceeded) && status != string(jobs.StatusFailed) {
+				return errors.Newf("job %d is %s", jobID, status)
+			}
+			return nil
+		})
+		return jobID
+	}
+
+	t.Run("run", func(t *testing.T) {
+		var scheduleID jobspb.ScheduleID
+		tdb.QueryRow(t, `
+CREATE SCHEDULE 'bump' FOR SQL 'UPDATE t SET v = v + 1' RECURRING '@hourly'`,
+		).Scan(&scheduleID, new(string), new(string), new(string), new(string), new(string))
+
+		tdb.CheckQueryResults(t,
+			`SELECT label, command FROM [SHOW SCHEDULES FOR SQL]`,
+			[][]string{{"bump", "UPDATE t SET v = v + 1"}},
+		)
+		tdb.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT create_statement FROM [SHOW CREATE SCHEDULE %d]`, scheduleID),
+			[][]string{{
+				`CREATE SCHEDULE 'bump' FOR SQL 'UPDATE t SET v = v + 1' RECURRING '@hourly' ` +
+					`WITH SCHEDULE OPTIONS on_execution_failure = 'RESCHEDULE', on_previous_running = 'WAIT'`,
+			}},
+		)
+
+		jobID := runSchedule(t, scheduleID)
+		tdb.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT job_type, status, user_name FROM [SHOW JOB %d]`, jobID),
+			[][]string{{"SCHEDULED SQL", "succeeded", "root"}},
+		)
+		tdb.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT running_status FROM crdb_internal.jobs WHERE job_id = %d`, jobID),
+			[][]string{{"5 rows affected"}},
+		)
+		tdb.CheckQueryResults(t, `SELECT sum(v) FROM t`, [][]string{{"5"}})
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		var scheduleID jobspb.ScheduleID
+		tdb.QueryRow(t, `
+CREATE SCHEDULE 'broken' FOR SQL 'INSERT INTO t VALUES (1, 1)' RECURRING '@hourly'
+WITH SCHEDULE OPTIONS on_execution_failure = 'pause'`,
+		).Scan(&scheduleID, new(string), new(string), new(string), new(string), new(string))
+
+		jobID := runSchedule(t, scheduleID)
+		tdb.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT status FROM [SHOW JOB %d]`, jobID), [][]string{{"failed"}},
+		)
+		tdb.CheckQueryResultsRetry(t,
+			fmt.Sprintf(`SELECT schedule_status FROM [SHOW SCHEDULE %d]`, scheduleID),
+			[][]string{{"PAUSED"}},
+		)
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		var scheduleID jobspb.ScheduleID
+		tdb.QueryRow(t, `
+CREATE SCHEDULE 'sleep' FOR SQL 'SELECT pg_sleep(1000)' RECURRING '@hourly'`,
+		).Scan(&scheduleID, new(string), new(string), new(string), new(string), new(string))
+		tdb.Exec(t, `UPDATE system.scheduled_jobs SET next_run = $1 WHERE schedule_id = $2`,
+			env.Now(), scheduleID)
+		env.AdvanceTime(time.Minute)
+		require.NoError(t, executeSchedules(ctx, 0 /* maxSchedules */))
+
+		// Pause the job once it started to execute the statement. When it is
+		// resumed, the statement is not executed again.
+		var jobID jobspb.JobID
+		testutils.SucceedsSoon(t, func() error {
+			s.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+			if err := db.QueryRow(fmt.Sprintf(`
+SELECT job_id FROM [SHOW JOBS FOR SCHEDULE %d] WHERE status = 'running'`, scheduleID),
+			).Scan(&jobID); err != nil {
+				return err
+			}
+			var started bool
+			if err := db.QueryRow(`
+SELECT count(*) > 0 FROM system.job_info WHERE job_id = $1 AND info_key = $2`,
+				jobID, "scheduled_sql_execution_started").Scan(&started); err != nil {
+				return err
+			}
+			if !started {
+				return errors.Newf("job %d has not started to execute its statement", jobID)
+			}
+			return nil
+		})
+		tdb.Exec(t, `PAUSE JOB $1`, jobID)
+		jobutils.WaitForJobToPause(t, tdb, jobID)
+		tdb.Exec(t, `RESUME JOB $1`, jobID)
+		jobutils.WaitForJobToFail(t, tdb, jobID)
+		tdb.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT error LIKE '%%not executed again%%' FROM [SHOW JOB %d]`, jobID),
+			[][]string{{"true"}},
+		)
+	})
+
+	t.Run("invalid statement", func(t *testing.T) {
+		tdb.ExpectErr(t, "invalid scheduled statement",
+			`CREATE SCHEDULE FOR SQL 'SELEC 1' RECURRING '@hourly'`)
+		tdb.ExpectErr(t, "invalid scheduled statement",
+			`CREATE SCHEDULE FOR SQL 'SELECT 1; SELECT 2' RECURRING '@hourly'`)
+	})
+}
@@ -2607,6 +2607,28 @@ var builtinOidsArray = []string{
 	2644: `crdb_internal.range_stats_with_errors(key: bytes) -> jsonb`,
 	2645: `crdb_internal.lease_holder_with_errors(key: bytes) -> jsonb`,
 	2646: `crdb_internal.pretty_key(raw_key: bytes) -> string`,
+	2647: `ts_rank_cd(weights: float[], vector: tsvector, query: tsquery, normalization: int) -> float4`,
+	2648: `ts_rank_cd(weights: float[], vector: tsvector, query: tsquery) -> float4`,
+	2649: `ts_rank_cd(vector: tsvector, query: tsquery, normalization: int) -> float4`,
+	2650: `ts_rank_cd(vector: tsvector, query: tsquery) -> float4`,
+	2651: `websearch_to_tsquery(config: string, text: string) -> tsquery`,
+	2652: `websearch_to_tsquery(text: string) -> tsquery`,
+	2653: `ts_headline(config: string, document: string, query: tsquery, options: string) -> string`,
+	2654: `ts_headline(config: string, document: string, query: tsquery) -> string`,
+	2655: `ts_headline(document: string, query: tsquery, options: string) -> string`,
+	2656: `ts_headline(document: string, query: tsquery) -> string`,
+	2657: `setweight(vector: tsvector, weight: "char") -> tsvector`,
+	2658: `setweight(vector: tsvector, weight: "char", lexemes: string[]) -> tsvector`,
+	2659: `ts_delete(vector: tsvector, lexeme: string) -> tsvector`,
+	2660: `ts_delete(vector: tsvector, lexemes: string[]) -> tsvector`,
+	2661: `ts_filter(vector: tsvector, weights: "char"[]) -> tsvector`,
+	2662: `jsonb_to_tsvector(config: string, document: jsonb, filter: jsonb) -> tsvector`,
+	2663: `jsonb_to_tsvector(document: jsonb, filter: jsonb) -> tsvector`,
+	2664: `json_to_tsvector(config: string, document: jsonb, filter: jsonb) -> tsvector`,
+	2665: `json_to_tsvector(document: jsonb, filter: jsonb) -> tsvector`,
+	2666: `crdb_internal.backup_rows(collection: string, subdir: string, table: string) -> tuple`,
+	2667: `crdb_internal.backup_rows(collection: string, subdir: string, table: string, as_of: timestamptz) -> tuple`,
+	2668: `crdb_internal.backup_rows(collection: string, subdir: string, table: string, as_of: timestamptz, filter: string) -> tuple`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
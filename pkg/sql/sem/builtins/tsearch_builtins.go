@@ -7,6 +7,7 @@ package builtins
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -16,7 +17,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -328,6 +331,367 @@ var tsearchBuiltins = map[string]builtinDefinition{
 			Volatility: volatility.Immutable,
 		},
 	),
+	"ts_rank_cd": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "weights", Typ: types.FloatArray},
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "normalization", Typ: types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				weights, err := getWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				rank, err := tsearch.RankCD(
+					weights,
+					tree.MustBeDTSVector(args[1]).TSVector,
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+					int(tree.MustBeDInt(args[3])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       "Ranks vectors based on the cover density of their matching lexemes.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "weights", Typ: types.FloatArray},
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				weights, err := getWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				rank, err := tsearch.RankCD(
+					weights,
+					tree.MustBeDTSVector(args[1]).TSVector,
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+					0, /* method */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       "Ranks vectors based on the cover density of their matching lexemes.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "normalization", Typ: types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				rank, err := tsearch.RankCD(
+					nil, /* weights */
+					tree.MustBeDTSVector(args[0]).TSVector,
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					int(tree.MustBeDInt(args[2])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       "Ranks vectors based on the cover density of their matching lexemes.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				rank, err := tsearch.RankCD(
+					nil, /* weights */
+					tree.MustBeDTSVector(args[0]).TSVector,
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					0, /* method */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       "Ranks vectors based on the cover density of their matching lexemes.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"websearch_to_tsquery": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := string(tree.MustBeDString(args[0]))
+				input := string(tree.MustBeDString(args[1]))
+				query, err := tsearch.WebSearchToTSQuery(config, input)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info: "Converts text to a tsquery, normalizing words according to the specified configuration." +
+				" The input may use web search syntax: quoted text is a phrase, `or` is the | operator," +
+				" `-` is the ! operator, and the & operator is inserted between the other tokens.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+				input := string(tree.MustBeDString(args[0]))
+				query, err := tsearch.WebSearchToTSQuery(config, input)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info: "Converts text to a tsquery, normalizing words according to the default configuration." +
+				" The input may use web search syntax: quoted text is a phrase, `or` is the | operator," +
+				" `-` is the ! operator, and the & operator is inserted between the other tokens.",
+			Volatility: volatility.Stable,
+		},
+	),
+	"ts_headline": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "config", Typ: types.String},
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "options", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tsHeadline(
+					string(tree.MustBeDString(args[0])),
+					args[1],
+					args[2],
+					string(tree.MustBeDString(args[3])),
+				)
+			},
+			Info: "Returns an excerpt of the document in which the words matching the query are highlighted, " +
+				"parsing the document according to the specified configuration.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "config", Typ: types.String},
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tsHeadline(string(tree.MustBeDString(args[0])), args[1], args[2], "" /* options */)
+			},
+			Info: "Returns an excerpt of the document in which the words matching the query are highlighted, " +
+				"parsing the document according to the specified configuration.",
+			Volatility: volatility.Immutable,
+			// In Postgres the configuration is a regconfig, so this overload is
+			// mistaken for the Stable ts_headline(document, query, options).
+			IgnoreVolatilityCheck: true,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "options", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+				return tsHeadline(config, args[0], args[1], string(tree.MustBeDString(args[2])))
+			},
+			Info: "Returns an excerpt of the document in which the words matching the query are highlighted, " +
+				"parsing the document according to the default configuration.",
+			Volatility: volatility.Stable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+				return tsHeadline(config, args[0], args[1], "" /* options */)
+			},
+			Info: "Returns an excerpt of the document in which the words matching the query are highlighted, " +
+				"parsing the document according to the default configuration.",
+			Volatility: volatility.Stable,
+		},
+	),
+	"setweight": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "weight", Typ: types.QChar},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				vector, err := tsearch.SetWeight(
+					tree.MustBeDTSVector(args[0]).TSVector,
+					getWeightLetter(args[1]),
+					nil, /* lexemes */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Assigns the given weight to each position of the vector.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "weight", Typ: types.QChar},
+				{Name: "lexemes", Typ: types.StringArray},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				lexemes, err := getLexemes(tree.MustBeDArray(args[2]))
+				if err != nil {
+					return nil, err
+				}
+				vector, err := tsearch.SetWeight(
+					tree.MustBeDTSVector(args[0]).TSVector,
+					getWeightLetter(args[1]),
+					lexemes,
+				)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Assigns the given weight to each position of the given lexemes of the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"ts_delete": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "lexeme", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				vector := tsearch.DeleteLexemes(
+					tree.MustBeDTSVector(args[0]).TSVector,
+					[]string{string(tree.MustBeDString(args[1]))},
+				)
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Removes the given lexeme from the vector.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "lexemes", Typ: types.StringArray},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				lexemes, err := getLexemes(tree.MustBeDArray(args[1]))
+				if err != nil {
+					return nil, err
+				}
+				vector := tsearch.DeleteLexemes(tree.MustBeDTSVector(args[0]).TSVector, lexemes)
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Removes the given lexemes from the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"ts_filter": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "weights", Typ: types.MakeArray(types.QChar)},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[1])
+				letters := make([]byte, len(arr.Array))
+				for i, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed, "weight array may not contain nulls")
+					}
+					letters[i] = getWeightLetter(d)
+				}
+				vector, err := tsearch.FilterWeights(tree.MustBeDTSVector(args[0]).TSVector, letters)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Returns the vector with only the positions which have one of the given weights.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"jsonb_to_tsvector": makeJSONToTSVectorBuiltin(),
+	"json_to_tsvector":  makeJSONToTSVectorBuiltin(),
+}
+
+func makeJSONToTSVectorBuiltin() builtinDefinition {
+	return makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "config", Typ: types.String},
+				{Name: "document", Typ: types.Jsonb},
+				{Name: "filter", Typ: types.Jsonb},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return jsonToTSVector(
+					string(tree.MustBeDString(args[0])),
+					tree.MustBeDJSON(args[1]).JSON,
+					tree.MustBeDJSON(args[2]).JSON,
+				)
+			},
+			Info: "Converts the values of a JSON document selected by the filter to a tsvector, " +
+				"normalizing words according to the specified configuration. The filter is a string " +
+				"or an array of strings among `string`, `numeric`, `boolean`, `key` and `all`.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "document", Typ: types.Jsonb},
+				{Name: "filter", Typ: types.Jsonb},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return jsonToTSVector(
+					tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig),
+					tree.MustBeDJSON(args[0]).JSON,
+					tree.MustBeDJSON(args[1]).JSON,
+				)
+			},
+			Info: "Converts the values of a JSON document selected by the filter to a tsvector, " +
+				"normalizing words according to the default configuration. The filter is a string " +
+				"or an array of strings among `string`, `numeric`, `boolean`, `key` and `all`.",
+			Volatility: volatility.Stable,
+		},
+	)
 }
 
 func getWeights(arr *tree.DArray) ([]float32, error) {
@@ -343,3 +707,155 @@ func getWeights(arr *tree.DArray) ([]float32, error) {
 	}
 	return ret, nil
 }
+
+// getWeightLetter returns the weight letter stored in the given "char" datum,
+// or 0 if it is empty, which isn't a valid weight.
+func getWeightLetter(d tree.Datum) byte {
+	s := tree.MustBeDString(d)
+	if len(s) == 0 {
+		return 0
+	}
+	return s[0]
+}
+
+func getLexemes(arr *tree.DArray) ([]string, error) {
+	ret := make([]string, len(arr.Array))
+	for i, d := range arr.Array {
+		if d == tree.DNull {
+			return nil, pgerror.New(pgcode.NullValueNotAllowed, "lexeme array may not contain nulls")
+		}
+		ret[i] = string(tree.MustBeDString(d))
+	}
+	return ret, nil
+}
+
+func tsHeadline(config string, document, query tree.Datum, options string) (tree.Datum, error) {
+	opts, err := tsearch.ParseHeadlineOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	headline, err := tsearch.Headline(
+		config,
+		string(tree.MustBeDString(document)),
+		tree.MustBeDTSQuery(query).TSQuery,
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDString(headline), nil
+}
+
+// jsonToTSVectorFlags are the kinds of JSON values which are included in the
+// result of jsonb_to_tsvector.
+type jsonToTSVectorFlags struct {
+	strings, numbers, booleans, keys bool
+}
+
+const jsonToTSVectorFlagsHint = `Possible values are: "string", "numeric", "boolean", "key", and "all".`
+
+func parseJSONToTSVectorFlags(filter json.JSON) (jsonToTSVectorFlags, error) {
+	var flags jsonToTSVectorFlags
+	var elems []json.JSON
+	switch filter.Type() {
+	case json.ArrayJSONType:
+		elems, _ = filter.AsArray()
+	case json.ObjectJSONType:
+		return flags, errors.WithHint(pgerror.New(pgcode.InvalidParameterValue,
+			"wrong flag type, only arrays and scalars are allowed"), jsonToTSVectorFlagsHint)
+	default:
+		elems = []json.JSON{filter}
+	}
+	for _, elem := range elems {
+		if elem.Type() != json.StringJSONType {
+			return flags, errors.WithHint(pgerror.New(pgcode.InvalidParameterValue,
+				"flag array element is not a string"), jsonToTSVectorFlagsHint)
+		}
+		text, err := elem.AsText()
+		if err != nil {
+			return flags, err
+		}
+		switch strings.ToLower(*text) {
+		case "string":
+			flags.strings = true
+		case "numeric":
+			flags.numbers = true
+		case "boolean":
+			flags.booleans = true
+		case "key":
+			flags.keys = true
+		case "all":
+			flags = jsonToTSVectorFlags{strings: true, numbers: true, booleans: true, keys: true}
+		default:
+			return flags, errors.WithHint(pgerror.Newf(pgcode.InvalidParameterValue,
+				"wrong flag in flag array: %q", *text), jsonToTSVectorFlagsHint)
+		}
+	}
+	return flags, nil
+}
+
+// collectJSONTexts appends the textual representation of the values of the
+// given JSON document which are selected by the flags to texts, in document
+// order.
+func collectJSONTexts(j json.JSON, flags jsonToTSVectorFlags, texts []string) ([]string, error) {
+	switch j.Type() {
+	case json.StringJSONType:
+		if !flags.strings {
+			return texts, nil
+		}
+	case json.NumberJSONType:
+		if !flags.numbers {
+			return texts, nil
+		}
+	case json.TrueJSONType, json.FalseJSONType:
+		if !flags.booleans {
+			return texts, nil
+		}
+	case json.ArrayJSONType:
+		elems, _ := j.AsArray()
+		var err error
+		for _, elem := range elems {
+			if texts, err = collectJSONTexts(elem, flags, texts); err != nil {
+				return nil, err
+			}
+		}
+		return texts, nil
+	case json.ObjectJSONType:
+		it, err := j.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			if flags.keys {
+				texts = append(texts, it.Key())
+			}
+			if texts, err = collectJSONTexts(it.Value(), flags, texts); err != nil {
+				return nil, err
+			}
+		}
+		return texts, nil
+	default:
+		return texts, nil
+	}
+	text, err := j.AsText()
+	if err != nil {
+		return nil, err
+	}
+	return append(texts, *text), nil
+}
+
+func jsonToTSVector(config string, document, filter json.JSON) (tree.Datum, error) {
+	flags, err := parseJSONToTSVectorFlags(filter)
+	if err != nil {
+		return nil, err
+	}
+	texts, err := collectJSONTexts(document, flags, nil /* texts */)
+	if err != nil {
+		return nil, err
+	}
+	vector, err := tsearch.DocumentsToTSVector(config, texts)
+	if err != nil {
+		return nil, err
+	}
+	return &tree.DTSVector{TSVector: vector}, nil
+}
@@ -43,6 +43,7 @@ type BackupOptions struct {
 	IncrementalStorage              StringOrPlaceholderOptList
 	ExecutionLocality               Expr
 	UpdatesClusterMonitoringMetrics Expr
+	DependsOn                       Exprs
 }
 
 var _ NodeFormatter = &BackupOptions{}
@@ -106,6 +107,46 @@ func (node Backup) Coverage() DescriptorCoverage {
 	return RequestedDescriptors
 }
 
+// BackupCopy represents a COPY BACKUP statement, which replicates a backup
+// collection to another destination.
+type BackupCopy struct {
+	// From is the URI of the collection being copied.
+	From Expr
+	// To is the URI of the collection that receives the copy.
+	To      Expr
+	Options KVOptions
+}
+
+var _ Statement = &BackupCopy{}
+
+// Format implements the NodeFormatter interface.
+func (node *BackupCopy) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY BACKUP FROM ")
+	ctx.FormatURI(node.From)
+	ctx.WriteString(" TO ")
+	ctx.FormatURI(node.To)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH OPTIONS (")
+		node.Options.formatEach(ctx, func(n *KVOption, ctx *FmtCtx) {
+			// Use literals here to avoid pulling in the backup package as a
+			// dependency.
+			switch string(n.Key) {
+			case "kms", "new_kms":
+				ctx.FormatURI(n.Value)
+			case "encryption_passphrase":
+				if ctx.flags.HasFlags(FmtShowPasswords) {
+					ctx.FormatNode(n.Value)
+				} else {
+					ctx.WriteString(PasswordSubstitution)
+				}
+			default:
+				ctx.FormatNode(n.Value)
+			}
+		})
+		ctx.WriteString(")")
+	}
+}
+
 // RestoreOptions describes options for the RESTORE execution.
 type RestoreOptions struct {
 	EncryptionPassphrase             Expr
@@ -148,6 +189,14 @@ type Restore struct {
 	// ... FROM 'subdir' IN 'from'...`. Alternatively, restore_planning.go will set
 	// it for the query `RESTORE ... FROM LATEST IN 'from'...`
 	Subdir Expr
+
+	// NewTableName is set by the parser when the SQL query is of the form
+	// `RESTORE TABLE t AS new_t ...`, in which case Targets contains exactly
+	// one table which is restored under this name.
+	NewTableName *UnresolvedObjectName
+	// Where, if set, restricts the rows of the table being restored under
+	// NewTableName to those that satisfy the predicate.
+	Where *Where
 }
 
 var _ Statement = &Restore{}
@@ -159,6 +208,11 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.Targets)
 		ctx.WriteString(" ")
 	}
+	if node.NewTableName != nil {
+		ctx.WriteString("AS ")
+		ctx.FormatNode(node.NewTableName)
+		ctx.WriteString(" ")
+	}
 	ctx.WriteString("FROM ")
 	if node.Subdir != nil {
 		ctx.FormatNode(node.Subdir)
@@ -169,6 +223,10 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.WriteString(" ")
 		ctx.FormatNode(&node.AsOf)
 	}
+	if node.Where != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Where)
+	}
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH OPTIONS (")
 		ctx.FormatNode(&node.Options)
@@ -297,6 +355,13 @@ func (o *BackupOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("updates_cluster_monitoring_metrics = ")
 		ctx.FormatNode(o.UpdatesClusterMonitoringMetrics)
 	}
+
+	if o.DependsOn != nil {
+		maybeAddSep()
+		ctx.WriteString("depends_on = (")
+		ctx.FormatNode(&o.DependsOn)
+		ctx.WriteString(")")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -357,6 +422,12 @@ func (o *BackupOptions) CombineWith(other *BackupOptions) error {
 	} else {
 		o.UpdatesClusterMonitoringMetrics = other.UpdatesClusterMonitoringMetrics
 	}
+
+	if o.DependsOn == nil {
+		o.DependsOn = other.DependsOn
+	} else if other.DependsOn != nil {
+		return errors.New("depends_on option specified multiple times")
+	}
 	return nil
 }
 
@@ -370,7 +441,8 @@ func (o BackupOptions) IsDefault() bool {
 		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants &&
-		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics
+		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics &&
+		o.DependsOn == nil
 }
 
 // Format implements the NodeFormatter interface.
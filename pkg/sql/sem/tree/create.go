@@ -1495,8 +1495,25 @@ const (
 	CreateTableOnCommitUnset CreateTableOnCommitSetting = iota
 	// CreateTableOnCommitPreserveRows indicates that ON COMMIT PRESERVE ROWS was set.
 	CreateTableOnCommitPreserveRows
+	// CreateTableOnCommitDeleteRows indicates that ON COMMIT DELETE ROWS was set.
+	CreateTableOnCommitDeleteRows
+	// CreateTableOnCommitDrop indicates that ON COMMIT DROP was set.
+	CreateTableOnCommitDrop
 )
 
+// clause returns the ON COMMIT clause for the setting. It is empty for
+// PRESERVE ROWS, which is the default behavior.
+func (s CreateTableOnCommitSetting) clause() string {
+	switch s {
+	case CreateTableOnCommitDeleteRows:
+		return "ON COMMIT DELETE ROWS"
+	case CreateTableOnCommitDrop:
+		return "ON COMMIT DROP"
+	default:
+		return ""
+	}
+}
+
 // CreateTable represents a CREATE TABLE statement.
 type CreateTable struct {
 	IfNotExists      bool
@@ -1567,6 +1584,10 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		}
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
+		if c := node.OnCommit.clause(); c != "" {
+			ctx.WriteByte(' ')
+			ctx.WriteString(c)
+		}
 	} else {
 		ctx.WriteString(" (")
 		ctx.FormatNode(&node.Defs)
@@ -1579,6 +1600,10 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 			ctx.FormatNode(&node.StorageParams)
 			ctx.WriteByte(')')
 		}
+		if c := node.OnCommit.clause(); c != "" {
+			ctx.WriteByte(' ')
+			ctx.WriteString(c)
+		}
 		if node.Locality != nil {
 			ctx.WriteString(" ")
 			ctx.FormatNode(node.Locality)
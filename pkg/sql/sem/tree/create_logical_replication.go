@@ -26,7 +26,9 @@ type LogicalReplicationResources struct {
 
 type LogicalReplicationOptions struct {
 	// Mapping of table name to UDF name
-	UserFunctions    map[UnresolvedName]RoutineName
+	UserFunctions map[UnresolvedName]RoutineName
+	// Mapping of table-qualified column name to conflict resolution policy
+	ConflictPolicies map[UnresolvedName]Expr
 	Cursor           Expr
 	MetricsLabel     Expr
 	Mode             Expr
@@ -135,6 +137,23 @@ func (lro *LogicalReplicationOptions) Format(ctx *FmtCtx) {
 			ctx.FormatNode(&k)
 		}
 	}
+	if lro.ConflictPolicies != nil {
+		keys := make([]UnresolvedName, 0, len(lro.ConflictPolicies))
+		for k := range lro.ConflictPolicies {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, k := range keys {
+			maybeAddSep()
+			ctx.WriteString("CONFLICT POLICY ")
+			ctx.FormatNode(lro.ConflictPolicies[k])
+			ctx.WriteString(" FOR COLUMN ")
+			ctx.FormatNode(&k)
+		}
+	}
 	if lro.Discard != nil {
 		maybeAddSep()
 		ctx.WriteString("DISCARD = ")
@@ -206,6 +225,18 @@ func (o *LogicalReplicationOptions) CombineWith(other *LogicalReplicationOptions
 		}
 	}
 
+	if other.ConflictPolicies != nil {
+		for col := range other.ConflictPolicies {
+			if _, ok := o.ConflictPolicies[col]; ok {
+				return errors.Newf("multiple conflict policies specified for column %s", col.String())
+			}
+			if o.ConflictPolicies == nil {
+				o.ConflictPolicies = make(map[UnresolvedName]Expr)
+			}
+			o.ConflictPolicies[col] = other.ConflictPolicies[col]
+		}
+	}
+
 	if o.Discard != nil {
 		if other.Discard != nil {
 			return errors.New("DISCARD option specified multiple times")
@@ -261,6 +292,7 @@ func (o LogicalReplicationOptions) IsDefault() bool {
 		o.Mode == options.Mode &&
 		o.DefaultFunction == options.DefaultFunction &&
 		o.UserFunctions == nil &&
+		o.ConflictPolicies == nil &&
 		o.Discard == options.Discard &&
 		o.SkipSchemaCheck == options.SkipSchemaCheck &&
 		o.MetricsLabel == options.MetricsLabel &&
@@ -637,6 +637,7 @@ const (
 	TTLExpirationExpr               SchemaExprContext = "TTL EXPIRATION EXPRESSION"
 	TTLDefaultExpr                  SchemaExprContext = "TTL DEFAULT"
 	TTLUpdateExpr                   SchemaExprContext = "TTL UPDATE"
+	RestoreRowFilterExpr            SchemaExprContext = "RESTORE WHERE"
 )
 
 func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
@@ -1303,6 +1303,9 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 			),
 		)
 	}
+	if c := node.OnCommit.clause(); c != "" {
+		clauses = append(clauses, pretty.Keyword(c))
+	}
 	if node.Locality != nil {
 		clauses = append(clauses, p.Doc(node.Locality))
 	}
@@ -2184,6 +2187,9 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	if node.DescriptorCoverage == RequestedDescriptors {
 		items = append(items, node.Targets.docRow(p))
 	}
+	if node.NewTableName != nil {
+		items = append(items, p.row("AS", p.Doc(node.NewTableName)))
+	}
 	from := p.Doc(&node.From)
 	items = append(items, p.row("FROM", p.Doc(node.Subdir)))
 	items = append(items, p.row("IN", from))
@@ -2191,6 +2197,9 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	if node.AsOf.Expr != nil {
 		items = append(items, node.AsOf.docRow(p))
 	}
+	if node.Where != nil {
+		items = append(items, node.Where.docRow(p))
+	}
 	if !node.Options.IsDefault() {
 		items = append(items, p.row("WITH", p.Doc(&node.Options)))
 	}
@@ -138,6 +138,55 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 	}
 }
 
+// ShowBackupDiff represents a SHOW BACKUP DIFF statement, which lists the rows
+// of a table that differ between two backups, two times of the live table, or
+// a backup and the live table.
+type ShowBackupDiff struct {
+	Table *UnresolvedObjectName
+	From  BackupDiffSource
+	To    BackupDiffSource
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowBackupDiff) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW BACKUP DIFF FOR TABLE ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" FROM ")
+	ctx.FormatNode(&node.From)
+	ctx.WriteString(" TO ")
+	ctx.FormatNode(&node.To)
+}
+
+// BackupDiffSource is one side of a SHOW BACKUP DIFF statement. It is a
+// backup if Path is set, and the live table otherwise.
+type BackupDiffSource struct {
+	// Path is the URI of a backup, or its subdirectory if InCollection is set.
+	Path         Expr
+	InCollection Expr
+	// AsOf is the time the table is read as of. It is required for the live
+	// table, and defaults to the end time of a backup.
+	AsOf AsOfClause
+}
+
+// Format implements the NodeFormatter interface.
+func (node *BackupDiffSource) Format(ctx *FmtCtx) {
+	if node.Path == nil {
+		ctx.FormatNode(&node.AsOf)
+		return
+	}
+	if node.InCollection != nil {
+		ctx.FormatNode(node.Path)
+		ctx.WriteString(" IN ")
+		ctx.FormatURI(node.InCollection)
+	} else {
+		ctx.FormatURI(node.Path)
+	}
+	if node.AsOf.Expr != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(&node.AsOf)
+	}
+}
+
 type ShowBackupOptions struct {
 	AsJson               bool
 	CheckFiles           bool
@@ -1356,6 +1405,10 @@ const (
 	// ScheduledChangefeedExecutor is an executor responsible for
 	// the execution of the scheduled changefeeds.
 	ScheduledChangefeedExecutor
+
+	// ScheduledSQLExecutor is an executor responsible for the execution of
+	// the SQL statements of CREATE SCHEDULE FOR SQL schedules.
+	ScheduledSQLExecutor
 )
 
 var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
@@ -1365,6 +1418,7 @@ var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
 	ScheduledRowLevelTTLExecutor:        "scheduled-row-level-ttl-executor",
 	ScheduledSchemaTelemetryExecutor:    "scheduled-schema-telemetry-executor",
 	ScheduledChangefeedExecutor:         "scheduled-changefeed-executor",
+	ScheduledSQLExecutor:                "scheduled-sql-executor",
 }
 
 // InternalName returns an internal executor name.
@@ -1386,6 +1440,8 @@ func (t ScheduledJobExecutorType) UserName() string {
 		return "SCHEMA TELEMETRY"
 	case ScheduledChangefeedExecutor:
 		return "CHANGEFEED"
+	case ScheduledSQLExecutor:
+		return "SQL"
 	}
 	return "unsupported-executor"
 }
@@ -243,7 +243,9 @@ type CCLOnlyStatement interface {
 var _ CCLOnlyStatement = &AlterBackup{}
 var _ CCLOnlyStatement = &AlterBackupSchedule{}
 var _ CCLOnlyStatement = &Backup{}
+var _ CCLOnlyStatement = &BackupCopy{}
 var _ CCLOnlyStatement = &ShowBackup{}
+var _ CCLOnlyStatement = &ShowBackupDiff{}
 var _ CCLOnlyStatement = &Restore{}
 var _ CCLOnlyStatement = &CreateChangefeed{}
 var _ CCLOnlyStatement = &AlterChangefeed{}
@@ -649,6 +651,17 @@ func (*Backup) cclOnlyStatement() {}
 
 func (*Backup) hiddenFromShowQueries() {}
 
+// StatementReturnType implements the Statement interface.
+func (*BackupCopy) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*BackupCopy) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*BackupCopy) StatementTag() string { return "COPY BACKUP" }
+
+func (*BackupCopy) cclOnlyStatement() {}
+
 // StatementReturnType implements the Statement interface.
 func (*ScheduledBackup) StatementReturnType() StatementReturnType { return Rows }
 
@@ -662,6 +675,15 @@ func (*ScheduledBackup) cclOnlyStatement() {}
 
 func (*ScheduledBackup) hiddenFromShowQueries() {}
 
+// StatementReturnType implements the Statement interface.
+func (*ScheduledSQL) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*ScheduledSQL) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ScheduledSQL) StatementTag() string { return "SCHEDULED SQL" }
+
 // StatementReturnType implements the Statement interface.
 func (*AlterBackupSchedule) StatementReturnType() StatementReturnType { return Rows }
 
@@ -1781,6 +1803,17 @@ func (*ShowBackup) StatementTag() string { return "SHOW BACKUP" }
 
 func (*ShowBackup) cclOnlyStatement() {}
 
+// StatementReturnType implements the Statement interface.
+func (*ShowBackupDiff) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*ShowBackupDiff) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowBackupDiff) StatementTag() string { return "SHOW BACKUP DIFF" }
+
+func (*ShowBackupDiff) cclOnlyStatement() {}
+
 // StatementReturnType implements the Statement interface.
 func (*ShowDatabases) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2492,6 +2525,7 @@ func (n *AlterRoleSet) String() string                        { return AsString(
 func (n *AlterSequence) String() string                       { return AsString(n) }
 func (n *Analyze) String() string                             { return AsString(n) }
 func (n *Backup) String() string                              { return AsString(n) }
+func (n *BackupCopy) String() string                          { return AsString(n) }
 func (n *BeginTransaction) String() string                    { return AsString(n) }
 func (n *Call) String() string                                { return AsString(n) }
 func (n *ControlJobs) String() string                         { return AsString(n) }
@@ -2583,6 +2617,7 @@ func (n *RollbackTransaction) String() string                 { return AsString(
 func (n *Savepoint) String() string                           { return AsString(n) }
 func (n *Scatter) String() string                             { return AsString(n) }
 func (n *ScheduledBackup) String() string                     { return AsString(n) }
+func (n *ScheduledSQL) String() string                        { return AsString(n) }
 func (n *Scrub) String() string                               { return AsString(n) }
 func (n *Select) String() string                              { return AsString(n) }
 func (n *SelectClause) String() string                        { return AsString(n) }
@@ -2594,6 +2629,7 @@ func (n *SetTransaction) String() string                      { return AsString(
 func (n *SetTracing) String() string                          { return AsString(n) }
 func (n *SetVar) String() string                              { return AsString(n) }
 func (n *ShowBackup) String() string                          { return AsString(n) }
+func (n *ShowBackupDiff) String() string                      { return AsString(n) }
 func (n *ShowClusterSetting) String() string                  { return AsString(n) }
 func (n *ShowClusterSettingList) String() string              { return AsString(n) }
 func (n *ShowTenantClusterSetting) String() string            { return AsString(n) }
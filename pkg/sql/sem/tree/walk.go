@@ -1580,12 +1580,23 @@ func (stmt *ParenSelect) walkStmt(v Visitor) Statement {
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *Restore) copyNode() *Restore {
 	stmtCopy := *stmt
+	if stmt.Where != nil {
+		wCopy := *stmt.Where
+		stmtCopy.Where = &wCopy
+	}
 	return &stmtCopy
 }
 
 // walkStmt is part of the walkableStmt interface.
 func (stmt *Restore) walkStmt(v Visitor) Statement {
 	ret := stmt
+	if stmt.Where != nil {
+		e, changed := WalkExpr(v, stmt.Where.Expr)
+		if changed {
+			ret = stmt.copyNode()
+			ret.Where.Expr = e
+		}
+	}
 	if stmt.AsOf.Expr != nil {
 		e, changed := WalkExpr(v, stmt.AsOf.Expr)
 		if changed {
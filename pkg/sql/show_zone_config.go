@@ -209,6 +209,10 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (tree.Datu
 		maybeWriteComma(f)
 		f.Printf("\tglobal_reads = %t", *zone.GlobalReads)
 	}
+	if zone.ColdAfterSeconds != nil && *zone.ColdAfterSeconds > 0 {
+		maybeWriteComma(f)
+		f.Printf("\tcold_after_seconds = %d", *zone.ColdAfterSeconds)
+	}
 	if zone.NumReplicas != nil {
 		maybeWriteComma(f)
 		f.Printf("\tnum_replicas = %d", *zone.NumReplicas)
@@ -217,6 +221,10 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (tree.Datu
 		maybeWriteComma(f)
 		f.Printf("\tnum_voters = %d", *zone.NumVoters)
 	}
+	if zone.NumWitnesses != nil && *zone.NumWitnesses > 0 {
+		maybeWriteComma(f)
+		f.Printf("\tnum_witnesses = %d", *zone.NumWitnesses)
+	}
 	if !zone.InheritedConstraints {
 		maybeWriteComma(f)
 		f.Printf("\tconstraints = %s", lexbase.EscapeSQLString(constraints))
@@ -192,9 +192,11 @@ func (tb *tableWriterBase) tryDoResponseAdmission(ctx context.Context) error {
 	if responseAdmissionQ != nil {
 		requestAdmissionHeader := tb.txn.AdmissionHeader()
 		responseAdmission := admission.WorkInfo{
-			TenantID:   roachpb.SystemTenantID,
-			Priority:   admissionpb.WorkPriority(requestAdmissionHeader.Priority),
-			CreateTime: requestAdmissionHeader.CreateTime,
+			TenantID:            roachpb.SystemTenantID,
+			Priority:            admissionpb.WorkPriority(requestAdmissionHeader.Priority),
+			ResourceGroup:       requestAdmissionHeader.ResourceGroup,
+			ResourceGroupWeight: requestAdmissionHeader.ResourceGroupWeight,
+			CreateTime:          requestAdmissionHeader.CreateTime,
 		}
 		if _, err := responseAdmissionQ.Admit(ctx, responseAdmission); err != nil {
 			return err
@@ -10,6 +10,7 @@ import (
 	gosql "database/sql"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -316,3 +317,44 @@ func constructNameToIDMapping(
 	}
 	return namesToID, tempSchemaNames
 }
+
+// TestTempTableOnCommitDeleteRowsImplicitTxn tests that the rows written to an
+// ON COMMIT DELETE ROWS table by an implicit transaction are deleted, which
+// requires the mutation not to commit the transaction itself.
+func TestTempTableOnCommitDeleteRowsImplicitTxn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var autoCommits atomic.Int64
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			SQLExecutor: &ExecutorTestingKnobs{
+				BeforeAutoCommit: func(ctx context.Context, stmt string) error {
+					if strings.Contains(stmt, "on_commit_delete") && !strings.HasPrefix(stmt, "SELECT") {
+						autoCommits.Add(1)
+					}
+					return nil
+				},
+			},
+		},
+	})
+	defer srv.Stopper().Stop(context.Background())
+	// Temporary tables belong to the session.
+	db.SetMaxOpenConns(1)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET experimental_enable_temp_tables = true`)
+	sqlDB.Exec(t, `CREATE TEMP TABLE on_commit_delete (a INT PRIMARY KEY, b INT) ON COMMIT DELETE ROWS`)
+
+	for i, stmt := range []string{
+		`INSERT INTO on_commit_delete VALUES (1, 1)`,
+		`INSERT INTO on_commit_delete SELECT i, i FROM generate_series(1, 10) AS g(i)`,
+		`UPSERT INTO on_commit_delete VALUES (1, 2)`,
+		`INSERT INTO on_commit_delete VALUES (1, 1) ON CONFLICT (a) DO UPDATE SET b = 3`,
+	} {
+		sqlDB.Exec(t, stmt)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM on_commit_delete`, [][]string{{"0"}})
+		// The transaction was committed by the connExecutor, which deleted the
+		// rows, rather than by the mutation.
+		require.Equal(t, int64(i+1), autoCommits.Load(), stmt)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// tempTableOnCommitActions tracks the temporary tables of a session which
+// were created with ON COMMIT DELETE ROWS or ON COMMIT DROP. The actions are
+// carried out by the connExecutor right before a transaction commits.
+//
+// Entries are added when the table is created and are never removed when the
+// creating transaction is rolled back; instead, an entry whose table cannot be
+// found at commit time is discarded then. Descriptor IDs are never reused, so
+// this cannot affect another table.
+type tempTableOnCommitActions struct {
+	actions map[descpb.ID]tree.CreateTableOnCommitSetting
+	// written is the set of ON COMMIT DELETE ROWS tables which were written by
+	// the current transaction. The rows of the other tables were all deleted
+	// when the transaction which last wrote them committed, so there is nothing
+	// to delete from them. It is reset when the transaction finishes.
+	written map[descpb.ID]struct{}
+}
+
+// add records the ON COMMIT action of a newly created temporary table.
+func (a *tempTableOnCommitActions) add(id descpb.ID, action tree.CreateTableOnCommitSetting) {
+	switch action {
+	case tree.CreateTableOnCommitDeleteRows, tree.CreateTableOnCommitDrop:
+	default:
+		// PRESERVE ROWS requires no action.
+		return
+	}
+	if a.actions == nil {
+		a.actions = make(map[descpb.ID]tree.CreateTableOnCommitSetting)
+	}
+	a.actions[id] = action
+}
+
+// markWritten records that the current transaction writes to the table. It
+// is called whenever a mutation of a temporary table is planned, which may
+// over-approximate the set of tables that are actually written. It returns
+// whether the table is an ON COMMIT DELETE ROWS table.
+func (a *tempTableOnCommitActions) markWritten(id descpb.ID) bool {
+	if a.actions[id] != tree.CreateTableOnCommitDeleteRows {
+		return false
+	}
+	if a.written == nil {
+		a.written = make(map[descpb.ID]struct{})
+	}
+	a.written[id] = struct{}{}
+	return true
+}
+
+// resetWritten forgets the tables written by the transaction which finished.
+func (a *tempTableOnCommitActions) resetWritten() {
+	a.written = nil
+}
+
+// noteTempTableWrite records that the current transaction writes to the
+// table, if it is a temporary table with ON COMMIT DELETE ROWS. In that case,
+// it returns true and disables auto-commit for the statement: the rows must be
+// deleted by runTempTableOnCommitActions before the transaction commits, which
+// would not happen if the mutation committed the transaction in its last
+// batch.
+func (p *planner) noteTempTableWrite(desc catalog.TableDescriptor) bool {
+	if !desc.IsTemporary() || p.extendedEvalCtx.tempTableOnCommit == nil ||
+		!p.extendedEvalCtx.tempTableOnCommit.markWritten(desc.GetID()) {
+		return false
+	}
+	p.autoCommit = false
+	return true
+}
+
+// ids returns the IDs of all tracked tables in ascending order.
+func (a *tempTableOnCommitActions) ids() []descpb.ID {
+	ids := make([]descpb.ID, 0, len(a.actions))
+	for id := range a.actions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// runTempTableOnCommitActions carries out the ON COMMIT actions of the
+// session's temporary tables as part of the committing transaction.
+//
+// ON COMMIT DELETE ROWS deletes the data of the table directly, without
+// touching its descriptor, so it neither bumps the descriptor version nor
+// queues a schema change job. It is skipped for the tables which the
+// transaction did not write, and thus for read-only transactions. ON COMMIT
+// DROP only ever applies to tables created by the committing transaction. When such a table is not referenced
+// by, and does not reference, any other descriptor, it is removed along with
+// its data before its descriptor ever becomes visible; otherwise, it goes
+// through the regular DROP TABLE path.
+func (ex *connExecutor) runTempTableOnCommitActions(ctx context.Context) error {
+	if len(ex.tempTableOnCommit.actions) == 0 {
+		return nil
+	}
+	readOnly := ex.state.readOnly.Load()
+	txn := ex.state.mu.txn
+	descsCol := ex.extraTxnState.descCollection
+	kvTrace := ex.planner.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	b := txn.NewBatch()
+	for _, id := range ex.tempTableOnCommit.ids() {
+		if ex.tempTableOnCommit.actions[id] == tree.CreateTableOnCommitDeleteRows {
+			if _, ok := ex.tempTableOnCommit.written[id]; !ok || readOnly {
+				continue
+			}
+		}
+		table, err := descsCol.ByIDWithLeased(txn).Get().Table(ctx, id)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				// The transaction which created the table was rolled back.
+				delete(ex.tempTableOnCommit.actions, id)
+				continue
+			}
+			return err
+		}
+		if table.Dropped() {
+			delete(ex.tempTableOnCommit.actions, id)
+			continue
+		}
+		if !table.Public() {
+			// The table is still being created by an asynchronous schema
+			// change, so it holds no rows written by this session yet.
+			continue
+		}
+		switch action := ex.tempTableOnCommit.actions[id]; action {
+		case tree.CreateTableOnCommitDeleteRows:
+			span := table.TableSpan(ex.server.cfg.Codec)
+			if kvTrace {
+				log.VEventf(ctx, 2, "DelRange %s - %s", span.Key, span.EndKey)
+			}
+			b.DelRange(span.Key, span.EndKey, false /* returnKeys */)
+		case tree.CreateTableOnCommitDrop:
+			if err := ex.dropTempTableOnCommit(ctx, b, kvTrace, id); err != nil {
+				return err
+			}
+			delete(ex.tempTableOnCommit.actions, id)
+		default:
+			return errors.AssertionFailedf("unexpected ON COMMIT action %d for table %d", action, id)
+		}
+	}
+	if len(b.Results) == 0 {
+		return nil
+	}
+	return txn.Run(ctx, b)
+}
+
+// dropTempTableOnCommit drops a temporary table created with ON COMMIT DROP.
+// The deletions which do not go through the planner are added to b.
+func (ex *connExecutor) dropTempTableOnCommit(
+	ctx context.Context, b *kv.Batch, kvTrace bool, id descpb.ID,
+) error {
+	descsCol := ex.extraTxnState.descCollection
+	mut, err := descsCol.MutableByID(ex.state.mu.txn).Table(ctx, id)
+	if err != nil {
+		return err
+	}
+	hasRefs, err := tempTableHasReferences(mut)
+	if err != nil {
+		return err
+	}
+	if !mut.IsNew() || hasRefs {
+		_, err := ex.planner.dropTableImpl(
+			ctx, mut, false /* droppingParent */, "ON COMMIT DROP", tree.DropCascade,
+		)
+		return err
+	}
+	// The descriptor was created by this transaction and nothing else points
+	// to it, so the table, its name and its data can simply be deleted: after
+	// the commit, it will be as if it never existed.
+	mut.SetDropped()
+	if err := descsCol.DeleteNamespaceEntryToBatch(ctx, kvTrace, mut, b); err != nil {
+		return err
+	}
+	if err := descsCol.DeleteDescToBatch(ctx, kvTrace, id, b); err != nil {
+		return err
+	}
+	span := mut.TableSpan(ex.server.cfg.Codec)
+	if kvTrace {
+		log.VEventf(ctx, 2, "DelRange %s - %s", span.Key, span.EndKey)
+	}
+	b.DelRange(span.Key, span.EndKey, false /* returnKeys */)
+	return nil
+}
+
+// tempTableHasReferences returns whether the table references, or is
+// referenced by, any descriptor other than its parent database and schema.
+func tempTableHasReferences(desc *tabledesc.Mutable) (bool, error) {
+	if len(desc.InboundFKs) > 0 || len(desc.DependedOnBy) > 0 {
+		return true, nil
+	}
+	for _, col := range desc.DeletableColumns() {
+		if col.NumOwnsSequences() > 0 {
+			return true, nil
+		}
+	}
+	ids, err := desc.GetReferencedDescIDs(catalog.ValidationLevelBackReferences)
+	if err != nil {
+		return false, err
+	}
+	ids.Remove(desc.GetID())
+	ids.Remove(desc.GetParentID())
+	ids.Remove(desc.GetParentSchemaID())
+	return !ids.Empty(), nil
+}
@@ -3741,6 +3741,65 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: globalTrue,
 		Hidden:        true,
 	},
+
+	// CockroachDB extension.
+	//
+	// Set is only used when the session is initialized, and the resource group
+	// can only be provided there by an admin: either as a connection parameter,
+	// or as a role default (see checkResourceGroupPrivilege).
+	`resource_group`: {
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			m.SetResourceGroup(s)
+			return nil
+		},
+		SetWithPlanner: func(ctx context.Context, p *planner, local bool, s string) error {
+			// Going back to the session's default, which an admin chose, is
+			// always allowed, so that e.g. RESET ALL works for all users.
+			if s != p.sessionDataMutatorIterator.defaults[`resource_group`] {
+				if err := p.checkResourceGroupPrivilege(ctx); err != nil {
+					return err
+				}
+			}
+			return p.applyOnSessionDataMutators(ctx, local, func(m sessionDataMutator) error {
+				m.SetResourceGroup(s)
+				return nil
+			})
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return evalCtx.SessionData().ResourceGroup, nil
+		},
+		GlobalDefault: func(_ *settings.Values) string { return "" },
+	},
+}
+
+// errResourceGroupRequiresAdmin is returned when a user other than an admin
+// tries to choose a resource group.
+var errResourceGroupRequiresAdmin = pgerror.New(pgcode.InsufficientPrivilege,
+	"only users with the admin role are allowed to set resource_group")
+
+// checkResourceGroupPrivilege returns an error if the current user may not
+// set the resource_group session variable, or a role default for it. Resource
+// groups divide the resources of the tenant between its users, so only admins
+// may assign them.
+func (p *planner) checkResourceGroupPrivilege(ctx context.Context) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		return errResourceGroupRequiresAdmin
+	}
+	return nil
+}
+
+// CheckResourceGroupSessionArg returns an error if a user that is not an admin
+// provided the resource_group session variable as a connection parameter. Role
+// defaults, which only admins can set for resource_group, are allowed.
+func CheckResourceGroupSessionArg(args SessionArgs, isSuperuser bool) error {
+	if _, ok := args.SessionDefaults["resource_group"]; ok && !isSuperuser {
+		return errResourceGroupRequiresAdmin
+	}
+	return nil
 }
 
 func ReplicationModeFromString(s string) (sessiondatapb.ReplicationMode, error) {
@@ -27,6 +27,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/objstorage"
+	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/rangekey"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/redact"
@@ -1124,6 +1126,24 @@ type Engine interface {
 	// key-by-key to a new file.
 	Download(ctx context.Context, span roachpb.Span, copy bool) error
 
+	// CreateExternalObject creates an object with the given name in the
+	// external storage identified by locator (a cloud.ExternalStorage URI). The
+	// object can subsequently be ingested as a pebble.ExternalFile. The caller
+	// must either Finish or Abort the returned Writable.
+	CreateExternalObject(
+		ctx context.Context, locator remote.Locator, objName string,
+	) (objstorage.Writable, error)
+
+	// DeleteExternalObject deletes an object created by CreateExternalObject.
+	// The caller must ensure that the object is not referenced by this or any
+	// other engine; see ReferencedExternalObjects.
+	DeleteExternalObject(ctx context.Context, locator remote.Locator, objName string) error
+
+	// ReferencedExternalObjects returns the names of the objects in the external
+	// storage identified by locator that are currently referenced by the
+	// engine's sstables, or by sstables pinned by open iterators and snapshots.
+	ReferencedExternalObjects(locator remote.Locator) map[string]struct{}
+
 	// RegisterDiskSlowCallback registers a callback that will be run when a
 	// write operation on the disk has been seen to be slow. This callback
 	// needs to be thread-safe as it could be called repeatedly in multiple threads
@@ -48,6 +48,7 @@ import (
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/bloom"
+	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
 	"github.com/cockroachdb/pebble/objstorage/remote"
 	"github.com/cockroachdb/pebble/rangekey"
@@ -1079,6 +1080,63 @@ func (p *Pebble) Download(ctx context.Context, span roachpb.Span, copy bool) err
 	return p.db.Download(ctx, []pebble.DownloadSpan{downloadSpan})
 }
 
+// CreateExternalObject implements the Engine interface.
+func (p *Pebble) CreateExternalObject(
+	ctx context.Context, locator remote.Locator, objName string,
+) (objstorage.Writable, error) {
+	// If shared storage is configured, Pebble only knows about the shared
+	// storage's locator, and wouldn't be able to read the object back.
+	if p.cfg.sharedStorage != nil || p.cfg.remoteStorageFactory == nil {
+		return nil, errors.New("engine is not configured to use external storage")
+	}
+	es, err := p.cfg.remoteStorageFactory.OpenURL(ctx, string(locator))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w, err := es.Writer(ctx, objName)
+	if err != nil {
+		cancel()
+		return nil, errors.CombineErrors(err, es.Close())
+	}
+	return &externalObjectWritable{
+		w:       &externalStorageWriter{WriteCloser: w, p: p},
+		es:      es,
+		objName: objName,
+		cancel:  cancel,
+	}, nil
+}
+
+// DeleteExternalObject implements the Engine interface.
+func (p *Pebble) DeleteExternalObject(
+	ctx context.Context, locator remote.Locator, objName string,
+) error {
+	if p.cfg.remoteStorageFactory == nil {
+		return errors.New("engine is not configured to use external storage")
+	}
+	es, err := p.cfg.remoteStorageFactory.OpenURL(ctx, string(locator))
+	if err != nil {
+		return err
+	}
+	err = es.Delete(ctx, objName)
+	if errors.Is(err, cloud.ErrFileDoesNotExist) {
+		// The object was already deleted, e.g. by a previous attempt.
+		err = nil
+	}
+	return errors.CombineErrors(err, es.Close())
+}
+
+// ReferencedExternalObjects implements the Engine interface.
+func (p *Pebble) ReferencedExternalObjects(locator remote.Locator) map[string]struct{} {
+	objs := make(map[string]struct{})
+	for _, meta := range p.db.ObjProvider().List() {
+		if meta.IsExternal() && meta.Remote.Locator == locator {
+			objs[meta.Remote.CustomObjectName] = struct{}{}
+		}
+	}
+	return objs
+}
+
 type remoteStorageAdaptor struct {
 	p       *Pebble
 	ctx     context.Context
@@ -12,6 +12,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/objstorage"
 	"github.com/cockroachdb/pebble/objstorage/remote"
 )
 
@@ -82,6 +83,58 @@ func (e *externalStorageWriter) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
+// externalObjectWritable implements objstorage.Writable on top of a writer
+// returned by cloud.ExternalStorage. See Pebble.CreateExternalObject.
+type externalObjectWritable struct {
+	w       io.WriteCloser
+	es      cloud.ExternalStorage
+	objName string
+	// cancel cancels the context the writer was created with, which aborts
+	// the upload.
+	cancel context.CancelFunc
+	// aborted is set once Abort has been called. Abort may be called directly
+	// by the user of the Writable, before e.g. an sstable.Writer that wraps it
+	// is closed, in which case subsequent calls are no-ops or return errors.
+	aborted bool
+}
+
+var _ objstorage.Writable = (*externalObjectWritable)(nil)
+
+var errExternalObjectAborted = errors.New("external object write aborted")
+
+// Write is part of the objstorage.Writable interface.
+func (e *externalObjectWritable) Write(p []byte) error {
+	if e.aborted {
+		return errExternalObjectAborted
+	}
+	_, err := e.w.Write(p)
+	return err
+}
+
+// Finish is part of the objstorage.Writable interface.
+func (e *externalObjectWritable) Finish() error {
+	if e.aborted {
+		return errExternalObjectAborted
+	}
+	defer e.cancel()
+	err := e.w.Close()
+	return errors.CombineErrors(err, e.es.Close())
+}
+
+// Abort is part of the objstorage.Writable interface.
+func (e *externalObjectWritable) Abort() {
+	if e.aborted {
+		return
+	}
+	e.aborted = true
+	e.cancel()
+	_ = e.w.Close()
+	// Some implementations of cloud.ExternalStorage (e.g. nodelocal) may have
+	// already written part of the object; remove it on a best-effort basis.
+	_ = e.es.Delete(context.Background(), e.objName)
+	_ = e.es.Close()
+}
+
 // externalStorageWrapper wraps a cloud.ExternalStorage and implements the
 // remote.Storage interface expected by Pebble. Also ensures reads and writes
 // to remote cloud storage are tracked in store-specific metrics.
@@ -30,6 +30,7 @@ const QUEUES = [
   "raftsnapshot",
   "consistencyChecker",
   "timeSeriesMaintenance",
+  "coldTiering",
 ];
 
 const queueOptions = QUEUES.map(q => {
@@ -17,7 +17,8 @@ import (
 // specifically how much on-CPU time a request is allowed to make use of (used
 // for cooperative scheduling with elastic CPU granters).
 type ElasticCPUWorkHandle struct {
-	tenantID roachpb.TenantID
+	tenantID      roachpb.TenantID
+	resourceGroup string
 	// cpuStart captures the running time of the calling goroutine when this
 	// handle is constructed.
 	cpuStart time.Duration
@@ -0,0 +1,217 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package admission
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// MaxResourceGroupCPUWeight is the maximum CPU weight of a resource group. Like
+// tenantWeightCap, it bounds the ratio of the resources used by the largest and
+// smallest groups of a tenant, so that small groups are not starved.
+const MaxResourceGroupCPUWeight = 100
+
+// ResourceGroup is a named group of work within a tenant, such as the work of
+// a reporting role, which shares the resources given to the tenant with the
+// other resource groups of the tenant.
+//
+// Resource groups are only defined through the admission.resource_groups
+// cluster setting; there is no SQL syntax to manage them. They limit the share
+// of the tenant's CPU, and the transaction concurrency, of their work, but not
+// its request unit consumption.
+//
+// Resource groups are assigned to SQL sessions using the resource_group
+// session variable, which only admins can set, either directly or as a role
+// default. The CPUWeight of the group is carried in the AdmissionHeader of the
+// KV requests of the session, so that the KV, IO and SQL-side WorkQueues on
+// every node enforce fair sharing between the groups of a tenant, without
+// needing to know the definitions of the groups.
+type ResourceGroup struct {
+	Name string
+	// CPUWeight is the weight of the group when sharing resources with the
+	// other groups of the tenant, in [1, MaxResourceGroupCPUWeight].
+	CPUWeight uint32
+	// MaxConcurrency is the maximum number of transactions of the group that
+	// execute concurrently on a SQL node. Zero means unlimited.
+	MaxConcurrency int
+}
+
+// ResourceGroups contains the definitions of the resource groups of the
+// tenant.
+var ResourceGroups = settings.RegisterStringSetting(
+	settings.ApplicationLevel,
+	"admission.resource_groups",
+	"definitions of resource groups, as a semicolon separated list of "+
+		"<name>:cpu_weight=<weight>,max_concurrency=<transactions> entries; "+
+		"sessions are assigned to a resource group with the resource_group session variable, "+
+		"which only admins can set",
+	"",
+	settings.WithValidateString(func(_ *settings.Values, s string) error {
+		_, err := ParseResourceGroups(s)
+		return err
+	}),
+	settings.WithPublic,
+)
+
+// ParseResourceGroups parses the resource group definitions in the format of
+// the admission.resource_groups cluster setting.
+func ParseResourceGroups(s string) (map[string]ResourceGroup, error) {
+	groups := make(map[string]ResourceGroup)
+	for _, def := range strings.Split(s, ";") {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		name, opts, _ := strings.Cut(def, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.Newf("resource group %q has no name", def)
+		}
+		if _, ok := groups[name]; ok {
+			return nil, errors.Newf("resource group %q is defined more than once", name)
+		}
+		group := ResourceGroup{Name: name, CPUWeight: defaultResourceGroupWeight}
+		for _, opt := range strings.Split(opts, ",") {
+			opt = strings.TrimSpace(opt)
+			if opt == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(opt, "=")
+			if !ok {
+				return nil, errors.Newf("resource group %q: option %q has no value", name, opt)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "resource group %q: invalid value for %s", name, key)
+			}
+			switch key {
+			case "cpu_weight":
+				if v < defaultResourceGroupWeight || v > MaxResourceGroupCPUWeight {
+					return nil, errors.Newf("resource group %q: cpu_weight must be between %d and %d",
+						name, defaultResourceGroupWeight, MaxResourceGroupCPUWeight)
+				}
+				group.CPUWeight = uint32(v)
+			case "max_concurrency":
+				if v < 0 {
+					return nil, errors.Newf("resource group %q: max_concurrency cannot be negative", name)
+				}
+				group.MaxConcurrency = v
+			case "kv_ru_per_sec":
+				return nil, errors.Newf("resource group %q: request unit limits are not supported; "+
+					"resource groups only limit cpu_weight and max_concurrency", name)
+			default:
+				return nil, errors.Newf("resource group %q: unknown option %q", name, key)
+			}
+		}
+		groups[name] = group
+	}
+	return groups, nil
+}
+
+// ResourceGroupLimiter limits the number of concurrently executing transactions
+// of each resource group on a SQL node, according to the MaxConcurrency of the
+// group.
+type ResourceGroupLimiter struct {
+	sv *settings.Values
+
+	mu struct {
+		syncutil.Mutex
+		// definition is the value of the admission.resource_groups setting that
+		// groups was parsed from.
+		definition string
+		groups     map[string]ResourceGroup
+		pools      map[string]*quotapool.IntPool
+	}
+}
+
+// NewResourceGroupLimiter returns a ResourceGroupLimiter that uses the
+// resource groups defined in the given settings.
+func NewResourceGroupLimiter(sv *settings.Values) *ResourceGroupLimiter {
+	l := &ResourceGroupLimiter{sv: sv}
+	l.mu.groups = map[string]ResourceGroup{}
+	l.mu.pools = map[string]*quotapool.IntPool{}
+	return l
+}
+
+// Lookup returns the definition of the given resource group.
+func (l *ResourceGroupLimiter) Lookup(name string) (ResourceGroup, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refreshLocked()
+	group, ok := l.mu.groups[name]
+	return group, ok
+}
+
+// refreshLocked re-parses the resource group definitions if the setting
+// changed, and updates the capacity of the concurrency limits.
+func (l *ResourceGroupLimiter) refreshLocked() {
+	definition := ResourceGroups.Get(l.sv)
+	if definition == l.mu.definition {
+		return
+	}
+	groups, err := ParseResourceGroups(definition)
+	if err != nil {
+		// The setting is validated, so this can only happen if the validation
+		// was bypassed. Keep using the previous definitions.
+		return
+	}
+	l.mu.definition = definition
+	l.mu.groups = groups
+	for name, pool := range l.mu.pools {
+		if group, ok := groups[name]; ok && group.MaxConcurrency > 0 {
+			pool.UpdateCapacity(uint64(group.MaxConcurrency))
+		} else {
+			// The limit was removed. Waiting transactions are unblocked by
+			// closing the pool, and will execute without a limit.
+			pool.Close("resource group concurrency limit removed")
+			delete(l.mu.pools, name)
+		}
+	}
+}
+
+// Acquire waits until a transaction of the given resource group can execute
+// under the MaxConcurrency limit of the group. The returned function must be
+// called once the transaction has finished. Transactions of unknown groups,
+// and of groups without a concurrency limit, are not limited.
+func (l *ResourceGroupLimiter) Acquire(ctx context.Context, name string) (release func(), _ error) {
+	if name == "" {
+		return func() {}, nil
+	}
+	pool := func() *quotapool.IntPool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.refreshLocked()
+		group, ok := l.mu.groups[name]
+		if !ok || group.MaxConcurrency == 0 {
+			return nil
+		}
+		pool, ok := l.mu.pools[name]
+		if !ok {
+			pool = quotapool.NewIntPool("resource group "+name, uint64(group.MaxConcurrency))
+			l.mu.pools[name] = pool
+		}
+		return pool
+	}()
+	if pool == nil {
+		return func() {}, nil
+	}
+	alloc, err := pool.Acquire(ctx, 1)
+	if err != nil {
+		if quotapool.HasErrClosed(err) {
+			return func() {}, nil
+		}
+		return nil, err
+	}
+	return alloc.Release, nil
+}
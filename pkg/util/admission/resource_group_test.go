@@ -0,0 +1,116 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceGroups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	groups, err := ParseResourceGroups(
+		" oltp:cpu_weight=8; reporting:cpu_weight=1, max_concurrency=4;batch;")
+	require.NoError(t, err)
+	require.Equal(t, map[string]ResourceGroup{
+		"oltp":      {Name: "oltp", CPUWeight: 8},
+		"reporting": {Name: "reporting", CPUWeight: 1, MaxConcurrency: 4},
+		"batch":     {Name: "batch", CPUWeight: 1},
+	}, groups)
+
+	groups, err = ParseResourceGroups("")
+	require.NoError(t, err)
+	require.Empty(t, groups)
+
+	for _, tc := range []struct {
+		def    string
+		errStr string
+	}{
+		{":cpu_weight=1", "has no name"},
+		{"a;a", "defined more than once"},
+		{"a:cpu_weight", "has no value"},
+		{"a:cpu_weight=x", "invalid value for cpu_weight"},
+		{"a:cpu_weight=0", "cpu_weight must be between 1 and 100"},
+		{"a:cpu_weight=101", "cpu_weight must be between 1 and 100"},
+		{"a:max_concurrency=-1", "max_concurrency cannot be negative"},
+		{"a:kv_ru_per_sec=10", "request unit limits are not supported"},
+	} {
+		t.Run(tc.def, func(t *testing.T) {
+			_, err := ParseResourceGroups(tc.def)
+			require.ErrorContains(t, err, tc.errStr)
+		})
+	}
+}
+
+func TestResourceGroupLimiter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	ResourceGroups.Override(ctx, &st.SV, "reporting:max_concurrency=1;oltp:cpu_weight=4")
+	l := NewResourceGroupLimiter(&st.SV)
+
+	group, ok := l.Lookup("oltp")
+	require.True(t, ok)
+	require.Equal(t, uint32(4), group.CPUWeight)
+	_, ok = l.Lookup("unknown")
+	require.False(t, ok)
+
+	// Groups without a limit, and unknown groups, are not limited.
+	for _, name := range []string{"", "oltp", "unknown"} {
+		release1, err := l.Acquire(ctx, name)
+		require.NoError(t, err)
+		release2, err := l.Acquire(ctx, name)
+		require.NoError(t, err)
+		release1()
+		release2()
+	}
+
+	release, err := l.Acquire(ctx, "reporting")
+	require.NoError(t, err)
+	// A second statement waits for the first one.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(timeoutCtx, "reporting")
+	require.Error(t, err)
+
+	acquired := make(chan func())
+	go func() {
+		release, err := l.Acquire(ctx, "reporting")
+		if err != nil {
+			panic(err)
+		}
+		acquired <- release
+	}()
+	release()
+	(<-acquired)()
+
+	// Removing the limit unblocks waiting statements.
+	release, err = l.Acquire(ctx, "reporting")
+	require.NoError(t, err)
+	go func() {
+		release, err := l.Acquire(ctx, "reporting")
+		if err != nil {
+			panic(err)
+		}
+		acquired <- release
+	}()
+	ResourceGroups.Override(ctx, &st.SV, "reporting:cpu_weight=1")
+	// Changes of the setting are picked up by the next call.
+	_, ok = l.Lookup("reporting")
+	require.True(t, ok)
+	(<-acquired)()
+	release()
+}
@@ -178,6 +178,16 @@ type WorkInfo struct {
 	TenantID roachpb.TenantID
 	// Priority is utilized within a tenant.
 	Priority admissionpb.WorkPriority
+	// ResourceGroup is the resource group of the work within its tenant. The
+	// resource groups of a tenant share the resources given to the tenant in
+	// proportion to their ResourceGroupWeight, and Priority is only utilized
+	// within a resource group. Work with an empty ResourceGroup is not part of
+	// any group, and competes with the groups of its tenant as if it were a
+	// group of weight 1.
+	ResourceGroup string
+	// ResourceGroupWeight is the weight of the ResourceGroup. It is clamped to
+	// [1, MaxResourceGroupCPUWeight].
+	ResourceGroupWeight uint32
 	// CreateTime is equivalent to Time.UnixNano() at the creation time of this
 	// work or a parent work (e.g. could be the start time of the transaction,
 	// if this work was created as part of a transaction). It is used to order
@@ -438,8 +448,11 @@ func initWorkQueue(
 }
 
 func isInTenantHeap(tenant *tenantInfo) bool {
-	// If there is some waiting work, this tenant is in tenantHeap.
-	return len(tenant.waitingWorkHeap) > 0 || len(tenant.openEpochsHeap) > 0
+	// If there is some waiting work, this tenant is in tenantHeap. For the
+	// tenantInfo of a resource group, this means it is in the groupHeap of its
+	// tenant.
+	return len(tenant.waitingWorkHeap) > 0 || len(tenant.openEpochsHeap) > 0 ||
+		len(tenant.groupHeap) > 0
 }
 
 func (q *WorkQueue) timeNow() time.Time {
@@ -530,7 +543,9 @@ func (q *WorkQueue) tryCloseEpoch(timeNow time.Time) {
 	}
 	q.mu.closedEpochThreshold = epoch
 	doLog := q.logThreshold.ShouldLog()
-	for _, tenant := range q.mu.tenants {
+	// closeEpoch is called for every tenant, and every resource group of a
+	// tenant, since each of them has its own heaps and FIFO threshold.
+	closeEpoch := func(tenant *tenantInfo) {
 		prevThreshold := tenant.fifoPriorityThreshold
 		tenant.fifoPriorityThreshold =
 			tenant.priorityStates.getFIFOPriorityThresholdAndReset(
@@ -548,8 +563,13 @@ func (q *WorkQueue) tryCloseEpoch(timeNow time.Time) {
 			// tenant. However, currently we share metrics across WorkQueues --
 			// specifically all the store WorkQueues share the same metric. We
 			// should eliminate that sharing and make those per store metrics.
-			log.Infof(q.ambientCtx, "%s: FIFO threshold for tenant %d %s %d",
-				q.workKind, tenant.id, logVerb, tenant.fifoPriorityThreshold)
+			if tenant.group != "" {
+				log.Infof(q.ambientCtx, "%s: FIFO threshold for tenant %d resource group %s %s %d",
+					q.workKind, tenant.id, tenant.group, logVerb, tenant.fifoPriorityThreshold)
+			} else {
+				log.Infof(q.ambientCtx, "%s: FIFO threshold for tenant %d %s %d",
+					q.workKind, tenant.id, logVerb, tenant.fifoPriorityThreshold)
+			}
 		}
 		// Note that we are ignoring the new priority threshold and only
 		// dequeueing the ones that are in the closed epoch. It is possible to
@@ -566,6 +586,12 @@ func (q *WorkQueue) tryCloseEpoch(timeNow time.Time) {
 			heap.Push(&tenant.waitingWorkHeap, work)
 		}
 	}
+	for _, tenant := range q.mu.tenants {
+		closeEpoch(tenant)
+		for _, group := range tenant.groups {
+			closeEpoch(group)
+		}
+	}
 }
 
 // Admit is called when requesting admission for some work. If err!=nil, the
@@ -607,6 +633,9 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
 		q.mu.tenants[tenantID] = tenant
 	}
+	// The resource group that the work is queued in, which is the tenant
+	// itself for work that is not part of a resource group.
+	group := tenant.getGroupLocked(info.ResourceGroup, info.ResourceGroupWeight)
 	if info.ReplicatedWorkInfo.Enabled {
 		if info.BypassAdmission {
 			// TODO(irfansharif): "Admin" work (like splits, scatters, lease
@@ -623,7 +652,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		}
 	}
 	if info.BypassAdmission && q.workKind == KVWork {
-		tenant.used += uint64(info.RequestedCount)
+		tenant.addUsedLocked(group, uint64(info.RequestedCount))
 		if isInTenantHeap(tenant) {
 			q.mu.tenantHeap.fix(tenant)
 		}
@@ -638,12 +667,12 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	// Tell priorityStates about this received work. We don't tell it about work
 	// that has bypassed admission control, since priorityStates is deciding the
 	// threshold for LIFO queueing based on observed admission latency.
-	tenant.priorityStates.requestAtPriority(info.Priority)
+	group.priorityStates.requestAtPriority(info.Priority)
 
 	if len(q.mu.tenantHeap) == 0 && !q.knobs.DisableWorkQueueFastPath {
 		// Fast-path. Try to grab token/slot.
 		// Optimistically update used to avoid locking again.
-		tenant.used += uint64(info.RequestedCount)
+		tenant.addUsedLocked(group, uint64(info.RequestedCount))
 		q.mu.Unlock()
 		// We have unlocked q.mu, so another concurrent request can also do tryGet
 		// and get ahead of this request. We don't need to be fair for such
@@ -705,13 +734,10 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
 			q.mu.tenants[tenantID] = tenant
 		}
+		group = tenant.getGroupLocked(info.ResourceGroup, info.ResourceGroupWeight)
 		// Don't want to overflow tenant.used if it has decreased because of being
 		// reset to 0 by the GC goroutine.
-		if tenant.used >= uint64(info.RequestedCount) {
-			tenant.used -= uint64(info.RequestedCount)
-		} else {
-			tenant.used = 0
-		}
+		tenant.subUsedLocked(group, uint64(info.RequestedCount))
 	}
 
 	// Check for cancellation.
@@ -731,17 +757,21 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	}
 	// Push onto heap(s).
 	ordering := fifoWorkOrdering
-	if int(info.Priority) < tenant.fifoPriorityThreshold {
+	if int(info.Priority) < group.fifoPriorityThreshold {
 		ordering = lifoWorkOrdering
 	}
 	work := newWaitingWork(info.Priority, ordering, info.CreateTime, info.RequestedCount, startTime, q.mu.epochLengthNanos)
 	work.replicated = info.ReplicatedWorkInfo
 
 	inTenantHeap := isInTenantHeap(tenant)
+	inGroupHeap := group != tenant && isInTenantHeap(group)
 	if work.epoch <= q.mu.closedEpochThreshold || ordering == fifoWorkOrdering {
-		heap.Push(&tenant.waitingWorkHeap, work)
+		heap.Push(&group.waitingWorkHeap, work)
 	} else {
-		heap.Push(&tenant.openEpochsHeap, work)
+		heap.Push(&group.openEpochsHeap, work)
+	}
+	if group != tenant && !inGroupHeap {
+		heap.Push(&tenant.groupHeap, group)
 	}
 	if !inTenantHeap {
 		heap.Push(&q.mu.tenantHeap, tenant)
@@ -755,7 +785,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	if info.ReplicatedWorkInfo.Enabled {
 		if log.V(1) {
 			q.mu.Lock()
-			queueLen := tenant.waitingWorkHeap.Len()
+			queueLen := group.waitingWorkHeap.Len()
 			q.mu.Unlock()
 
 			log.Infof(ctx, "async-path: len(waiting-work)=%d: enqueued t%d pri=%s r%s origin=n%s log-position=%s ingested=%t",
@@ -785,7 +815,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		// since it is possible that all work at this priority is exceeding the
 		// deadline and being cancelled. The risk here is that if the deadlines
 		// are too short, we could underestimate the actual wait time.
-		tenant.priorityStates.updateDelayLocked(work.priority, waitDur, true /* canceled */)
+		group.priorityStates.updateDelayLocked(work.priority, waitDur, true /* canceled */)
 		if work.heapIndex == -1 {
 			// No longer in heap. Raced with token/slot grant. Don't bother
 			// decrementing tenant.used since we don't want to race with the gc
@@ -802,9 +832,12 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			q.granter.continueGrantChain(chainID)
 		} else {
 			if work.inWaitingWorkHeap {
-				tenant.waitingWorkHeap.remove(work)
+				group.waitingWorkHeap.remove(work)
 			} else {
-				tenant.openEpochsHeap.remove(work)
+				group.openEpochsHeap.remove(work)
+			}
+			if group != tenant && !isInTenantHeap(group) {
+				tenant.groupHeap.remove(group)
 			}
 			if !isInTenantHeap(tenant) {
 				q.mu.tenantHeap.remove(tenant)
@@ -857,8 +890,11 @@ func recordAdmissionWorkQueueStats(
 // finished. It must be called iff the WorkKind of this WorkQueue uses slots
 // (not tokens), i.e., KVWork, SQLStatementLeafStartWork,
 // SQLStatementRootStartWork. Note, there is no support for SQLStatementLeafStartWork,
-// SQLStatementRootStartWork in the code yet.
-func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID, cpuTime time.Duration) {
+// SQLStatementRootStartWork in the code yet. The resourceGroup must be the
+// WorkInfo.ResourceGroup of the admitted work.
+func (q *WorkQueue) AdmittedWorkDone(
+	tenantID roachpb.TenantID, resourceGroup string, cpuTime time.Duration,
+) {
 	if q.usesTokens {
 		panic(errors.AssertionFailedf("tokens should not be returned"))
 	}
@@ -866,7 +902,7 @@ func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID, cpuTime time.Dur
 	// incremented by 1.
 	additionalUsed := cpuTime - 1
 	if additionalUsed != 0 {
-		q.adjustTenantUsed(tenantID, additionalUsed.Nanoseconds())
+		q.adjustTenantUsed(tenantID, resourceGroup, additionalUsed.Nanoseconds())
 	}
 	q.granter.returnGrant(1)
 }
@@ -890,15 +926,19 @@ func (q *WorkQueue) granted(grantChainID grantChainID) int64 {
 		return 0
 	}
 	tenant := q.mu.tenantHeap[0]
+	group := tenant.nextGroupLocked()
 	var item *waitingWork
-	if len(tenant.waitingWorkHeap) > 0 {
-		item = heap.Pop(&tenant.waitingWorkHeap).(*waitingWork)
+	if len(group.waitingWorkHeap) > 0 {
+		item = heap.Pop(&group.waitingWorkHeap).(*waitingWork)
 	} else {
-		item = heap.Pop(&tenant.openEpochsHeap).(*waitingWork)
+		item = heap.Pop(&group.openEpochsHeap).(*waitingWork)
 	}
 	waitDur := now.Sub(item.enqueueingTime)
-	tenant.priorityStates.updateDelayLocked(item.priority, waitDur, false /* canceled */)
-	tenant.used += uint64(item.requestedCount)
+	group.priorityStates.updateDelayLocked(item.priority, waitDur, false /* canceled */)
+	tenant.addUsedLocked(group, uint64(item.requestedCount))
+	if group != tenant && !isInTenantHeap(group) {
+		tenant.groupHeap.remove(group)
+	}
 	if isInTenantHeap(tenant) {
 		q.mu.tenantHeap.fix(tenant)
 	} else {
@@ -921,7 +961,7 @@ func (q *WorkQueue) granted(grantChainID grantChainID) int64 {
 		// to replicated writes.
 		if log.V(1) {
 			q.mu.Lock()
-			queueLen := tenant.waitingWorkHeap.Len()
+			queueLen := group.waitingWorkHeap.Len()
 			q.mu.Unlock()
 
 			log.Infof(q.ambientCtx, "async-path: len(waiting-work)=%d dequeued t%d pri=%s r%s origin=n%s log-position=%s ingested=%t",
@@ -964,8 +1004,17 @@ func (q *WorkQueue) gcTenantsAndResetUsed() {
 			releaseTenantInfo(info)
 		} else {
 			info.used = 0
+			info.ungroupedUsed = 0
 			// All the heap members will reset used=0, so no need to change heap
-			// ordering.
+			// ordering. The same applies to the groupHeap.
+			for name, group := range info.groups {
+				if group.used == 0 && !isInTenantHeap(group) {
+					delete(info.groups, name)
+					releaseTenantInfo(group)
+				} else {
+					group.used = 0
+				}
+			}
 		}
 	}
 }
@@ -973,8 +1022,12 @@ func (q *WorkQueue) gcTenantsAndResetUsed() {
 // adjustTenantUsed is used internally by StoreWorkQueue, and by the KV queue
 // in AdmittedWorkDone. The additionalUsed count can be negative, in which
 // case it is returning unused resources. This is only for WorkQueue's own
-// accounting -- it should not call into granter.
-func (q *WorkQueue) adjustTenantUsed(tenantID roachpb.TenantID, additionalUsed int64) {
+// accounting -- it should not call into granter. The resourceGroup is the
+// resource group of the work, and is empty for work that is not part of a
+// group.
+func (q *WorkQueue) adjustTenantUsed(
+	tenantID roachpb.TenantID, resourceGroup string, additionalUsed int64,
+) {
 	tid := tenantID.ToUint64()
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -982,15 +1035,15 @@ func (q *WorkQueue) adjustTenantUsed(tenantID roachpb.TenantID, additionalUsed i
 	if !ok {
 		return
 	}
+	// If the resource group has been GC'd, only the tenant is adjusted.
+	group := tenant
+	if resourceGroup != "" {
+		group = tenant.groups[resourceGroup]
+	}
 	if additionalUsed < 0 {
-		toReturn := uint64(-additionalUsed)
-		if tenant.used < toReturn {
-			tenant.used = 0
-		} else {
-			tenant.used -= toReturn
-		}
+		tenant.subUsedLocked(group, uint64(-additionalUsed))
 	} else {
-		tenant.used += uint64(additionalUsed)
+		tenant.addUsedLocked(group, uint64(additionalUsed))
 	}
 	if isInTenantHeap(tenant) {
 		q.mu.tenantHeap.fix(tenant)
@@ -1015,10 +1068,8 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, _ rune) {
 		ids = append(ids, id)
 	}
 	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
-	for _, id := range ids {
-		tenant := q.mu.tenants[id]
-		s.Printf("\n tenant-id: %d used: %d, w: %d, fifo: %d", tenant.id, tenant.used,
-			tenant.weight, tenant.fifoPriorityThreshold)
+	// formatHeaps prints the waiting work of a tenant or resource group.
+	formatHeaps := func(tenant *tenantInfo) {
 		if len(tenant.waitingWorkHeap) > 0 {
 			// Sort items within waitingWorkHeap
 			sortedWaitingWorkHeap := slices.Clone(tenant.waitingWorkHeap)
@@ -1050,6 +1101,23 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, _ rune) {
 			}
 		}
 	}
+	for _, id := range ids {
+		tenant := q.mu.tenants[id]
+		s.Printf("\n tenant-id: %d used: %d, w: %d, fifo: %d", tenant.id, tenant.used,
+			tenant.weight, tenant.fifoPriorityThreshold)
+		formatHeaps(tenant)
+		var groups []string
+		for name := range tenant.groups {
+			groups = append(groups, name)
+		}
+		sort.Strings(groups)
+		for _, name := range groups {
+			group := tenant.groups[name]
+			s.Printf("\n  group: %s used: %d, w: %d, fifo: %d", group.group, group.used,
+				group.weight, group.fifoPriorityThreshold)
+			formatHeaps(group)
+		}
+	}
 }
 
 // Weight for tenants that are not assigned a weight. This typically applies
@@ -1057,6 +1125,11 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, _ rune) {
 // SetTenantWeights. Additionally, it is also the minimum tenant weight.
 const defaultTenantWeight = 1
 
+// Weight of the work of a tenant that is not part of a resource group, when
+// competing with the resource groups of the tenant. Additionally, it is also
+// the minimum resource group weight.
+const defaultResourceGroupWeight = 1
+
 // The current cap on the weight of a tenant. We don't allow a single tenant
 // to use more than cap times the number of resources of the smallest tenant.
 // For KV slots, we have seen a range of slot counts from 50-200 for 16 cpu
@@ -1351,11 +1424,113 @@ type tenantInfo struct {
 	// than WorkPriority since the threshold can be > MaxPri.
 	fifoPriorityThreshold int
 
+	// groups contains the resource groups of the tenant, keyed by name. Each
+	// group is represented by a tenantInfo with the id of the tenant, whose
+	// heaps contain the waiting work of the group, and whose used and weight
+	// fields are used for fair sharing between the groups of the tenant. The
+	// heaps of the tenant itself contain the waiting work that is not part of
+	// a resource group, which competes with the groups as if it were a group
+	// of weight defaultResourceGroupWeight. Groups are GC'd in the same way as
+	// tenants. Lazily allocated, and always nil for the tenantInfo of a group.
+	groups map[string]*tenantInfo
+	// groupHeap contains the groups with waiting work, ordered like the
+	// tenantHeap.
+	groupHeap tenantHeap
+	// group is the name of the resource group, for the tenantInfo of a group.
+	group string
+	// ungroupedUsed is the part of used that was consumed by work that is not
+	// part of a resource group.
+	ungroupedUsed uint64
+
 	// The heapIndex is maintained by the heap.Interface methods, and represents
 	// the heapIndex of the item in the heap.
 	heapIndex int
 }
 
+// getGroupLocked returns the tenantInfo of the given resource group of the
+// tenant, creating it if needed, and updates the weight of the group. It
+// returns the tenant itself for work that is not part of a resource group.
+func (t *tenantInfo) getGroupLocked(name string, weight uint32) *tenantInfo {
+	if name == "" {
+		return t
+	}
+	if weight < defaultResourceGroupWeight {
+		weight = defaultResourceGroupWeight
+	} else if weight > MaxResourceGroupCPUWeight {
+		weight = MaxResourceGroupCPUWeight
+	}
+	group, ok := t.groups[name]
+	if !ok {
+		if t.groups == nil {
+			t.groups = make(map[string]*tenantInfo)
+		}
+		group = newTenantInfo(t.id, weight)
+		group.group = name
+		t.groups[name] = group
+	} else if group.weight != weight {
+		group.weight = weight
+		if isInTenantHeap(group) {
+			t.groupHeap.fix(group)
+		}
+	}
+	return group
+}
+
+// nextGroupLocked returns the resource group of the tenant whose waiting work
+// should be granted next, which is the tenant itself for work that is not part
+// of a resource group. It must only be called when the tenant has waiting
+// work.
+func (t *tenantInfo) nextGroupLocked() *tenantInfo {
+	if len(t.groupHeap) == 0 {
+		return t
+	}
+	group := t.groupHeap[0]
+	if len(t.waitingWorkHeap) == 0 && len(t.openEpochsHeap) == 0 {
+		return group
+	}
+	if t.ungroupedUsed*uint64(group.weight) <= group.used*defaultResourceGroupWeight {
+		return t
+	}
+	return group
+}
+
+// addUsedLocked adds to the resources used by the tenant and by the given
+// resource group of the tenant, which is the tenant itself for work that is
+// not part of a resource group, and nil if the group is unknown. The caller
+// is responsible for fixing the position of the tenant in the tenantHeap.
+func (t *tenantInfo) addUsedLocked(group *tenantInfo, used uint64) {
+	t.used += used
+	if group == t {
+		t.ungroupedUsed += used
+	} else if group != nil {
+		group.used += used
+		if isInTenantHeap(group) {
+			t.groupHeap.fix(group)
+		}
+	}
+}
+
+// subUsedLocked is the inverse of addUsedLocked. Used values that would become
+// negative, because they were reset to 0 by the GC goroutine, are set to 0.
+func (t *tenantInfo) subUsedLocked(group *tenantInfo, used uint64) {
+	sub := func(v *uint64) {
+		if *v >= used {
+			*v -= used
+		} else {
+			*v = 0
+		}
+	}
+	sub(&t.used)
+	if group == t {
+		sub(&t.ungroupedUsed)
+	} else if group != nil {
+		sub(&group.used)
+		if isInTenantHeap(group) {
+			t.groupHeap.fix(group)
+		}
+	}
+}
+
 // tenantHeap is a heap of tenants with waiting work, ordered in increasing
 // order of tenantInfo.used/tenantInfo.weight (weights are an optional
 // feature, and default to 1). That is, we prefer tenants that are using less.
@@ -1376,6 +1551,7 @@ func newTenantInfo(id uint64, weight uint32) *tenantInfo {
 		weight:                weight,
 		waitingWorkHeap:       ti.waitingWorkHeap,
 		openEpochsHeap:        ti.openEpochsHeap,
+		groupHeap:             ti.groupHeap,
 		priorityStates:        makePriorityStates(ti.priorityStates.ps),
 		fifoPriorityThreshold: int(admissionpb.LowPri),
 		heapIndex:             -1,
@@ -1395,10 +1571,19 @@ func releaseTenantInfo(ti *tenantInfo) {
 	if cap(ti.openEpochsHeap) > 100 {
 		ti.openEpochsHeap = nil
 	}
+	if cap(ti.groupHeap) > 100 {
+		ti.groupHeap = nil
+	}
+	// The groups have no waiting work either, since they are only in the
+	// groupHeap when they do.
+	for _, group := range ti.groups {
+		releaseTenantInfo(group)
+	}
 
 	*ti = tenantInfo{
 		waitingWorkHeap: ti.waitingWorkHeap,
 		openEpochsHeap:  ti.openEpochsHeap,
+		groupHeap:       ti.groupHeap,
 		priorityStates:  makePriorityStates(ti.priorityStates.ps),
 	}
 	tenantInfoPool.Put(ti)
@@ -1419,9 +1604,13 @@ func (th *tenantHeap) Len() int {
 func (th *tenantHeap) Less(i, j int) bool {
 	// For tenant fairness, use used_i/weight_i < used_j/weight_j to determine
 	// order. In case of a tie, prioritize items with higher weight, and then
-	// items with lower tenant id.
+	// items with lower tenant id. The resource groups of a tenant, which have
+	// the same tenant id, are ordered by name.
 	if (*th)[i].used*uint64((*th)[j].weight) == (*th)[j].used*uint64((*th)[i].weight) {
 		if (*th)[i].weight == (*th)[j].weight {
+			if (*th)[i].id == (*th)[j].id {
+				return (*th)[i].group < (*th)[j].group
+			}
 			return (*th)[i].id < (*th)[j].id
 		}
 		return (*th)[i].weight > (*th)[j].weight
@@ -1924,7 +2113,8 @@ type StoreWorkQueue struct {
 // needed by the caller (see StoreWorkHandle.UseAdmittedWorkDone) and by
 // StoreWorkQueue.AdmittedWorkDone.
 type StoreWorkHandle struct {
-	tenantID roachpb.TenantID
+	tenantID      roachpb.TenantID
+	resourceGroup string
 	// The writeTokens acquired by this request. Must be > 0.
 	writeTokens         int64
 	workClass           admissionpb.WorkClass
@@ -1977,6 +2167,7 @@ func (q *StoreWorkQueue) Admit(
 
 	h := StoreWorkHandle{
 		tenantID:            info.TenantID,
+		resourceGroup:       info.ResourceGroup,
 		workClass:           wc,
 		writeTokens:         info.RequestedCount,
 		useAdmittedWorkDone: enabled,
@@ -2082,7 +2273,7 @@ func (q *StoreWorkQueue) admittedReplicatedWork(
 	if !coordMuLocked {
 		q.coordMu.Unlock()
 	}
-	q.q[wc].adjustTenantUsed(tenantID, additionalTokensNeeded)
+	q.q[wc].adjustTenantUsed(tenantID, "" /* resourceGroup */, additionalTokensNeeded)
 
 	// Inform callers of the entry we just admitted.
 	//
@@ -2157,7 +2348,7 @@ func (q *StoreWorkQueue) AdmittedWorkDone(h StoreWorkHandle, doneInfo StoreWorkD
 	}
 	q.updateStoreStatsAfterWorkDone(1, doneInfo, false, true)
 	additionalTokens := q.granters[h.workClass].storeWriteDone(h.writeTokens, doneInfo)
-	q.q[h.workClass].adjustTenantUsed(h.tenantID, additionalTokens)
+	q.q[h.workClass].adjustTenantUsed(h.tenantID, h.resourceGroup, additionalTokens)
 	return nil
 }
 
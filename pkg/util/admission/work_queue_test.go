@@ -108,9 +108,10 @@ func (tg *testGranter) storeReplicatedWorkAdmittedLocked(
 }
 
 type testWork struct {
-	tenantID roachpb.TenantID
-	cancel   context.CancelFunc
-	admitted bool
+	tenantID      roachpb.TenantID
+	resourceGroup string
+	cancel        context.CancelFunc
+	admitted      bool
 	// For StoreWorkQueue testing.
 	handle StoreWorkHandle
 }
@@ -159,7 +160,7 @@ func (m *workMap) get(id int) (work testWork, ok bool) {
 /*
 TestWorkQueueBasic is a datadriven test with the following commands:
 init
-admit id=<int> tenant=<int> priority=<int> create-time-millis=<int> bypass=<bool>
+admit id=<int> tenant=<int> priority=<int> create-time-millis=<int> bypass=<bool> [group=<string> group-weight=<int>]
 set-try-get-return-value v=<bool>
 granted chain-id=<int>
 cancel-work id=<int>
@@ -217,13 +218,21 @@ func TestWorkQueueBasic(t *testing.T) {
 				d.ScanArgs(t, "create-time-millis", &createTime)
 				var bypass bool
 				d.ScanArgs(t, "bypass", &bypass)
+				var group string
+				var groupWeight int
+				if d.HasArg("group") {
+					d.ScanArgs(t, "group", &group)
+					d.ScanArgs(t, "group-weight", &groupWeight)
+				}
 				ctx, cancel := context.WithCancel(context.Background())
-				wrkMap.set(id, &testWork{tenantID: tenant, cancel: cancel})
+				wrkMap.set(id, &testWork{tenantID: tenant, resourceGroup: group, cancel: cancel})
 				workInfo := WorkInfo{
-					TenantID:        tenant,
-					Priority:        admissionpb.WorkPriority(priority),
-					CreateTime:      int64(createTime) * int64(time.Millisecond),
-					BypassAdmission: bypass,
+					TenantID:            tenant,
+					Priority:            admissionpb.WorkPriority(priority),
+					ResourceGroup:       group,
+					ResourceGroupWeight: uint32(groupWeight),
+					CreateTime:          int64(createTime) * int64(time.Millisecond),
+					BypassAdmission:     bypass,
 				}
 				go func(ctx context.Context, info WorkInfo, id int) {
 					enabled, err := q.Admit(ctx, info)
@@ -289,7 +298,7 @@ func TestWorkQueueBasic(t *testing.T) {
 				if d.HasArg("cpu-time") {
 					d.ScanArgs(t, "cpu-time", &cpuTime)
 				}
-				q.AdmittedWorkDone(work.tenantID, time.Duration(cpuTime))
+				q.AdmittedWorkDone(work.tenantID, work.resourceGroup, time.Duration(cpuTime))
 				wrkMap.delete(id)
 				return buf.stringAndReset()
 
@@ -8,6 +8,7 @@ package tsearch
 import (
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -394,6 +395,99 @@ func (e *tsEvaluator) evalWithinFollowedBy(node *tsNode) (tsPositionSet, error)
 	return tsPositionSet{}, errors.AssertionFailedf("invalid operator %d", node.op)
 }
 
+// evalIgnoringNot is like evalNode, but it considers every ! operator which
+// isn't nested within a followed by operator to be satisfied.
+func (e *tsEvaluator) evalIgnoringNot(node *tsNode) (bool, error) {
+	switch node.op {
+	case and:
+		l, err := e.evalIgnoringNot(node.l)
+		if err != nil || !l {
+			return false, err
+		}
+		return e.evalIgnoringNot(node.r)
+	case or:
+		l, err := e.evalIgnoringNot(node.l)
+		if err != nil || l {
+			return l, err
+		}
+		return e.evalIgnoringNot(node.r)
+	case not:
+		return true, nil
+	}
+	return e.evalNode(node)
+}
+
+// indexedQuery is a copy of a TSQuery in which each leaf is replaced by a
+// placeholder lexeme naming the index of the leaf. It's used to evaluate a
+// query given the positions at which each of its leaves were found within a
+// range of a document, rather than against a whole TSVector, as required by
+// cover density ranking and headline generation.
+type indexedQuery struct {
+	root *tsNode
+	// leaves are the leaves of the original query, in the order of the indexes
+	// of their placeholders.
+	leaves []*tsNode
+}
+
+func makeIndexedQuery(q TSQuery) indexedQuery {
+	var iq indexedQuery
+	iq.root = iq.copyNode(q.root)
+	return iq
+}
+
+func (iq *indexedQuery) copyNode(n *tsNode) *tsNode {
+	if n == nil {
+		return nil
+	}
+	if n.op == invalid {
+		iq.leaves = append(iq.leaves, n)
+		return &tsNode{term: tsTerm{lexeme: strconv.Itoa(len(iq.leaves) - 1)}}
+	}
+	return &tsNode{op: n.op, followedN: n.followedN, l: iq.copyNode(n.l), r: iq.copyNode(n.r)}
+}
+
+// eval returns whether the query is satisfied given the sorted positions at
+// which each of its leaves were found. If ignoreNot is true, the ! operators
+// are considered to be satisfied, as done by evalIgnoringNot.
+func (iq *indexedQuery) eval(positions [][]tsPosition, ignoreNot bool) (bool, error) {
+	if iq.root == nil {
+		return false, nil
+	}
+	v := make(TSVector, 0, len(positions))
+	for i := range positions {
+		if len(positions[i]) > 0 {
+			v = append(v, tsTerm{lexeme: strconv.Itoa(i), positions: positions[i]})
+		}
+	}
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].lexeme < v[j].lexeme
+	})
+	e := tsEvaluator{v: v}
+	if ignoreNot {
+		return e.evalIgnoringNot(iq.root)
+	}
+	return e.evalNode(iq.root)
+}
+
+// matchesLeaf returns whether the given lexeme, found at a position with the
+// given weight, is matched by the ith leaf of the query.
+func (iq *indexedQuery) matchesLeaf(i int, lexeme string, weight tsWeight) bool {
+	term := iq.leaves[i].term
+	if len(term.positions) == 0 {
+		return lexeme == term.lexeme
+	}
+	queryWeight := term.positions[0].weight
+	if queryWeight&weightStar != 0 {
+		if !strings.HasPrefix(lexeme, term.lexeme) {
+			return false
+		}
+		queryWeight &^= weightStar
+	} else if lexeme != term.lexeme {
+		return false
+	}
+	return queryWeight == 0 || weight.matches(queryWeight)
+}
+
 func filterPositionsByWeight(positions []tsPosition, weight tsWeight) []tsPosition {
 	if weight == weightAny {
 		return positions
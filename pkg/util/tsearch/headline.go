@@ -0,0 +1,652 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package tsearch
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// This file implements ts_headline, which returns an excerpt of a document
+// in which the words matching a query are highlighted. It follows the
+// default headline generator of Postgres, which is implemented by
+// prsd_headline in src/backend/tsearch/wparser_def.c.
+
+// HeadlineOptions are the options of ts_headline. See
+// https://www.postgresql.org/docs/current/textsearch-controls.html#TEXTSEARCH-HEADLINE
+// for their meaning.
+type HeadlineOptions struct {
+	StartSel          string
+	StopSel           string
+	MaxWords          int
+	MinWords          int
+	ShortWord         int
+	HighlightAll      bool
+	MaxFragments      int
+	FragmentDelimiter string
+}
+
+// DefaultHeadlineOptions returns the options used by ts_headline when none
+// are specified.
+func DefaultHeadlineOptions() HeadlineOptions {
+	return HeadlineOptions{
+		StartSel:          "<b>",
+		StopSel:           "</b>",
+		MaxWords:          35,
+		MinWords:          15,
+		ShortWord:         3,
+		FragmentDelimiter: " ... ",
+	}
+}
+
+// ParseHeadlineOptions parses the options string of ts_headline, which is a
+// list of option=value pairs separated by commas or whitespace. Values may be
+// double-quoted. Options which aren't specified keep their default value.
+func ParseHeadlineOptions(input string) (HeadlineOptions, error) {
+	opts := DefaultHeadlineOptions()
+	syntaxError := func() (HeadlineOptions, error) {
+		return opts, pgerror.Newf(pgcode.Syntax, "invalid parameter list format: %q", input)
+	}
+	isSeparator := func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}
+	s := input
+	for {
+		s = strings.TrimLeftFunc(s, isSeparator)
+		if s == "" {
+			break
+		}
+		end := strings.IndexFunc(s, func(r rune) bool {
+			return r == '=' || unicode.IsSpace(r)
+		})
+		if end <= 0 {
+			return syntaxError()
+		}
+		name := s[:end]
+		s = strings.TrimLeftFunc(s[end:], unicode.IsSpace)
+		if !strings.HasPrefix(s, "=") {
+			return syntaxError()
+		}
+		s = strings.TrimLeftFunc(s[1:], unicode.IsSpace)
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			// A quoted value ends at the next unescaped quote.
+			var buf strings.Builder
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				buf.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return syntaxError()
+			}
+			value, s = buf.String(), s[i+1:]
+		} else {
+			end := strings.IndexFunc(s, isSeparator)
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return syntaxError()
+			}
+			value, s = s[:end], s[end:]
+		}
+		if err := opts.set(name, value); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+func (o *HeadlineOptions) set(name, value string) error {
+	parseInt := func() (int, error) {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, pgerror.Newf(pgcode.InvalidTextRepresentation,
+				"invalid input syntax for type integer: %q", value)
+		}
+		return i, nil
+	}
+	var err error
+	switch strings.ToLower(name) {
+	case "startsel":
+		o.StartSel = value
+	case "stopsel":
+		o.StopSel = value
+	case "maxwords":
+		o.MaxWords, err = parseInt()
+	case "minwords":
+		o.MinWords, err = parseInt()
+	case "shortword":
+		o.ShortWord, err = parseInt()
+	case "highlightall":
+		switch strings.ToLower(value) {
+		case "1", "on", "true", "t", "y", "yes":
+			o.HighlightAll = true
+		default:
+			o.HighlightAll = false
+		}
+	case "maxfragments":
+		o.MaxFragments, err = parseInt()
+	case "fragmentdelimiter":
+		o.FragmentDelimiter = value
+	default:
+		return pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized headline parameter: %q", name)
+	}
+	return err
+}
+
+func (o *HeadlineOptions) validate() error {
+	if o.HighlightAll {
+		// The other options are ignored.
+		return nil
+	}
+	if o.MinWords >= o.MaxWords {
+		return pgerror.New(pgcode.InvalidParameterValue, "MinWords should be less than MaxWords")
+	}
+	if o.MinWords <= 0 {
+		return pgerror.New(pgcode.InvalidParameterValue, "MinWords should be positive")
+	}
+	if o.ShortWord < 0 {
+		return pgerror.New(pgcode.InvalidParameterValue, "ShortWord should be >= 0")
+	}
+	if o.MaxFragments < 0 {
+		return pgerror.New(pgcode.InvalidParameterValue, "MaxFragments should be >= 0")
+	}
+	return nil
+}
+
+// Headline implements the ts_headline builtin. It returns an excerpt of the
+// input document in which the words matching the query are surrounded by
+// the StartSel and StopSel options. The document is parsed according to the
+// text search configuration passed by name.
+func Headline(config string, document string, q TSQuery, opts HeadlineOptions) (string, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+	h := headline{iq: makeIndexedQuery(q), opts: opts}
+	if err := h.parse(config, document); err != nil {
+		return "", err
+	}
+	if len(h.tokens) == 0 {
+		return "", nil
+	}
+	var err error
+	if opts.MaxFragments == 0 {
+		err = h.markWords()
+	} else {
+		err = h.markFragments()
+	}
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+// hlToken is a token of a document for which a headline is generated: either
+// a word, or the text between two words.
+type hlToken struct {
+	text string
+	word bool
+	// number is set for words made only of digits, which, like the text
+	// between words, don't make good end points for a headline.
+	number bool
+	// pos is the position of a word within the document.
+	pos uint16
+	// leaves are the indexes of the leaves of the query matching a word.
+	leaves []int
+
+	// selected is set for the tokens to highlight.
+	selected bool
+	// in is set for the tokens which are part of the headline.
+	in bool
+}
+
+type headline struct {
+	iq     indexedQuery
+	opts   HeadlineOptions
+	tokens []hlToken
+}
+
+// parse splits the document into tokens, consistently with TSParse, and
+// finds the leaves of the query matched by each word.
+func (h *headline) parse(config string, document string) error {
+	var pos int
+	for i := 0; i < len(document); {
+		r, _ := utf8.DecodeRuneInString(document[i:])
+		word := unicode.IsOneOf(validCharTables, r)
+		end := strings.IndexFunc(document[i:], func(r rune) bool {
+			return unicode.IsOneOf(validCharTables, r) != word
+		})
+		if end < 0 {
+			end = len(document) - i
+		}
+		t := hlToken{text: document[i : i+end], word: word}
+		i += end
+		if word {
+			pos++
+			if pos > maxTSVectorPosition {
+				pos = maxTSVectorPosition
+			}
+			t.pos = uint16(pos)
+			t.number = strings.IndexFunc(t.text, func(r rune) bool {
+				return !unicode.IsDigit(r)
+			}) < 0
+			lexeme, stopWord, err := TSLexize(config, t.text)
+			if err != nil {
+				return err
+			}
+			if !stopWord {
+				for leaf := range h.iq.leaves {
+					// Documents have no weights, so the weights of the query are
+					// ignored.
+					if h.iq.matchesLeaf(leaf, lexeme, weightAny) {
+						t.leaves = append(t.leaves, leaf)
+					}
+				}
+			}
+		}
+		h.tokens = append(h.tokens, t)
+	}
+	return nil
+}
+
+// interesting returns whether the ith token matches the query.
+func (h *headline) interesting(i int) bool {
+	return len(h.tokens[i].leaves) > 0
+}
+
+// badEndpoint returns whether the ith token is a poor choice for either end
+// of a headline.
+func (h *headline) badEndpoint(i int) bool {
+	t := &h.tokens[i]
+	return (!t.word || t.number || len(t.text) <= h.opts.ShortWord) && !h.interesting(i)
+}
+
+// nextInteresting returns the index of the first token at or after i which
+// matches the query, or -1 if there is none.
+func (h *headline) nextInteresting(i int) int {
+	for ; i < len(h.tokens); i++ {
+		if h.interesting(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches returns whether the tokens between indexes pMin and pMax, included,
+// satisfy the query.
+func (h *headline) matches(pMin, pMax int) (bool, error) {
+	positions := make([][]tsPosition, len(h.iq.leaves))
+	for i := pMin; i <= pMax; i++ {
+		for _, leaf := range h.tokens[i].leaves {
+			positions[leaf] = append(positions[leaf], tsPosition{position: h.tokens[i].pos})
+		}
+	}
+	return h.iq.eval(positions, false /* ignoreNot */)
+}
+
+// cover returns the earliest and shortest range of tokens, starting at or
+// after the token p, which satisfies the query. Both ends of the range are
+// words matching the query.
+func (h *headline) cover(p int) (int, int, bool, error) {
+	maxCover := h.opts.MaxWords * 10
+	if maxCover < 100 {
+		maxCover = 100
+	}
+	pMin := h.nextInteresting(p)
+	for pMin >= 0 {
+		nextPMin := -1
+		// Consider the range made of the single token at pMin first, then the
+		// longer ones.
+		for pMax := pMin; pMax >= 0 && pMax-pMin < maxCover; {
+			ok, err := h.matches(pMin, pMax)
+			if err != nil || ok {
+				return pMin, pMax, ok, err
+			}
+			nextPMax := h.nextInteresting(pMax + 1)
+			if pMax == pMin {
+				nextPMin = nextPMax
+			}
+			pMax = nextPMax
+		}
+		pMin = nextPMin
+	}
+	return 0, 0, false, nil
+}
+
+// markWords selects the tokens of the headline when MaxFragments is 0: the
+// headline is the best excerpt containing a cover of the query, stretched to
+// between MinWords and MaxWords words. This parallels mark_hl_words in
+// Postgres.
+func (h *headline) markWords() error {
+	minWords, maxWords := h.opts.MinWords, h.opts.MaxWords
+	var bestB, bestE int
+	if h.opts.HighlightAll {
+		bestB, bestE = 0, len(h.tokens)-1
+	} else {
+		bestLen := -1
+		bestCover := false
+		for p := 0; ; p++ {
+			var q int
+			var ok bool
+			var err error
+			p, q, ok, err = h.cover(p)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			// Count the words and the interesting words within the cover, but
+			// stop once MaxWords is reached.
+			curLen, posLen := 0, 0
+			posB, posE := p, p
+			i := p
+			for ; i <= q && curLen < maxWords; i++ {
+				if h.tokens[i].word {
+					curLen++
+				}
+				if h.interesting(i) {
+					posLen++
+				}
+				posE = i
+			}
+			if curLen < maxWords {
+				// There is room to lengthen the headline, so search forward
+				// until it's full or a good end point is found, starting over
+				// from the last token of the cover.
+				for i = i - 1; i < len(h.tokens) && curLen < maxWords; i++ {
+					if i > q {
+						if h.tokens[i].word {
+							curLen++
+						}
+						if h.interesting(i) {
+							posLen++
+						}
+					}
+					posE = i
+					if h.badEndpoint(i) {
+						continue
+					}
+					if curLen >= minWords {
+						break
+					}
+				}
+				if curLen < minWords {
+					// The end of the document was reached and the headline is
+					// still too short, so try to extend it backward.
+					for i = p - 1; i >= 0; i-- {
+						if h.tokens[i].word {
+							curLen++
+						}
+						if h.interesting(i) {
+							posLen++
+						}
+						if curLen >= maxWords {
+							break
+						}
+						if h.badEndpoint(i) {
+							continue
+						}
+						if curLen >= minWords {
+							break
+						}
+					}
+					posB = i
+					if posB < 0 {
+						posB = 0
+					}
+				}
+			} else {
+				// The headline can't be made longer, so consider making it
+				// shorter to avoid a bad end point.
+				if i > q {
+					i = q
+				}
+				for ; curLen > minWords; i-- {
+					if !h.badEndpoint(i) {
+						break
+					}
+					if h.tokens[i].word {
+						curLen--
+					}
+					if h.interesting(i) {
+						posLen--
+					}
+					posE = i - 1
+				}
+			}
+			// Prefer the headlines which include the whole cover, then the ones
+			// with more interesting words, then the ones with a good end point.
+			includesCover := posB <= p && posE >= q
+			if (includesCover && !bestCover) ||
+				(includesCover == bestCover && (posLen > bestLen ||
+					(posLen == bestLen && !h.badEndpoint(posE) && h.badEndpoint(bestE)))) {
+				bestB, bestE = posB, posE
+				bestLen = posLen
+				bestCover = includesCover
+			}
+		}
+		if bestLen < 0 {
+			// No cover was found, so the headline is made of the first MinWords
+			// words of the document.
+			bestB, bestE = 0, h.firstWordsEnd()
+		}
+	}
+	h.mark(bestB, bestE)
+	return nil
+}
+
+// hlFragment is a candidate fragment of a headline made of several fragments.
+type hlFragment struct {
+	startPos, endPos int
+	curLen, posLen   int
+	chosen, excluded bool
+}
+
+// markFragments selects the tokens of the headline when MaxFragments is
+// positive: the headline is made of up to MaxFragments excerpts of at most
+// MaxWords words, each containing part of a cover of the query. This
+// parallels mark_hl_fragments in Postgres.
+func (h *headline) markFragments() error {
+	maxWords := h.opts.MaxWords
+	maxCover := h.opts.MaxWords * 10
+	if maxCover < 100 {
+		maxCover = 100
+	}
+
+	// Split each of the covers into fragments of at most MaxWords words which
+	// start and end with words matching the query.
+	var fragments []hlFragment
+	for p := 0; ; p++ {
+		var q int
+		var ok bool
+		var err error
+		p, q, ok, err = h.cover(p)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		for startPos, endPos := p, q; startPos <= endPos; startPos, endPos = endPos+1, q {
+			f := h.nextFragment(startPos, endPos)
+			fragments = append(fragments, f)
+			endPos = f.endPos
+		}
+	}
+
+	numChosen := 0
+	for n := 0; n < h.opts.MaxFragments; n++ {
+		// Choose the fragment with the most interesting words, breaking ties in
+		// favor of the one with fewer words.
+		best := -1
+		maxItems, minWords := 0, math.MaxInt
+		for i := range fragments {
+			f := &fragments[i]
+			if !f.chosen && !f.excluded &&
+				(maxItems < f.posLen || (maxItems == f.posLen && minWords > f.curLen)) {
+				maxItems, minWords = f.posLen, f.curLen
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		f := &fragments[best]
+		f.chosen = true
+		startPos, endPos, curLen := f.startPos, f.endPos, f.curLen
+		if curLen < maxWords {
+			// Stretch the fragment on both sides, without running into the
+			// fragments which were already chosen.
+			maxStretch := (maxWords - curLen) / 2
+			stretch := 0
+			marker := startPos
+			for i := startPos - 1; i >= 0 && stretch < maxStretch && !h.tokens[i].in; i-- {
+				if h.tokens[i].word {
+					curLen++
+					stretch++
+				}
+				marker = i
+			}
+			// Move the start forward until a good end point is found.
+			i := marker
+			for ; i < startPos && h.badEndpoint(i); i++ {
+				if h.tokens[i].word {
+					curLen--
+				}
+			}
+			startPos = i
+			marker = endPos
+			for i = endPos + 1; i < len(h.tokens) && curLen < maxWords && !h.tokens[i].in; i++ {
+				if h.tokens[i].word {
+					curLen++
+				}
+				marker = i
+			}
+			// Move the end backward until a good end point is found.
+			for i = marker; i > endPos && h.badEndpoint(i); i-- {
+				if h.tokens[i].word {
+					curLen--
+				}
+			}
+			endPos = i
+		}
+		f.startPos, f.endPos, f.curLen = startPos, endPos, curLen
+		h.mark(startPos, endPos)
+		numChosen++
+		// Exclude the fragments overlapping with the chosen one.
+		for i := range fragments {
+			g := &fragments[i]
+			if i != best && ((g.startPos >= startPos && g.startPos <= endPos) ||
+				(g.endPos >= startPos && g.endPos <= endPos) ||
+				(g.startPos < startPos && g.endPos > endPos)) {
+				g.excluded = true
+			}
+		}
+	}
+	if numChosen == 0 {
+		// No cover was found, so the headline is made of the first MinWords
+		// words of the document.
+		h.mark(0, h.firstWordsEnd())
+	}
+	return nil
+}
+
+// nextFragment returns the fragment of at most MaxWords words which starts
+// with the first word matching the query at or after startPos, and which ends
+// at endPos or at an earlier word matching the query.
+func (h *headline) nextFragment(startPos, endPos int) hlFragment {
+	for i := startPos; i <= endPos; i++ {
+		startPos = i
+		if h.interesting(i) {
+			break
+		}
+	}
+	f := hlFragment{startPos: startPos, endPos: endPos}
+	i := startPos
+	for ; i <= endPos && f.curLen < h.opts.MaxWords; i++ {
+		if h.tokens[i].word {
+			f.curLen++
+		}
+		if h.interesting(i) {
+			f.posLen++
+		}
+	}
+	if endPos > i {
+		// The fragment was cut short, so move its end back to a word matching
+		// the query.
+		for f.endPos = i; i >= startPos; i-- {
+			f.endPos = i
+			if h.interesting(i) {
+				break
+			}
+			if h.tokens[i].word {
+				f.curLen--
+			}
+		}
+	}
+	return f
+}
+
+// firstWordsEnd returns the index of the last token of the first MinWords
+// words of the document.
+func (h *headline) firstWordsEnd() int {
+	curLen, end := 0, 0
+	for i := 0; i < len(h.tokens) && curLen < h.opts.MinWords; i++ {
+		if h.tokens[i].word {
+			curLen++
+		}
+		end = i
+	}
+	return end
+}
+
+// mark adds the tokens between the given indexes, included, to the headline.
+func (h *headline) mark(startPos, endPos int) {
+	for i := startPos; i <= endPos; i++ {
+		h.tokens[i].selected = h.interesting(i)
+		h.tokens[i].in = true
+	}
+}
+
+// String returns the headline, with the fragments which aren't adjacent
+// separated by the FragmentDelimiter option.
+func (h *headline) String() string {
+	var buf strings.Builder
+	inFragment := false
+	numFragments := 0
+	for _, t := range h.tokens {
+		if !t.in {
+			inFragment = false
+			continue
+		}
+		if !inFragment {
+			inFragment = true
+			numFragments++
+			if numFragments > 1 {
+				buf.WriteString(h.opts.FragmentDelimiter)
+			}
+		}
+		if t.selected {
+			buf.WriteString(h.opts.StartSel)
+		}
+		buf.WriteString(t.text)
+		if t.selected {
+			buf.WriteString(h.opts.StopSel)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadline(t *testing.T) {
+	const doc = `The most common type of search
+is to find all documents containing given query terms
+and return them in order of their similarity to the
+query.`
+	for _, tc := range []struct {
+		query    string
+		options  string
+		expected string
+	}{
+		{
+			query: "query & similarity",
+			expected: `containing given <b>query</b> terms
+and return them in order of their <b>similarity</b> to the
+<b>query</b>.`,
+		},
+		{
+			query:   "query & similarity",
+			options: "StartSel = <, StopSel = >",
+			expected: `containing given <query> terms
+and return them in order of their <similarity> to the
+<query>.`,
+		},
+		{
+			query:   "search & term",
+			options: "MaxFragments=10, MaxWords=7, MinWords=3, StartSel=<<, StopSel=>>",
+			expected: `common type of <<search>>
+is to find ... containing given query <<terms>>
+and return them`,
+		},
+		{
+			query:   "search & term",
+			options: "HighlightAll=true",
+			expected: `The most common type of <b>search</b>
+is to find all documents containing given query <b>terms</b>
+and return them in order of their similarity to the
+query.`,
+		},
+		{
+			query:    "nothing",
+			options:  "MaxWords=5, MinWords=3",
+			expected: `The most common`,
+		},
+		{
+			query: "type <-> search",
+			expected: `The most common <b>type</b> of <b>search</b>
+is to find all documents containing given query terms`,
+		},
+	} {
+		t.Log(tc.query, tc.options)
+		q, err := ToTSQuery("english", tc.query)
+		require.NoError(t, err)
+		opts, err := ParseHeadlineOptions(tc.options)
+		require.NoError(t, err)
+		actual, err := Headline("english", doc, q, opts)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, actual)
+	}
+}
+
+func TestParseHeadlineOptionsError(t *testing.T) {
+	for _, tc := range []string{
+		`MaxWords`,
+		`MaxWords=`,
+		`Foo=1`,
+		`MaxWords=abc`,
+		`StartSel="<b`,
+	} {
+		t.Log(tc)
+		_, err := ParseHeadlineOptions(tc)
+		assert.Error(t, err)
+	}
+}
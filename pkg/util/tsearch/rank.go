@@ -9,6 +9,9 @@ import (
 	"math"
 	"sort"
 	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 )
 
 // defaultWeights is the default list of weights corresponding to the tsvector
@@ -20,12 +23,8 @@ var defaultWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
 // 0, the default, ignores the document length.
 // 1 devides the rank by 1 + the logarithm of the document length.
 // 2 divides the rank by the document length.
-// 4 divides the rank by the mean harmonic distance between extents.
-//
-//	NOTE: This is only implemented by ts_rank_cd, which is currently not
-//	implemented by CockroachDB. This constant is left for consistency with
-//	the original PostgreSQL source code.
-//
+// 4 divides the rank by the mean harmonic distance between extents. This is
+// only implemented by ts_rank_cd.
 // 8 divides the rank by the number of unique words in document.
 // 16 divides the rank by 1 + the logarithm of the number of unique words in document.
 // 32 divides the rank by itself + 1.
@@ -39,8 +38,7 @@ const (
 	// rankNormLength divides the rank by the document length.
 	rankNormLength = 0x02
 	// rankNormExtdist divides the rank by the mean harmonic distance between extents.
-	// Note, this is only implemented by ts_rank_cd, which is not currently implemented
-	// by CockroachDB. The constant is kept for consistency with Postgres.
+	// Note, this is only implemented by ts_rank_cd.
 	rankNormExtdist = 0x04
 	// rankNormUniq divides the rank by the number of unique words in document.
 	rankNormUniq = 0x08
@@ -52,7 +50,6 @@ const (
 
 // Defeat the unused linter.
 var _ = rankNoNorm
-var _ = rankNormExtdist
 
 // cntLen returns the count of represented lexemes in a tsvector, including
 // the number of repeated lexemes in the vector.
@@ -279,3 +276,242 @@ func wordDistance(dist int) float32 {
 	}
 	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(float32(dist)/1.5-2))))
 }
+
+// RankCD implements the ts_rank_cd functionality, which ranks a tsvector
+// against a tsquery using the cover density ranking described in Clarke,
+// Cormack, and Tudhope's "Relevance Ranking for One to Three Term Queries".
+// The parameters are the same as those of Rank. Since the ranking depends on
+// the proximity of the matching lexemes, lexemes without positions are
+// ignored.
+//
+// N.B.: this function is translated from the calc_rank_cd function in
+// tsrank.c.
+// https://github.com/postgres/postgres/blob/765f5df726918bcdcfd16bcc5418e48663d1dd59/src/backend/utils/adt/tsrank.c#L833
+func RankCD(weights []float32, v TSVector, q TSQuery, method int) (float32, error) {
+	w := defaultWeights
+	if weights != nil {
+		copy(w[:4], weights[:4])
+	}
+	var invWeights [4]float64
+	for i := range w {
+		if w[i] < 0 {
+			w[i] = defaultWeights[i]
+		}
+		if w[i] > 1 {
+			return 0, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		}
+		invWeights[i] = 1 / float64(w[i])
+	}
+	if len(v) == 0 || q.root == nil {
+		return 0, nil
+	}
+
+	iq := makeIndexedQuery(q)
+	f := coverFinder{
+		iq:        &iq,
+		doc:       makeCoverDocument(v, &iq),
+		positions: make([][]tsPosition, len(iq.leaves)),
+	}
+	var res, sumDist, prevExtPos float64
+	var nExtents int
+	for {
+		c, ok, err := f.next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		var invSum float64
+		for i := c.begin; i <= c.end; i++ {
+			invSum += invWeights[f.doc[i].pos.weight.val()]
+		}
+		cPos := float64(c.end-c.begin+1) / invSum
+		// If the document is big enough, q may be equal to p because of the
+		// limit on positions. In that case, the number of noise words is
+		// approximated as half of the length of the cover.
+		nNoise := (c.q - c.p) - (c.end - c.begin)
+		if nNoise < 0 {
+			nNoise = (c.end - c.begin) / 2
+		}
+		res += cPos / float64(1+nNoise)
+
+		curExtPos := float64(c.q+c.p) / 2
+		// Comparing the positions prevents a division by zero when several
+		// lexemes share the same position.
+		if nExtents > 0 && curExtPos > prevExtPos {
+			sumDist += 1 / (curExtPos - prevExtPos)
+		}
+		prevExtPos = curExtPos
+		nExtents++
+	}
+
+	if method&rankNormLoglength > 0 {
+		res /= math.Log(float64(cntLen(v) + 1))
+	}
+	if method&rankNormLength > 0 {
+		l := cntLen(v)
+		if l > 0 {
+			res /= float64(l)
+		}
+	}
+	if method&rankNormExtdist > 0 && nExtents > 0 && sumDist > 0 {
+		res /= float64(nExtents) / sumDist
+	}
+	if method&rankNormUniq > 0 {
+		res /= float64(len(v))
+	}
+	if method&rankNormLoguniq > 0 {
+		res /= math.Log(float64(len(v)+1)) / math.Log(2.0)
+	}
+	if method&rankNormRdivrplus1 > 0 {
+		res /= res + 1
+	}
+	return float32(res), nil
+}
+
+// coverEntry is a position of a document at which some leaves of a query
+// match.
+type coverEntry struct {
+	pos tsPosition
+	// lexeme is the index of the matching lexeme within the document.
+	lexeme int
+	// leaves are the indexes of the matching leaves within the query.
+	leaves []int
+}
+
+// makeCoverDocument returns the positions of v which are matched by the leaves
+// of the query, sorted by position.
+func makeCoverDocument(v TSVector, iq *indexedQuery) []coverEntry {
+	var doc []coverEntry
+	for leaf, n := range iq.leaves {
+		target := n.term.lexeme
+		i := sort.Search(len(v), func(i int) bool {
+			return v[i].lexeme >= target
+		})
+		for ; i < len(v) && strings.HasPrefix(v[i].lexeme, target); i++ {
+			for _, pos := range v[i].positions {
+				if iq.matchesLeaf(leaf, v[i].lexeme, pos.weight) {
+					doc = append(doc, coverEntry{pos: pos, lexeme: i, leaves: []int{leaf}})
+				}
+			}
+		}
+	}
+	sort.Slice(doc, func(i, j int) bool {
+		if doc[i].pos.position != doc[j].pos.position {
+			return doc[i].pos.position < doc[j].pos.position
+		}
+		if doc[i].pos.weight != doc[j].pos.weight {
+			return doc[i].pos.weight.val() < doc[j].pos.weight.val()
+		}
+		return doc[i].lexeme < doc[j].lexeme
+	})
+	// Merge the entries of the leaves which match the same position.
+	merged := doc[:0]
+	for _, e := range doc {
+		if n := len(merged); n > 0 && merged[n-1].pos == e.pos && merged[n-1].lexeme == e.lexeme {
+			merged[n-1].leaves = append(merged[n-1].leaves, e.leaves...)
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// cover is a range of document entries which satisfies a query.
+type cover struct {
+	// begin and end are the indexes of the first and last entries of the
+	// cover.
+	begin, end int
+	// p and q are the first and last positions of the cover.
+	p, q int
+}
+
+// coverFinder iterates over the covers of a query within a document. It's a
+// translation of the Cover function in tsrank.c.
+type coverFinder struct {
+	iq  *indexedQuery
+	doc []coverEntry
+	// positions are the positions of the document at which each leaf of the
+	// query was found while scanning for a cover.
+	positions [][]tsPosition
+	// start is the index of the entry at which to look for the next cover.
+	start int
+}
+
+// next returns the next cover, if there is one.
+func (f *coverFinder) next() (cover, bool, error) {
+	for f.start < len(f.doc) {
+		// Look for the end of the cover by scanning forward until the query is
+		// satisfied. The ! operators are ignored, since the absence of a lexeme
+		// can't end a cover.
+		f.reset()
+		end := -1
+		for i := f.start; i < len(f.doc); i++ {
+			f.add(i)
+			ok, err := f.iq.eval(f.positions, true /* ignoreNot */)
+			if err != nil {
+				return cover{}, false, err
+			}
+			if ok {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return cover{}, false, nil
+		}
+		// Then, look for the beginning of the cover by scanning backward from
+		// its end until the query is satisfied.
+		f.reset()
+		for i := end; i >= f.start; i-- {
+			f.add(i)
+			ok, err := f.iq.eval(f.reversedPositions(), false /* ignoreNot */)
+			if err != nil {
+				return cover{}, false, err
+			}
+			if ok {
+				f.start = i + 1
+				return cover{
+					begin: i,
+					end:   end,
+					p:     int(f.doc[i].pos.position),
+					q:     int(f.doc[end].pos.position),
+				}, true, nil
+			}
+		}
+		f.start++
+	}
+	return cover{}, false, nil
+}
+
+func (f *coverFinder) reset() {
+	for i := range f.positions {
+		f.positions[i] = f.positions[i][:0]
+	}
+}
+
+// add records the position of the ith entry of the document for each of its
+// leaves.
+func (f *coverFinder) add(i int) {
+	e := f.doc[i]
+	for _, leaf := range e.leaves {
+		p := f.positions[leaf]
+		if n := len(p); n == 0 || p[n-1].position != e.pos.position {
+			f.positions[leaf] = append(p, tsPosition{position: e.pos.position})
+		}
+	}
+}
+
+// reversedPositions returns the positions recorded while scanning the
+// document backward, in ascending order.
+func (f *coverFinder) reversedPositions() [][]tsPosition {
+	ret := make([][]tsPosition, len(f.positions))
+	for i, p := range f.positions {
+		ret[i] = make([]tsPosition, len(p))
+		for j := range p {
+			ret[i][j] = p[len(p)-1-j]
+		}
+	}
+	return ret
+}
@@ -39,3 +39,36 @@ func TestRank(t *testing.T) {
 		assert.Equalf(t, tt.expected, actual, "Rank(%v, %v, %v, %v)", tt.weights, tt.v, tt.q, tt.method)
 	}
 }
+
+func TestRankCD(t *testing.T) {
+	tests := []struct {
+		weights  []float32
+		v        string
+		q        string
+		method   int
+		expected float32
+	}{
+		{v: "a:1 s:2C d g", q: "a | s", expected: 0.3},
+		{v: "a:1 sa:2C d g", q: "a | s", expected: 0.1},
+		{v: "a:1 sa:2C d g", q: "a | s:*", expected: 0.3},
+		{v: "a:1 sa:2C d g", q: "a | sa:*", expected: 0.3},
+		{v: "a:1 sa:3C sab:2c d g", q: "a | sa:*", expected: 0.5},
+		{v: "a:1 s:2B d g", q: "a | s", expected: 0.5},
+		{v: "a:1 s:2 d g", q: "a | s", expected: 0.2},
+		{v: "a:1 s:2C d g", q: "a & s", expected: 0.13333334},
+		{v: "a:1 s:2B d g", q: "a & s", expected: 0.16},
+		{v: "a:1 s:2 d g", q: "a & s", expected: 0.1},
+		{v: "a:1 b:2", q: "a <-> b", expected: 0.1},
+		{v: "a:1 b:3", q: "a <-> b", expected: 0},
+		{v: "a:1 b:3", q: "a & !c", expected: 0.1},
+	}
+	for _, tt := range tests {
+		v, err := ParseTSVector(tt.v)
+		assert.NoError(t, err)
+		q, err := ParseTSQuery(tt.q)
+		assert.NoError(t, err)
+		actual, err := RankCD(tt.weights, v, q, tt.method)
+		assert.NoError(t, err)
+		assert.Equalf(t, tt.expected, actual, "RankCD(%v, %v, %v, %v)", tt.weights, tt.v, tt.q, tt.method)
+	}
+}
@@ -8,6 +8,8 @@ package tsearch
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/keysbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
@@ -505,7 +507,14 @@ func toTSQuery(config string, interpose tsOperator, input string) (TSQuery, erro
 		}
 	}
 
-	// Now create the operator tree.
+	return parseNormalizedTSQuery(tokens, input, foundStopwords)
+}
+
+// parseNormalizedTSQuery creates the operator tree of a query out of the
+// stopworded and normalized tokens of the given input. If foundStopwords is
+// true, the stopwords, which are represented by empty lexemes, are removed
+// from the tree.
+func parseNormalizedTSQuery(tokens TSVector, input string, foundStopwords bool) (TSQuery, error) {
 	queryParser := tsQueryParser{terms: tokens, input: input}
 	query, err := queryParser.parse()
 	if err != nil {
@@ -521,6 +530,139 @@ func toTSQuery(config string, interpose tsOperator, input string) (TSQuery, erro
 	return query, err
 }
 
+// WebSearchToTSQuery implements the websearch_to_tsquery builtin, which
+// converts an input written in the syntax used by web search engines into a
+// query, performing stopwording and normalization on its tokens:
+//   - unquoted words are connected by the & operator;
+//   - the words of quoted text are connected by the <-> operator;
+//   - the word "or" is converted to the | operator;
+//   - a dash in front of a word or quoted text is converted to the ! operator.
+//
+// Any other punctuation is ignored, so that the conversion never fails with a
+// syntax error. This parallels gettoken_query_websearch in Postgres.
+func WebSearchToTSQuery(config string, input string) (TSQuery, error) {
+	var tokens TSVector
+	foundStopwords := false
+	// addOperand adds the lexemes of the given text to the tokens, connected
+	// by the <-> operator.
+	addOperand := func(text string) error {
+		words := TSParse(text)
+		if len(words) == 0 {
+			// Like in Postgres, an operand without any lexemes is treated as a
+			// stopword, so that the operators surrounding it are cleaned up.
+			tokens = append(tokens, tsTerm{})
+			foundStopwords = true
+			return nil
+		}
+		if len(words) > 1 {
+			tokens = append(tokens, tsTerm{operator: lparen})
+		}
+		for i := range words {
+			if i > 0 {
+				tokens = append(tokens, tsTerm{operator: followedby, followedN: 1})
+			}
+			lexeme, stopWord, err := TSLexize(config, words[i])
+			if err != nil {
+				return err
+			}
+			if stopWord {
+				foundStopwords = true
+			}
+			tokens = append(tokens, tsTerm{lexeme: lexeme})
+		}
+		if len(words) > 1 {
+			tokens = append(tokens, tsTerm{operator: rparen})
+		}
+		return nil
+	}
+
+	expectingOperand := true
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if !expectingOperand {
+			if unicode.IsSpace(r) {
+				i += size
+				continue
+			}
+			expectingOperand = true
+			if n := webSearchOrLen(input[i:]); n > 0 {
+				tokens = append(tokens, tsTerm{operator: or})
+				i += n
+				continue
+			}
+			// Any other character starts a new operand, which is implicitly
+			// connected to the previous one by the & operator.
+			tokens = append(tokens, tsTerm{operator: and})
+		}
+		switch {
+		case r == '-':
+			tokens = append(tokens, tsTerm{operator: not})
+			i += size
+		case r == '"':
+			// Everything up to the closing quote, or the end of the input, is a
+			// single operand.
+			text := input[i+size:]
+			if end := strings.IndexByte(text, '"'); end >= 0 {
+				text = text[:end]
+				i++
+			}
+			i += size + len(text)
+			if err := addOperand(text); err != nil {
+				return TSQuery{}, err
+			}
+			expectingOperand = false
+		case unicode.IsSpace(r) || isWebSearchOperator(r):
+			i += size
+		default:
+			end := strings.IndexFunc(input[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || r == '"' || isWebSearchOperator(r)
+			})
+			if end < 0 {
+				end = len(input) - i
+			}
+			if err := addOperand(input[i : i+end]); err != nil {
+				return TSQuery{}, err
+			}
+			i += end
+			expectingOperand = false
+		}
+	}
+	if expectingOperand && len(tokens) > 0 {
+		// The input ended with an operator, which still needs an operand.
+		tokens = append(tokens, tsTerm{})
+		foundStopwords = true
+	}
+	return parseNormalizedTSQuery(tokens, input, foundStopwords)
+}
+
+// isWebSearchOperator returns whether the given character is one of the
+// tsquery operators, which have no meaning in web search syntax.
+func isWebSearchOperator(r rune) bool {
+	switch r {
+	case '!', '&', '|', '(', ')', '<':
+		return true
+	}
+	return false
+}
+
+// webSearchOrLen returns the length of the "or" operator at the start of the
+// input, or 0 if the input doesn't start with one. Like in Postgres, "or" is
+// only an operator if it isn't part of a longer word, and if it's followed by
+// an operand.
+func webSearchOrLen(input string) int {
+	if len(input) < 2 || !strings.EqualFold(input[:2], "or") {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(input[2:])
+	if size == 0 || r == '-' || r == '_' || unicode.IsOneOf(validCharTables, r) {
+		return 0
+	}
+	if strings.TrimLeftFunc(input[2+size:], unicode.IsSpace) == "" {
+		return 0
+	}
+	return 2
+}
+
 func cleanupStopwords(query TSQuery) TSQuery {
 	query.root, _, _ = cleanupStopword(query.root)
 	if query.root == nil {
@@ -290,3 +290,30 @@ func TestParseTSQueryError(t *testing.T) {
 		assert.Error(t, err)
 	}
 }
+
+func TestWebSearchToTSQuery(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`The fat rats`, `'fat' & 'rat'`},
+		{`"supernovae stars" -crab`, `'supernova' <-> 'star' & !'crab'`},
+		{`"sad cat" or "fat rat"`, `'sad' <-> 'cat' | 'fat' <-> 'rat'`},
+		{`signal -"segmentation fault"`, `'signal' & !( 'segment' <-> 'fault' )`},
+		{`"fat the rats"`, `'fat' <2> 'rat'`},
+		{`a orange`, `'orang'`},
+		{`a or "b c" -d`, `'b' <-> 'c' & !'d'`},
+		{`""" )( dummy \\ query <->`, `'dummi' <-> 'queri'`},
+	} {
+		t.Log(tc.input)
+		query, err := WebSearchToTSQuery("english", tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, query.String())
+	}
+
+	for _, tc := range []string{``, `the`, `-`, `a or`} {
+		t.Log(tc)
+		_, err := WebSearchToTSQuery("english", tc)
+		assert.Error(t, err)
+	}
+}
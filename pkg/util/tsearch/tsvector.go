@@ -423,25 +423,143 @@ func TSLexize(config string, token string) (lexeme string, stopWord bool, err er
 // stems and normalizes the lexemes, and returns a TSVector annotated with
 // lexeme positions according to a text search configuration passed by name.
 func DocumentToTSVector(config string, input string) (TSVector, error) {
-	tokens := TSParse(input)
-	vector := make(TSVector, 0, len(tokens))
-	for i := range tokens {
-		lexeme, stopWord, err := TSLexize(config, tokens[i])
-		if err != nil {
-			return nil, err
+	return DocumentsToTSVector(config, []string{input})
+}
+
+// DocumentsToTSVector is like DocumentToTSVector, but it produces a single
+// TSVector out of several documents, such as the string values of a JSON
+// document. The positions of the lexemes of a document follow those of the
+// previous one, leaving a gap of one position after each document which
+// contains lexemes so that phrase searches don't match across documents.
+func DocumentsToTSVector(config string, inputs []string) (TSVector, error) {
+	var vector TSVector
+	var offset int
+	for _, input := range inputs {
+		tokens := TSParse(input)
+		foundLexemes := false
+		for i := range tokens {
+			lexeme, stopWord, err := TSLexize(config, tokens[i])
+			if err != nil {
+				return nil, err
+			}
+			if stopWord {
+				continue
+			}
+			foundLexemes = true
+
+			term := tsTerm{lexeme: lexeme}
+			pos := offset + i + 1
+			if pos > maxTSVectorPosition {
+				// Postgres silently truncates positions larger than 16383 to 16383.
+				pos = maxTSVectorPosition
+			}
+			term.positions = []tsPosition{{position: uint16(pos)}}
+			vector = append(vector, term)
+		}
+		offset += len(tokens)
+		if foundLexemes {
+			offset++
 		}
-		if stopWord {
+	}
+	return normalizeTSVector(vector)
+}
+
+// tsWeightFromLetter returns the weight named by the given letter, which is
+// one of A, B, C or D, in either case.
+func tsWeightFromLetter(letter byte) (tsWeight, error) {
+	switch letter {
+	case 'A', 'a':
+		return weightA, nil
+	case 'B', 'b':
+		return weightB, nil
+	case 'C', 'c':
+		return weightC, nil
+	case 'D', 'd':
+		return weightD, nil
+	}
+	return invalidWeight, pgerror.Newf(pgcode.InvalidParameterValue, `unrecognized weight: "%c"`, letter)
+}
+
+// SetWeight implements the setweight builtin. It returns a copy of the input
+// vector in which all of the positions of the given lexemes are assigned the
+// weight named by the given letter. If lexemes is nil, the positions of every
+// lexeme of the vector are updated. Lexemes without positions are left
+// unchanged.
+func SetWeight(v TSVector, letter byte, lexemes []string) (TSVector, error) {
+	weight, err := tsWeightFromLetter(letter)
+	if err != nil {
+		return nil, err
+	}
+	if weight == weightD {
+		// D is the default weight, which isn't stored explicitly.
+		weight = 0
+	}
+	var targets map[string]struct{}
+	if lexemes != nil {
+		targets = make(map[string]struct{}, len(lexemes))
+		for _, l := range lexemes {
+			targets[l] = struct{}{}
+		}
+	}
+	ret := make(TSVector, len(v))
+	for i, term := range v {
+		ret[i] = term
+		if targets != nil {
+			if _, ok := targets[term.lexeme]; !ok {
+				continue
+			}
+		}
+		if len(term.positions) == 0 {
 			continue
 		}
+		ret[i].positions = make([]tsPosition, len(term.positions))
+		for j, pos := range term.positions {
+			ret[i].positions[j] = tsPosition{position: pos.position, weight: weight}
+		}
+	}
+	return ret, nil
+}
 
-		term := tsTerm{lexeme: lexeme}
-		pos := i + 1
-		if i > maxTSVectorPosition {
-			// Postgres silently truncates positions larger than 16383 to 16383.
-			pos = maxTSVectorPosition
+// DeleteLexemes implements the ts_delete builtin. It returns a copy of the
+// input vector without the given lexemes.
+func DeleteLexemes(v TSVector, lexemes []string) TSVector {
+	targets := make(map[string]struct{}, len(lexemes))
+	for _, l := range lexemes {
+		targets[l] = struct{}{}
+	}
+	ret := make(TSVector, 0, len(v))
+	for _, term := range v {
+		if _, ok := targets[term.lexeme]; !ok {
+			ret = append(ret, term)
 		}
-		term.positions = []tsPosition{{position: uint16(pos)}}
-		vector = append(vector, term)
 	}
-	return normalizeTSVector(vector)
+	return ret
+}
+
+// FilterWeights implements the ts_filter builtin. It returns a copy of the
+// input vector which only contains the positions with one of the weights
+// named by the given letters. Lexemes left without positions, including the
+// ones which had no positions to begin with, are removed.
+func FilterWeights(v TSVector, letters []byte) (TSVector, error) {
+	var mask tsWeight
+	for _, letter := range letters {
+		weight, err := tsWeightFromLetter(letter)
+		if err != nil {
+			return nil, err
+		}
+		mask |= weight
+	}
+	ret := make(TSVector, 0, len(v))
+	for _, term := range v {
+		var positions []tsPosition
+		for _, pos := range term.positions {
+			if pos.weight.matches(mask) {
+				positions = append(positions, pos)
+			}
+		}
+		if len(positions) > 0 {
+			ret = append(ret, tsTerm{lexeme: term.lexeme, positions: positions})
+		}
+	}
+	return ret, nil
 }
@@ -213,3 +213,48 @@ func BenchmarkTSVector(b *testing.B) {
 		}
 	})
 }
+
+func TestSetWeight(t *testing.T) {
+	v, err := ParseTSVector("a:1,3B b:2 c")
+	require.NoError(t, err)
+
+	actual, err := SetWeight(v, 'c', nil)
+	require.NoError(t, err)
+	assert.Equal(t, `'a':1C,3C 'b':2C 'c'`, actual.String())
+
+	actual, err = SetWeight(v, 'A', []string{"b", "c", "z"})
+	require.NoError(t, err)
+	assert.Equal(t, `'a':1,3B 'b':2A 'c'`, actual.String())
+
+	_, err = SetWeight(v, 'x', nil)
+	assert.Error(t, err)
+}
+
+func TestDeleteLexemes(t *testing.T) {
+	v, err := ParseTSVector("a:1,3B b:2 c")
+	require.NoError(t, err)
+	assert.Equal(t, `'a':1,3B 'c'`, DeleteLexemes(v, []string{"b", "z"}).String())
+	assert.Equal(t, ``, DeleteLexemes(v, []string{"a", "b", "c"}).String())
+}
+
+func TestFilterWeights(t *testing.T) {
+	v, err := ParseTSVector("a:1,3B b:2 c")
+	require.NoError(t, err)
+
+	actual, err := FilterWeights(v, []byte{'b'})
+	require.NoError(t, err)
+	assert.Equal(t, `'a':3B`, actual.String())
+
+	actual, err = FilterWeights(v, []byte{'d'})
+	require.NoError(t, err)
+	assert.Equal(t, `'a':1 'b':2`, actual.String())
+
+	_, err = FilterWeights(v, []byte{'e'})
+	assert.Error(t, err)
+}
+
+func TestDocumentsToTSVector(t *testing.T) {
+	v, err := DocumentsToTSVector("english", []string{"hello world", "the", "foo bar"})
+	require.NoError(t, err)
+	assert.Equal(t, `'bar':6 'foo':5 'hello':1 'world':2`, v.String())
+}